@@ -32,8 +32,11 @@ import (
 
 	// Injection related imports.
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	filteredpodinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/pod/filtered"
+	filteredFactory "knative.dev/pkg/client/injection/kube/informers/factory/filtered"
 	"knative.dev/pkg/injection"
 	"knative.dev/serving/pkg/activator"
+	"knative.dev/serving/pkg/apis/serving"
 	revisioninformer "knative.dev/serving/pkg/client/injection/informers/serving/v1/revision"
 
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -59,7 +62,9 @@ import (
 	activatornet "knative.dev/serving/pkg/activator/net"
 	asmetrics "knative.dev/serving/pkg/autoscaler/metrics"
 	pkghttp "knative.dev/serving/pkg/http"
+	"knative.dev/serving/pkg/http/handler"
 	"knative.dev/serving/pkg/logging"
+	smetrics "knative.dev/serving/pkg/metrics"
 	"knative.dev/serving/pkg/networking"
 )
 
@@ -68,6 +73,12 @@ const (
 
 	// The port on which autoscaler WebSocket server listens.
 	autoscalerPort = ":8080"
+
+	// The port on which the admin server, rendering the call graph, listens.
+	adminPort = ":8022"
+
+	// The path on which the call graph is rendered.
+	callGraphPath = "/call-graph"
 )
 
 type config struct {
@@ -78,6 +89,10 @@ type config struct {
 	// TODO: run loadtests using these flags to determine optimal default values.
 	MaxIdleProxyConns        int `split_words:"true" default:"1000"`
 	MaxIdleProxyConnsPerHost int `split_words:"true" default:"100"`
+
+	// PreviewSecret authorizes the requests previewing a revision by name.
+	// Previews are rejected when it is empty.
+	PreviewSecret string `split_words:"true"`
 }
 
 func main() {
@@ -94,6 +109,8 @@ func main() {
 	log.Printf("Registering %d informer factories", len(injection.Default.GetInformerFactories()))
 	log.Printf("Registering %d informers", len(injection.Default.GetInformers()))
 
+	// The caller resolver only watches the pods of Knative Services.
+	ctx = filteredFactory.WithSelectors(ctx, serving.ServiceLabelKey)
 	ctx, informers := injection.Default.SetupInformers(ctx, cfg)
 
 	var env config
@@ -126,6 +143,13 @@ func main() {
 	ctx = pkglogging.WithLogger(ctx, logger)
 	defer flush(logger)
 
+	// The resolver indexes pods by IP, which has to be set up before the pod informer starts.
+	callerResolver, err := activatorhandler.NewCallerResolver(
+		filteredpodinformer.Get(ctx, serving.ServiceLabelKey).Informer())
+	if err != nil {
+		logger.Fatalw("Failed to set up the caller resolver", zap.Error(err))
+	}
+
 	// Run informers instead of starting them from the factory to prevent the sync hanging because of empty handler.
 	if err := controller.StartInformers(ctx.Done(), informers...); err != nil {
 		logger.Fatalw("Failed to start informers", zap.Error(err))
@@ -177,6 +201,8 @@ func main() {
 	// Create activation handler chain
 	// Note: innermost handlers are specified first, ie. the last handler in the chain will be executed first
 	var ah http.Handler = activatorhandler.New(ctx, throttler, transport)
+	ah = handler.NewRewriteHandler(ah)
+	ah = activatorhandler.NewDeadlineHandler(ah, throttler)
 	ah = concurrencyReporter.Handler(ah)
 	ah = tracing.HTTPSpanMiddleware(ah)
 	ah = configStore.HTTPMiddleware(ah)
@@ -189,16 +215,23 @@ func main() {
 
 	// NOTE: MetricHandler is being used as the outermost handler of the meaty bits. We're not interested in measuring
 	// the healthchecks or probes.
+	callGraph := activatorhandler.NewCallGraph()
+	ah = activatorhandler.NewCallerHandler(env.PodName, callerResolver, callGraph, ah)
 	ah = activatorhandler.NewMetricHandler(env.PodName, ah)
+	ah = activatorhandler.NewOverflowHandler(ah)
 	ah = activatorhandler.NewContextHandler(ctx, ah)
+	ah = activatorhandler.NewPreviewHandler(ctx, env.PreviewSecret, ah)
 
 	// Network probe handlers.
 	ah = &activatorhandler.ProbeHandler{NextHandler: ah}
 	ah = network.NewProbeHandler(ah)
 
-	// Set up our health check based on the health of stat sink and environmental factors.
+	// Set up our health check based on environmental factors. The health of the
+	// stat sink is deliberately not taken into account: while the autoscaler is
+	// unreachable stats are buffered for replay and we keep routing to the pods
+	// we know about, rather than dropping out of the endpoints.
 	sigCtx, sigCancel := context.WithCancel(context.Background())
-	hc := newHealthCheck(sigCtx, logger, statSink)
+	hc := newHealthCheck(sigCtx, logger)
 	ah = &activatorhandler.HealthHandler{HealthCheck: hc, NextHandler: ah, Logger: logger}
 
 	profilingHandler := profiling.NewHandler(logger, false)
@@ -208,6 +241,7 @@ func main() {
 	// Watch the observability config map
 	configMapWatcher.Watch(metrics.ConfigMapName(),
 		metrics.ConfigMapWatcher(ctx, component, nil /* SecretFetcher */, logger),
+		smetrics.UpdateCardinalityFromConfigMap(logger),
 		updateRequestLogFromConfigMap(logger, reqLogHandler),
 		profilingHandler.UpdateFromConfigMap)
 
@@ -219,6 +253,7 @@ func main() {
 		"http1":   pkgnet.NewServer(":"+strconv.Itoa(networking.BackendHTTPPort), ah),
 		"h2c":     pkgnet.NewServer(":"+strconv.Itoa(networking.BackendHTTP2Port), ah),
 		"profile": profiling.NewServer(profilingHandler),
+		"admin":   pkgnet.NewServer(adminPort, adminHandler(callGraph)),
 	}
 
 	errCh := make(chan error, len(servers))
@@ -256,7 +291,7 @@ func main() {
 	logger.Info("Servers shutdown.")
 }
 
-func newHealthCheck(sigCtx context.Context, logger *zap.SugaredLogger, statSink *websocket.ManagedConnection) func() error {
+func newHealthCheck(sigCtx context.Context, logger *zap.SugaredLogger) func() error {
 	once := sync.Once{}
 	return func() error {
 		select {
@@ -268,7 +303,7 @@ func newHealthCheck(sigCtx context.Context, logger *zap.SugaredLogger, statSink
 			return errors.New("received SIGTERM from kubelet")
 		default:
 			logger.Debug("No signal yet.")
-			return statSink.Status()
+			return nil
 		}
 	}
 }
@@ -279,3 +314,9 @@ func flush(logger *zap.SugaredLogger) {
 	os.Stderr.Sync()
 	metrics.FlushExporter()
 }
+
+func adminHandler(callGraph http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(callGraphPath, callGraph)
+	return mux
+}
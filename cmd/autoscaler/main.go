@@ -46,6 +46,7 @@ import (
 	"knative.dev/pkg/signals"
 	"knative.dev/pkg/system"
 	"knative.dev/pkg/version"
+	"knative.dev/serving/pkg/apis/autoscaling"
 	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
 	"knative.dev/serving/pkg/apis/serving"
 	"knative.dev/serving/pkg/autoscaler/bucket"
@@ -120,6 +121,7 @@ func main() {
 	// Watch the observability config map
 	cmw.Watch(metrics.ConfigMapName(),
 		metrics.ConfigMapWatcher(ctx, component, nil /* SecretFetcher */, logger),
+		smetrics.UpdateCardinalityFromConfigMap(logger),
 		profilingHandler.UpdateFromConfigMap)
 
 	podLister := podinformer.Get(ctx).Lister()
@@ -210,6 +212,15 @@ func main() {
 func uniScalerFactoryFunc(podLister corev1listers.PodLister,
 	metricClient asmetrics.MetricClient) scaling.UniScalerFactory {
 	return func(decider *scaling.Decider) (scaling.UniScaler, error) {
+		// Standalone PodAutoscalers scale user-managed workloads, whose pods
+		// are labeled with the PodAutoscaler's name rather than a revision.
+		if paName := decider.Labels[autoscaling.PodAutoscalerLabelKey]; paName != "" {
+			ctx := smetrics.RevisionContext(decider.Namespace, "" /*service*/, "" /*configuration*/, paName)
+			podAccessor := resources.NewStandalonePodAccessor(podLister, decider.Namespace, paName)
+			return scaling.New(ctx, decider.Namespace, decider.Name, metricClient,
+				podAccessor, &decider.Spec), nil
+		}
+
 		configName := decider.Labels[serving.ConfigurationLabelKey]
 		if configName == "" {
 			return nil, fmt.Errorf("label %q not found or empty in Decider %s", serving.ConfigurationLabelKey, decider.Name)
@@ -235,6 +246,14 @@ func statsScraperFactoryFunc(podLister corev1listers.PodLister) asmetrics.StatsS
 			return nil, nil
 		}
 
+		if paName := metric.Labels[autoscaling.PodAutoscalerLabelKey]; paName != "" {
+			podAccessor := resources.NewStandalonePodAccessor(podLister, metric.Namespace, paName)
+			if metric.Annotations[autoscaling.MetricSourceAnnotationKey] == autoscaling.MetricSourceRPS {
+				return asmetrics.NewRPSStatsScraper(metric, paName, podAccessor, logger), nil
+			}
+			return asmetrics.NewStatsScraper(metric, paName, podAccessor, logger), nil
+		}
+
 		revisionName := metric.Labels[serving.RevisionLabelKey]
 		if revisionName == "" {
 			return nil, fmt.Errorf("label %q not found or empty in Metric %s", serving.RevisionLabelKey, metric.Name)
@@ -25,6 +25,7 @@ import (
 	kubeinformers "k8s.io/client-go/informers"
 	fakek8s "k8s.io/client-go/kubernetes/fake"
 
+	"knative.dev/serving/pkg/apis/autoscaling"
 	"knative.dev/serving/pkg/apis/serving"
 	"knative.dev/serving/pkg/autoscaler/scaling"
 )
@@ -108,6 +109,23 @@ func TestUniScalerFactoryFunc(t *testing.T) {
 	}
 }
 
+func TestUniScalerFactoryFuncStandalone(t *testing.T) {
+	decider := &scaling.Decider{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "user-namespace",
+			Name:      "user-pa",
+			Labels: map[string]string{
+				autoscaling.PodAutoscalerLabelKey: "user-pa",
+			},
+		},
+		Spec: scaling.DeciderSpec{},
+	}
+
+	if _, err := testUniScalerFactory()(decider); err != nil {
+		t.Error("got error from uniScalerFactory:", err)
+	}
+}
+
 func testUniScalerFactory() func(decider *scaling.Decider) (scaling.UniScaler, error) {
 	return uniScalerFactoryFunc(kubeInformer.Core().V1().Pods().Lister(), nil)
 }
@@ -27,8 +27,11 @@ import (
 	"knative.dev/serving/pkg/reconciler/service"
 
 	// This defines the shared main for injected controllers.
+	filteredFactory "knative.dev/pkg/client/injection/kube/informers/factory/filtered"
 	"knative.dev/pkg/injection"
 	"knative.dev/pkg/injection/sharedmain"
+	"knative.dev/pkg/signals"
+	"knative.dev/serving/pkg/apis/serving"
 )
 
 var ctors = []injection.ControllerConstructor{
@@ -39,8 +42,11 @@ var ctors = []injection.ControllerConstructor{
 	serverlessservice.NewController,
 	service.NewController,
 	gc.NewController,
+	gc.NewNamespaceController,
 }
 
 func main() {
-	sharedmain.Main("controller", ctors...)
+	// The revision controller only watches the pods of revisions.
+	ctx := filteredFactory.WithSelectors(signals.NewContext(), serving.RevisionLabelKey)
+	sharedmain.MainWithContext(ctx, "controller", ctors...)
 }
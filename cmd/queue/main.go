@@ -45,9 +45,11 @@ import (
 	tracingconfig "knative.dev/pkg/tracing/config"
 	"knative.dev/pkg/tracing/propagation/tracecontextb3"
 	"knative.dev/serving/pkg/activator"
+	"knative.dev/serving/pkg/apis/serving"
 	pkghttp "knative.dev/serving/pkg/http"
 	"knative.dev/serving/pkg/http/handler"
 	"knative.dev/serving/pkg/logging"
+	smetrics "knative.dev/serving/pkg/metrics"
 	"knative.dev/serving/pkg/networking"
 	"knative.dev/serving/pkg/queue"
 	"knative.dev/serving/pkg/queue/health"
@@ -74,6 +76,16 @@ type config struct {
 	ServingReadinessProbe  string `split_words:"true" required:"true"`
 	EnableProfiling        bool   `split_words:"true"` // optional
 
+	// Response header policy, JSON encoded.
+	ServingResponseHeaderPolicy string `split_words:"true"` // optional
+
+	// Warm-up requests, JSON encoded, and how long to spend on them.
+	ServingWarmupRequests string `split_words:"true"` // optional
+	ServingWarmupTimeout  string `split_words:"true"` // optional
+
+	// Percentile of the in-flight requests to report in addition to their maximum.
+	ServingConcurrencyPercentile float64 `split_words:"true"` // optional
+
 	// Logging configuration
 	ServingLoggingConfig         string `split_words:"true" required:"true"`
 	ServingLoggingLevel          string `split_words:"true" required:"true"`
@@ -91,6 +103,12 @@ type config struct {
 	ServingRequestMetricsBackend string `split_words:"true"` // optional
 	MetricsCollectorAddress      string `split_words:"true"` // optional
 
+	// Metric cardinality configuration
+	MetricsRequestDropPodTag            bool `split_words:"true"` // optional
+	MetricsRequestResponseCodeClassOnly bool `split_words:"true"` // optional
+	MetricsRequestMaxRouteTags          int  `split_words:"true"` // optional
+	MetricsRequestMaxSourceServices     int  `split_words:"true"` // optional
+
 	// Tracing configuration
 	TracingConfigDebug                bool                      `split_words:"true"` // optional
 	TracingConfigBackend              tracingconfig.BackendType `split_words:"true"` // optional
@@ -156,11 +174,12 @@ func main() {
 	defer reportTicker.Stop()
 
 	stats := network.NewRequestStats(time.Now())
+	concurrency := queue.NewConcurrencyRecorder(env.ServingConcurrencyPercentile, time.Now())
 	go func() {
 		for now := range reportTicker.C {
-			stat := stats.Report(now)
-			promStatReporter.Report(stat)
-			protoStatReporter.Report(stat)
+			stat, dist := stats.Report(now), concurrency.Report(now)
+			promStatReporter.ReportWithConcurrency(stat, dist)
+			protoStatReporter.ReportWithConcurrency(stat, dist)
 		}
 	}()
 
@@ -168,11 +187,12 @@ func main() {
 	probe := buildProbe(logger, env.ServingReadinessProbe)
 	healthState := &health.State{}
 
-	mainServer := buildServer(ctx, env, healthState, probe, stats, logger)
+	mainServer := buildServer(ctx, env, healthState, probe, stats, concurrency, logger)
 	servers := map[string]*http.Server{
-		"main":    mainServer,
-		"admin":   buildAdminServer(logger, healthState),
-		"metrics": buildMetricsServer(promStatReporter, protoStatReporter),
+		"main":     mainServer,
+		"admin":    buildAdminServer(logger, healthState),
+		"metrics":  buildMetricsServer(promStatReporter, protoStatReporter),
+		"outbound": buildOutboundServer(env),
 	}
 	if env.EnableProfiling {
 		servers["profile"] = profiling.NewServer(profiling.NewHandler(logger, true))
@@ -262,8 +282,39 @@ func buildProbe(logger *zap.SugaredLogger, probeJSON string) *readiness.Probe {
 	return readiness.NewProbe(coreProbe)
 }
 
+func buildResponseHeaderPolicy(logger *zap.SugaredLogger, policyJSON string) *serving.ResponseHeaderPolicy {
+	if policyJSON == "" {
+		return nil
+	}
+	policy, err := serving.ParseResponseHeaderPolicy(policyJSON)
+	if err != nil {
+		logger.Fatalw("Queue container failed to parse response header policy", zap.Error(err))
+	}
+	return policy
+}
+
+func buildWarmer(logger *zap.SugaredLogger, env config, target string, transport http.RoundTripper) *queue.Warmer {
+	if env.ServingWarmupRequests == "" {
+		return nil
+	}
+	requests, err := serving.ParseWarmupRequests(env.ServingWarmupRequests)
+	if err != nil {
+		logger.Fatalw("Queue container failed to parse warm-up requests", zap.Error(err))
+	}
+	timeout, err := serving.ParseWarmupTimeout(env.ServingWarmupTimeout)
+	if err != nil {
+		logger.Fatalw("Queue container failed to parse warm-up timeout", zap.Error(err))
+	}
+	warmer, err := queue.NewWarmer(logger, transport, target, requests, timeout, env.ServingNamespace,
+		env.ServingService, env.ServingConfiguration, env.ServingRevision, env.ServingPod)
+	if err != nil {
+		logger.Fatalw("Queue container failed to set up warm-up", zap.Error(err))
+	}
+	return warmer
+}
+
 func buildServer(ctx context.Context, env config, healthState *health.State, rp *readiness.Probe, stats *network.RequestStats,
-	logger *zap.SugaredLogger) *http.Server {
+	concurrency *queue.ConcurrencyRecorder, logger *zap.SugaredLogger) *http.Server {
 
 	maxIdleConns := 1000 // TODO: somewhat arbitrary value for CC=0, needs experimental validation.
 	if env.ContainerConcurrency > 0 {
@@ -272,12 +323,16 @@ func buildServer(ctx context.Context, env config, healthState *health.State, rp
 
 	target := net.JoinHostPort("127.0.0.1", env.UserPort)
 
-	httpProxy := pkghttp.NewHeaderPruningReverseProxy(target, activator.RevisionHeaders)
+	httpProxy := pkghttp.NewHeaderPruningReverseProxy(target, activator.PodHeadersToRemove)
 	httpProxy.Transport = buildTransport(env, logger, maxIdleConns)
 	httpProxy.ErrorHandler = pkgnet.ErrorHandler(logger)
 	httpProxy.BufferPool = network.NewBufferPool()
 	httpProxy.FlushInterval = network.FlushInterval
 
+	if warmer := buildWarmer(logger, env, target, httpProxy.Transport); warmer != nil {
+		rp.SetWarmup(func() { warmer.Run(ctx) })
+	}
+
 	breaker := buildBreaker(logger, env)
 	metricsSupported := supportsMetrics(ctx, logger, env)
 	tracingEnabled := env.TracingConfigBackend != tracingconfig.None
@@ -285,13 +340,19 @@ func buildServer(ctx context.Context, env config, healthState *health.State, rp
 
 	// Create queue handler chain.
 	// Note: innermost handlers are specified first, ie. the last handler in the chain will be executed first.
-	var composedHandler http.Handler = httpProxy
+	// The rewrites are applied here when the activator is not in the path.
+	var composedHandler http.Handler = handler.NewRewriteHandler(handler.NewDeadlineForwardingHandler(httpProxy))
 	if metricsSupported {
 		composedHandler = requestAppMetricsHandler(logger, composedHandler, breaker, env)
 	}
-	composedHandler = queue.ProxyHandler(breaker, stats, tracingEnabled, composedHandler)
+	composedHandler = queue.ConcurrencyRecordingProxyHandler(breaker, stats, concurrency, tracingEnabled, composedHandler)
+	composedHandler = handler.NewDeadlineHandler(composedHandler, handler.StaticTimeoutFunc(timeout), queueWaitFunc(breaker))
 	composedHandler = queue.ForwardedShimHandler(composedHandler)
 	composedHandler = handler.NewTimeToFirstByteTimeoutHandler(composedHandler, "request timeout", handler.StaticTimeoutFunc(timeout))
+	// The policy handler answers preflight requests, so it must come before the breaker.
+	if policy := buildResponseHeaderPolicy(logger, env.ServingResponseHeaderPolicy); policy != nil {
+		composedHandler = queue.ResponseHeaderPolicyHandler(policy, composedHandler)
+	}
 
 	if metricsSupported {
 		composedHandler = requestMetricsHandler(logger, composedHandler, env)
@@ -303,7 +364,6 @@ func buildServer(ctx context.Context, env config, healthState *health.State, rp
 	// We might want sometimes capture the probes/healthchecks in the request
 	// logs. Hence we need to have RequestLogHandler to be the first one.
 	composedHandler = pushRequestLogHandler(logger, composedHandler, env)
-
 	return pkgnet.NewServer(":"+env.QueueServingPort, composedHandler)
 }
 
@@ -358,9 +418,24 @@ func supportsMetrics(ctx context.Context, logger *zap.SugaredLogger, env config)
 		return false
 	}
 
+	smetrics.SetCardinalityConfig(&smetrics.CardinalityConfig{
+		DropPodTag:            env.MetricsRequestDropPodTag,
+		ResponseCodeClassOnly: env.MetricsRequestResponseCodeClassOnly,
+		MaxRouteTags:          env.MetricsRequestMaxRouteTags,
+		MaxSourceServices:     env.MetricsRequestMaxSourceServices,
+	})
 	return true
 }
 
+// sourceService returns the calling service the outbound calls are
+// attributed to, as "<namespace>/<name>".
+func sourceService(env config) string {
+	if env.ServingService == "" {
+		return ""
+	}
+	return env.ServingNamespace + "/" + env.ServingService
+}
+
 func buildAdminServer(logger *zap.SugaredLogger, healthState *health.State) *http.Server {
 	adminMux := http.NewServeMux()
 	drainHandler := healthState.DrainHandlerFunc()
@@ -375,6 +450,17 @@ func buildAdminServer(logger *zap.SugaredLogger, healthState *health.State) *htt
 	}
 }
 
+// buildOutboundServer returns the server proxying the calls the
+// user-container sends through the queue-proxy, which are attributed to its
+// service. It only listens on localhost, so that it can't be used as an open
+// proxy by other pods.
+func buildOutboundServer(env config) *http.Server {
+	return &http.Server{
+		Addr:    net.JoinHostPort("127.0.0.1", strconv.Itoa(networking.QueueOutboundPort)),
+		Handler: queue.OutboundHandler(sourceService(env), http.DefaultTransport),
+	}
+}
+
 func buildMetricsServer(promStatReporter *queue.PrometheusStatsReporter, protobufStatReporter *queue.ProtobufStatsReporter) *http.Server {
 	metricsMux := http.NewServeMux()
 	metricsMux.Handle("/metrics", queue.NewStatsHandler(promStatReporter, protobufStatReporter))
@@ -451,3 +537,14 @@ func flush(logger *zap.SugaredLogger) {
 	os.Stderr.Sync()
 	metrics.FlushExporter()
 }
+
+// queueWaitFunc returns the function estimating how long requests wait in
+// the breaker, nil if there is no breaker.
+func queueWaitFunc(breaker *queue.Breaker) handler.QueueWaitFunc {
+	if breaker == nil {
+		return nil
+	}
+	return func(*http.Request) time.Duration {
+		return breaker.ExpectedWait()
+	}
+}
@@ -19,6 +19,7 @@ package main
 import (
 	"context"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
@@ -38,6 +39,7 @@ import (
 	net "knative.dev/networking/pkg/apis/networking/v1alpha1"
 	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingv1alpha1 "knative.dev/serving/pkg/apis/serving/v1alpha1"
 	extravalidation "knative.dev/serving/pkg/webhook"
 
 	// config validation constructors
@@ -56,6 +58,8 @@ var types = map[schema.GroupVersionKind]resourcesemantics.GenericCRD{
 	servingv1.SchemeGroupVersion.WithKind("Route"):         &servingv1.Route{},
 	servingv1.SchemeGroupVersion.WithKind("Service"):       &servingv1.Service{},
 
+	servingv1alpha1.SchemeGroupVersion.WithKind("ServiceTemplate"): &servingv1alpha1.ServiceTemplate{},
+
 	autoscalingv1alpha1.SchemeGroupVersion.WithKind("PodAutoscaler"): &autoscalingv1alpha1.PodAutoscaler{},
 	autoscalingv1alpha1.SchemeGroupVersion.WithKind("Metric"):        &autoscalingv1alpha1.Metric{},
 
@@ -104,6 +108,14 @@ func newValidationAdmissionController(ctx context.Context, cmw configmap.Watcher
 	store := defaultconfig.NewStore(logging.FromContext(ctx).Named("config-store"))
 	store.WatchConfigs(cmw)
 
+	// Namespaces are validated, but not defaulted, for the config-autoscaler
+	// overrides they carry.
+	validationTypes := make(map[schema.GroupVersionKind]resourcesemantics.GenericCRD, len(types)+1)
+	for gvk, t := range types {
+		validationTypes[gvk] = t
+	}
+	validationTypes[corev1.SchemeGroupVersion.WithKind("Namespace")] = &extravalidation.Namespace{}
+
 	return validation.NewAdmissionController(ctx,
 
 		// Name of the resource webhook.
@@ -113,7 +125,7 @@ func newValidationAdmissionController(ctx context.Context, cmw configmap.Watcher
 		"/resource-validation",
 
 		// The resources to validate.
-		types,
+		validationTypes,
 
 		// A function that infuses the context passed to Validate/SetDefaults with custom metadata.
 		store.ToContext,
@@ -137,16 +149,17 @@ func newConfigValidationController(ctx context.Context, cmw configmap.Watcher) *
 
 		// The configmaps to validate.
 		configmap.Constructors{
-			tracingconfig.ConfigName:         tracingconfig.NewTracingConfigFromConfigMap,
-			autoscalerconfig.ConfigName:      autoscalerconfig.NewConfigFromConfigMap,
-			gc.ConfigName:                    gc.NewConfigFromConfigMapFunc(ctx),
-			network.ConfigName:               network.NewConfigFromConfigMap,
-			deployment.ConfigName:            deployment.NewConfigFromConfigMap,
-			metrics.ConfigMapName():          metrics.NewObservabilityConfigFromConfigMap,
-			logging.ConfigMapName():          logging.NewConfigFromConfigMap,
-			leaderelection.ConfigMapName():   leaderelection.NewConfigFromConfigMap,
-			domainconfig.DomainConfigName:    domainconfig.NewDomainFromConfigMap,
-			defaultconfig.DefaultsConfigName: defaultconfig.NewDefaultsConfigFromConfigMap,
+			tracingconfig.ConfigName:                    tracingconfig.NewTracingConfigFromConfigMap,
+			autoscalerconfig.ConfigName:                 autoscalerconfig.NewConfigFromConfigMap,
+			gc.ConfigName:                               gc.NewConfigFromConfigMapFunc(ctx),
+			network.ConfigName:                          network.NewConfigFromConfigMap,
+			deployment.ConfigName:                       deployment.NewConfigFromConfigMap,
+			metrics.ConfigMapName():                     metrics.NewObservabilityConfigFromConfigMap,
+			logging.ConfigMapName():                     logging.NewConfigFromConfigMap,
+			leaderelection.ConfigMapName():              leaderelection.NewConfigFromConfigMap,
+			domainconfig.DomainConfigName:               domainconfig.NewDomainFromConfigMap,
+			defaultconfig.DefaultsConfigName:            defaultconfig.NewDefaultsConfigFromConfigMap,
+			defaultconfig.MetadataPropagationConfigName: defaultconfig.NewMetadataPropagationFromConfigMap,
 		},
 	)
 }
@@ -23,6 +23,52 @@ const (
 	RevisionHeaderName = "Knative-Serving-Revision"
 	// RevisionHeaderNamespace is the header key for revision's namespace.
 	RevisionHeaderNamespace = "Knative-Serving-Namespace"
+	// OverflowHeaderName is the header key marking a request an activator
+	// forwarded because its revision was at capacity. Requests carrying it
+	// are never forwarded again by any activator, which prevents overflow
+	// loops. It is removed before requests reach the user container.
+	OverflowHeaderName = "Knative-Serving-Overflow"
+	// Overflowed is the value of the overflow header on forwarded requests.
+	Overflowed = "true"
+	// NoOverflow is the value the ingress sets the overflow header to on the
+	// requests from outside the cluster, overwriting whatever a client sent,
+	// so that clients cannot opt out of overflow routing.
+	NoOverflow = "-"
+	// RemoveHeadersHeaderName is the header key carrying the comma separated
+	// request headers a traffic target asks to strip. The ingress can only
+	// add headers, so the removal is applied by the data-plane.
+	RemoveHeadersHeaderName = "Knative-Serving-Remove-Headers"
+	// PathPrefixHeaderName is the header key carrying the path prefix a
+	// traffic target asks to rewrite.
+	PathPrefixHeaderName = "Knative-Serving-Path-Prefix"
+	// PathReplacementHeaderName is the header key carrying what the path
+	// prefix is rewritten to.
+	PathReplacementHeaderName = "Knative-Serving-Path-Replacement"
+	// NoRewrite is the value of the rewrite headers above for the traffic
+	// targets that don't rewrite requests. The ingress can't strip headers
+	// and skips empty ones, so it sets the rewrite headers of every target,
+	// overwriting whatever a client sent.
+	NoRewrite = "-"
+	// SourceServiceHeaderName is the header key carrying the Knative Service
+	// a request originates from, as "<namespace>/<name>". It is used to
+	// attribute requests to their caller in metrics. It is set by the
+	// queue-proxy of the calling service on the calls sent through it.
+	SourceServiceHeaderName = "K-Source-Service"
+	// NoSourceService is the value the ingress sets the source service header
+	// to on the requests from outside the cluster, overwriting whatever a
+	// client sent.
+	NoSourceService = "-"
+	// PreviewRevisionHeaderName is the header key naming a Revision of the
+	// Route's Configurations to preview. Such requests are routed through the
+	// activator regardless of the Route's traffic targets.
+	PreviewRevisionHeaderName = "Knative-Serving-Preview-Revision"
+	// PreviewSecretHeaderName is the header key carrying the shared secret
+	// authorizing a preview request.
+	PreviewSecretHeaderName = "Knative-Serving-Preview-Secret"
+	// PreviewConfigurationsHeaderName is the header key the ingress sets to
+	// the comma separated Configurations of the Route a preview request was
+	// sent to. The previewed Revision must belong to one of them.
+	PreviewConfigurationsHeaderName = "Knative-Serving-Preview-Configurations"
 )
 
 var (
@@ -32,4 +78,8 @@ var (
 		RevisionHeaderName,
 		RevisionHeaderNamespace,
 	}
+
+	// PodHeadersToRemove are the data-plane's internal headers, which must
+	// not reach the user container.
+	PodHeadersToRemove = append([]string{OverflowHeaderName}, RevisionHeaders...)
 )
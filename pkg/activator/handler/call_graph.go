@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"knative.dev/pkg/metrics/metricskey"
+)
+
+// maxCallGraphEdges bounds the memory used by the call graph. Calls
+// between services not in the graph yet are dropped past it.
+const maxCallGraphEdges = 10000
+
+// CallGraph records the calls between Knative Services seen by this
+// activator, and renders them over HTTP.
+type CallGraph struct {
+	mu    sync.Mutex
+	edges map[callEdge]*callStats
+}
+
+type callEdge struct {
+	caller, callee string
+}
+
+type callStats struct {
+	requests int64
+	errors   int64
+	latency  time.Duration
+}
+
+// CallGraphEdge is the JSON representation of the calls from one service
+// to another.
+type CallGraphEdge struct {
+	Caller        string  `json:"caller"`
+	Callee        string  `json:"callee"`
+	Requests      int64   `json:"requests"`
+	Errors        int64   `json:"errors"`
+	MeanLatencyMs float64 `json:"meanLatencyMs"`
+}
+
+// NewCallGraph creates an empty CallGraph.
+func NewCallGraph() *CallGraph {
+	return &CallGraph{
+		edges: make(map[callEdge]*callStats),
+	}
+}
+
+// Record records a call from `caller` to `callee`. Unattributed calls are
+// recorded as coming from an unknown caller.
+func (g *CallGraph) Record(caller, callee string, responseCode int, latency time.Duration) {
+	if caller == "" {
+		caller = metricskey.ValueUnknown
+	}
+	e := callEdge{caller: caller, callee: callee}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	s, ok := g.edges[e]
+	if !ok {
+		if len(g.edges) >= maxCallGraphEdges {
+			return
+		}
+		s = &callStats{}
+		g.edges[e] = s
+	}
+	s.requests++
+	if responseCode >= http.StatusInternalServerError {
+		s.errors++
+	}
+	s.latency += latency
+}
+
+// Edges returns the edges of the graph, sorted by caller and callee.
+func (g *CallGraph) Edges() []CallGraphEdge {
+	g.mu.Lock()
+	edges := make([]CallGraphEdge, 0, len(g.edges))
+	for e, s := range g.edges {
+		edges = append(edges, CallGraphEdge{
+			Caller:        e.caller,
+			Callee:        e.callee,
+			Requests:      s.requests,
+			Errors:        s.errors,
+			MeanLatencyMs: float64(s.latency.Milliseconds()) / float64(s.requests),
+		})
+	}
+	g.mu.Unlock()
+
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Caller != edges[j].Caller {
+			return edges[i].Caller < edges[j].Caller
+		}
+		return edges[i].Callee < edges[j].Callee
+	})
+	return edges
+}
+
+// ServeHTTP renders the call graph, as JSON if the `format` query
+// parameter is "json" and in the Graphviz DOT language otherwise.
+func (g *CallGraph) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	edges := g.Edges()
+	if r.URL.Query().Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(edges)
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph calls {\n")
+	for _, e := range edges {
+		fmt.Fprintf(&b, "  %q -> %q [label=\"%d req, %d err, %.0fms\"];\n",
+			e.Caller, e.Callee, e.Requests, e.Errors, e.MeanLatencyMs)
+	}
+	b.WriteString("}\n")
+	w.Header().Set("Content-Type", "text/vnd.graphviz")
+	w.Write([]byte(b.String()))
+}
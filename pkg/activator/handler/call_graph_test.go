@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCallGraph(t *testing.T) {
+	g := NewCallGraph()
+	g.Record("ns/b", "ns/c", http.StatusOK, 30*time.Millisecond)
+	g.Record("ns/a", "ns/b", http.StatusOK, 10*time.Millisecond)
+	g.Record("ns/a", "ns/b", http.StatusBadGateway, 20*time.Millisecond)
+	g.Record("", "ns/a", http.StatusNotFound, time.Millisecond)
+
+	want := []CallGraphEdge{
+		{Caller: "ns/a", Callee: "ns/b", Requests: 2, Errors: 1, MeanLatencyMs: 15},
+		{Caller: "ns/b", Callee: "ns/c", Requests: 1, MeanLatencyMs: 30},
+		{Caller: "unknown", Callee: "ns/a", Requests: 1, MeanLatencyMs: 1},
+	}
+	if got := g.Edges(); !cmp.Equal(got, want) {
+		t.Error("Edges() (-want, +got):", cmp.Diff(want, got))
+	}
+
+	rec := httptest.NewRecorder()
+	g.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/call-graph?format=json", nil))
+	var got []CallGraphEdge
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatal("Failed to decode JSON call graph:", err)
+	}
+	if !cmp.Equal(got, want) {
+		t.Error("JSON call graph (-want, +got):", cmp.Diff(want, got))
+	}
+
+	rec = httptest.NewRecorder()
+	g.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/call-graph", nil))
+	wantDOT := `digraph calls {
+  "ns/a" -> "ns/b" [label="2 req, 1 err, 15ms"];
+  "ns/b" -> "ns/c" [label="1 req, 0 err, 30ms"];
+  "unknown" -> "ns/a" [label="1 req, 0 err, 1ms"];
+}
+`
+	if got := rec.Body.String(); got != wantDOT {
+		t.Errorf("DOT call graph = %s, want: %s", got, wantDOT)
+	}
+	if got, want := rec.Header().Get("Content-Type"), "text/vnd.graphviz"; got != want {
+		t.Errorf("Content-Type = %q, want %q", got, want)
+	}
+}
+
+func TestCallGraphMaxEdges(t *testing.T) {
+	g := NewCallGraph()
+	for i := 0; i < maxCallGraphEdges+10; i++ {
+		g.Record("ns/caller", "ns/callee-"+strconv.Itoa(i), http.StatusOK, time.Millisecond)
+	}
+	if got := len(g.Edges()); got != maxCallGraphEdges {
+		t.Errorf("len(Edges()) = %d, want %d", got, maxCallGraphEdges)
+	}
+	// Known edges are still updated.
+	g.Record("ns/caller", "ns/callee-0", http.StatusOK, time.Millisecond)
+	for _, e := range g.Edges() {
+		if e.Callee == "ns/callee-0" && e.Requests != 2 {
+			t.Errorf("Requests = %d, want 2", e.Requests)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	pkgmetrics "knative.dev/pkg/metrics"
+	"knative.dev/serving/pkg/activator"
+	"knative.dev/serving/pkg/apis/serving"
+	pkghttp "knative.dev/serving/pkg/http"
+	"knative.dev/serving/pkg/metrics"
+)
+
+const podIPIndex = "podIP"
+
+// CallerResolver attributes requests to the Knative Service calling them.
+type CallerResolver struct {
+	pods cache.Indexer
+}
+
+// NewCallerResolver creates a CallerResolver. It indexes the pods of the
+// informer, which should only watch the pods of Knative Services, by IP, so
+// it must be created before the informer starts.
+func NewCallerResolver(informer cache.SharedIndexInformer) (*CallerResolver, error) {
+	if err := informer.AddIndexers(cache.Indexers{podIPIndex: indexByPodIP}); err != nil {
+		return nil, err
+	}
+	return &CallerResolver{pods: informer.GetIndexer()}, nil
+}
+
+func indexByPodIP(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok || pod.Status.PodIP == "" || pod.Labels[serving.ServiceLabelKey] == "" {
+		return nil, nil
+	}
+	return []string{pod.Status.PodIP}, nil
+}
+
+// Resolve returns the calling service of the request, as
+// "<namespace>/<name>", or the empty string if it is unknown. The service
+// owning the pod the request originates from wins over the one named by the
+// source service header, which is only used for the requests coming through
+// the ingress.
+func (c *CallerResolver) Resolve(r *http.Request) string {
+	if objs, err := c.pods.ByIndex(podIPIndex, sourceIP(r)); err == nil && len(objs) > 0 {
+		pod := objs[0].(*corev1.Pod)
+		return pod.Namespace + "/" + pod.Labels[serving.ServiceLabelKey]
+	}
+	return metrics.SourceService(r.Header.Get(activator.SourceServiceHeaderName))
+}
+
+// sourceIP returns the IP the request originates from. That is the last
+// entry of X-Forwarded-For, which is added by the ingress, and otherwise the
+// address of the peer.
+func sourceIP(r *http.Request) string {
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		return strings.TrimSpace(xff[strings.LastIndexByte(xff, ',')+1:])
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// NewCallerHandler creates a handler that attributes requests to their
+// calling service, reports request metrics by caller and records the calls
+// in the call graph. The caller is passed on to the queue-proxy in the
+// source service header.
+// This handler must run after the context handler.
+func NewCallerHandler(podName string, resolver *CallerResolver, graph *CallGraph, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rev := revisionFrom(r.Context())
+		reporterCtx, _ := metrics.PodRevisionContext(podName, activator.Name,
+			rev.Namespace, rev.Labels[serving.ServiceLabelKey], rev.Labels[serving.ConfigurationLabelKey],
+			rev.Name)
+
+		caller := resolver.Resolve(r)
+		if caller != "" {
+			r.Header.Set(activator.SourceServiceHeaderName, caller)
+		} else {
+			r.Header.Del(activator.SourceServiceHeaderName)
+		}
+		callee := rev.Labels[serving.ServiceLabelKey]
+		if callee == "" {
+			callee = rev.Labels[serving.ConfigurationLabelKey]
+		}
+		callee = rev.Namespace + "/" + callee
+
+		start := time.Now()
+		rr := pkghttp.NewResponseRecorder(w, http.StatusOK)
+		defer func() {
+			latency := time.Since(start)
+			code := rr.ResponseCode
+			if err := recover(); err != nil {
+				code = http.StatusInternalServerError
+				defer panic(err)
+			}
+			graph.Record(caller, callee, code, latency)
+			ctx := metrics.AugmentWithResponse(metrics.AugmentWithSourceService(reporterCtx, caller), code)
+			pkgmetrics.RecordBatch(ctx, callerRequestCountM.M(1),
+				callerResponseTimeInMsecM.M(float64(latency.Milliseconds())))
+		}()
+
+		next.ServeHTTP(rr, r)
+	})
+}
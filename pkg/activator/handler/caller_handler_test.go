@@ -0,0 +1,156 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fakepodinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/pod/fake"
+	"knative.dev/pkg/metrics/metricskey"
+	"knative.dev/pkg/metrics/metricstest"
+	rtesting "knative.dev/pkg/reconciler/testing"
+	"knative.dev/serving/pkg/activator"
+	"knative.dev/serving/pkg/apis/serving"
+)
+
+func TestCallerHandler(t *testing.T) {
+	defer reset()
+	ctx, cancel, _ := rtesting.SetupFakeContextWithCancel(t)
+	defer cancel()
+
+	resolver, err := NewCallerResolver(fakepodinformer.Get(ctx).Informer())
+	if err != nil {
+		t.Fatal("NewCallerResolver() =", err)
+	}
+	pods := fakepodinformer.Get(ctx).Informer().GetIndexer()
+	for _, p := range []*corev1.Pod{
+		callerPod("caller-pod", "10.0.0.1", "caller"),
+		callerPod("other-pod", "10.0.0.2", ""),
+	} {
+		pods.Add(p)
+	}
+
+	rev := revision(testNamespace, testRevName)
+	graph := NewCallGraph()
+	var forwarded []string
+	handler := NewCallerHandler("testPod", resolver, graph, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = append(forwarded, r.Header.Get(activator.SourceServiceHeaderName))
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+
+	tests := []struct {
+		name       string
+		path       string
+		remoteAddr string
+		xff        string
+		header     string
+		want       string
+	}{{
+		name:       "pod wins over header",
+		remoteAddr: "10.0.0.1:1234",
+		header:     "other-ns/upstream",
+		want:       testNamespace + "/caller",
+	}, {
+		name:       "header",
+		remoteAddr: "10.2.0.1:1234",
+		header:     "other-ns/upstream",
+		want:       "other-ns/upstream",
+	}, {
+		name:       "invalid header falls back to pod",
+		remoteAddr: "10.0.0.1:1234",
+		header:     "upstream",
+		want:       testNamespace + "/caller",
+	}, {
+		name:       "forwarded for pod",
+		remoteAddr: "10.1.0.1:1234",
+		xff:        "1.2.3.4, 10.0.0.1",
+		want:       testNamespace + "/caller",
+		path:       "/fail",
+	}, {
+		name:       "pod without service",
+		remoteAddr: "10.0.0.2:1234",
+	}, {
+		name:       "unknown pod",
+		remoteAddr: "10.0.0.3:1234",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			forwarded = nil
+			req := httptest.NewRequest(http.MethodGet, "http://example.com"+test.path, nil)
+			req.RemoteAddr = test.remoteAddr
+			if test.xff != "" {
+				req.Header.Set("X-Forwarded-For", test.xff)
+			}
+			if test.header != "" {
+				req.Header.Set(activator.SourceServiceHeaderName, test.header)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(withRevision(context.Background(), rev)))
+
+			if len(forwarded) != 1 || forwarded[0] != test.want {
+				t.Errorf("Forwarded source = %q, want %q", forwarded, test.want)
+			}
+		})
+	}
+
+	callee := testNamespace + "/" + rev.Labels[serving.ServiceLabelKey]
+	wantEdges := []CallGraphEdge{
+		{Caller: "other-ns/upstream", Callee: callee, Requests: 1},
+		{Caller: testNamespace + "/caller", Callee: callee, Requests: 3, Errors: 1},
+		{Caller: metricskey.ValueUnknown, Callee: callee, Requests: 2},
+	}
+	got := graph.Edges()
+	if len(got) != len(wantEdges) {
+		t.Fatalf("Edges() = %v, want %v", got, wantEdges)
+	}
+	for _, want := range wantEdges {
+		found := false
+		for _, e := range got {
+			if e.Caller == want.Caller && e.Callee == want.Callee && e.Requests == want.Requests && e.Errors == want.Errors {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Edges() = %v, want to contain %v", got, want)
+		}
+	}
+
+	metricstest.AssertMetricExists(t, callerRequestCountM.Name(), callerResponseTimeInMsecM.Name())
+}
+
+func callerPod(name, ip, service string) *corev1.Pod {
+	p := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      name,
+			Labels:    map[string]string{},
+		},
+		Status: corev1.PodStatus{PodIP: ip},
+	}
+	if service != "" {
+		p.Labels[serving.ServiceLabelKey] = service
+	}
+	return p
+}
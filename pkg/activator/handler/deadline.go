@@ -0,0 +1,50 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	pkghandler "knative.dev/serving/pkg/http/handler"
+)
+
+// WaitEstimator estimates how long a request for a revision would wait for
+// capacity.
+type WaitEstimator interface {
+	ExpectedWait(revID types.NamespacedName) time.Duration
+}
+
+// NewDeadlineHandler returns a handler that applies the client deadline of
+// requests, capped by the timeout of their revision, and rejects requests
+// that would not get capacity before their deadline.
+// This handler must run after the context handler.
+func NewDeadlineHandler(next http.Handler, e WaitEstimator) http.Handler {
+	return pkghandler.NewDeadlineHandler(next, revisionTimeout, func(r *http.Request) time.Duration {
+		return e.ExpectedWait(revIDFrom(r.Context()))
+	})
+}
+
+// revisionTimeout returns the timeout of the request's revision.
+func revisionTimeout(r *http.Request) time.Duration {
+	rev := revisionFrom(r.Context())
+	if rev.Spec.TimeoutSeconds == nil {
+		return 0
+	}
+	return time.Duration(*rev.Spec.TimeoutSeconds) * time.Second
+}
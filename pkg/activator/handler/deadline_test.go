@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/ptr"
+	pkghandler "knative.dev/serving/pkg/http/handler"
+)
+
+type fakeWaitEstimator map[types.NamespacedName]time.Duration
+
+func (f fakeWaitEstimator) ExpectedWait(revID types.NamespacedName) time.Duration {
+	return f[revID]
+}
+
+func TestDeadlineHandler(t *testing.T) {
+	revID := types.NamespacedName{Namespace: testNamespace, Name: testRevName}
+	tests := []struct {
+		name       string
+		budget     string
+		timeout    int64
+		wait       time.Duration
+		wantStatus int
+		wantBudget time.Duration
+	}{{
+		name:       "within budget",
+		budget:     "10s",
+		timeout:    300,
+		wait:       time.Second,
+		wantStatus: http.StatusOK,
+		wantBudget: 10 * time.Second,
+	}, {
+		name:       "capped by revision timeout",
+		budget:     "1h",
+		timeout:    1,
+		wantStatus: http.StatusOK,
+		wantBudget: time.Second,
+	}, {
+		name:       "wait exceeds budget",
+		budget:     "500ms",
+		timeout:    300,
+		wait:       time.Second,
+		wantStatus: http.StatusGatewayTimeout,
+	}, {
+		name:       "wait exceeds revision timeout",
+		budget:     "1h",
+		timeout:    1,
+		wait:       2 * time.Second,
+		wantStatus: http.StatusGatewayTimeout,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got string
+			h := NewDeadlineHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				pkghandler.ForwardDeadline(r)
+				got = r.Header.Get(pkghandler.RequestTimeoutHeaderName)
+			}), fakeWaitEstimator{revID: test.wait})
+
+			rev := revision(testNamespace, testRevName)
+			rev.Spec.TimeoutSeconds = ptr.Int64(test.timeout)
+			ctx := withRevision(context.Background(), rev)
+			ctx = withRevID(ctx, revID)
+			req := httptest.NewRequest(http.MethodPost, "http://example.com", nil).WithContext(ctx)
+			req.Header.Set(pkghandler.RequestTimeoutHeaderName, test.budget)
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != test.wantStatus {
+				t.Errorf("Status = %d, want %d", rec.Code, test.wantStatus)
+			}
+			if test.wantBudget == 0 {
+				return
+			}
+			if d, err := time.ParseDuration(got); err != nil || d > test.wantBudget || d < test.wantBudget-time.Second {
+				t.Errorf("Forwarded budget = %q, want about %v", got, test.wantBudget)
+			}
+		})
+	}
+}
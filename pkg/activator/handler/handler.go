@@ -34,7 +34,10 @@ import (
 	"knative.dev/pkg/tracing/propagation/tracecontextb3"
 	"knative.dev/serving/pkg/activator"
 	activatorconfig "knative.dev/serving/pkg/activator/config"
+	revisioninformer "knative.dev/serving/pkg/client/injection/informers/serving/v1/revision"
+	servinglisters "knative.dev/serving/pkg/client/listers/serving/v1"
 	pkghttp "knative.dev/serving/pkg/http"
+	pkghandler "knative.dev/serving/pkg/http/handler"
 	"knative.dev/serving/pkg/queue"
 )
 
@@ -50,18 +53,20 @@ type activationHandler struct {
 	tracingTransport http.RoundTripper
 	throttler        Throttler
 	bufferPool       httputil.BufferPool
+	revisionLister   servinglisters.RevisionLister
 }
 
 // New constructs a new http.Handler that deals with revision activation.
-func New(_ context.Context, t Throttler, transport http.RoundTripper) http.Handler {
+func New(ctx context.Context, t Throttler, transport http.RoundTripper) http.Handler {
 	return &activationHandler{
 		transport: transport,
 		tracingTransport: &ochttp.Transport{
 			Base:        transport,
 			Propagation: tracecontextb3.TraceContextB3Egress,
 		},
-		throttler:  t,
-		bufferPool: network.NewBufferPool(),
+		throttler:      t,
+		bufferPool:     network.NewBufferPool(),
+		revisionLister: revisioninformer.Get(ctx).Lister(),
 	}
 }
 
@@ -69,12 +74,20 @@ func (a *activationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	logger := logging.FromContext(r.Context())
 	tracingEnabled := activatorconfig.FromContext(r.Context()).Tracing.Backend != tracingconfig.None
 
+	revID := revIDFrom(r.Context())
+	overflow, hasOverflow := overflowFrom(r)
+
 	tryContext, trySpan := r.Context(), (*trace.Span)(nil)
+	if hasOverflow && overflow.activationTimeout > 0 {
+		var cancel context.CancelFunc
+		tryContext, cancel = context.WithTimeout(tryContext, overflow.activationTimeout)
+		defer cancel()
+	}
 	if tracingEnabled {
-		tryContext, trySpan = trace.StartSpan(r.Context(), "throttler_try")
+		tryContext, trySpan = trace.StartSpan(tryContext, "throttler_try")
 	}
 
-	if err := a.throttler.Try(tryContext, revIDFrom(r.Context()), func(dest string) error {
+	if err := a.throttler.Try(tryContext, revID, func(dest string) error {
 		trySpan.End()
 
 		proxyCtx, proxySpan := r.Context(), (*trace.Span)(nil)
@@ -90,9 +103,16 @@ func (a *activationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		trySpan.Annotate([]trace.Attribute{trace.StringAttribute("activator.throttler.error", err.Error())}, "ThrottlerTry")
 		trySpan.End()
 
+		atCapacity := errors.Is(err, context.DeadlineExceeded) || errors.Is(err, queue.ErrRequestQueueFull)
+		if atCapacity && hasOverflow && r.Context().Err() == nil {
+			logger.Debugw("Forwarding request to overflow target", zap.Stringer("target", overflow.target), zap.Error(err))
+			a.overflowRequest(logger, w, r, revID, overflow.target, tracingEnabled)
+			return
+		}
+
 		logger.Errorw("Throttler try error", zap.Error(err))
 
-		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, queue.ErrRequestQueueFull) {
+		if atCapacity {
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
 		} else {
 			w.WriteHeader(http.StatusInternalServerError)
@@ -103,9 +123,12 @@ func (a *activationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func (a *activationHandler) proxyRequest(logger *zap.SugaredLogger, w http.ResponseWriter, r *http.Request, target string, tracingEnabled bool) {
 	network.RewriteHostIn(r)
 	r.Header.Set(network.ProxyHeaderName, activator.Name)
+	a.proxy(logger, w, r, target, activator.PodHeadersToRemove, tracingEnabled)
+}
 
+func (a *activationHandler) proxy(logger *zap.SugaredLogger, w http.ResponseWriter, r *http.Request, target string, headersToRemove []string, tracingEnabled bool) {
 	// Set up the reverse proxy.
-	proxy := pkghttp.NewHeaderPruningReverseProxy(target, activator.RevisionHeaders)
+	proxy := pkghttp.NewHeaderPruningReverseProxy(target, headersToRemove)
 	proxy.BufferPool = a.bufferPool
 	proxy.Transport = a.transport
 	if tracingEnabled {
@@ -114,5 +137,6 @@ func (a *activationHandler) proxyRequest(logger *zap.SugaredLogger, w http.Respo
 	proxy.FlushInterval = network.FlushInterval
 	proxy.ErrorHandler = pkgnet.ErrorHandler(logger)
 
+	pkghandler.ForwardDeadline(r)
 	proxy.ServeHTTP(w, r)
 }
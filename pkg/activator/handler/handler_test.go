@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math/rand"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -186,6 +187,152 @@ func TestActivationHandlerProxyHeader(t *testing.T) {
 	}
 }
 
+func TestActivationHandlerOverflow(t *testing.T) {
+	const fallbackName = "fallback"
+	localAddr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 8012}
+
+	tests := []struct {
+		name          string
+		target        string
+		throttler     Throttler
+		fallbackCfg   string
+		forwarded     bool
+		spoofed       bool
+		wantCode      int
+		wantURLHost   string
+		wantHost      string
+		wantRevHeader string
+	}{{
+		name:        "capacity available",
+		target:      "revision:" + fallbackName,
+		throttler:   fakeThrottler{},
+		wantCode:    http.StatusOK,
+		wantURLHost: "10.10.10.10:1234",
+	}, {
+		name:        "capacity available, client sent overflow header",
+		target:      "revision:" + fallbackName,
+		throttler:   fakeThrottler{},
+		spoofed:     true,
+		wantCode:    http.StatusOK,
+		wantURLHost: "10.10.10.10:1234",
+	}, {
+		name:          "queue full, revision target",
+		target:        "revision:" + fallbackName,
+		throttler:     fakeThrottler{err: queue.ErrRequestQueueFull},
+		wantCode:      http.StatusOK,
+		wantURLHost:   localAddr.String(),
+		wantHost:      "example.com",
+		wantRevHeader: fallbackName,
+	}, {
+		name:          "activation timeout, revision target",
+		target:        "revision:" + fallbackName,
+		throttler:     fakeThrottler{err: context.DeadlineExceeded},
+		wantCode:      http.StatusOK,
+		wantURLHost:   localAddr.String(),
+		wantHost:      "example.com",
+		wantRevHeader: fallbackName,
+	}, {
+		name:        "queue full, revision target of another configuration",
+		target:      "revision:" + fallbackName,
+		throttler:   fakeThrottler{err: queue.ErrRequestQueueFull},
+		fallbackCfg: "another-config",
+		wantCode:    http.StatusServiceUnavailable,
+	}, {
+		name:        "queue full, service target",
+		target:      "service:" + fallbackName,
+		throttler:   fakeThrottler{err: queue.ErrRequestQueueFull},
+		wantCode:    http.StatusOK,
+		wantURLHost: "fallback.real-namespace.svc.cluster.local",
+		wantHost:    "fallback.real-namespace.svc.cluster.local",
+	}, {
+		name:      "queue full, already forwarded",
+		target:    "service:" + fallbackName,
+		throttler: fakeThrottler{err: queue.ErrRequestQueueFull},
+		forwarded: true,
+		wantCode:  http.StatusServiceUnavailable,
+	}, {
+		name:          "queue full, client sent overflow header",
+		target:        "revision:" + fallbackName,
+		throttler:     fakeThrottler{err: queue.ErrRequestQueueFull},
+		spoofed:       true,
+		wantCode:      http.StatusOK,
+		wantURLHost:   localAddr.String(),
+		wantHost:      "example.com",
+		wantRevHeader: fallbackName,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			interceptCh := make(chan *http.Request, 1)
+			rt := pkgnet.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				interceptCh <- r
+				return httptest.NewRecorder().Result(), nil
+			})
+
+			ctx, cancel, _ := rtesting.SetupFakeContextWithCancel(t)
+			defer cancel()
+
+			rev := revision(testNamespace, testRevName)
+			rev.Annotations = map[string]string{serving.OverflowTargetAnnotationKey: test.target}
+			fallback := revision(testNamespace, fallbackName)
+			fallback.Labels[serving.ConfigurationLabelKey] = rev.Labels[serving.ConfigurationLabelKey]
+			if test.fallbackCfg != "" {
+				fallback.Labels[serving.ConfigurationLabelKey] = test.fallbackCfg
+			}
+			revisionInformer(ctx, rev, fallback)
+
+			handler := New(ctx, test.throttler, rt)
+
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "http://example.com", nil)
+			if test.forwarded {
+				req.Header.Set(activator.OverflowHeaderName, activator.Overflowed)
+			}
+			if test.spoofed {
+				req.Header.Set(activator.OverflowHeaderName, "another-revision")
+			}
+
+			configStore := setupConfigStore(t, logging.FromContext(ctx))
+			ctx = configStore.ToContext(req.Context())
+			ctx = context.WithValue(ctx, http.LocalAddrContextKey, localAddr)
+			ctx = withRevision(ctx, rev)
+			ctx = withRevID(ctx, types.NamespacedName{Namespace: testNamespace, Name: testRevName})
+
+			handler.ServeHTTP(resp, req.WithContext(ctx))
+
+			if resp.Code != test.wantCode {
+				t.Fatalf("Unexpected response status. Want %d, got %d", test.wantCode, resp.Code)
+			}
+			if test.wantURLHost == "" {
+				if len(interceptCh) != 0 {
+					t.Fatal("Request was unexpectedly proxied")
+				}
+				return
+			}
+
+			httpReq := <-interceptCh
+			if got := httpReq.URL.Host; got != test.wantURLHost {
+				t.Errorf("URL.Host = %q, want: %q", got, test.wantURLHost)
+			}
+			if test.wantHost == "" {
+				if got := httpReq.Header.Get(activator.OverflowHeaderName); got != "" {
+					t.Errorf("Header %q = %q, want empty", activator.OverflowHeaderName, got)
+				}
+				return
+			}
+			if got := httpReq.Host; got != test.wantHost {
+				t.Errorf("Host = %q, want: %q", got, test.wantHost)
+			}
+			if got := httpReq.Header.Get(activator.OverflowHeaderName); got != activator.Overflowed {
+				t.Errorf("Header %q = %q, want: %q", activator.OverflowHeaderName, got, activator.Overflowed)
+			}
+			if got := httpReq.Header.Get(activator.RevisionHeaderName); got != test.wantRevHeader {
+				t.Errorf("Header %q = %q, want: %q", activator.RevisionHeaderName, got, test.wantRevHeader)
+			}
+		})
+	}
+}
+
 func TestActivationHandlerTraceSpans(t *testing.T) {
 	testcases := []struct {
 		name         string
@@ -380,3 +527,40 @@ func (rr *responseRecorder) Write(p []byte) (int, error) {
 func (rr *responseRecorder) WriteHeader(code int) {
 	rr.code = code
 }
+
+func TestOverflowHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{{
+		name: "no header",
+	}, {
+		name:   "forwarded by an activator",
+		header: activator.Overflowed,
+		want:   activator.Overflowed,
+	}, {
+		name:   "marked by the ingress",
+		header: activator.NoOverflow,
+	}, {
+		name:   "sent by a client",
+		header: "another-revision",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got string
+			handler := NewOverflowHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get(activator.OverflowHeaderName)
+			}))
+			req := httptest.NewRequest(http.MethodPost, "http://example.com", nil)
+			if test.header != "" {
+				req.Header.Set(activator.OverflowHeaderName, test.header)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			if got != test.want {
+				t.Errorf("Header %q = %q, want: %q", activator.OverflowHeaderName, got, test.want)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	pkgmetrics "knative.dev/pkg/metrics"
 	"knative.dev/serving/pkg/activator"
 	"knative.dev/serving/pkg/apis/serving"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	pkghttp "knative.dev/serving/pkg/http"
 	"knative.dev/serving/pkg/metrics"
 )
@@ -44,7 +45,12 @@ type MetricHandler struct {
 func (h *MetricHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	rev := revisionFrom(r.Context())
 	reporterCtx, _ := metrics.PodRevisionContext(h.podName, activator.Name,
-		rev.Namespace, rev.Labels[serving.ServiceLabelKey], rev.Labels[serving.ConfigurationLabelKey], rev.Name)
+		rev.Namespace, rev.Labels[serving.ServiceLabelKey], rev.Labels[serving.ConfigurationLabelKey],
+		metrics.RevisionName(rev.Name, rev.GetRoutingState() == v1.RoutingStateReserve))
+
+	// The activation handler marks the requests it forwards to the overflow
+	// target. Requests arriving already marked are counted by their source.
+	overflowed := isOverflowed(r)
 
 	start := time.Now()
 
@@ -57,6 +63,9 @@ func (h *MetricHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			pkgmetrics.RecordBatch(reporterCtx, responseTimeInMsecM.M(float64(latency.Milliseconds())), requestCountM.M(1))
 			panic(err)
 		}
+		if !overflowed && isOverflowed(r) {
+			pkgmetrics.Record(reporterCtx, overflowRequestCountM.M(1))
+		}
 		reporterCtx := metrics.AugmentWithResponse(reporterCtx, rr.ResponseCode)
 		pkgmetrics.RecordBatch(reporterCtx, responseTimeInMsecM.M(float64(latency.Milliseconds())), requestCountM.M(1))
 	}()
@@ -32,6 +32,8 @@ import (
 	_ "knative.dev/pkg/metrics/testing"
 	"knative.dev/serving/pkg/activator"
 	"knative.dev/serving/pkg/apis/serving"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/metrics"
 )
 
 func TestRequestMetricHandler(t *testing.T) {
@@ -121,8 +123,90 @@ func TestRequestMetricHandler(t *testing.T) {
 	}
 }
 
+func TestRequestMetricHandlerOverflow(t *testing.T) {
+	const testPod = "testPod"
+	rev := revision(testNamespace, testRevName)
+
+	tests := []struct {
+		label     string
+		forwarded bool
+		overflow  bool
+	}{{
+		label: "not forwarded",
+	}, {
+		label:    "forwarded to overflow target",
+		overflow: true,
+	}, {
+		label:     "received from overflow source",
+		forwarded: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.label, func(t *testing.T) {
+			defer reset()
+			handler := NewMetricHandler(testPod, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if test.overflow {
+					r.Header.Set(activator.OverflowHeaderName, activator.Overflowed)
+				}
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "http://example.com", nil)
+			if test.forwarded {
+				req.Header.Set(activator.OverflowHeaderName, activator.Overflowed)
+			}
+			reqCtx := withRevision(context.Background(), rev)
+			reqCtx = withRevID(reqCtx, types.NamespacedName{Namespace: testNamespace, Name: testRevName})
+			handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(reqCtx))
+
+			if !test.overflow {
+				metricstest.AssertNoMetric(t, overflowRequestCountM.Name())
+				return
+			}
+			wantTags := map[string]string{
+				metricskey.PodName:       testPod,
+				metricskey.ContainerName: activator.Name,
+			}
+			metricstest.AssertMetric(t, metricstest.IntMetric(overflowRequestCountM.Name(), 1, wantTags))
+		})
+	}
+}
+
+func TestRequestMetricHandlerRetiredRevision(t *testing.T) {
+	const testPod = "testPod"
+	defer reset()
+	metrics.SetCardinalityConfig(&metrics.CardinalityConfig{AggregateRetiredRevisions: true})
+	defer metrics.SetCardinalityConfig(&metrics.CardinalityConfig{})
+
+	rev := revision(testNamespace, testRevName)
+	rev.Labels[serving.RoutingStateLabelKey] = string(v1.RoutingStateReserve)
+	handler := NewMetricHandler(testPod, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodPost, "http://example.com", nil)
+	reqCtx := withRevision(context.Background(), rev)
+	reqCtx = withRevID(reqCtx, types.NamespacedName{Namespace: testNamespace, Name: testRevName})
+	handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(reqCtx))
+
+	wantResource := &resource.Resource{
+		Type: "knative_revision",
+		Labels: map[string]string{
+			metricskey.LabelNamespaceName:     rev.Namespace,
+			metricskey.LabelServiceName:       rev.Labels[serving.ServiceLabelKey],
+			metricskey.LabelConfigurationName: rev.Labels[serving.ConfigurationLabelKey],
+			metricskey.LabelRevisionName:      metrics.OtherValue,
+		},
+	}
+	wantTags := map[string]string{
+		metricskey.PodName:                testPod,
+		metricskey.ContainerName:          activator.Name,
+		metricskey.LabelResponseCode:      "200",
+		metricskey.LabelResponseCodeClass: "2xx",
+	}
+	metricstest.AssertMetric(t, metricstest.IntMetric(requestCountM.Name(), 1, wantTags).WithResource(wantResource))
+}
+
 func reset() {
-	metricstest.Unregister(requestConcurrencyM.Name(), requestCountM.Name(), responseTimeInMsecM.Name())
+	metricstest.Unregister(requestConcurrencyM.Name(), requestCountM.Name(), overflowRequestCountM.Name(), responseTimeInMsecM.Name(),
+		callerRequestCountM.Name(), callerResponseTimeInMsecM.Name())
 	register()
 }
 
@@ -34,10 +34,22 @@ var (
 		"request_count",
 		"The number of requests that are routed to Activator",
 		stats.UnitDimensionless)
+	overflowRequestCountM = stats.Int64(
+		"overflow_request_count",
+		"The number of requests forwarded to the overflow target of a revision at capacity",
+		stats.UnitDimensionless)
 	responseTimeInMsecM = stats.Float64(
 		"request_latencies",
 		"The response time in millisecond",
 		stats.UnitMilliseconds)
+	callerRequestCountM = stats.Int64(
+		"caller_request_count",
+		"The number of requests that are routed to Activator by calling service",
+		stats.UnitDimensionless)
+	callerResponseTimeInMsecM = stats.Float64(
+		"caller_request_latencies",
+		"The response time in millisecond by calling service",
+		stats.UnitMilliseconds)
 
 	// NOTE: 0 should not be used as boundary. See
 	// https://github.com/census-ecosystem/opencensus-go-exporter-stackdriver/issues/98
@@ -65,12 +77,30 @@ func register() {
 			Aggregation: view.Count(),
 			TagKeys:     []tag.Key{metrics.PodTagKey, metrics.ContainerTagKey, metrics.ResponseCodeKey, metrics.ResponseCodeClassKey},
 		},
+		&view.View{
+			Description: "The number of requests forwarded to the overflow target of a revision at capacity",
+			Measure:     overflowRequestCountM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{metrics.PodTagKey, metrics.ContainerTagKey},
+		},
 		&view.View{
 			Description: "The response time in millisecond",
 			Measure:     responseTimeInMsecM,
 			Aggregation: defaultLatencyDistribution,
 			TagKeys:     []tag.Key{metrics.PodTagKey, metrics.ContainerTagKey, metrics.ResponseCodeKey, metrics.ResponseCodeClassKey},
 		},
+		&view.View{
+			Description: "The number of requests that are routed to Activator by calling service",
+			Measure:     callerRequestCountM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{metrics.PodTagKey, metrics.ContainerTagKey, metrics.SourceServiceKey, metrics.ResponseCodeClassKey},
+		},
+		&view.View{
+			Description: "The response time in millisecond by calling service",
+			Measure:     callerResponseTimeInMsecM,
+			Aggregation: defaultLatencyDistribution,
+			TagKeys:     []tag.Key{metrics.PodTagKey, metrics.ContainerTagKey, metrics.SourceServiceKey, metrics.ResponseCodeClassKey},
+		},
 	); err != nil {
 		panic(err)
 	}
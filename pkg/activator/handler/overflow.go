@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+
+	network "knative.dev/networking/pkg"
+	pkgnet "knative.dev/pkg/network"
+	"knative.dev/serving/pkg/activator"
+	"knative.dev/serving/pkg/apis/serving"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// isOverflowed returns whether the request was forwarded by an activator
+// to an overflow target.
+func isOverflowed(r *http.Request) bool {
+	return r.Header.Get(activator.OverflowHeaderName) == activator.Overflowed
+}
+
+// NewOverflowHandler drops the overflow header from the requests that were
+// not forwarded by an activator, such as the ones the ingress marked with
+// activator.NoOverflow.
+func NewOverflowHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isOverflowed(r) {
+			r.Header.Del(activator.OverflowHeaderName)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// overflow describes where and when requests to a revision at capacity
+// are forwarded.
+type overflow struct {
+	target            v1.OverflowTarget
+	activationTimeout time.Duration
+}
+
+// overflowFrom returns the overflow configuration for the request's revision.
+// Requests that were already forwarded once are never forwarded again.
+func overflowFrom(r *http.Request) (overflow, bool) {
+	if isOverflowed(r) {
+		return overflow{}, false
+	}
+	rev, ok := r.Context().Value(revisionKey{}).(*v1.Revision)
+	if !ok {
+		return overflow{}, false
+	}
+	target, ok := rev.GetOverflowTarget()
+	if !ok {
+		return overflow{}, false
+	}
+	timeout, _ := rev.GetOverflowActivationTimeout()
+	return overflow{target: target, activationTimeout: timeout}, true
+}
+
+// overflowRequest forwards a request the revision identified by revID has no
+// capacity for to the overflow target.
+func (a *activationHandler) overflowRequest(logger *zap.SugaredLogger, w http.ResponseWriter, r *http.Request,
+	revID types.NamespacedName, target v1.OverflowTarget, tracingEnabled bool) {
+	r.Header.Set(activator.OverflowHeaderName, activator.Overflowed)
+
+	switch target.Kind {
+	case v1.OverflowTargetRevision:
+		// Only spill within the same configuration, so that the fallback
+		// serves the same application.
+		rev := r.Context().Value(revisionKey{}).(*v1.Revision)
+		fallback, err := a.revisionLister.Revisions(revID.Namespace).Get(target.Name)
+		if err != nil {
+			logger.Errorw("Error while getting overflow revision", zap.Error(err))
+			sendError(err, w)
+			return
+		}
+		if cfg := rev.Labels[serving.ConfigurationLabelKey]; fallback.Labels[serving.ConfigurationLabelKey] != cfg {
+			logger.Errorf("Overflow revision %s is not part of configuration %s", target.Name, cfg)
+			http.Error(w, "overflow revision is not part of the same configuration", http.StatusServiceUnavailable)
+			return
+		}
+
+		// Dispatch the request back to this activator, so that it is
+		// throttled and accounted for against the fallback revision.
+		addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr)
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		r.Header.Set(activator.RevisionHeaderName, fallback.Name)
+		r.Header.Set(activator.RevisionHeaderNamespace, fallback.Namespace)
+		a.proxy(logger, w, r, addr.String(), nil, tracingEnabled)
+
+	case v1.OverflowTargetService:
+		// Route the request through the ingress of the service, as any
+		// other cluster-local client would.
+		host := pkgnet.GetServiceHostname(target.Name, revID.Namespace)
+		r.Host = host
+		r.Header.Del(network.OriginalHostHeader)
+		a.proxy(logger, w, r, host, activator.RevisionHeaders, tracingEnabled)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"knative.dev/serving/pkg/activator"
+	"knative.dev/serving/pkg/apis/serving"
+	revisioninformer "knative.dev/serving/pkg/client/injection/informers/serving/v1/revision"
+	servinglisters "knative.dev/serving/pkg/client/listers/serving/v1"
+)
+
+// NewPreviewHandler creates a handler that only lets through the requests
+// previewing a Revision by name which carry the shared `secret`. The
+// previewed Revision is looked up among the Configurations of the Route the
+// request was sent to, and the request is forwarded to it. The preview
+// headers are removed before the request is forwarded, so that the secret
+// never reaches the user container. All previews are rejected when the
+// secret is empty.
+func NewPreviewHandler(ctx context.Context, secret string, next http.Handler) http.Handler {
+	return &previewHandler{
+		secret:         secret,
+		revisionLister: revisioninformer.Get(ctx).Lister(),
+		nextHandler:    next,
+	}
+}
+
+// previewHandler routes the authorized preview requests to the Revision
+// they name.
+type previewHandler struct {
+	secret         string
+	revisionLister servinglisters.RevisionLister
+	nextHandler    http.Handler
+}
+
+func (h *previewHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := r.Header.Get(activator.PreviewRevisionHeaderName)
+	if name == "" {
+		h.nextHandler.ServeHTTP(w, r)
+		return
+	}
+
+	got := r.Header.Get(activator.PreviewSecretHeaderName)
+	configs := r.Header.Get(activator.PreviewConfigurationsHeaderName)
+	r.Header.Del(activator.PreviewRevisionHeaderName)
+	r.Header.Del(activator.PreviewSecretHeaderName)
+	r.Header.Del(activator.PreviewConfigurationsHeaderName)
+	if h.secret == "" || subtle.ConstantTimeCompare([]byte(got), []byte(h.secret)) != 1 {
+		http.Error(w, "revision preview is not authorized", http.StatusForbidden)
+		return
+	}
+
+	namespace := r.Header.Get(activator.RevisionHeaderNamespace)
+	revision, err := h.revisionLister.Revisions(namespace).Get(name)
+	if err != nil {
+		sendError(err, w)
+		return
+	}
+	if !hasConfiguration(configs, revision.Labels[serving.ConfigurationLabelKey]) {
+		http.Error(w, fmt.Sprintf("revision %q does not belong to the route", name), http.StatusNotFound)
+		return
+	}
+
+	r.Header.Set(activator.RevisionHeaderName, name)
+	h.nextHandler.ServeHTTP(w, r)
+}
+
+// hasConfiguration returns whether the comma separated configs contain the
+// given Configuration.
+func hasConfiguration(configs, config string) bool {
+	if config == "" {
+		return false
+	}
+	for _, c := range strings.Split(configs, ",") {
+		if c == config {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	rtesting "knative.dev/pkg/reconciler/testing"
+	"knative.dev/serving/pkg/activator"
+)
+
+func TestPreviewHandler(t *testing.T) {
+	ctx, cancel, _ := rtesting.SetupFakeContextWithCancel(t)
+	defer cancel()
+	revisionInformer(ctx, revision(testNamespace, "rev-00001"))
+
+	tests := []struct {
+		name         string
+		secret       string
+		headers      map[string]string
+		wantCode     int
+		wantRevision string
+	}{{
+		name:     "not a preview",
+		secret:   "s3cr3t",
+		wantCode: http.StatusOK,
+	}, {
+		name:   "authorized preview",
+		secret: "s3cr3t",
+		headers: map[string]string{
+			activator.PreviewRevisionHeaderName:       "rev-00001",
+			activator.PreviewSecretHeaderName:         "s3cr3t",
+			activator.PreviewConfigurationsHeaderName: "other,config-rev-00001",
+		},
+		wantCode:     http.StatusOK,
+		wantRevision: "rev-00001",
+	}, {
+		name:   "revision of another route",
+		secret: "s3cr3t",
+		headers: map[string]string{
+			activator.PreviewRevisionHeaderName:       "rev-00001",
+			activator.PreviewSecretHeaderName:         "s3cr3t",
+			activator.PreviewConfigurationsHeaderName: "other",
+		},
+		wantCode: http.StatusNotFound,
+	}, {
+		name:   "unknown revision",
+		secret: "s3cr3t",
+		headers: map[string]string{
+			activator.PreviewRevisionHeaderName:       "rev-00002",
+			activator.PreviewSecretHeaderName:         "s3cr3t",
+			activator.PreviewConfigurationsHeaderName: "config-rev-00002",
+		},
+		wantCode: http.StatusNotFound,
+	}, {
+		name:   "wrong secret",
+		secret: "s3cr3t",
+		headers: map[string]string{
+			activator.PreviewRevisionHeaderName:       "rev-00001",
+			activator.PreviewSecretHeaderName:         "guess",
+			activator.PreviewConfigurationsHeaderName: "config-rev-00001",
+		},
+		wantCode: http.StatusForbidden,
+	}, {
+		name:   "missing secret",
+		secret: "s3cr3t",
+		headers: map[string]string{
+			activator.PreviewRevisionHeaderName:       "rev-00001",
+			activator.PreviewConfigurationsHeaderName: "config-rev-00001",
+		},
+		wantCode: http.StatusForbidden,
+	}, {
+		name: "no secret configured",
+		headers: map[string]string{
+			activator.PreviewRevisionHeaderName:       "rev-00001",
+			activator.PreviewSecretHeaderName:         "",
+			activator.PreviewConfigurationsHeaderName: "config-rev-00001",
+		},
+		wantCode: http.StatusForbidden,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for _, h := range []string{activator.PreviewRevisionHeaderName, activator.PreviewSecretHeaderName,
+					activator.PreviewConfigurationsHeaderName} {
+					if v := r.Header.Get(h); v != "" {
+						t.Errorf("Header %s = %q was forwarded", h, v)
+					}
+				}
+				if got := r.Header.Get(activator.RevisionHeaderName); got != test.wantRevision {
+					t.Errorf("Revision = %q, want: %q", got, test.wantRevision)
+				}
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+			req.Header.Set(activator.RevisionHeaderNamespace, testNamespace)
+			for k, v := range test.headers {
+				req.Header.Set(k, v)
+			}
+			resp := httptest.NewRecorder()
+			NewPreviewHandler(ctx, test.secret, next).ServeHTTP(resp, req)
+
+			if got := resp.Code; got != test.wantCode {
+				t.Errorf("StatusCode = %d, want: %d", got, test.wantCode)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"sort"
 	"sync"
+	"time"
 
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
@@ -60,6 +61,11 @@ const (
 	// requires an explicit buffer size (it's backed by a chan struct{}), but
 	// queue.MaxBreakerCapacity is math.MaxInt32.
 	revisionMaxConcurrency = queue.MaxBreakerCapacity
+
+	// numThrottlerShards is the number of shards the revision throttlers are
+	// spread across. Sharding the map reduces lock contention on the request
+	// path when many revisions are being served at the same time.
+	numThrottlerShards = 32
 )
 
 func newPodTracker(dest string, b breaker) *podTracker {
@@ -120,6 +126,7 @@ type breaker interface {
 	Maybe(ctx context.Context, thunk func()) error
 	UpdateConcurrency(int)
 	Reserve(ctx context.Context) (func(), bool)
+	ExpectedWait() time.Duration
 }
 
 // revisionThrottler is used to throttle requests across the entire revision.
@@ -149,23 +156,44 @@ type revisionThrottler struct {
 	breaker breaker
 
 	// This will be non-empty when we're able to use pod addressing.
+	// podTrackers is only accessed by the goroutine processing updates and is
+	// never mutated after it has been assigned, since it may be shared with
+	// the published throttlerState.
 	podTrackers []*podTracker
 
-	// Effective trackers that are assigned to this Activator.
-	// This is a subset of podTrackers.
-	assignedTrackers []*podTracker
-
 	// If we don't have a healthy clusterIPTracker this is set to nil, otherwise
 	// it is the l4dest for this revision's private clusterIP.
+	// Like podTrackers this is only accessed by the update goroutine.
 	clusterIPTracker *podTracker
 
-	// mux guards the "throttler state" which is the state we use during the
-	// request path. This is: trackers, clusterIPDest.
-	mux sync.RWMutex
+	// state holds the *throttlerState, which is the state we use during the
+	// request path. It is replaced atomically on every update, so the request
+	// path never has to take a lock.
+	state atomic.Value
 
 	logger *zap.SugaredLogger
 }
 
+// throttlerState is an immutable snapshot of the revisionThrottler state used
+// on the request path. It is never modified after being published, instead a
+// new copy is built and swapped in on every update.
+type throttlerState struct {
+	// Effective trackers that are assigned to this Activator.
+	// This is a subset of podTrackers.
+	assignedTrackers []*podTracker
+
+	// The l4dest for this revision's private clusterIP, if it is healthy.
+	clusterIPTracker *podTracker
+}
+
+// loadState returns the current request path state of the throttler.
+func (rt *revisionThrottler) loadState() *throttlerState {
+	if s, ok := rt.state.Load().(*throttlerState); ok {
+		return s
+	}
+	return &throttlerState{}
+}
+
 func newRevisionThrottler(revID types.NamespacedName,
 	containerConcurrency int, proto string,
 	breakerParams queue.BreakerParams,
@@ -204,13 +232,11 @@ func noop() {}
 // Returns a dest that at the moment of choosing had an open slot
 // for request.
 func (rt *revisionThrottler) acquireDest(ctx context.Context) (func(), *podTracker) {
-	rt.mux.RLock()
-	defer rt.mux.RUnlock()
-
-	if rt.clusterIPTracker != nil {
-		return noop, rt.clusterIPTracker
+	s := rt.loadState()
+	if s.clusterIPTracker != nil {
+		return noop, s.clusterIPTracker
 	}
-	return rt.lbPolicy(ctx, rt.assignedTrackers)
+	return rt.lbPolicy(ctx, s.assignedTrackers)
 }
 
 func (rt *revisionThrottler) try(ctx context.Context, function func(string) error) error {
@@ -280,28 +306,21 @@ func (rt *revisionThrottler) updateCapacity(backendCount int) {
 	numTrackers := func() int {
 		// We do not have to process the `podTrackers` under lock, since
 		// updateCapacity is guaranteed to be executed by a single goroutine.
-		// But `assignedTrackers` is being read by the serving thread, so the
-		// actual assignment has to be done under lock.
-
-		// We're using cluster IP.
+		// The serving thread only ever sees the published state, which
+		// we swap in atomically once it is fully computed.
 		if rt.clusterIPTracker != nil {
+			// We're using cluster IP.
+			rt.state.Store(&throttlerState{clusterIPTracker: rt.clusterIPTracker})
 			return 0
 		}
 
-		// Sort, so we get more or less stable results.
-		sort.Slice(rt.podTrackers, func(i, j int) bool {
-			return rt.podTrackers[i].dest < rt.podTrackers[j].dest
-		})
 		assigned := rt.podTrackers
 		if rt.containerConcurrency > 0 {
 			rt.resetTrackers()
 			assigned = assignSlice(rt.podTrackers, ai, ac, rt.containerConcurrency)
 		}
 		rt.logger.Debugf("Trackers %d/%d: assignment: %v", ai, ac, assigned)
-		// The actual write out of the assigned trackers has to be under lock.
-		rt.mux.Lock()
-		defer rt.mux.Unlock()
-		rt.assignedTrackers = assigned
+		rt.state.Store(&throttlerState{assignedTrackers: assigned})
 		return len(assigned)
 	}()
 
@@ -328,14 +347,10 @@ func (rt *revisionThrottler) updateThrottlerState(backendCount int, trackers []*
 
 	// Update trackers / clusterIP before capacity. Otherwise we can race updating our breaker when
 	// we increase capacity, causing a request to fall through before a tracker is added, causing an
-	// incorrect LB decision.
-	if func() bool {
-		rt.mux.Lock()
-		defer rt.mux.Unlock()
-		rt.podTrackers = trackers
-		rt.clusterIPTracker = clusterIPDest
-		return clusterIPDest != nil || len(trackers) > 0
-	}() {
+	// incorrect LB decision. updateCapacity publishes the new trackers before touching the breaker.
+	rt.podTrackers = trackers
+	rt.clusterIPTracker = clusterIPDest
+	if clusterIPDest != nil || len(trackers) > 0 {
 		// If we have an address to target, then pass through an accurate
 		// accounting of the number of backends.
 		rt.updateCapacity(backendCount)
@@ -385,7 +400,9 @@ func assignSlice(trackers []*podTracker, selfIndex, numActivators, cc int) []*po
 	bi, ei, remnants := pickIndices(lt, selfIndex, numActivators)
 	x := append(trackers[:0:0], trackers[bi:ei]...)
 	if remnants > 0 {
-		tail := trackers[len(trackers)-remnants:]
+		// Copy the tail, since `trackers` might be concurrently read on
+		// the request path and must not be reordered in place.
+		tail := append(trackers[:0:0], trackers[len(trackers)-remnants:]...)
 		// We shuffle the tail, to ensure that pods in the tail get better
 		// load distribution, since we sort the pods above, this puts more requests
 		// on the very first tail pod, than on the others.
@@ -439,6 +456,10 @@ func (rt *revisionThrottler) handleUpdate(update revisionDestsUpdate) {
 			}
 			trackers = append(trackers, tracker)
 		}
+		// Sort, so we get more or less stable results.
+		sort.Slice(trackers, func(i, j int) bool {
+			return trackers[i].dest < trackers[j].dest
+		})
 
 		rt.updateThrottlerState(len(update.Dests), trackers, nil /*clusterIP*/)
 		return
@@ -447,23 +468,99 @@ func (rt *revisionThrottler) handleUpdate(update revisionDestsUpdate) {
 	rt.updateThrottlerState(len(update.Dests), nil /*trackers*/, newPodTracker(update.ClusterIPDest, nil))
 }
 
+// revisionThrottlerShard is a single shard of the revisionThrottlerMap.
+type revisionThrottlerShard struct {
+	mux        sync.RWMutex
+	throttlers map[types.NamespacedName]*revisionThrottler
+}
+
+// revisionThrottlerMap is a map of revisionThrottlers, sharded by revision
+// so that lookups for different revisions rarely contend on the same lock.
+type revisionThrottlerMap struct {
+	shards [numThrottlerShards]revisionThrottlerShard
+}
+
+func newRevisionThrottlerMap() *revisionThrottlerMap {
+	m := &revisionThrottlerMap{}
+	for i := range m.shards {
+		m.shards[i].throttlers = make(map[types.NamespacedName]*revisionThrottler)
+	}
+	return m
+}
+
+// shard returns the shard the given revision belongs to.
+func (m *revisionThrottlerMap) shard(revID types.NamespacedName) *revisionThrottlerShard {
+	// FNV-1a, computed inline to avoid allocations on the request path.
+	const prime32 = 16777619
+	h := uint32(2166136261)
+	for i := 0; i < len(revID.Namespace); i++ {
+		h = (h ^ uint32(revID.Namespace[i])) * prime32
+	}
+	h = (h ^ uint32('/')) * prime32
+	for i := 0; i < len(revID.Name); i++ {
+		h = (h ^ uint32(revID.Name[i])) * prime32
+	}
+	return &m.shards[h%numThrottlerShards]
+}
+
+// get returns the revisionThrottler for the given revision, if it exists.
+func (m *revisionThrottlerMap) get(revID types.NamespacedName) (*revisionThrottler, bool) {
+	s := m.shard(revID)
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	rt, ok := s.throttlers[revID]
+	return rt, ok
+}
+
+// getOrCreate returns the revisionThrottler for the given revision, creating
+// and storing it via `create` if it does not exist yet.
+func (m *revisionThrottlerMap) getOrCreate(revID types.NamespacedName,
+	create func() (*revisionThrottler, error)) (*revisionThrottler, error) {
+	// First, see if we can succeed with just an RLock. This is in the request path so optimizing
+	// for this case is important
+	if rt, ok := m.get(revID); ok {
+		return rt, nil
+	}
+
+	// Redo with a write lock since we failed the first time and may need to create
+	s := m.shard(revID)
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if rt, ok := s.throttlers[revID]; ok {
+		return rt, nil
+	}
+	rt, err := create()
+	if err != nil {
+		return nil, err
+	}
+	s.throttlers[revID] = rt
+	return rt, nil
+}
+
+// delete removes the revisionThrottler of the given revision.
+func (m *revisionThrottlerMap) delete(revID types.NamespacedName) {
+	s := m.shard(revID)
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	delete(s.throttlers, revID)
+}
+
 // Throttler load balances requests to revisions based on capacity. When `Run` is called it listens for
 // updates to revision backends and decides when and when and where to forward a request.
 type Throttler struct {
-	revisionThrottlers      map[types.NamespacedName]*revisionThrottler
-	revisionThrottlersMutex sync.RWMutex
-	revisionLister          servinglisters.RevisionLister
-	serviceLister           corev1listers.ServiceLister
-	ipAddress               string // The IP address of this activator.
-	logger                  *zap.SugaredLogger
-	epsUpdateCh             chan *corev1.Endpoints
+	revisionThrottlers *revisionThrottlerMap
+	revisionLister     servinglisters.RevisionLister
+	serviceLister      corev1listers.ServiceLister
+	ipAddress          string // The IP address of this activator.
+	logger             *zap.SugaredLogger
+	epsUpdateCh        chan *corev1.Endpoints
 }
 
 // NewThrottler creates a new Throttler
 func NewThrottler(ctx context.Context, ipAddr string) *Throttler {
 	revisionInformer := revisioninformer.Get(ctx)
 	t := &Throttler{
-		revisionThrottlers: make(map[types.NamespacedName]*revisionThrottler),
+		revisionThrottlers: newRevisionThrottlerMap(),
 		revisionLister:     revisionInformer.Lister(),
 		serviceLister:      serviceinformer.Get(ctx).Lister(),
 		ipAddress:          ipAddr,
@@ -525,35 +622,30 @@ func (t *Throttler) Try(ctx context.Context, revID types.NamespacedName, functio
 	return rt.try(ctx, function)
 }
 
-func (t *Throttler) getOrCreateRevisionThrottler(revID types.NamespacedName) (*revisionThrottler, error) {
-	// First, see if we can succeed with just an RLock. This is in the request path so optimizing
-	// for this case is important
-	t.revisionThrottlersMutex.RLock()
-	revThrottler, ok := t.revisionThrottlers[revID]
-	t.revisionThrottlersMutex.RUnlock()
-	if ok {
-		return revThrottler, nil
+// ExpectedWait estimates how long a request for the given revision would
+// currently wait for capacity. It is zero for unknown revisions.
+func (t *Throttler) ExpectedWait(revID types.NamespacedName) time.Duration {
+	rt, ok := t.revisionThrottlers.get(revID)
+	if !ok {
+		return 0
 	}
+	return rt.breaker.ExpectedWait()
+}
 
-	// Redo with a write lock since we failed the first time and may need to create
-	t.revisionThrottlersMutex.Lock()
-	defer t.revisionThrottlersMutex.Unlock()
-	revThrottler, ok = t.revisionThrottlers[revID]
-	if !ok {
+func (t *Throttler) getOrCreateRevisionThrottler(revID types.NamespacedName) (*revisionThrottler, error) {
+	return t.revisionThrottlers.getOrCreate(revID, func() (*revisionThrottler, error) {
 		rev, err := t.revisionLister.Revisions(revID.Namespace).Get(revID.Name)
 		if err != nil {
 			return nil, err
 		}
-		revThrottler = newRevisionThrottler(
+		return newRevisionThrottler(
 			revID,
 			int(rev.Spec.GetContainerConcurrency()),
 			pkgnet.ServicePortName(rev.GetProtocol()),
 			queue.BreakerParams{QueueDepth: breakerQueueDepth, MaxConcurrency: revisionMaxConcurrency},
 			t.logger,
-		)
-		t.revisionThrottlers[revID] = revThrottler
-	}
-	return revThrottler, nil
+		), nil
+	})
 }
 
 // revisionUpdated is used to ensure we have a backlog set up for a revision as soon as it is created
@@ -578,9 +670,7 @@ func (t *Throttler) revisionDeleted(obj interface{}) {
 
 	t.logger.Debugw("Revision delete", zap.String(logkey.Key, revID.String()))
 
-	t.revisionThrottlersMutex.Lock()
-	defer t.revisionThrottlersMutex.Unlock()
-	delete(t.revisionThrottlers, revID)
+	t.revisionThrottlers.delete(revID)
 }
 
 func (t *Throttler) handleUpdate(update revisionDestsUpdate) {
@@ -771,3 +861,7 @@ func (ib *infiniteBreaker) Maybe(ctx context.Context, thunk func()) error {
 }
 
 func (ib *infiniteBreaker) Reserve(context.Context) (func(), bool) { return noop, true }
+
+// ExpectedWait returns zero, since requests are either let through at once
+// or held until the revision scales up from zero, which cannot be estimated.
+func (ib *infiniteBreaker) ExpectedWait() time.Duration { return 0 }
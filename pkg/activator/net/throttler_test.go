@@ -19,7 +19,9 @@ package net
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
+	"math/rand"
 	"strconv"
 	"sync"
 	"testing"
@@ -27,6 +29,7 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -188,9 +191,9 @@ func TestThrottlerUpdateCapacity(t *testing.T) {
 	if got, want := rt.breaker.Capacity(), 1; got != want {
 		t.Errorf("Capacity = %d, want: %d", got, want)
 	}
-	if got, want := len(rt.assignedTrackers), len(rt.podTrackers); got != want {
+	if got, want := len(rt.loadState().assignedTrackers), len(rt.podTrackers); got != want {
 		t.Errorf("Assigned tracker count = %d, want: %d, diff:\n%s", got, want,
-			cmp.Diff(rt.assignedTrackers, rt.podTrackers))
+			cmp.Diff(rt.loadState().assignedTrackers, rt.podTrackers))
 	}
 }
 
@@ -477,15 +480,13 @@ func TestThrottlerSuccesses(t *testing.T) {
 				wantCapacity = dests * int(*cc)
 			}
 			if err := wait.PollImmediate(10*time.Millisecond, 3*time.Second, func() (bool, error) {
-				rt.mux.RLock()
-				defer rt.mux.RUnlock()
 				if *cc != 0 {
 					return rt.activatorIndex.Load() != -1 && rt.breaker.Capacity() == wantCapacity &&
-						sortedTrackers(rt.assignedTrackers), nil
+						sortedTrackers(rt.loadState().assignedTrackers), nil
 				}
 				// If CC=0 then verify number of backends, rather the capacity of breaker.
-				return rt.activatorIndex.Load() != -1 && dests == len(rt.assignedTrackers) &&
-					sortedTrackers(rt.assignedTrackers), nil
+				return rt.activatorIndex.Load() != -1 && dests == len(rt.loadState().assignedTrackers) &&
+					sortedTrackers(rt.loadState().assignedTrackers), nil
 			}); err != nil {
 				t.Fatal("Timed out waiting for the capacity to be updated")
 			}
@@ -511,10 +512,8 @@ func TestThrottlerSuccesses(t *testing.T) {
 
 			if got, want := gotDests.List(), tc.wantDests.List(); !cmp.Equal(want, got) {
 				t.Errorf("Dests = %v, want: %v, diff: %s", got, want, cmp.Diff(want, got))
-				rt.mux.RLock()
-				defer rt.mux.RUnlock()
 				t.Log("podTrackers:\n", spew.Sdump(rt.podTrackers))
-				t.Log("assignedTrackers:\n", spew.Sdump(rt.assignedTrackers))
+				t.Log("assignedTrackers:\n", spew.Sdump(rt.loadState().assignedTrackers))
 			}
 		})
 	}
@@ -541,7 +540,7 @@ func TestPodAssignmentFinite(t *testing.T) {
 	rt := newRevisionThrottler(revName, 42 /*cc*/, pkgnet.ServicePortNameHTTP1, testBreakerParams, logger)
 	rt.numActivators.Store(4)
 	rt.activatorIndex.Store(0)
-	throttler.revisionThrottlers[revName] = rt
+	throttler.revisionThrottlers.getOrCreate(revName, func() (*revisionThrottler, error) { return rt, nil })
 
 	update := revisionDestsUpdate{
 		Rev:           revName,
@@ -554,19 +553,19 @@ func TestPodAssignmentFinite(t *testing.T) {
 	if got, want := len(rt.podTrackers), len(update.Dests); got != want {
 		t.Errorf("NumTrackers = %d, want: %d", got, want)
 	}
-	if got, want := trackerDestSet(rt.assignedTrackers), sets.NewString("ip0", "ip4", "ip5"); !got.Equal(want) {
+	if got, want := trackerDestSet(rt.loadState().assignedTrackers), sets.NewString("ip0", "ip4", "ip5"); !got.Equal(want) {
 		t.Errorf("Assigned trackers = %v, want: %v, diff: %s", got, want, cmp.Diff(want, got))
 	}
 	if got, want := rt.breaker.Capacity(), 6*42/4; got != want {
 		t.Errorf("TotalCapacity = %d, want: %d", got, want)
 	}
-	if got, want := rt.assignedTrackers[0].Capacity(), 42; got != want {
+	if got, want := rt.loadState().assignedTrackers[0].Capacity(), 42; got != want {
 		t.Errorf("Exclusive tracker capacity: %d, want: %d", got, want)
 	}
-	if got, want := rt.assignedTrackers[1].Capacity(), int(math.Ceil(42./4.)); got != want {
+	if got, want := rt.loadState().assignedTrackers[1].Capacity(), int(math.Ceil(42./4.)); got != want {
 		t.Errorf("Shared tracker capacity: %d, want: %d", got, want)
 	}
-	if got, want := rt.assignedTrackers[2].Capacity(), int(math.Ceil(42./4.)); got != want {
+	if got, want := rt.loadState().assignedTrackers[2].Capacity(), int(math.Ceil(42./4.)); got != want {
 		t.Errorf("Shared tracker capacity: %d, want: %d", got, want)
 	}
 
@@ -576,7 +575,7 @@ func TestPodAssignmentFinite(t *testing.T) {
 	if got, want := len(rt.podTrackers), 0; got != want {
 		t.Errorf("NumTrackers = %d, want: %d", got, want)
 	}
-	if got, want := len(rt.assignedTrackers), 0; got != want {
+	if got, want := len(rt.loadState().assignedTrackers), 0; got != want {
 		t.Errorf("NumAssignedTrackers = %d, want: %d", got, want)
 	}
 	if got, want := rt.breaker.Capacity(), 0; got != want {
@@ -593,7 +592,7 @@ func TestPodAssignmentInfinite(t *testing.T) {
 
 	throttler := newTestThrottler(ctx)
 	rt := newRevisionThrottler(revName, 0 /*cc*/, pkgnet.ServicePortNameHTTP1, testBreakerParams, logger)
-	throttler.revisionThrottlers[revName] = rt
+	throttler.revisionThrottlers.getOrCreate(revName, func() (*revisionThrottler, error) { return rt, nil })
 
 	update := revisionDestsUpdate{
 		Rev:           revName,
@@ -606,13 +605,13 @@ func TestPodAssignmentInfinite(t *testing.T) {
 	if got, want := len(rt.podTrackers), 3; got != want {
 		t.Errorf("NumTrackers = %d, want: %d", got, want)
 	}
-	if got, want := len(rt.assignedTrackers), 3; got != want {
+	if got, want := len(rt.loadState().assignedTrackers), 3; got != want {
 		t.Errorf("NumAssigned trackers = %d, want: %d", got, want)
 	}
 	if got, want := rt.breaker.Capacity(), 1; got != want {
 		t.Errorf("TotalCapacity = %d, want: %d", got, want)
 	}
-	if got, want := rt.assignedTrackers[0].Capacity(), 1; got != want {
+	if got, want := rt.loadState().assignedTrackers[0].Capacity(), 1; got != want {
 		t.Errorf("Exclusive tracker capacity: %d, want: %d", got, want)
 	}
 
@@ -622,7 +621,7 @@ func TestPodAssignmentInfinite(t *testing.T) {
 	if got, want := len(rt.podTrackers), 0; got != want {
 		t.Errorf("NumTrackers = %d, want: %d", got, want)
 	}
-	if got, want := len(rt.assignedTrackers), 0; got != want {
+	if got, want := len(rt.loadState().assignedTrackers), 0; got != want {
 		t.Errorf("NumAssignedTrackers = %d, want: %d", got, want)
 	}
 	if got, want := rt.breaker.Capacity(), 0; got != want {
@@ -706,7 +705,7 @@ func TestActivatorsIndexUpdate(t *testing.T) {
 	if got, want := rt.activatorIndex.Load(), int32(1); got != want {
 		t.Fatalf("activatorIndex = %d, want %d", got, want)
 	}
-	if got, want := len(rt.assignedTrackers), 2; got != want {
+	if got, want := len(rt.loadState().assignedTrackers), 2; got != want {
 		t.Fatalf("len(assignedTrackers) = %d, want %d", got, want)
 	}
 
@@ -849,6 +848,135 @@ func (t *Throttler) try(ctx context.Context, requests int, try func(string) erro
 	return resultChan
 }
 
+func BenchmarkThrottlerTry(b *testing.B) {
+	logger := zap.NewNop().Sugar()
+	dests := sets.NewString()
+	for i := 0; i < 10; i++ {
+		dests.Insert(fmt.Sprintf("128.0.0.%d:1234", i))
+	}
+	for _, cc := range []int{0, 10} {
+		for _, numRevs := range []int{1, 10, 100, 1000} {
+			b.Run(fmt.Sprintf("cc-%d-%d-revisions-parallel", cc, numRevs), func(b *testing.B) {
+				throttler := &Throttler{
+					revisionThrottlers: newRevisionThrottlerMap(),
+					logger:             logger,
+				}
+				revIDs := make([]types.NamespacedName, numRevs)
+				for i := range revIDs {
+					revID := types.NamespacedName{Namespace: testNamespace, Name: testRevision + strconv.Itoa(i)}
+					rt := newRevisionThrottler(revID, cc, pkgnet.ServicePortNameHTTP1,
+						queue.BreakerParams{QueueDepth: breakerQueueDepth, MaxConcurrency: revisionMaxConcurrency}, logger)
+					throttler.revisionThrottlers.getOrCreate(revID, func() (*revisionThrottler, error) { return rt, nil })
+					rt.handleUpdate(revisionDestsUpdate{Rev: revID, Dests: dests})
+					revIDs[i] = revID
+				}
+
+				ctx := context.Background()
+				noopFn := func(string) error { return nil }
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					// Start at a random revision, so goroutines don't move in lockstep.
+					i := rand.Intn(numRevs)
+					for pb.Next() {
+						if err := throttler.Try(ctx, revIDs[i%numRevs], noopFn); err != nil {
+							b.Error("Try() =", err)
+						}
+						i++
+					}
+				})
+			})
+		}
+	}
+}
+
+func BenchmarkRevisionThrottlerAcquireDest(b *testing.B) {
+	logger := zap.NewNop().Sugar()
+	revID := types.NamespacedName{Namespace: testNamespace, Name: testRevision}
+	for _, cc := range []int{0, 1, 10} {
+		for _, numPods := range []int{1, 10, 100} {
+			b.Run(fmt.Sprintf("cc-%d-%d-pods-parallel", cc, numPods), func(b *testing.B) {
+				dests := sets.NewString()
+				for i := 0; i < numPods; i++ {
+					dests.Insert(fmt.Sprintf("128.0.%d.%d:1234", i/256, i%256))
+				}
+				rt := newRevisionThrottler(revID, cc, pkgnet.ServicePortNameHTTP1,
+					queue.BreakerParams{QueueDepth: breakerQueueDepth, MaxConcurrency: revisionMaxConcurrency}, logger)
+				rt.handleUpdate(revisionDestsUpdate{Rev: revID, Dests: dests})
+
+				ctx := context.Background()
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						cb, _ := rt.acquireDest(ctx)
+						cb()
+					}
+				})
+			})
+		}
+	}
+}
+
+func TestRevisionThrottlerMap(t *testing.T) {
+	m := newRevisionThrottlerMap()
+	logger := TestLogger(t)
+
+	revIDs := make([]types.NamespacedName, 100)
+	for i := range revIDs {
+		revIDs[i] = types.NamespacedName{Namespace: testNamespace, Name: testRevision + strconv.Itoa(i)}
+		rt := newRevisionThrottler(revIDs[i], 0, pkgnet.ServicePortNameHTTP1, testBreakerParams, logger)
+		got, err := m.getOrCreate(revIDs[i], func() (*revisionThrottler, error) { return rt, nil })
+		if err != nil {
+			t.Fatal("getOrCreate() =", err)
+		}
+		if got != rt {
+			t.Errorf("getOrCreate() = %p, want: %p", got, rt)
+		}
+	}
+
+	// Existing throttlers must be returned without invoking create.
+	for _, revID := range revIDs {
+		rt, err := m.getOrCreate(revID, func() (*revisionThrottler, error) {
+			return nil, errors.New("create must not be called")
+		})
+		if err != nil {
+			t.Fatal("getOrCreate() =", err)
+		}
+		if rt.revID != revID {
+			t.Errorf("revID = %v, want: %v", rt.revID, revID)
+		}
+	}
+
+	// Revisions are not all put into the same shard.
+	if got := m.shard(revIDs[0]); func() bool {
+		for _, revID := range revIDs[1:] {
+			if m.shard(revID) != got {
+				return false
+			}
+		}
+		return true
+	}() {
+		t.Error("All revisions were put into the same shard")
+	}
+
+	// Creation errors are propagated and nothing is stored.
+	missing := types.NamespacedName{Namespace: testNamespace, Name: "missing"}
+	wantErr := errors.New("not found")
+	if _, err := m.getOrCreate(missing, func() (*revisionThrottler, error) { return nil, wantErr }); !errors.Is(err, wantErr) {
+		t.Errorf("getOrCreate() = %v, want: %v", err, wantErr)
+	}
+	if _, ok := m.get(missing); ok {
+		t.Error("Throttler was stored despite creation error")
+	}
+
+	m.delete(revIDs[0])
+	if _, ok := m.get(revIDs[0]); ok {
+		t.Error("Throttler was not deleted")
+	}
+	if _, ok := m.get(revIDs[1]); !ok {
+		t.Error("Unrelated throttler was deleted")
+	}
+}
+
 func TestInfiniteBreaker(t *testing.T) {
 	b := &infiniteBreaker{
 		broadcast: make(chan struct{}),
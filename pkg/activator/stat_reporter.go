@@ -17,11 +17,50 @@ limitations under the License.
 package activator
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	"github.com/gorilla/websocket"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
 	"go.uber.org/zap"
+	pkgmetrics "knative.dev/pkg/metrics"
 	"knative.dev/serving/pkg/autoscaler/metrics"
 )
 
+// maxBufferedStats bounds the number of stat messages kept for replay while
+// the autoscaler is unreachable. The oldest messages are dropped first.
+const maxBufferedStats = 10000
+
+var (
+	statReportingDegradedM = stats.Int64(
+		"stat_reporting_degraded",
+		"Whether stats currently cannot be sent to the autoscaler",
+		stats.UnitDimensionless)
+	droppedStatsM = stats.Int64(
+		"dropped_stat_count",
+		"The number of stat messages dropped while the autoscaler was unreachable",
+		stats.UnitDimensionless)
+)
+
+func init() {
+	if err := pkgmetrics.RegisterResourceView(
+		&view.View{
+			Description: "Whether stats currently cannot be sent to the autoscaler",
+			Measure:     statReportingDegradedM,
+			Aggregation: view.LastValue(),
+		},
+		&view.View{
+			Description: "The number of stat messages dropped while the autoscaler was unreachable",
+			Measure:     droppedStatsM,
+			Aggregation: view.Sum(),
+		},
+	); err != nil {
+		panic(err)
+	}
+}
+
 // RawSender sends raw byte array messages with a message type
 // (implemented by gorilla/websocket.Socket).
 type RawSender interface {
@@ -31,19 +70,83 @@ type RawSender interface {
 // ReportStats sends any messages received on the source channel to the sink.
 // The messages are sent on a goroutine to avoid blocking, which means that
 // messages may arrive out of order.
+// Messages that cannot be sent are buffered, up to maxBufferedStats, and
+// replayed once sending succeeds again.
 func ReportStats(logger *zap.SugaredLogger, sink RawSender, source <-chan []metrics.StatMessage) {
+	buffer := &statBuffer{max: maxBufferedStats}
 	for sms := range source {
 		go func(sms []metrics.StatMessage) {
-			wsms := metrics.ToWireStatMessages(sms)
-			b, err := wsms.Marshal()
-			if err != nil {
-				logger.Errorw("Error while marshaling stats", zap.Error(err))
+			if err := sendStats(logger, sink, sms); err != nil {
+				logger.Errorw("Error while sending stats, buffering for replay", zap.Error(err))
+				bufferStats(buffer, sms)
+				pkgmetrics.Record(context.Background(), statReportingDegradedM.M(1))
 				return
 			}
+			pkgmetrics.Record(context.Background(), statReportingDegradedM.M(0))
 
-			if err := sink.SendRaw(websocket.BinaryMessage, b); err != nil {
-				logger.Errorw("Error while sending stats", zap.Error(err))
+			if replay := buffer.drain(); len(replay) > 0 {
+				logger.Infof("Replaying %d buffered stats", len(replay))
+				if err := sendStats(logger, sink, replay); err != nil {
+					logger.Errorw("Error while replaying stats", zap.Error(err))
+					bufferStats(buffer, replay)
+				}
 			}
 		}(sms)
 	}
 }
+
+// sendStats sends the messages to the sink. Only errors from the sink are
+// returned, since marshalling errors would not go away on replay.
+func sendStats(logger *zap.SugaredLogger, sink RawSender, sms []metrics.StatMessage) error {
+	wsms := metrics.ToWireStatMessages(sms)
+	b, err := wsms.Marshal()
+	if err != nil {
+		logger.Errorw("Error while marshaling stats", zap.Error(err))
+		return nil
+	}
+	return sink.SendRaw(websocket.BinaryMessage, b)
+}
+
+// bufferStats stamps the messages with the current time, if they are not
+// stamped yet, so that the autoscaler attributes them correctly on replay.
+func bufferStats(buffer *statBuffer, sms []metrics.StatMessage) {
+	now := time.Now().Unix()
+	for i := range sms {
+		if sms[i].Stat.Timestamp == 0 {
+			sms[i].Stat.Timestamp = now
+		}
+	}
+	if dropped := buffer.add(sms); dropped > 0 {
+		pkgmetrics.Record(context.Background(), droppedStatsM.M(int64(dropped)))
+	}
+}
+
+// statBuffer is a bounded buffer of stat messages awaiting replay.
+type statBuffer struct {
+	mux   sync.Mutex
+	max   int
+	stats []metrics.StatMessage
+}
+
+// add appends the messages to the buffer, dropping the oldest messages if it
+// overflows, and returns the number of messages dropped.
+func (b *statBuffer) add(sms []metrics.StatMessage) int {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.stats = append(b.stats, sms...)
+	dropped := len(b.stats) - b.max
+	if dropped <= 0 {
+		return 0
+	}
+	b.stats = append([]metrics.StatMessage(nil), b.stats[dropped:]...)
+	return dropped
+}
+
+// drain empties the buffer and returns its contents.
+func (b *statBuffer) drain() []metrics.StatMessage {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	sms := b.stats
+	b.stats = nil
+	return sms
+}
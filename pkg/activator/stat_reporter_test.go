@@ -17,6 +17,7 @@ limitations under the License.
 package activator
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -24,7 +25,10 @@ import (
 	gorillawebsocket "github.com/gorilla/websocket"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/metrics/metricstest"
+	_ "knative.dev/pkg/metrics/testing"
 	"knative.dev/serving/pkg/autoscaler/metrics"
 )
 
@@ -90,6 +94,82 @@ func TestReportStats(t *testing.T) {
 	}
 }
 
+func TestReportStatsReplaysAfterFailure(t *testing.T) {
+	logger := logtesting.TestLogger(t)
+	ch := make(chan []metrics.StatMessage)
+
+	failing := make(chan struct{})
+	results := make(chan []byte, 2)
+	sink := sendRawFunc(func(msgType int, msg []byte) error {
+		select {
+		case <-failing:
+			results <- msg
+			return nil
+		default:
+			return errors.New("autoscaler unreachable")
+		}
+	})
+
+	defer close(ch)
+	go ReportStats(logger, sink, ch)
+
+	ch <- []metrics.StatMessage{{Key: types.NamespacedName{Name: "buffered"}}}
+	// Wait for the first send to fail and the stat to be buffered.
+	if err := wait.PollImmediate(10*time.Millisecond, 2*time.Second, func() (bool, error) {
+		m := metricstest.GetMetric(statReportingDegradedM.Name())
+		return len(m) == 1 && *m[0].Values[0].Int64 == 1, nil
+	}); err != nil {
+		t.Fatal("Stats were never reported as degraded:", err)
+	}
+	close(failing)
+	ch <- []metrics.StatMessage{{Key: types.NamespacedName{Name: "sent"}}}
+
+	var got []metrics.StatMessage
+	for len(got) < 2 {
+		select {
+		case b := <-results:
+			var wsms metrics.WireStatMessages
+			if err := wsms.Unmarshal(b); err != nil {
+				t.Fatal("Unmarshal stats =", err)
+			}
+			for _, m := range wsms.Messages {
+				got = append(got, m.ToStatMessage())
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("Did not receive replayed stats after 2 seconds")
+		}
+	}
+	for _, sm := range got {
+		if sm.Key.Name == "buffered" && sm.Stat.Timestamp == 0 {
+			t.Error("Expected buffered stat to be timestamped")
+		}
+	}
+}
+
+func TestStatBuffer(t *testing.T) {
+	b := &statBuffer{max: 3}
+	sms := func(names ...string) []metrics.StatMessage {
+		ret := make([]metrics.StatMessage, 0, len(names))
+		for _, n := range names {
+			ret = append(ret, metrics.StatMessage{Key: types.NamespacedName{Name: n}})
+		}
+		return ret
+	}
+
+	if dropped := b.add(sms("a", "b")); dropped != 0 {
+		t.Errorf("add() dropped %d, want: 0", dropped)
+	}
+	if dropped := b.add(sms("c", "d")); dropped != 1 {
+		t.Errorf("add() dropped %d, want: 1", dropped)
+	}
+	if got, want := b.drain(), sms("b", "c", "d"); !cmp.Equal(got, want) {
+		t.Error("drain() (-want, +got):", cmp.Diff(want, got))
+	}
+	if got := b.drain(); len(got) != 0 {
+		t.Errorf("drain() = %v, want empty", got)
+	}
+}
+
 type sendRawFunc func(msgType int, msg []byte) error
 
 func (fn sendRawFunc) SendRaw(msgType int, msg []byte) error {
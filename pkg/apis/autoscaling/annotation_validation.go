@@ -58,6 +58,7 @@ func ValidateAnnotations(ctx context.Context, config *autoscalerconfig.Config, a
 		Also(validateLastPodRetention(anns)).
 		Also(validateScaleDownDelay(anns)).
 		Also(validateMetric(anns)).
+		Also(validateConcurrencyPercentile(anns)).
 		Also(validateInitialScale(config, anns))
 }
 
@@ -110,6 +111,26 @@ func validateFloats(annotations map[string]string) (errs *apis.FieldError) {
 	return errs
 }
 
+func validateConcurrencyPercentile(annotations map[string]string) *apis.FieldError {
+	v, ok := annotations[ConcurrencyPercentileAnnotationKey]
+	if !ok {
+		return nil
+	}
+	if annotations[ClassAnnotationKey] == HPA {
+		return apis.ErrInvalidKeyName(ConcurrencyPercentileAnnotationKey, apis.CurrentField, HPA)
+	}
+	if m, ok := annotations[MetricAnnotationKey]; ok && m != Concurrency {
+		return apis.ErrInvalidKeyName(ConcurrencyPercentileAnnotationKey, apis.CurrentField,
+			fmt.Sprintf("%s %s", MetricAnnotationKey, m))
+	}
+	if fv, err := strconv.ParseFloat(v, 64); err != nil {
+		return apis.ErrInvalidValue(v, ConcurrencyPercentileAnnotationKey)
+	} else if fv <= 0 || fv >= 100 {
+		return apis.ErrGeneric(fmt.Sprintf("concurrency percentile %s should be between 0 and 100 exclusive", v), ConcurrencyPercentileAnnotationKey)
+	}
+	return nil
+}
+
 func validateScaleDownDelay(annotations map[string]string) *apis.FieldError {
 	var errs *apis.FieldError
 	if w, ok := annotations[ScaleDownDelayAnnotationKey]; ok {
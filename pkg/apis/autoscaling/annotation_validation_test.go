@@ -246,6 +246,25 @@ func TestValidateAnnotations(t *testing.T) {
 		name:        "annotation /window is valid for other than HPA and KPA class",
 		annotations: map[string]string{WindowAnnotationKey: "7s", ClassAnnotationKey: "test"},
 		expectErr:   "",
+	}, {
+		name:        "concurrency percentile",
+		annotations: map[string]string{ConcurrencyPercentileAnnotationKey: "95", MetricAnnotationKey: Concurrency},
+	}, {
+		name:        "concurrency percentile invalid",
+		annotations: map[string]string{ConcurrencyPercentileAnnotationKey: "p95"},
+		expectErr:   "invalid value: p95: " + ConcurrencyPercentileAnnotationKey,
+	}, {
+		name:        "concurrency percentile out of range",
+		annotations: map[string]string{ConcurrencyPercentileAnnotationKey: "100"},
+		expectErr:   "concurrency percentile 100 should be between 0 and 100 exclusive: " + ConcurrencyPercentileAnnotationKey,
+	}, {
+		name:        "concurrency percentile with rps metric",
+		annotations: map[string]string{ConcurrencyPercentileAnnotationKey: "95", MetricAnnotationKey: RPS},
+		expectErr:   fmt.Sprintf("invalid key name %q: \n%s %s", ConcurrencyPercentileAnnotationKey, MetricAnnotationKey, RPS),
+	}, {
+		name:        "concurrency percentile with class HPA",
+		annotations: map[string]string{ConcurrencyPercentileAnnotationKey: "95", ClassAnnotationKey: HPA},
+		expectErr:   fmt.Sprintf("invalid key name %q: \n%s", ConcurrencyPercentileAnnotationKey, HPA),
 	}, {
 		name:        "value too short and invalid class for /window annotation",
 		annotations: map[string]string{WindowAnnotationKey: "1s", ClassAnnotationKey: HPA, MetricAnnotationKey: CPU},
@@ -37,6 +37,41 @@ const (
 	// HPA is Kubernetes Horizontal Pod Autoscaler
 	HPA = "hpa.autoscaling.knative.dev"
 
+	// StandaloneAnnotationKey is the annotation opting a PodAutoscaler that
+	// was not created for a Revision into scaling a user-managed PodScalable
+	// directly. For example,
+	//   autoscaling.knative.dev/standalone: "true"
+	StandaloneAnnotationKey = GroupName + "/standalone"
+
+	// PodAutoscalerLabelKey is the label key the pods of a user-managed
+	// PodScalable must carry, set to the name of the standalone
+	// PodAutoscaler scaling them, so that the autoscaler can find them.
+	//   autoscaling.knative.dev/podAutoscaler: my-pa
+	PodAutoscalerLabelKey = GroupName + "/podAutoscaler"
+
+	// MetricSourceAnnotationKey is the annotation to specify where a standalone
+	// PodAutoscaler gets the metrics of the pods it scales from. For example,
+	//   autoscaling.knative.dev/metricSource: rps
+	MetricSourceAnnotationKey = GroupName + "/metricSource"
+	// MetricSourceQueueProxy makes the PodAutoscaler inject the queue-proxy
+	// sidecar into the pods it scales, and scrape it. This is the default.
+	MetricSourceQueueProxy = "queue-proxy"
+	// MetricSourceRPS makes the PodAutoscaler derive the requests per second
+	// from a request counter the pods it scales export in the Prometheus text
+	// format, see RPSMetricAnnotationKey and RPSMetricPortAnnotationKey.
+	MetricSourceRPS = "rps"
+
+	// RPSMetricAnnotationKey is the annotation to specify the name of the
+	// Prometheus counter of the served requests, for the rps metric source.
+	// The samples of all the counter's series are summed up. For example,
+	//   autoscaling.knative.dev/rpsMetric: http_requests_total
+	RPSMetricAnnotationKey = GroupName + "/rpsMetric"
+	// RPSMetricPortAnnotationKey is the annotation to specify the port on
+	// which the pods serve their Prometheus metrics at /metrics, for the rps
+	// metric source. For example,
+	//   autoscaling.knative.dev/rpsMetricPort: "8080"
+	RPSMetricPortAnnotationKey = GroupName + "/rpsMetricPort"
+
 	// MinScaleAnnotationKey is the annotation to specify the minimum number of Pods
 	// the PodAutoscaler should provision. For example,
 	//   autoscaling.knative.dev/minScale: "1"
@@ -172,4 +207,15 @@ const (
 	// PanicThresholdPercentageMax is the counterpart to the PanicThresholdPercentageMin
 	// but bounding from above.
 	PanicThresholdPercentageMax = 1000.0
+
+	// ConcurrencyPercentileAnnotationKey is the annotation to specify the
+	// percentile of the in-flight requests observed within each reporting
+	// period that the autoscaler should scale on, instead of their average.
+	// Higher percentiles make the autoscaler react to short spikes that the
+	// average would smooth away. For example,
+	//   autoscaling.knative.dev/metric: concurrency
+	//   autoscaling.knative.dev/concurrencyPercentile: "95"
+	// Only the kpa.autoscaling.knative.dev class autoscaler with the
+	// concurrency metric supports the concurrencyPercentile annotation.
+	ConcurrencyPercentileAnnotationKey = GroupName + "/concurrencyPercentile"
 )
@@ -17,8 +17,11 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"strconv"
+
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/pkg/apis"
+	"knative.dev/serving/pkg/apis/autoscaling"
 )
 
 const (
@@ -74,3 +77,13 @@ func (m *Metric) IsReady() bool {
 	return ms.ObservedGeneration == m.Generation &&
 		ms.GetCondition(MetricConditionReady).IsTrue()
 }
+
+// ConcurrencyPercentile returns the concurrency percentile annotation value,
+// or false if not present or invalid.
+func (m *Metric) ConcurrencyPercentile() (float64, bool) {
+	if s, ok := m.Annotations[autoscaling.ConcurrencyPercentileAnnotationKey]; ok {
+		f, err := strconv.ParseFloat(s, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
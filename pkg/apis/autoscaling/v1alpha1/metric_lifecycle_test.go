@@ -189,3 +189,33 @@ func TestMetricGetGroupVersionKind(t *testing.T) {
 		t.Errorf("got: %v, want: %v", got, want)
 	}
 }
+
+func TestMetricConcurrencyPercentile(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		wantValue   float64
+		wantOK      bool
+	}{{
+		name: "not present",
+	}, {
+		name:        "present",
+		annotations: map[string]string{autoscaling.ConcurrencyPercentileAnnotationKey: "95"},
+		wantValue:   95,
+		wantOK:      true,
+	}, {
+		name:        "invalid",
+		annotations: map[string]string{autoscaling.ConcurrencyPercentileAnnotationKey: "p95"},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &Metric{}
+			m.Annotations = tc.annotations
+			got, ok := m.ConcurrencyPercentile()
+			if got != tc.wantValue || ok != tc.wantOK {
+				t.Errorf("ConcurrencyPercentile() = (%v, %v), want: (%v, %v)", got, ok, tc.wantValue, tc.wantOK)
+			}
+		})
+	}
+}
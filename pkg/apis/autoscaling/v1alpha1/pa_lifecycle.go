@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -162,6 +163,22 @@ func (pa *PodAutoscaler) InitialScale() (int32, bool) {
 	return pa.annotationInt32(autoscaling.InitialScaleAnnotationKey)
 }
 
+// IsStandalone returns true if the PA opted into being attached directly to a
+// user-managed PodScalable, rather than being created for a Revision.
+func (pa *PodAutoscaler) IsStandalone() bool {
+	// The value is validated in the webhook.
+	return strings.EqualFold(pa.Annotations[autoscaling.StandaloneAnnotationKey], "true")
+}
+
+// MetricSource returns where the standalone PA gets the metrics of the pods
+// it scales from.
+func (pa *PodAutoscaler) MetricSource() string {
+	if s, ok := pa.Annotations[autoscaling.MetricSourceAnnotationKey]; ok {
+		return s
+	}
+	return autoscaling.MetricSourceQueueProxy
+}
+
 // IsReady returns true if the Status condition PodAutoscalerConditionReady
 // is true and the latest spec has been observed.
 func (pa *PodAutoscaler) IsReady() bool {
@@ -198,6 +215,12 @@ func (pas *PodAutoscalerStatus) MarkScaleTargetInitialized() {
 	podCondSet.Manage(pas).MarkTrue(PodAutoscalerConditionScaleTargetInitialized)
 }
 
+// MarkScaleTargetNotPodScalable marks the PA's PodAutoscalerConditionScaleTargetInitialized
+// condition false, since the scale target cannot be scaled by this PA.
+func (pas *PodAutoscalerStatus) MarkScaleTargetNotPodScalable(mes string) {
+	podCondSet.Manage(pas).MarkFalse(PodAutoscalerConditionScaleTargetInitialized, "NotPodScalable", mes)
+}
+
 // MarkSKSReady marks the PA condition denoting that SKS is ready.
 func (pas *PodAutoscalerStatus) MarkSKSReady() {
 	podCondSet.Manage(pas).MarkTrue(PodAutoscalerConditionSKSReady)
@@ -208,6 +231,21 @@ func (pas *PodAutoscalerStatus) MarkSKSNotReady(mes string) {
 	podCondSet.Manage(pas).MarkUnknown(PodAutoscalerConditionSKSReady, "NotReady", mes)
 }
 
+// MarkLoadBalanced marks the PA condition denoting that the load is spread
+// evenly across the pods.
+func (pas *PodAutoscalerStatus) MarkLoadBalanced() {
+	podCondSet.Manage(pas).MarkTrue(PodAutoscalerConditionLoadBalanced)
+}
+
+// MarkLoadImbalanced marks the PA condition denoting that the given pods carry
+// a disproportionate share of the load. index is the ratio of the busiest
+// pod's concurrency to the average per-pod concurrency.
+func (pas *PodAutoscalerStatus) MarkLoadImbalanced(index float64, hotPods []string) {
+	podCondSet.Manage(pas).MarkFalse(PodAutoscalerConditionLoadBalanced, "LoadImbalanced",
+		"The busiest pod carries %.2fx the average per-pod concurrency; hot pods: %s.",
+		index, strings.Join(hotPods, ", "))
+}
+
 // GetCondition gets the condition `t`.
 func (pas *PodAutoscalerStatus) GetCondition(t apis.ConditionType) *apis.Condition {
 	return podCondSet.Manage(pas).GetCondition(t)
@@ -1187,3 +1187,56 @@ func TestIsScaleTargetInitialized(t *testing.T) {
 		t.Errorf("after marking initially active: got: %v, want: %v", got, want)
 	}
 }
+
+func TestMarkScaleTargetNotPodScalable(t *testing.T) {
+	p := &PodAutoscalerStatus{}
+	p.MarkScaleTargetNotPodScalable("no selector")
+	apistest.CheckConditionFailed(p, PodAutoscalerConditionScaleTargetInitialized, t)
+
+	if got, want := p.GetCondition(PodAutoscalerConditionScaleTargetInitialized).Reason, "NotPodScalable"; got != want {
+		t.Errorf("Reason = %q, want: %q", got, want)
+	}
+}
+
+func TestMarkLoadImbalanced(t *testing.T) {
+	p := &PodAutoscalerStatus{}
+	p.InitializeConditions()
+	p.MarkActive()
+	p.MarkScaleTargetInitialized()
+	p.MarkSKSReady()
+
+	p.MarkLoadImbalanced(2.5, []string{"pod-a", "pod-b"})
+	apistest.CheckConditionFailed(p, PodAutoscalerConditionLoadBalanced, t)
+	cond := p.GetCondition(PodAutoscalerConditionLoadBalanced)
+	if got, want := cond.Reason, "LoadImbalanced"; got != want {
+		t.Errorf("Reason = %q, want: %q", got, want)
+	}
+	if got, want := cond.Message, "The busiest pod carries 2.50x the average per-pod concurrency; hot pods: pod-a, pod-b."; got != want {
+		t.Errorf("Message = %q, want: %q", got, want)
+	}
+	if got, want := cond.Severity, apis.ConditionSeverityInfo; got != want {
+		t.Errorf("Severity = %q, want: %q", got, want)
+	}
+	// The imbalance is informational and must not affect readiness.
+	if !p.GetCondition(PodAutoscalerConditionReady).IsTrue() {
+		t.Error("Ready condition is not true for an imbalanced PA")
+	}
+
+	p.MarkLoadBalanced()
+	apistest.CheckConditionSucceeded(p, PodAutoscalerConditionLoadBalanced, t)
+}
+
+func TestIsStandalone(t *testing.T) {
+	p := &PodAutoscaler{}
+	if p.IsStandalone() {
+		t.Error("IsStandalone() = true for a PA without the standalone annotation")
+	}
+	p.Annotations = map[string]string{autoscaling.StandaloneAnnotationKey: "false"}
+	if p.IsStandalone() {
+		t.Error("IsStandalone() = true for a PA opted out")
+	}
+	p.Annotations = map[string]string{autoscaling.StandaloneAnnotationKey: "True"}
+	if !p.IsStandalone() {
+		t.Error("IsStandalone() = false for a PA opted in")
+	}
+}
@@ -108,6 +108,9 @@ const (
 	PodAutoscalerConditionActive apis.ConditionType = "Active"
 	// PodAutoscalerConditionSKSReady is set when SKS is ready.
 	PodAutoscalerConditionSKSReady = "SKSReady"
+	// PodAutoscalerConditionLoadBalanced is set when the load is spread evenly across
+	// the pods of the ScaleTargetRef. It is informational and does not affect readiness.
+	PodAutoscalerConditionLoadBalanced apis.ConditionType = "LoadBalanced"
 )
 
 // PodAutoscalerStatus communicates the observed state of the PodAutoscaler (from the controller).
@@ -18,16 +18,84 @@ package v1alpha1
 
 import (
 	"context"
+	"strconv"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	"knative.dev/pkg/apis"
+	"knative.dev/serving/pkg/apis/autoscaling"
 	"knative.dev/serving/pkg/apis/serving"
 )
 
 // Validate implements apis.Validatable interface.
 func (pa *PodAutoscaler) Validate(ctx context.Context) *apis.FieldError {
 	return serving.ValidateObjectMetadata(ctx, pa.GetObjectMeta()).ViaField("metadata").
-		Also(pa.Spec.Validate(apis.WithinSpec(ctx)).ViaField("spec"))
+		Also(pa.Spec.Validate(apis.WithinSpec(ctx)).ViaField("spec")).
+		Also(pa.validateStandalone()).
+		Also(pa.validateMetricSource())
+}
+
+// validateStandalone validates the opt-in of a PodAutoscaler into scaling a
+// user-managed PodScalable.
+func (pa *PodAutoscaler) validateStandalone() (errs *apis.FieldError) {
+	v, ok := pa.Annotations[autoscaling.StandaloneAnnotationKey]
+	if !ok {
+		return nil
+	}
+	if !strings.EqualFold(v, "true") && !strings.EqualFold(v, "false") {
+		return apis.ErrInvalidValue(v, autoscaling.StandaloneAnnotationKey).ViaField("metadata", "annotations")
+	}
+	if pa.IsStandalone() && pa.Labels[serving.RevisionLabelKey] != "" {
+		errs = errs.Also(apis.ErrGeneric("PodAutoscalers of Revisions cannot be standalone",
+			autoscaling.StandaloneAnnotationKey).ViaField("metadata", "annotations"))
+	}
+	return errs
+}
+
+// validateMetricSource validates how a standalone KPA-class PodAutoscaler
+// gets the metrics of the pods it scales. Those are Deployments, into which
+// the KPA injects the queue-proxy or the PodAutoscaler label.
+func (pa *PodAutoscaler) validateMetricSource() (errs *apis.FieldError) {
+	source, hasSource := pa.Annotations[autoscaling.MetricSourceAnnotationKey]
+	if !pa.IsStandalone() || pa.Class() != autoscaling.KPA {
+		if hasSource {
+			errs = errs.Also(apis.ErrGeneric("only supported by standalone KPA-class PodAutoscalers",
+				autoscaling.MetricSourceAnnotationKey).ViaField("metadata", "annotations"))
+		}
+		return errs
+	}
+
+	if ref := pa.Spec.ScaleTargetRef; ref.APIVersion != "apps/v1" || ref.Kind != "Deployment" {
+		errs = errs.Also(apis.ErrGeneric("standalone KPA-class PodAutoscalers can only scale apps/v1 Deployments",
+			"apiVersion", "kind").ViaField("spec", "scaleTargetRef"))
+	}
+
+	switch source {
+	case "", autoscaling.MetricSourceQueueProxy:
+	case autoscaling.MetricSourceRPS:
+		errs = errs.Also(pa.validateRPSMetricSource())
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(source, autoscaling.MetricSourceAnnotationKey).
+			ViaField("metadata", "annotations"))
+	}
+	return errs
+}
+
+func (pa *PodAutoscaler) validateRPSMetricSource() (errs *apis.FieldError) {
+	if m := pa.Metric(); m != autoscaling.RPS {
+		errs = errs.Also(apis.ErrGeneric("the rps metric source requires the rps metric, got: "+m,
+			autoscaling.MetricAnnotationKey).ViaField("metadata", "annotations"))
+	}
+	if pa.Annotations[autoscaling.RPSMetricAnnotationKey] == "" {
+		errs = errs.Also(apis.ErrMissingField(autoscaling.RPSMetricAnnotationKey).ViaField("metadata", "annotations"))
+	}
+	port, ok := pa.Annotations[autoscaling.RPSMetricPortAnnotationKey]
+	if !ok {
+		errs = errs.Also(apis.ErrMissingField(autoscaling.RPSMetricPortAnnotationKey).ViaField("metadata", "annotations"))
+	} else if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		errs = errs.Also(apis.ErrInvalidValue(port, autoscaling.RPSMetricPortAnnotationKey).ViaField("metadata", "annotations"))
+	}
+	return errs
 }
 
 // Validate validates PodAutoscaler Spec.
@@ -28,6 +28,7 @@ import (
 	"knative.dev/pkg/apis"
 	"knative.dev/serving/pkg/apis/autoscaling"
 	"knative.dev/serving/pkg/apis/config"
+	"knative.dev/serving/pkg/apis/serving"
 )
 
 func TestPodAutoscalerSpecValidation(t *testing.T) {
@@ -208,6 +209,197 @@ func TestPodAutoscalerValidation(t *testing.T) {
 			},
 		},
 		want: apis.ErrInvalidValue("FOO", autoscaling.MinScaleAnnotationKey).ViaField("metadata", "annotations"),
+	}, {
+		name: "standalone KPA scaling a StatefulSet",
+		r: &PodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Annotations: map[string]string{
+					autoscaling.StandaloneAnnotationKey: "true",
+				},
+			},
+			Spec: PodAutoscalerSpec{
+				ScaleTargetRef: corev1.ObjectReference{
+					APIVersion: "apps/v1",
+					Kind:       "StatefulSet",
+					Name:       "bar",
+				},
+				ProtocolType: net.ProtocolHTTP1,
+			},
+		},
+		want: apis.ErrGeneric("standalone KPA-class PodAutoscalers can only scale apps/v1 Deployments",
+			"spec.scaleTargetRef.apiVersion", "spec.scaleTargetRef.kind"),
+	}, {
+		name: "standalone HPA scaling a StatefulSet",
+		r: &PodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Annotations: map[string]string{
+					autoscaling.StandaloneAnnotationKey: "true",
+					autoscaling.ClassAnnotationKey:      autoscaling.HPA,
+				},
+			},
+			Spec: PodAutoscalerSpec{
+				ScaleTargetRef: corev1.ObjectReference{
+					APIVersion: "apps/v1",
+					Kind:       "StatefulSet",
+					Name:       "bar",
+				},
+				ProtocolType: net.ProtocolHTTP1,
+			},
+		},
+		want: nil,
+	}, {
+		name: "rps metric source",
+		r: &PodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Annotations: map[string]string{
+					autoscaling.StandaloneAnnotationKey:    "true",
+					autoscaling.MetricAnnotationKey:        autoscaling.RPS,
+					autoscaling.MetricSourceAnnotationKey:  autoscaling.MetricSourceRPS,
+					autoscaling.RPSMetricAnnotationKey:     "http_requests_total",
+					autoscaling.RPSMetricPortAnnotationKey: "8080",
+				},
+			},
+			Spec: PodAutoscalerSpec{
+				ScaleTargetRef: corev1.ObjectReference{
+					APIVersion: "apps/v1",
+					Kind:       "Deployment",
+					Name:       "bar",
+				},
+				ProtocolType: net.ProtocolHTTP1,
+			},
+		},
+		want: nil,
+	}, {
+		name: "incomplete rps metric source",
+		r: &PodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Annotations: map[string]string{
+					autoscaling.StandaloneAnnotationKey:    "true",
+					autoscaling.MetricSourceAnnotationKey:  autoscaling.MetricSourceRPS,
+					autoscaling.RPSMetricPortAnnotationKey: "80800",
+				},
+			},
+			Spec: PodAutoscalerSpec{
+				ScaleTargetRef: corev1.ObjectReference{
+					APIVersion: "apps/v1",
+					Kind:       "Deployment",
+					Name:       "bar",
+				},
+				ProtocolType: net.ProtocolHTTP1,
+			},
+		},
+		want: apis.ErrGeneric("the rps metric source requires the rps metric, got: concurrency",
+			"metadata.annotations."+autoscaling.MetricAnnotationKey).Also(
+			apis.ErrMissingField("metadata.annotations." + autoscaling.RPSMetricAnnotationKey)).Also(
+			apis.ErrInvalidValue("80800", "metadata.annotations."+autoscaling.RPSMetricPortAnnotationKey)),
+	}, {
+		name: "unknown metric source",
+		r: &PodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Annotations: map[string]string{
+					autoscaling.StandaloneAnnotationKey:   "true",
+					autoscaling.MetricSourceAnnotationKey: "statsd",
+				},
+			},
+			Spec: PodAutoscalerSpec{
+				ScaleTargetRef: corev1.ObjectReference{
+					APIVersion: "apps/v1",
+					Kind:       "Deployment",
+					Name:       "bar",
+				},
+				ProtocolType: net.ProtocolHTTP1,
+			},
+		},
+		want: apis.ErrInvalidValue("statsd", "metadata.annotations."+autoscaling.MetricSourceAnnotationKey),
+	}, {
+		name: "standalone KPA without apiVersion and kind",
+		r: &PodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Annotations: map[string]string{
+					autoscaling.StandaloneAnnotationKey: "true",
+				},
+			},
+			Spec: PodAutoscalerSpec{
+				ScaleTargetRef: corev1.ObjectReference{
+					Name: "bar",
+				},
+				ProtocolType: net.ProtocolHTTP1,
+			},
+		},
+		want: apis.ErrMissingField("spec.scaleTargetRef.apiVersion", "spec.scaleTargetRef.kind").Also(
+			apis.ErrGeneric("standalone KPA-class PodAutoscalers can only scale apps/v1 Deployments",
+				"spec.scaleTargetRef.apiVersion", "spec.scaleTargetRef.kind")),
+	}, {
+		name: "invalid standalone annotation",
+		r: &PodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Annotations: map[string]string{
+					autoscaling.StandaloneAnnotationKey: "yes",
+				},
+			},
+			Spec: PodAutoscalerSpec{
+				ScaleTargetRef: corev1.ObjectReference{
+					APIVersion: "apps/v1",
+					Kind:       "Deployment",
+					Name:       "bar",
+				},
+				ProtocolType: net.ProtocolHTTP1,
+			},
+		},
+		want: apis.ErrInvalidValue("yes", "metadata.annotations."+autoscaling.StandaloneAnnotationKey),
+	}, {
+		name: "standalone revision PA",
+		r: &PodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Labels: map[string]string{
+					serving.RevisionLabelKey: "rev",
+				},
+				Annotations: map[string]string{
+					autoscaling.StandaloneAnnotationKey: "true",
+				},
+			},
+			Spec: PodAutoscalerSpec{
+				ScaleTargetRef: corev1.ObjectReference{
+					APIVersion: "apps/v1",
+					Kind:       "Deployment",
+					Name:       "bar",
+				},
+				ProtocolType: net.ProtocolHTTP1,
+			},
+		},
+		want: apis.ErrGeneric("PodAutoscalers of Revisions cannot be standalone",
+			"metadata.annotations."+autoscaling.StandaloneAnnotationKey),
+	}, {
+		name: "metric source of a revision PA",
+		r: &PodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Labels: map[string]string{
+					serving.RevisionLabelKey: "rev",
+				},
+				Annotations: map[string]string{
+					autoscaling.MetricSourceAnnotationKey: autoscaling.MetricSourceQueueProxy,
+				},
+			},
+			Spec: PodAutoscalerSpec{
+				ScaleTargetRef: corev1.ObjectReference{
+					APIVersion: "apps/v1",
+					Kind:       "Deployment",
+					Name:       "bar",
+				},
+				ProtocolType: net.ProtocolHTTP1,
+			},
+		},
+		want: apis.ErrGeneric("only supported by standalone KPA-class PodAutoscalers",
+			"metadata.annotations."+autoscaling.MetricSourceAnnotationKey),
 	}, {
 		name: "empty spec",
 		r: &PodAutoscaler{
@@ -59,28 +59,43 @@ func TestFeaturesConfiguration(t *testing.T) {
 		name:    "features Enabled",
 		wantErr: false,
 		wantFeatures: defaultWith(&Features{
+			DebugContainers:         Enabled,
 			MultiContainer:          Enabled,
 			PodSpecAffinity:         Enabled,
 			PodSpecDryRun:           Enabled,
 			PodSpecHostAliases:      Enabled,
 			PodSpecNodeSelector:     Enabled,
+			PodSpecReadinessGates:   Enabled,
 			PodSpecRuntimeClassName: Enabled,
 			PodSpecSecurityContext:  Enabled,
 			PodSpecTolerations:      Enabled,
+			RevisionPreview:         Enabled,
 			TagHeaderBasedRouting:   Enabled,
 		}),
 		data: map[string]string{
+			"debug-containers":                    "Enabled",
 			"multi-container":                     "Enabled",
 			"kubernetes.podspec-affinity":         "Enabled",
 			"kubernetes.podspec-dryrun":           "Enabled",
 			"kubernetes.podspec-hostaliases":      "Enabled",
 			"kubernetes.podspec-nodeselector":     "Enabled",
+			"kubernetes.podspec-readinessgates":   "Enabled",
 			"kubernetes.podspec-runtimeclassname": "Enabled",
 			"kubernetes.podspec-securitycontext":  "Enabled",
 			"kubernetes.podspec-tolerations":      "Enabled",
 			"responsive-revision-gc":              "Enabled",
+			"revision-preview":                    "Enabled",
 			"tag-header-based-routing":            "Enabled",
 		},
+	}, {
+		name:    "debug-containers Enabled",
+		wantErr: false,
+		wantFeatures: defaultWith(&Features{
+			DebugContainers: Enabled,
+		}),
+		data: map[string]string{
+			"debug-containers": "Enabled",
+		},
 	}, {
 		name:    "multi-container Allowed",
 		wantErr: false,
@@ -216,6 +231,33 @@ func TestFeaturesConfiguration(t *testing.T) {
 		data: map[string]string{
 			"kubernetes.podspec-nodeselector": "Disabled",
 		},
+	}, {
+		name:    "kubernetes.podspec-readinessgates Allowed",
+		wantErr: false,
+		wantFeatures: defaultWith(&Features{
+			PodSpecReadinessGates: Allowed,
+		}),
+		data: map[string]string{
+			"kubernetes.podspec-readinessgates": "Allowed",
+		},
+	}, {
+		name:    "kubernetes.podspec-readinessgates Enabled",
+		wantErr: false,
+		wantFeatures: defaultWith(&Features{
+			PodSpecReadinessGates: Enabled,
+		}),
+		data: map[string]string{
+			"kubernetes.podspec-readinessgates": "Enabled",
+		},
+	}, {
+		name:    "kubernetes.podspec-readinessgates Disabled",
+		wantErr: false,
+		wantFeatures: defaultWith(&Features{
+			PodSpecReadinessGates: Disabled,
+		}),
+		data: map[string]string{
+			"kubernetes.podspec-readinessgates": "Disabled",
+		},
 	}, {
 		name:    "kubernetes.podspec-runtimeclassname Allowed",
 		wantErr: false,
@@ -288,6 +330,15 @@ func TestFeaturesConfiguration(t *testing.T) {
 		data: map[string]string{
 			"kubernetes.podspec-securitycontext": "Disabled",
 		},
+	}, {
+		name:    "revision-preview Enabled",
+		wantErr: false,
+		wantFeatures: defaultWith(&Features{
+			RevisionPreview: Enabled,
+		}),
+		data: map[string]string{
+			"revision-preview": "Enabled",
+		},
 	}, {
 		name:    "tag-header-based-routing Allowed",
 		wantErr: false,
@@ -0,0 +1,230 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// MetadataPropagationConfigName is the name of the ConfigMap holding the
+	// metadata propagation policy.
+	MetadataPropagationConfigName = "config-metadata-propagation"
+)
+
+// PropagationTarget is a kind of resource that is generated from a Service
+// and receives labels and annotations from its parent.
+type PropagationTarget string
+
+const (
+	// PropagateToRoute is the Route generated from a Service.
+	PropagateToRoute PropagationTarget = "route"
+	// PropagateToConfiguration is the Configuration generated from a Service.
+	PropagateToConfiguration PropagationTarget = "configuration"
+	// PropagateToRevision is the Revision stamped out from a Configuration.
+	PropagateToRevision PropagationTarget = "revision"
+	// PropagateToDeployment is the Deployment generated for a Revision.
+	PropagateToDeployment PropagationTarget = "deployment"
+	// PropagateToPod is the pod template of the Deployment of a Revision.
+	PropagateToPod PropagationTarget = "pod"
+	// PropagateToService is the K8s Service generated for a Route and its tags.
+	PropagateToService PropagationTarget = "service"
+	// PropagateToIngress is the Ingress generated for a Route.
+	PropagateToIngress PropagationTarget = "ingress"
+)
+
+// PropagationTargets lists all the targets the policy may be set for.
+var PropagationTargets = []PropagationTarget{
+	PropagateToRoute,
+	PropagateToConfiguration,
+	PropagateToRevision,
+	PropagateToDeployment,
+	PropagateToPod,
+	PropagateToService,
+	PropagateToIngress,
+}
+
+// PropagationRule selects metadata keys by pattern. A pattern may contain
+// any number of `*` wildcards, each of which matches any sequence of characters.
+type PropagationRule struct {
+	// Allow, if not empty, replaces the keys propagated by default
+	// with the keys matching any of these patterns.
+	Allow []string
+	// Deny lists the patterns of keys that are never propagated.
+	Deny []string
+}
+
+// MetadataPropagation specifies which labels and annotations flow from
+// a resource to the resources generated from it, per target kind.
+type MetadataPropagation struct {
+	Labels      map[PropagationTarget]PropagationRule
+	Annotations map[PropagationTarget]PropagationRule
+}
+
+// NewMetadataPropagationFromMap creates a MetadataPropagation from the supplied Map.
+func NewMetadataPropagationFromMap(data map[string]string) (*MetadataPropagation, error) {
+	mp := &MetadataPropagation{
+		Labels:      map[PropagationTarget]PropagationRule{},
+		Annotations: map[PropagationTarget]PropagationRule{},
+	}
+	for _, t := range PropagationTargets {
+		for _, kind := range []struct {
+			name  string
+			rules map[PropagationTarget]PropagationRule
+		}{{"labels", mp.Labels}, {"annotations", mp.Annotations}} {
+			prefix := string(t) + "." + kind.name
+			allow, err := asPatterns(data, prefix+".allow")
+			if err != nil {
+				return nil, err
+			}
+			deny, err := asPatterns(data, prefix+".deny")
+			if err != nil {
+				return nil, err
+			}
+			if len(allow) > 0 || len(deny) > 0 {
+				kind.rules[t] = PropagationRule{Allow: allow, Deny: deny}
+			}
+		}
+	}
+	return mp, nil
+}
+
+// NewMetadataPropagationFromConfigMap creates a MetadataPropagation from the supplied ConfigMap.
+func NewMetadataPropagationFromConfigMap(config *corev1.ConfigMap) (*MetadataPropagation, error) {
+	return NewMetadataPropagationFromMap(config.Data)
+}
+
+// asPatterns parses the comma separated list of key patterns at key.
+func asPatterns(data map[string]string, key string) ([]string, error) {
+	raw, ok := data[key]
+	if !ok {
+		return nil, nil
+	}
+	var patterns []string
+	for _, p := range strings.Split(raw, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		// Substitute the wildcards to check the rest of the pattern
+		// is made of characters valid in a metadata key.
+		if p != "*" {
+			if errs := validation.IsQualifiedName(strings.ReplaceAll(p, "*", "x")); len(errs) > 0 {
+				return nil, fmt.Errorf("invalid pattern %q for %s: %s", p, key, strings.Join(errs, ", "))
+			}
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+// FilterLabels returns the labels from the given map that propagate to
+// target. byDefault reports whether a key propagates when no policy says
+// otherwise; nil means that all keys do.
+func (mp *MetadataPropagation) FilterLabels(target PropagationTarget, in map[string]string, byDefault func(string) bool) map[string]string {
+	var rule PropagationRule
+	if mp != nil {
+		rule = mp.Labels[target]
+	}
+	return rule.filter(in, byDefault)
+}
+
+// FilterAnnotations returns the annotations from the given map that propagate
+// to target. byDefault reports whether a key propagates when no policy says
+// otherwise; nil means that all keys do.
+func (mp *MetadataPropagation) FilterAnnotations(target PropagationTarget, in map[string]string, byDefault func(string) bool) map[string]string {
+	var rule PropagationRule
+	if mp != nil {
+		rule = mp.Annotations[target]
+	}
+	return rule.filter(in, byDefault)
+}
+
+func (r PropagationRule) filter(in map[string]string, byDefault func(string) bool) map[string]string {
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		if r.propagates(k, byDefault) {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func (r PropagationRule) propagates(key string, byDefault func(string) bool) bool {
+	dflt := byDefault == nil || byDefault(key)
+	// Keys owned by Knative and the tooling around it are wired explicitly
+	// by the builders and are not subject to the policy.
+	if reservedKey(key) {
+		return dflt
+	}
+	if matchesAny(r.Deny, key) {
+		return false
+	}
+	if len(r.Allow) > 0 {
+		return matchesAny(r.Allow, key)
+	}
+	return dflt
+}
+
+// reservedKey returns whether the key belongs to a knative.dev domain
+// or is the kubectl last-applied-configuration annotation.
+func reservedKey(key string) bool {
+	if key == corev1.LastAppliedConfigAnnotation {
+		return true
+	}
+	i := strings.Index(key, "/")
+	if i < 0 {
+		return false
+	}
+	domain := key[:i]
+	return domain == "knative.dev" || strings.HasSuffix(domain, ".knative.dev")
+}
+
+func matchesAny(patterns []string, key string) bool {
+	for _, p := range patterns {
+		if matchPattern(p, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern matches key against pattern, where each `*` in the pattern
+// matches any, possibly empty, sequence of characters.
+func matchPattern(pattern, key string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == key
+	}
+	if !strings.HasPrefix(key, parts[0]) {
+		return false
+	}
+	key = key[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, p := range parts[1 : len(parts)-1] {
+		i := strings.Index(key, p)
+		if i < 0 {
+			return false
+		}
+		key = key[i+len(p):]
+	}
+	return len(key) >= len(last) && strings.HasSuffix(key, last)
+}
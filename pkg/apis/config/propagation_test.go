@@ -0,0 +1,229 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	. "knative.dev/pkg/configmap/testing"
+)
+
+func TestMetadataPropagationFromFile(t *testing.T) {
+	cm, example := ConfigMapsFromTestFile(t, MetadataPropagationConfigName)
+
+	got, err := NewMetadataPropagationFromConfigMap(cm)
+	if err != nil {
+		t.Fatal("NewMetadataPropagationFromConfigMap(actual) =", err)
+	}
+	if want := (&MetadataPropagation{
+		Labels:      map[PropagationTarget]PropagationRule{},
+		Annotations: map[PropagationTarget]PropagationRule{},
+	}); !cmp.Equal(got, want) {
+		t.Errorf("NewMetadataPropagationFromConfigMap(actual) = %#v, want: %#v", got, want)
+	}
+
+	got, err = NewMetadataPropagationFromConfigMap(example)
+	if err != nil {
+		t.Fatal("NewMetadataPropagationFromConfigMap(example) =", err)
+	}
+	want := &MetadataPropagation{
+		Labels: map[PropagationTarget]PropagationRule{
+			PropagateToRevision: {Allow: []string{"cost.example.com/*"}},
+			PropagateToIngress:  {Deny: []string{"contact.example.com/*"}},
+			PropagateToService:  {Deny: []string{"contact.example.com/*"}},
+		},
+		Annotations: map[PropagationTarget]PropagationRule{
+			PropagateToRevision:   {Allow: []string{"prometheus.io/*"}},
+			PropagateToDeployment: {Deny: []string{"prometheus.io/*"}},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("Unexpected example policy (-want, +got):", diff)
+	}
+}
+
+func TestMetadataPropagationFromMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		want    *MetadataPropagation
+		wantErr bool
+	}{{
+		name: "empty",
+		want: &MetadataPropagation{
+			Labels:      map[PropagationTarget]PropagationRule{},
+			Annotations: map[PropagationTarget]PropagationRule{},
+		},
+	}, {
+		name: "lists are trimmed",
+		data: map[string]string{
+			"pod.labels.allow":      " team, cost.example.com/* ,,",
+			"pod.annotations.deny":  "*",
+			"route.labels.deny":     "internal-*",
+			"unknown.labels.allow":  "ignored",
+			"route.labels.whatever": "ignored",
+		},
+		want: &MetadataPropagation{
+			Labels: map[PropagationTarget]PropagationRule{
+				PropagateToPod:   {Allow: []string{"team", "cost.example.com/*"}},
+				PropagateToRoute: {Deny: []string{"internal-*"}},
+			},
+			Annotations: map[PropagationTarget]PropagationRule{
+				PropagateToPod: {Deny: []string{"*"}},
+			},
+		},
+	}, {
+		name: "invalid pattern",
+		data: map[string]string{
+			"ingress.annotations.allow": "not a key/*",
+		},
+		wantErr: true,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NewMetadataPropagationFromMap(tc.data)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("NewMetadataPropagationFromMap() = %v, wantErr: %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error("NewMetadataPropagationFromMap (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func TestMetadataPropagationFilter(t *testing.T) {
+	in := map[string]string{
+		"team":                             "a-team",
+		"cost.example.com/center":          "1234",
+		"cost.example.com/owner":           "hannibal",
+		"prometheus.io/scrape":             "true",
+		"serving.knative.dev/service":      "van",
+		"knative.dev/thing":                "stuff",
+		corev1.LastAppliedConfigAnnotation: "{}",
+	}
+	notLastApplied := func(k string) bool {
+		return k != corev1.LastAppliedConfigAnnotation
+	}
+	none := func(string) bool {
+		return false
+	}
+
+	tests := []struct {
+		name      string
+		mp        *MetadataPropagation
+		byDefault func(string) bool
+		want      map[string]string
+	}{{
+		name: "nil policy",
+		want: in,
+	}, {
+		name:      "no rule, default filter",
+		mp:        &MetadataPropagation{},
+		byDefault: notLastApplied,
+		want: map[string]string{
+			"team":                        "a-team",
+			"cost.example.com/center":     "1234",
+			"cost.example.com/owner":      "hannibal",
+			"prometheus.io/scrape":        "true",
+			"serving.knative.dev/service": "van",
+			"knative.dev/thing":           "stuff",
+		},
+	}, {
+		name: "allow replaces the defaults",
+		mp: &MetadataPropagation{
+			Labels: map[PropagationTarget]PropagationRule{
+				PropagateToRevision: {Allow: []string{"cost.example.com/*", "team"}},
+			},
+		},
+		byDefault: none,
+		want: map[string]string{
+			"team":                    "a-team",
+			"cost.example.com/center": "1234",
+			"cost.example.com/owner":  "hannibal",
+		},
+	}, {
+		name: "deny wins over allow, reserved keys untouched",
+		mp: &MetadataPropagation{
+			Labels: map[PropagationTarget]PropagationRule{
+				PropagateToRevision: {
+					Allow: []string{"*"},
+					Deny:  []string{"*/owner", "prometheus.io/*"},
+				},
+			},
+		},
+		byDefault: notLastApplied,
+		want: map[string]string{
+			"team":                        "a-team",
+			"cost.example.com/center":     "1234",
+			"serving.knative.dev/service": "van",
+			"knative.dev/thing":           "stuff",
+		},
+	}, {
+		name: "rules are per target",
+		mp: &MetadataPropagation{
+			Labels: map[PropagationTarget]PropagationRule{
+				PropagateToPod: {Deny: []string{"*"}},
+			},
+		},
+		want: in,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.mp.FilterLabels(PropagateToRevision, in, tc.byDefault)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error("FilterLabels (-want, +got):", diff)
+			}
+			// Annotation rules don't affect labels and vice versa.
+			if tc.mp != nil {
+				swapped := &MetadataPropagation{Annotations: tc.mp.Labels}
+				got := swapped.FilterAnnotations(PropagateToRevision, in, tc.byDefault)
+				if diff := cmp.Diff(tc.want, got); diff != "" {
+					t.Error("FilterAnnotations (-want, +got):", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern, key string
+		want         bool
+	}{
+		{"team", "team", true},
+		{"team", "teams", false},
+		{"*", "cost.example.com/center", true},
+		{"cost.example.com/*", "cost.example.com/center", true},
+		{"cost.example.com/*", "example.com/center", false},
+		{"*.example.com/*", "cost.example.com/center", true},
+		{"*/center", "cost.example.com/center", true},
+		{"a*a", "a", false},
+		{"a*a", "aa", true},
+		{"a*b*c", "abbbc", true},
+		{"a*b*c", "acb", false},
+	}
+	for _, tc := range tests {
+		if got := matchPattern(tc.pattern, tc.key); got != tc.want {
+			t.Errorf("matchPattern(%q, %q) = %v, want: %v", tc.pattern, tc.key, got, tc.want)
+		}
+	}
+}
@@ -28,9 +28,10 @@ type cfgKey struct{}
 
 // Config holds the collection of configurations that we attach to contexts.
 type Config struct {
-	Defaults   *Defaults
-	Features   *Features
-	Autoscaler *autoscalerconfig.Config
+	Defaults            *Defaults
+	Features            *Features
+	Autoscaler          *autoscalerconfig.Config
+	MetadataPropagation *MetadataPropagation
 }
 
 // FromContext extracts a Config from the provided context.
@@ -61,6 +62,10 @@ func FromContextOrDefaults(ctx context.Context) *Config {
 	if cfg.Autoscaler == nil {
 		cfg.Autoscaler, _ = asconfig.NewConfigFromMap(map[string]string{})
 	}
+
+	if cfg.MetadataPropagation == nil {
+		cfg.MetadataPropagation, _ = NewMetadataPropagationFromMap(map[string]string{})
+	}
 	return cfg
 }
 
@@ -83,9 +88,10 @@ func NewStore(logger configmap.Logger, onAfterStore ...func(name string, value i
 			"apis",
 			logger,
 			configmap.Constructors{
-				DefaultsConfigName:  NewDefaultsConfigFromConfigMap,
-				FeaturesConfigName:  NewFeaturesConfigFromConfigMap,
-				asconfig.ConfigName: asconfig.NewConfigFromConfigMap,
+				DefaultsConfigName:            NewDefaultsConfigFromConfigMap,
+				FeaturesConfigName:            NewFeaturesConfigFromConfigMap,
+				asconfig.ConfigName:           asconfig.NewConfigFromConfigMap,
+				MetadataPropagationConfigName: NewMetadataPropagationFromConfigMap,
 			},
 			onAfterStore...,
 		),
@@ -111,5 +117,8 @@ func (s *Store) Load() *Config {
 	if as, ok := s.UntypedLoad(asconfig.ConfigName).(*autoscalerconfig.Config); ok {
 		cfg.Autoscaler = as.DeepCopy()
 	}
+	if mp, ok := s.UntypedLoad(MetadataPropagationConfigName).(*MetadataPropagation); ok {
+		cfg.MetadataPropagation = mp.DeepCopy()
+	}
 	return cfg
 }
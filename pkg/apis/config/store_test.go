@@ -39,10 +39,12 @@ func TestStoreLoadWithContext(t *testing.T) {
 	defaultsConfig := ConfigMapFromTestFile(t, DefaultsConfigName)
 	featuresConfig := ConfigMapFromTestFile(t, FeaturesConfigName)
 	autoscalerConfig := ConfigMapFromTestFile(t, autoscalerconfig.ConfigName)
+	propagationConfig := ConfigMapFromTestFile(t, MetadataPropagationConfigName)
 
 	store.OnConfigChanged(defaultsConfig)
 	store.OnConfigChanged(featuresConfig)
 	store.OnConfigChanged(autoscalerConfig)
+	store.OnConfigChanged(propagationConfig)
 
 	config := FromContextOrDefaults(store.ToContext(context.Background()))
 
@@ -66,6 +68,13 @@ func TestStoreLoadWithContext(t *testing.T) {
 			t.Errorf("Unexpected autoscaler config (-want, +got):\n%v", diff)
 		}
 	})
+
+	t.Run("metadata-propagation", func(t *testing.T) {
+		expected, _ := NewMetadataPropagationFromConfigMap(propagationConfig)
+		if diff := cmp.Diff(expected, config.MetadataPropagation); diff != "" {
+			t.Errorf("Unexpected metadata propagation config (-want, +got):\n%v", diff)
+		}
+	})
 }
 
 func TestStoreLoadWithContextOrDefaults(t *testing.T) {
@@ -94,6 +103,13 @@ func TestStoreLoadWithContextOrDefaults(t *testing.T) {
 			t.Errorf("Unexpected autoscaler config (-want, +got):\n%v", diff)
 		}
 	})
+
+	t.Run("metadata-propagation", func(t *testing.T) {
+		expected, _ := NewMetadataPropagationFromMap(nil)
+		if diff := cmp.Diff(expected, config.MetadataPropagation); diff != "" {
+			t.Errorf("Unexpected metadata propagation config (-want, +got):\n%v", diff)
+		}
+	})
 }
 
 func TestStoreImmutableConfig(t *testing.T) {
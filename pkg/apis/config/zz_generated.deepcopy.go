@@ -42,6 +42,11 @@ func (in *Config) DeepCopyInto(out *Config) {
 		*out = new(autoscalerconfig.Config)
 		**out = **in
 	}
+	if in.MetadataPropagation != nil {
+		in, out := &in.MetadataPropagation, &out.MetadataPropagation
+		*out = new(MetadataPropagation)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -121,3 +126,59 @@ func (in *Features) DeepCopy() *Features {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataPropagation) DeepCopyInto(out *MetadataPropagation) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[PropagationTarget]PropagationRule, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[PropagationTarget]PropagationRule, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataPropagation.
+func (in *MetadataPropagation) DeepCopy() *MetadataPropagation {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataPropagation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PropagationRule) DeepCopyInto(out *PropagationRule) {
+	*out = *in
+	if in.Allow != nil {
+		in, out := &in.Allow, &out.Allow
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deny != nil {
+		in, out := &in.Deny, &out.Deny
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropagationRule.
+func (in *PropagationRule) DeepCopy() *PropagationRule {
+	if in == nil {
+		return nil
+	}
+	out := new(PropagationRule)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -182,6 +182,9 @@ func PodSpecMask(ctx context.Context, in *corev1.PodSpec) *corev1.PodSpec {
 	if cfg.Features.PodSpecNodeSelector != config.Disabled {
 		out.NodeSelector = in.NodeSelector
 	}
+	if cfg.Features.PodSpecReadinessGates != config.Disabled {
+		out.ReadinessGates = in.ReadinessGates
+	}
 	if cfg.Features.PodSpecRuntimeClassName != config.Disabled {
 		out.RuntimeClassName = in.RuntimeClassName
 	}
@@ -211,7 +214,6 @@ func PodSpecMask(ctx context.Context, in *corev1.PodSpec) *corev1.PodSpec {
 	out.PriorityClassName = ""
 	out.Priority = nil
 	out.DNSConfig = nil
-	out.ReadinessGates = nil
 
 	return out
 }
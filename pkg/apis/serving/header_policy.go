@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serving
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ResponseHeaderPolicy declares the headers the queue-proxy adds to, or
+// strips from, the responses of a Revision.
+type ResponseHeaderPolicy struct {
+	// CORS configures cross-origin resource sharing. When set, preflight
+	// requests are answered by the queue-proxy without reaching the app.
+	CORS *CORSPolicy `json:"cors,omitempty"`
+
+	// HSTS configures the Strict-Transport-Security response header.
+	HSTS *HSTSPolicy `json:"hsts,omitempty"`
+
+	// ContentTypeNoSniff adds `X-Content-Type-Options: nosniff`.
+	ContentTypeNoSniff bool `json:"contentTypeNoSniff,omitempty"`
+
+	// Set holds response headers that are added, replacing any value
+	// set by the app.
+	Set map[string]string `json:"set,omitempty"`
+
+	// Remove holds response headers that are stripped from the response.
+	Remove []string `json:"remove,omitempty"`
+}
+
+// CORSPolicy configures cross-origin resource sharing.
+type CORSPolicy struct {
+	// AllowOrigins are the origins allowed to make cross-origin requests.
+	// "*" allows any origin.
+	AllowOrigins []string `json:"allowOrigins"`
+	// AllowMethods are the methods allowed in preflight requests.
+	AllowMethods []string `json:"allowMethods,omitempty"`
+	// AllowHeaders are the request headers allowed in preflight requests.
+	// When empty, the headers requested by the preflight are allowed.
+	AllowHeaders []string `json:"allowHeaders,omitempty"`
+	// ExposeHeaders are the response headers exposed to the caller.
+	ExposeHeaders []string `json:"exposeHeaders,omitempty"`
+	// AllowCredentials allows the caller to include credentials.
+	AllowCredentials bool `json:"allowCredentials,omitempty"`
+	// MaxAgeSeconds is how long preflight responses may be cached.
+	MaxAgeSeconds int `json:"maxAgeSeconds,omitempty"`
+}
+
+// HSTSPolicy configures the Strict-Transport-Security response header.
+type HSTSPolicy struct {
+	MaxAgeSeconds     int  `json:"maxAgeSeconds"`
+	IncludeSubDomains bool `json:"includeSubDomains,omitempty"`
+	Preload           bool `json:"preload,omitempty"`
+}
+
+// ParseResponseHeaderPolicy decodes and validates the value of
+// ResponseHeaderPolicyAnnotationKey.
+func ParseResponseHeaderPolicy(v string) (*ResponseHeaderPolicy, error) {
+	dec := json.NewDecoder(bytes.NewBufferString(v))
+	dec.DisallowUnknownFields()
+	p := &ResponseHeaderPolicy{}
+	if err := dec.Decode(p); err != nil {
+		return nil, fmt.Errorf("invalid response header policy: %w", err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *ResponseHeaderPolicy) validate() error {
+	if c := p.CORS; c != nil {
+		if len(c.AllowOrigins) == 0 {
+			return fmt.Errorf("cors.allowOrigins must not be empty")
+		}
+		for _, o := range c.AllowOrigins {
+			if o == "*" {
+				if c.AllowCredentials {
+					return fmt.Errorf("cors.allowOrigins must not contain %q when cors.allowCredentials is set", o)
+				}
+				continue
+			}
+			if !strings.HasPrefix(o, "http://") && !strings.HasPrefix(o, "https://") {
+				return fmt.Errorf("cors.allowOrigins entry %q must be \"*\" or an http(s) origin", o)
+			}
+		}
+		for _, m := range c.AllowMethods {
+			if !ValidHeaderName(m) {
+				return fmt.Errorf("cors.allowMethods entry %q is not a valid method", m)
+			}
+		}
+		if err := validateHeaderNames("cors.allowHeaders", c.AllowHeaders); err != nil {
+			return err
+		}
+		if err := validateHeaderNames("cors.exposeHeaders", c.ExposeHeaders); err != nil {
+			return err
+		}
+		if c.MaxAgeSeconds < 0 {
+			return fmt.Errorf("cors.maxAgeSeconds must not be negative, was %d", c.MaxAgeSeconds)
+		}
+	}
+	if p.HSTS != nil && p.HSTS.MaxAgeSeconds < 0 {
+		return fmt.Errorf("hsts.maxAgeSeconds must not be negative, was %d", p.HSTS.MaxAgeSeconds)
+	}
+	for k, v := range p.Set {
+		if !ValidHeaderName(k) {
+			return fmt.Errorf("set key %q is not a valid header name", k)
+		}
+		if !ValidHeaderValue(v) {
+			return fmt.Errorf("set value for %q is not a valid header value", k)
+		}
+	}
+	return validateHeaderNames("remove", p.Remove)
+}
+
+func validateHeaderNames(field string, names []string) error {
+	for _, n := range names {
+		if !ValidHeaderName(n) {
+			return fmt.Errorf("%s entry %q is not a valid header name", field, n)
+		}
+	}
+	return nil
+}
+
+// AllowsOrigin returns whether cross-origin requests from origin are allowed.
+func (c *CORSPolicy) AllowsOrigin(origin string) bool {
+	for _, o := range c.AllowOrigins {
+		if o == "*" || o == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsMethod returns whether preflight requests for method are allowed.
+// Simple methods are always allowed.
+func (c *CORSPolicy) AllowsMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost:
+		return true
+	}
+	for _, m := range c.AllowMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// HeaderValue returns the value of the Strict-Transport-Security header.
+func (h *HSTSPolicy) HeaderValue() string {
+	v := fmt.Sprint("max-age=", h.MaxAgeSeconds)
+	if h.IncludeSubDomains {
+		v += "; includeSubDomains"
+	}
+	if h.Preload {
+		v += "; preload"
+	}
+	return v
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serving
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseResponseHeaderPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    *ResponseHeaderPolicy
+		wantErr string
+	}{{
+		name:  "full policy",
+		value: `{"cors":{"allowOrigins":["https://example.com"],"allowMethods":["PUT"],"allowCredentials":true,"maxAgeSeconds":60},"hsts":{"maxAgeSeconds":10,"preload":true},"contentTypeNoSniff":true,"set":{"X-Frame-Options":"DENY"},"remove":["Server"]}`,
+		want: &ResponseHeaderPolicy{
+			CORS: &CORSPolicy{
+				AllowOrigins:     []string{"https://example.com"},
+				AllowMethods:     []string{"PUT"},
+				AllowCredentials: true,
+				MaxAgeSeconds:    60,
+			},
+			HSTS:               &HSTSPolicy{MaxAgeSeconds: 10, Preload: true},
+			ContentTypeNoSniff: true,
+			Set:                map[string]string{"X-Frame-Options": "DENY"},
+			Remove:             []string{"Server"},
+		},
+	}, {
+		name:    "not json",
+		value:   "nosniff",
+		wantErr: "invalid response header policy: invalid character 'o' in literal null (expecting 'u')",
+	}, {
+		name:    "unknown field",
+		value:   `{"cros":{}}`,
+		wantErr: `invalid response header policy: json: unknown field "cros"`,
+	}, {
+		name:    "no origins",
+		value:   `{"cors":{}}`,
+		wantErr: "cors.allowOrigins must not be empty",
+	}, {
+		name:    "origin without scheme",
+		value:   `{"cors":{"allowOrigins":["example.com"]}}`,
+		wantErr: `cors.allowOrigins entry "example.com" must be "*" or an http(s) origin`,
+	}, {
+		name:    "wildcard with credentials",
+		value:   `{"cors":{"allowOrigins":["*"],"allowCredentials":true}}`,
+		wantErr: `cors.allowOrigins must not contain "*" when cors.allowCredentials is set`,
+	}, {
+		name:    "invalid method",
+		value:   `{"cors":{"allowOrigins":["*"],"allowMethods":["GET POST"]}}`,
+		wantErr: `cors.allowMethods entry "GET POST" is not a valid method`,
+	}, {
+		name:    "negative hsts max age",
+		value:   `{"hsts":{"maxAgeSeconds":-1}}`,
+		wantErr: "hsts.maxAgeSeconds must not be negative, was -1",
+	}, {
+		name:    "invalid set header",
+		value:   `{"set":{"X Frame":"DENY"}}`,
+		wantErr: `set key "X Frame" is not a valid header name`,
+	}, {
+		name:    "invalid remove header",
+		value:   `{"remove":["Ser:ver"]}`,
+		wantErr: `remove entry "Ser:ver" is not a valid header name`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseResponseHeaderPolicy(test.value)
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("ParseResponseHeaderPolicy() = %v, want error: %s", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("ParseResponseHeaderPolicy() =", err)
+			}
+			if !cmp.Equal(got, test.want) {
+				t.Error("ParseResponseHeaderPolicy() (-want, +got):", cmp.Diff(test.want, got))
+			}
+		})
+	}
+}
+
+func TestHSTSHeaderValue(t *testing.T) {
+	h := &HSTSPolicy{MaxAgeSeconds: 31536000, IncludeSubDomains: true, Preload: true}
+	if got, want := h.HeaderValue(), "max-age=31536000; includeSubDomains; preload"; got != want {
+		t.Errorf("HeaderValue() = %q, want: %q", got, want)
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serving
+
+import "strings"
+
+// tokenChars are the characters, besides the letters and digits, allowed
+// in an HTTP token, see https://tools.ietf.org/html/rfc7230#section-3.2.6.
+const tokenChars = "!#$%&'*+-.^_`|~"
+
+// ValidHeaderName returns whether name is a valid HTTP header field name,
+// which is also what's required of an HTTP method.
+func ValidHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r < 0x80 && strings.ContainsRune(tokenChars, r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ValidHeaderValue returns whether value is a valid HTTP header field value,
+// i.e. that it holds no control characters other than horizontal tabs.
+func ValidHeaderValue(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if b := value[i]; (b < ' ' && b != '\t') || b == 0x7f {
+			return false
+		}
+	}
+	return true
+}
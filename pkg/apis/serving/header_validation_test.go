@@ -0,0 +1,50 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serving
+
+import "testing"
+
+func TestValidHeaderName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"X-Frame-Options": true,
+		"GET":             true,
+		"x_custom~1":      true,
+		"":                false,
+		"X Frame":         false,
+		"X-Frame:":        false,
+		"X-Främe":         false,
+	} {
+		if got := ValidHeaderName(name); got != want {
+			t.Errorf("ValidHeaderName(%q) = %v, want: %v", name, got, want)
+		}
+	}
+}
+
+func TestValidHeaderValue(t *testing.T) {
+	for value, want := range map[string]bool{
+		"":                      true,
+		"max-age=31536000":      true,
+		"text/html;\tcharset=x": true,
+		"a\r\nSet-Cookie: b":    false,
+		"a\x00b":                false,
+		"a\x7fb":                false,
+	} {
+		if got := ValidHeaderValue(value); got != want {
+			t.Errorf("ValidHeaderValue(%q) = %v, want: %v", value, got, want)
+		}
+	}
+}
@@ -308,6 +308,13 @@ func ValidatePodSpec(ctx context.Context, ps corev1.PodSpec) *apis.FieldError {
 			errs = errs.Also(apis.ErrInvalidValue("serviceAccountName", ps.ServiceAccountName))
 		}
 	}
+	for i, gate := range ps.ReadinessGates {
+		if msgs := validation.IsQualifiedName(string(gate.ConditionType)); len(msgs) > 0 {
+			err := apis.ErrInvalidValue(gate.ConditionType, "conditionType")
+			err.Details = strings.Join(msgs, ", ")
+			errs = errs.Also(err.ViaFieldIndex("readinessGates", i))
+		}
+	}
 	return errs
 }
 
@@ -69,6 +69,13 @@ func withPodSpecNodeSelectorEnabled() configOption {
 	}
 }
 
+func withPodSpecReadinessGatesEnabled() configOption {
+	return func(cfg *config.Config) *config.Config {
+		cfg.Features.PodSpecReadinessGates = config.Enabled
+		return cfg
+	}
+}
+
 func withPodSpecTolerationsEnabled() configOption {
 	return func(cfg *config.Config) *config.Config {
 		cfg.Features.PodSpecTolerations = config.Enabled
@@ -125,6 +132,24 @@ func TestPodSpecValidation(t *testing.T) {
 			}},
 		},
 		want: nil,
+	}, {
+		name: "with invalid readiness gate",
+		ps: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Image: "helloworld",
+			}},
+			ReadinessGates: []corev1.PodReadinessGate{{
+				ConditionType: "example.com/cache-warm",
+			}, {
+				ConditionType: "not a condition",
+			}},
+		},
+		cfgOpts: []configOption{withPodSpecReadinessGatesEnabled()},
+		want: &apis.FieldError{
+			Message: "invalid value: not a condition",
+			Paths:   []string{"readinessGates[1].conditionType"},
+			Details: "name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')",
+		},
 	}, {
 		name: "with volume name collision",
 		ps: corev1.PodSpec{
@@ -611,6 +636,18 @@ func TestPodSpecFeatureValidation(t *testing.T) {
 			Paths:   []string{"nodeSelector"},
 		},
 		cfgOpts: []configOption{withPodSpecNodeSelectorEnabled()},
+	}, {
+		name: "ReadinessGates",
+		featureSpec: corev1.PodSpec{
+			ReadinessGates: []corev1.PodReadinessGate{{
+				ConditionType: "example.com/cache-warm",
+			}},
+		},
+		err: &apis.FieldError{
+			Message: "must not set the field(s)",
+			Paths:   []string{"readinessGates"},
+		},
+		cfgOpts: []configOption{withPodSpecReadinessGatesEnabled()},
 	}, {
 		name: "Tolerations",
 		featureSpec: corev1.PodSpec{
@@ -33,14 +33,27 @@ import (
 
 var (
 	allowedAnnotations = sets.NewString(
+		ChangeCauseAnnotationKey,
 		CreatorAnnotation,
+		DebugImageAnnotationKey,
+		DebugPodAnnotationKey,
+		DebugRequesterAnnotationKey,
 		ForceUpgradeAnnotationKey,
+		OverflowActivationTimeoutAnnotationKey,
+		OverflowTargetAnnotationKey,
+		ResponseHeaderPolicyAnnotationKey,
 		RevisionLastPinnedAnnotationKey,
 		RevisionPreservedAnnotationKey,
 		RolloutDurationKey,
+		RouteResponseHeaderPolicyAnnotationKey,
 		RoutesAnnotationKey,
 		RoutingStateModifiedAnnotationKey,
+		ServiceTemplateAnnotationKey,
+		ServiceTemplateGenerationAnnotationKey,
+		ServiceTemplateRolloutAnnotationKey,
 		UpdaterAnnotation,
+		WarmupRequestsAnnotationKey,
+		WarmupTimeoutAnnotationKey,
 	)
 )
 
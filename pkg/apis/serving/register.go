@@ -97,6 +97,11 @@ const (
 	// metadata generation of the Configuration that created this revision
 	ConfigurationGenerationLabelKey = GroupName + "/configurationGeneration"
 
+	// RevisionPreviewLabelKey is the label key a Namespace is set to
+	// RevisionPreviewEnabled with to opt in to previewing its Revisions by
+	// name, when the revision-preview feature is enabled.
+	RevisionPreviewLabelKey = GroupName + "/revision-preview"
+
 	// ForceUpgradeAnnotationKey is the annotation which was added to resources
 	// upgraded from v1alpha1.
 	// This annotation is no longer used since v1alpha1 was removed, but
@@ -109,6 +114,91 @@ const (
 	// UpdaterAnnotation is the annotation key to describe the user that
 	// last updated the resource.
 	UpdaterAnnotation = GroupName + "/lastModifier"
+	// ChangeCauseAnnotationKey is the annotation attached to a Revision to
+	// summarize how its template differs from the previous Revision of its
+	// Configuration, e.g. "image changed, env FOO added".
+	ChangeCauseAnnotationKey = GroupName + "/change-cause"
+
+	// DebugImageAnnotationKey is the annotation attached to a Revision to
+	// request an ephemeral debug container running the given image to be
+	// attached to the running pods of the Revision.
+	DebugImageAnnotationKey = GroupName + "/debug-image"
+
+	// DebugPodAnnotationKey is the annotation attached to a Revision to restrict
+	// the debug container to a single named pod of the Revision. When it is
+	// absent, the debug container is attached to all running pods.
+	DebugPodAnnotationKey = GroupName + "/debug-pod"
+
+	// DebugRequesterAnnotationKey is the annotation key describing the user that
+	// requested the debug container, as the JSON encoding of their UserInfo.
+	// It is maintained by the webhook and used to authorize the request before
+	// attaching the debug container.
+	DebugRequesterAnnotationKey = GroupName + "/debug-requester"
+
+	// OverflowTargetAnnotationKey is the annotation attached to a Revision
+	// naming where the activator forwards requests to when the Revision is at
+	// capacity. The value is either "revision:<name>", naming another Revision
+	// of the same Configuration, or "service:<name>", naming a Service in the
+	// same namespace.
+	OverflowTargetAnnotationKey = GroupName + "/overflow-target"
+
+	// OverflowActivationTimeoutAnnotationKey is the annotation attached to a
+	// Revision specifying how long the activator waits for capacity before
+	// forwarding a request to the overflow target. When it is absent, requests
+	// are only forwarded once the activator's request queue is full.
+	OverflowActivationTimeoutAnnotationKey = GroupName + "/overflow-activation-timeout"
+
+	// ResponseHeaderPolicyAnnotationKey is the annotation attached to a
+	// Revision holding a JSON encoded ResponseHeaderPolicy, which the
+	// queue-proxy applies to every response before it leaves the pod.
+	// It can also be attached to a Route, whose policy applies to the
+	// Revisions it routes to that don't declare their own.
+	ResponseHeaderPolicyAnnotationKey = GroupName + "/response-header-policy"
+
+	// RouteResponseHeaderPolicyAnnotationKey is the annotation attached by
+	// the system to a Revision holding the ResponseHeaderPolicy of the Route
+	// routing to it. When several Routes route to the Revision, the policy of
+	// the first of them by name is used.
+	RouteResponseHeaderPolicyAnnotationKey = GroupName + "/route-response-header-policy"
+
+	// WarmupRequestsAnnotationKey is the annotation attached to a Revision
+	// holding a JSON encoded list of WarmupRequests, which the queue-proxy
+	// sends to the user container before reporting the pod ready.
+	WarmupRequestsAnnotationKey = GroupName + "/warmup-requests"
+
+	// WarmupTimeoutAnnotationKey is the annotation attached to a Revision
+	// bounding how long the queue-proxy spends on its warm-up requests, e.g.
+	// "30s". Past it, the pod is reported ready regardless.
+	WarmupTimeoutAnnotationKey = GroupName + "/warmup-timeout"
+
+	// TagExpiryAnnotationPrefix is the prefix of the annotations attached
+	// to a Route or Service setting when a traffic tag expires, e.g.
+	// `tag-expiry.serving.knative.dev/pr-1234: "2021-06-01T00:00:00Z"`.
+	// The value is an RFC3339 time, past which the targets carrying the tag
+	// are removed from the traffic.
+	TagExpiryAnnotationPrefix = "tag-expiry." + GroupNamePrefix
+
+	// ServiceTemplateAnnotationKey is the annotation attached to a Service
+	// naming the ServiceTemplate, in the same namespace, that its
+	// spec.template is merged on top of. It is also attached to the
+	// Revisions built from the template.
+	ServiceTemplateAnnotationKey = GroupName + "/service-template"
+
+	// ServiceTemplateRolloutAnnotationKey is the annotation attached to a
+	// Service controlling whether changes to its ServiceTemplate roll out
+	// new Revisions. See ServiceTemplateRollout for the allowed values.
+	ServiceTemplateRolloutAnnotationKey = GroupName + "/service-template-rollout"
+
+	// ServiceTemplateGenerationAnnotationKey is the annotation attached to a
+	// Revision recording the generation of the ServiceTemplate it was
+	// built from.
+	ServiceTemplateGenerationAnnotationKey = GroupName + "/service-template-generation"
+
+	// MaxNonActiveRevisionsAnnotationKey is the annotation attached to a
+	// Namespace capping the number of non-active Revisions kept across all
+	// of its Configurations, or "disabled". It overrides the
+	// max-non-active-revisions-per-namespace setting of config-gc.
+	MaxNonActiveRevisionsAnnotationKey = GroupName + "/max-non-active-revisions"
 
 	// QueueSideCarResourcePercentageAnnotation is the percentage of user container resources to be used for queue-proxy
 	// It has to be in [0.1,100]
@@ -118,6 +208,24 @@ const (
 	// that will result to the Route/KService getting a cluster local
 	// domain suffix.
 	VisibilityClusterLocal = "cluster-local"
+
+	// RevisionPreviewEnabled is the value of RevisionPreviewLabelKey that
+	// opts a Namespace in to previewing its Revisions.
+	RevisionPreviewEnabled = "enabled"
+)
+
+// ServiceTemplateRollout is the value of the ServiceTemplateRolloutAnnotationKey
+// annotation.
+type ServiceTemplateRollout string
+
+const (
+	// ServiceTemplateRolloutAutomatic rolls out a new Revision whenever the
+	// ServiceTemplate changes. This is the default.
+	ServiceTemplateRolloutAutomatic ServiceTemplateRollout = "automatic"
+
+	// ServiceTemplateRolloutManual only picks up ServiceTemplate changes
+	// when the Service itself is updated.
+	ServiceTemplateRolloutManual ServiceTemplateRollout = "manual"
 )
 
 var (
@@ -18,6 +18,7 @@ package v1
 
 import (
 	"context"
+	"encoding/json"
 	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
@@ -27,10 +28,13 @@ import (
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/ptr"
 	"knative.dev/serving/pkg/apis/config"
+	"knative.dev/serving/pkg/apis/serving"
 )
 
 // SetDefaults implements apis.Defaultable
 func (r *Revision) SetDefaults(ctx context.Context) {
+	r.setDebugRequester(ctx)
+
 	// SetDefaults may update revision spec which is immutable.
 	// See: https://github.com/knative/serving/issues/8128 for details.
 	if apis.IsInUpdate(ctx) {
@@ -39,6 +43,45 @@ func (r *Revision) SetDefaults(ctx context.Context) {
 	r.Spec.SetDefaults(apis.WithinSpec(ctx))
 }
 
+// setDebugRequester records the identity of the user requesting a debug container for the
+// Revision, so that the reconciler can authorize the request. Users cannot
+// set the requester themselves; it is carried over from the previous version
+// of the Revision unless the debug request changes, and dropped when the
+// requesting user is unknown.
+func (r *Revision) setDebugRequester(ctx context.Context) {
+	anns := r.Annotations
+	if anns[serving.DebugImageAnnotationKey] == "" {
+		delete(anns, serving.DebugRequesterAnnotationKey)
+		return
+	}
+
+	var oldAnns map[string]string
+	if apis.IsInUpdate(ctx) {
+		if old, ok := apis.GetBaseline(ctx).(*Revision); ok {
+			oldAnns = old.Annotations
+		}
+	}
+	if oldAnns[serving.DebugImageAnnotationKey] == anns[serving.DebugImageAnnotationKey] &&
+		oldAnns[serving.DebugPodAnnotationKey] == anns[serving.DebugPodAnnotationKey] {
+		if requester, ok := oldAnns[serving.DebugRequesterAnnotationKey]; ok {
+			anns[serving.DebugRequesterAnnotationKey] = requester
+		} else {
+			delete(anns, serving.DebugRequesterAnnotationKey)
+		}
+		return
+	}
+
+	// The whole identity is recorded, since the requester may be authorized
+	// to debug only through one of their groups.
+	if ui := apis.GetUserInfo(ctx); ui != nil && ui.Username != "" {
+		if b, err := json.Marshal(ui); err == nil {
+			anns[serving.DebugRequesterAnnotationKey] = string(b)
+			return
+		}
+	}
+	delete(anns, serving.DebugRequesterAnnotationKey)
+}
+
 // SetDefaults implements apis.Defaultable
 func (rts *RevisionTemplateSpec) SetDefaults(ctx context.Context) {
 	rts.Spec.SetDefaults(apis.WithinSpec(ctx))
@@ -23,6 +23,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 
+	authv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -31,6 +32,7 @@ import (
 	logtesting "knative.dev/pkg/logging/testing"
 	"knative.dev/pkg/ptr"
 	"knative.dev/serving/pkg/apis/config"
+	"knative.dev/serving/pkg/apis/serving"
 	autoscalerconfig "knative.dev/serving/pkg/autoscaler/config"
 )
 
@@ -629,3 +631,114 @@ func TestRevisionDefaultingContainerName(t *testing.T) {
 		t.Errorf("Failed to set default values for container name")
 	}
 }
+
+func TestRevisionDebugRequesterDefaulting(t *testing.T) {
+	const (
+		u1 = "oveja@knative.dev"
+		u2 = "cabra@knative.dev"
+
+		// The requesters recorded for u1 and u2.
+		r1 = `{"username":"oveja@knative.dev","groups":["debuggers"]}`
+		r2 = `{"username":"cabra@knative.dev","groups":["debuggers"]}`
+	)
+	withDebugAnns := func(image, pod, requester string) map[string]string {
+		anns := map[string]string{serving.DebugImageAnnotationKey: image}
+		if pod != "" {
+			anns[serving.DebugPodAnnotationKey] = pod
+		}
+		if requester != "" {
+			anns[serving.DebugRequesterAnnotationKey] = requester
+		}
+		return anns
+	}
+	tests := []struct {
+		name     string
+		user     string
+		this     map[string]string
+		prev     map[string]string
+		wantAnns map[string]string
+	}{{
+		name:     "create with debug request",
+		user:     u1,
+		this:     withDebugAnns("busybox", "", ""),
+		wantAnns: withDebugAnns("busybox", "", r1),
+	}, {
+		name:     "create with spoofed requester",
+		user:     u1,
+		this:     withDebugAnns("busybox", "", u2),
+		wantAnns: withDebugAnns("busybox", "", r1),
+	}, {
+		name:     "add debug request",
+		user:     u2,
+		this:     withDebugAnns("busybox", "pod-1", ""),
+		prev:     map[string]string{},
+		wantAnns: withDebugAnns("busybox", "pod-1", r2),
+	}, {
+		name:     "unchanged debug request keeps requester",
+		user:     u2,
+		this:     withDebugAnns("busybox", "", ""),
+		prev:     withDebugAnns("busybox", "", r1),
+		wantAnns: withDebugAnns("busybox", "", r1),
+	}, {
+		name:     "unchanged debug request with spoofed requester",
+		user:     u2,
+		this:     withDebugAnns("busybox", "", u2),
+		prev:     withDebugAnns("busybox", "", r1),
+		wantAnns: withDebugAnns("busybox", "", r1),
+	}, {
+		name:     "changed debug image",
+		user:     u2,
+		this:     withDebugAnns("alpine", "", u1),
+		prev:     withDebugAnns("busybox", "", r1),
+		wantAnns: withDebugAnns("alpine", "", r2),
+	}, {
+		name:     "changed debug pod",
+		user:     u2,
+		this:     withDebugAnns("busybox", "pod-2", u1),
+		prev:     withDebugAnns("busybox", "pod-1", r1),
+		wantAnns: withDebugAnns("busybox", "pod-2", r2),
+	}, {
+		name: "removed debug request",
+		user: u2,
+		this: map[string]string{
+			serving.DebugRequesterAnnotationKey: u1,
+		},
+		prev:     withDebugAnns("busybox", "", r1),
+		wantAnns: map[string]string{},
+	}, {
+		name:     "unknown user with spoofed requester",
+		this:     withDebugAnns("busybox", "", u2),
+		wantAnns: withDebugAnns("busybox", "", ""),
+	}, {
+		name:     "unknown user with unchanged debug request",
+		this:     withDebugAnns("busybox", "", u2),
+		prev:     withDebugAnns("busybox", "", r1),
+		wantAnns: withDebugAnns("busybox", "", r1),
+	}, {
+		name:     "unknown user with changed debug request",
+		this:     withDebugAnns("alpine", "", u1),
+		prev:     withDebugAnns("busybox", "", r1),
+		wantAnns: withDebugAnns("alpine", "", ""),
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.user != "" {
+				ctx = apis.WithUserInfo(ctx, &authv1.UserInfo{
+					Username: test.user,
+					Groups:   []string{"debuggers"},
+				})
+			}
+			if test.prev != nil {
+				ctx = apis.WithinUpdate(ctx, &Revision{
+					ObjectMeta: metav1.ObjectMeta{Annotations: test.prev},
+				})
+			}
+			rev := &Revision{ObjectMeta: metav1.ObjectMeta{Annotations: test.this}}
+			rev.SetDefaults(ctx)
+			if got, want := rev.Annotations, test.wantAnns; !cmp.Equal(got, want) {
+				t.Errorf("Annotations = %v, want: %v, diff (-got, +want): %s", got, want, cmp.Diff(got, want))
+			}
+		})
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -68,6 +70,45 @@ const (
 	RoutingStateReserve RoutingState = "reserve"
 )
 
+// OverflowTargetKind is the kind of resource requests are forwarded to
+// when a revision is at capacity.
+type OverflowTargetKind string
+
+const (
+	// OverflowTargetRevision forwards requests to another Revision of the
+	// same Configuration.
+	OverflowTargetRevision OverflowTargetKind = "revision"
+
+	// OverflowTargetService forwards requests to a Service in the same namespace.
+	OverflowTargetService OverflowTargetKind = "service"
+)
+
+// OverflowTarget is the parsed form of the overflow target annotation.
+type OverflowTarget struct {
+	Kind OverflowTargetKind
+	Name string
+}
+
+// String implements fmt.Stringer.
+func (ot OverflowTarget) String() string {
+	return string(ot.Kind) + ":" + ot.Name
+}
+
+// ParseOverflowTarget parses the value of serving.OverflowTargetAnnotationKey.
+func ParseOverflowTarget(v string) (OverflowTarget, error) {
+	parts := strings.SplitN(v, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return OverflowTarget{}, fmt.Errorf("overflow target %q must have the form <kind>:<name>", v)
+	}
+	switch kind := OverflowTargetKind(parts[0]); kind {
+	case OverflowTargetRevision, OverflowTargetService:
+		return OverflowTarget{Kind: kind, Name: parts[1]}, nil
+	default:
+		return OverflowTarget{}, fmt.Errorf("overflow target kind %q must be one of %q or %q",
+			parts[0], OverflowTargetRevision, OverflowTargetService)
+	}
+}
+
 // GetContainer returns a pointer to the relevant corev1.Container field.
 // It is never nil and should be exactly the specified container if len(containers) == 1 or
 // if there are multiple containers it returns the container which has Ports
@@ -143,6 +184,34 @@ func (r *Revision) GetProtocol() net.ProtocolType {
 	return net.ProtocolHTTP1
 }
 
+// GetOverflowTarget returns the target requests are forwarded to when the
+// revision is at capacity, and whether one is configured.
+func (r *Revision) GetOverflowTarget() (OverflowTarget, bool) {
+	v, ok := r.Annotations[serving.OverflowTargetAnnotationKey]
+	if !ok {
+		return OverflowTarget{}, false
+	}
+	ot, err := ParseOverflowTarget(v)
+	if err != nil {
+		return OverflowTarget{}, false
+	}
+	return ot, true
+}
+
+// GetOverflowActivationTimeout returns how long requests wait for capacity
+// before being forwarded to the overflow target, and whether it is set.
+func (r *Revision) GetOverflowActivationTimeout() (time.Duration, bool) {
+	v, ok := r.Annotations[serving.OverflowActivationTimeoutAnnotationKey]
+	if !ok {
+		return 0, false
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, false
+	}
+	return d, true
+}
+
 // IsActivationRequired returns true if activation is required.
 func (rs *RevisionStatus) IsActivationRequired() bool {
 	c := revisionCondSet.Manage(rs).GetCondition(RevisionConditionActive)
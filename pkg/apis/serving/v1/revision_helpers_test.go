@@ -299,3 +299,50 @@ func TestSetRoutingState(t *testing.T) {
 		t.Errorf("expected default value for unparsable annotation.")
 	}
 }
+
+func TestRevisionGetOverflowTarget(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        OverflowTarget
+		wantOK      bool
+		wantTimeout time.Duration
+	}{{
+		name: "no annotations",
+	}, {
+		name: "revision target",
+		annotations: map[string]string{
+			serving.OverflowTargetAnnotationKey:            "revision:fallback",
+			serving.OverflowActivationTimeoutAnnotationKey: "2s",
+		},
+		want:        OverflowTarget{Kind: OverflowTargetRevision, Name: "fallback"},
+		wantOK:      true,
+		wantTimeout: 2 * time.Second,
+	}, {
+		name: "service target",
+		annotations: map[string]string{
+			serving.OverflowTargetAnnotationKey: "service:fallback",
+		},
+		want:   OverflowTarget{Kind: OverflowTargetService, Name: "fallback"},
+		wantOK: true,
+	}, {
+		name: "invalid target",
+		annotations: map[string]string{
+			serving.OverflowTargetAnnotationKey:            "fallback",
+			serving.OverflowActivationTimeoutAnnotationKey: "soon",
+		},
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rev := &Revision{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			got, ok := rev.GetOverflowTarget()
+			if got != tc.want || ok != tc.wantOK {
+				t.Errorf("GetOverflowTarget() = (%v, %t), want: (%v, %t)", got, ok, tc.want, tc.wantOK)
+			}
+			if got, _ := rev.GetOverflowActivationTimeout(); got != tc.wantTimeout {
+				t.Errorf("GetOverflowActivationTimeout() = %v, want: %v", got, tc.wantTimeout)
+			}
+		})
+	}
+}
@@ -49,6 +49,10 @@ const (
 	// ReasonProgressDeadlineExceeded defines the reason for marking revision availability
 	// status as false if progress has exceeded the deadline.
 	ReasonProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+
+	// ReasonReadinessGatePending defines the reason for marking revision availability
+	// status as unknown while its pods wait on a custom readiness gate.
+	ReasonReadinessGatePending = "ReadinessGatePending"
 )
 
 var revisionCondSet = apis.NewLivingConditionSet(
@@ -228,6 +232,12 @@ func ResourceNotOwnedMessage(kind, name string) string {
 	return fmt.Sprintf("There is an existing %s %q that we do not own.", kind, name)
 }
 
+// ReadinessGatePendingMessage constructs the status message if a pod is
+// waiting on a readiness gate condition to become True.
+func ReadinessGatePendingMessage(pod, gate string) string {
+	return fmt.Sprintf("Pod %q is waiting on readiness gate %q.", pod, gate)
+}
+
 // ExitCodeReason constructs the status message from an exit code
 func ExitCodeReason(exitCode int32) string {
 	return fmt.Sprint("ExitCode", exitCode)
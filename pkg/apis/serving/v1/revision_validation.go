@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/validation"
 	"knative.dev/pkg/apis"
@@ -33,11 +34,16 @@ import (
 // Validate ensures Revision is properly configured.
 func (r *Revision) Validate(ctx context.Context) *apis.FieldError {
 	errs := serving.ValidateObjectMetadata(ctx, r.GetObjectMeta()).Also(
-		r.ValidateLabels().ViaField("labels")).ViaField("metadata")
+		r.ValidateLabels().ViaField("labels")).Also(
+		r.validateDebugAnnotations(ctx).ViaField("annotations")).ViaField("metadata")
 	errs = errs.Also(r.Status.Validate(apis.WithinStatus(ctx)).ViaField("status"))
 
 	if apis.IsInUpdate(ctx) {
 		original := apis.GetBaseline(ctx).(*Revision)
+		if original.Annotations[serving.ChangeCauseAnnotationKey] != r.Annotations[serving.ChangeCauseAnnotationKey] {
+			errs = errs.Also(apis.ErrInvalidKeyName(serving.ChangeCauseAnnotationKey, "metadata.annotations",
+				"the annotation is reserved for the system"))
+		}
 		if diff, err := kmp.ShortDiff(original.Spec, r.Spec); err != nil {
 			return &apis.FieldError{
 				Message: "Failed to diff Revision",
@@ -60,14 +66,24 @@ func (r *Revision) Validate(ctx context.Context) *apis.FieldError {
 
 // Validate implements apis.Validatable
 func (rts *RevisionTemplateSpec) Validate(ctx context.Context) *apis.FieldError {
-	errs := rts.Spec.Validate(apis.WithinSpec(ctx)).ViaField("spec")
-	errs = errs.Also(autoscaling.ValidateAnnotations(ctx, config.FromContextOrDefaults(ctx).Autoscaler,
-		rts.GetAnnotations()).ViaField("metadata.annotations"))
+	return rts.Spec.Validate(apis.WithinSpec(ctx)).ViaField("spec").Also(
+		rts.validateMetadata(ctx))
+}
+
+// validateMetadata validates the metadata of the RevisionTemplateSpec.
+func (rts *RevisionTemplateSpec) validateMetadata(ctx context.Context) *apis.FieldError {
+	errs := autoscaling.ValidateAnnotations(ctx, config.FromContextOrDefaults(ctx).Autoscaler,
+		rts.GetAnnotations()).ViaField("metadata.annotations")
 
 	// If the RevisionTemplateSpec has a name specified, then check that
 	// it follows the requirements on the name.
 	errs = errs.Also(validateRevisionName(ctx, rts.Name, rts.GenerateName))
 	errs = errs.Also(validateQueueSidecarAnnotation(rts.Annotations).ViaField("metadata.annotations"))
+	errs = errs.Also(validateNoDebugAnnotations(rts.Annotations).ViaField("metadata.annotations"))
+	errs = errs.Also(validateNoSystemAnnotations(rts.Annotations).ViaField("metadata.annotations"))
+	errs = errs.Also(validateOverflowAnnotations(rts.Name, rts.Annotations).ViaField("metadata.annotations"))
+	errs = errs.Also(validateResponseHeaderPolicyAnnotation(rts.Annotations).ViaField("metadata.annotations"))
+	errs = errs.Also(validateWarmupAnnotations(rts.Annotations).ViaField("metadata.annotations"))
 	return errs
 }
 
@@ -191,6 +207,146 @@ func validateTimeoutSeconds(ctx context.Context, timeoutSeconds int64) *apis.Fie
 	return nil
 }
 
+// validateDebugAnnotations validates the annotations requesting a debug
+// container for the Revision. Unchanged requests are not revalidated, so that
+// Revisions can still be updated if the feature is disabled in the meantime.
+func (r *Revision) validateDebugAnnotations(ctx context.Context) (errs *apis.FieldError) {
+	annotations := r.GetAnnotations()
+	if apis.IsInUpdate(ctx) {
+		old := apis.GetBaseline(ctx).(*Revision).GetAnnotations()
+		if old[serving.DebugImageAnnotationKey] == annotations[serving.DebugImageAnnotationKey] &&
+			old[serving.DebugPodAnnotationKey] == annotations[serving.DebugPodAnnotationKey] {
+			return nil
+		}
+	}
+
+	image, hasImage := annotations[serving.DebugImageAnnotationKey]
+	if !hasImage {
+		if _, ok := annotations[serving.DebugPodAnnotationKey]; ok {
+			errs = errs.Also(&apis.FieldError{
+				Message: fmt.Sprintf("%s requires %s to be set", serving.DebugPodAnnotationKey, serving.DebugImageAnnotationKey),
+				Paths:   []string{serving.DebugPodAnnotationKey},
+			})
+		}
+		return errs
+	}
+	if config.FromContextOrDefaults(ctx).Features.DebugContainers != config.Enabled {
+		return errs.Also(&apis.FieldError{
+			Message: "debug containers are not enabled",
+			Paths:   []string{serving.DebugImageAnnotationKey},
+		})
+	}
+	if strings.TrimSpace(image) == "" {
+		errs = errs.Also(apis.ErrInvalidValue(image, serving.DebugImageAnnotationKey))
+	}
+	if pod, ok := annotations[serving.DebugPodAnnotationKey]; ok {
+		if msgs := validation.NameIsDNSSubdomain(pod, false); len(msgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(
+				fmt.Sprint("not a valid pod name: ", msgs), serving.DebugPodAnnotationKey))
+		}
+	}
+	return errs
+}
+
+// validateNoDebugAnnotations validates that a revision template does not
+// request debug containers, since that must be done on individual Revisions.
+func validateNoDebugAnnotations(annotations map[string]string) (errs *apis.FieldError) {
+	for _, key := range []string{
+		serving.DebugImageAnnotationKey,
+		serving.DebugPodAnnotationKey,
+		serving.DebugRequesterAnnotationKey,
+	} {
+		if _, ok := annotations[key]; ok {
+			errs = errs.Also(apis.ErrInvalidKeyName(key, apis.CurrentField,
+				"debug containers must be requested on the Revision"))
+		}
+	}
+	return errs
+}
+
+// validateNoSystemAnnotations validates that the annotations the system
+// records on the Revisions, like the change cause, aren't set by the users.
+func validateNoSystemAnnotations(annotations map[string]string) (errs *apis.FieldError) {
+	for _, key := range []string{
+		serving.ChangeCauseAnnotationKey,
+		serving.RouteResponseHeaderPolicyAnnotationKey,
+	} {
+		if _, ok := annotations[key]; ok {
+			errs = errs.Also(apis.ErrInvalidKeyName(key, apis.CurrentField,
+				"the annotation is reserved for the system"))
+		}
+	}
+	return errs
+}
+
+// validateOverflowAnnotations validates the overflow target and activation
+// timeout annotations of a revision template.
+func validateOverflowAnnotations(name string, annotations map[string]string) (errs *apis.FieldError) {
+	if v, ok := annotations[serving.OverflowTargetAnnotationKey]; ok {
+		ot, err := ParseOverflowTarget(v)
+		var details string
+		switch {
+		case err != nil:
+			details = err.Error()
+		case len(validation.NameIsDNS1035Label(ot.Name, false)) != 0:
+			details = strings.Join(validation.NameIsDNS1035Label(ot.Name, false), ", ")
+		case ot.Kind == OverflowTargetRevision && ot.Name == name:
+			details = "a revision cannot overflow to itself"
+		}
+		if details != "" {
+			fe := apis.ErrInvalidValue(v, apis.CurrentField).ViaKey(serving.OverflowTargetAnnotationKey)
+			fe.Details = details
+			errs = errs.Also(fe)
+		}
+	}
+	if v, ok := annotations[serving.OverflowActivationTimeoutAnnotationKey]; ok {
+		if _, ok := annotations[serving.OverflowTargetAnnotationKey]; !ok {
+			errs = errs.Also((&apis.FieldError{
+				Message: "overflow activation timeout requires an overflow target",
+				Paths:   []string{apis.CurrentField},
+			}).ViaKey(serving.OverflowActivationTimeoutAnnotationKey))
+		}
+		if d, err := time.ParseDuration(v); err != nil || d <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(v, apis.CurrentField).
+				ViaKey(serving.OverflowActivationTimeoutAnnotationKey))
+		}
+	}
+	return errs
+}
+
+// validateResponseHeaderPolicyAnnotation validates the response header
+// policy annotation of a revision template, Route or Service.
+func validateResponseHeaderPolicyAnnotation(annotations map[string]string) *apis.FieldError {
+	v, ok := annotations[serving.ResponseHeaderPolicyAnnotationKey]
+	if !ok {
+		return nil
+	}
+	if _, err := serving.ParseResponseHeaderPolicy(v); err != nil {
+		fe := apis.ErrInvalidValue(v, apis.CurrentField).ViaKey(serving.ResponseHeaderPolicyAnnotationKey)
+		fe.Details = err.Error()
+		return fe
+	}
+	return nil
+}
+
+// validateWarmupAnnotations validates the warm-up annotations of a revision
+// template.
+func validateWarmupAnnotations(annotations map[string]string) (errs *apis.FieldError) {
+	if v, ok := annotations[serving.WarmupRequestsAnnotationKey]; ok {
+		if _, err := serving.ParseWarmupRequests(v); err != nil {
+			fe := apis.ErrInvalidValue(v, apis.CurrentField).ViaKey(serving.WarmupRequestsAnnotationKey)
+			fe.Details = err.Error()
+			errs = errs.Also(fe)
+		}
+	}
+	if v, ok := annotations[serving.WarmupTimeoutAnnotationKey]; ok {
+		if _, err := serving.ParseWarmupTimeout(v); err != nil || v == "" {
+			errs = errs.Also(apis.ErrInvalidValue(v, apis.CurrentField).ViaKey(serving.WarmupTimeoutAnnotationKey))
+		}
+	}
+	return errs
+}
+
 // validateQueueSidecarAnnotation validates QueueSideCarResourcePercentageAnnotation
 func validateQueueSidecarAnnotation(annotations map[string]string) *apis.FieldError {
 	if len(annotations) == 0 {
@@ -34,6 +34,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -480,6 +481,102 @@ func TestRevisionSpecValidation(t *testing.T) {
 	}
 }
 
+func TestRevisionDebugAnnotationValidation(t *testing.T) {
+	validRevisionSpec := RevisionSpec{
+		PodSpec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Image: "busybox",
+			}},
+		},
+	}
+	debugEnabled := func(ctx context.Context) context.Context {
+		return config.ToContext(ctx, &config.Config{
+			Features: &config.Features{DebugContainers: config.Enabled},
+		})
+	}
+	tests := []struct {
+		name string
+		anns map[string]string
+		old  map[string]string
+		wc   func(context.Context) context.Context
+		want *apis.FieldError
+	}{{
+		name: "debug image",
+		anns: map[string]string{
+			serving.DebugImageAnnotationKey:     "busybox",
+			serving.DebugPodAnnotationKey:       "pod-1",
+			serving.DebugRequesterAnnotationKey: "oveja@knative.dev",
+		},
+		wc: debugEnabled,
+	}, {
+		name: "debug feature disabled",
+		anns: map[string]string{
+			serving.DebugImageAnnotationKey: "busybox",
+		},
+		want: &apis.FieldError{
+			Message: "debug containers are not enabled",
+			Paths:   []string{"metadata.annotations." + serving.DebugImageAnnotationKey},
+		},
+	}, {
+		name: "unchanged debug request with feature disabled",
+		anns: map[string]string{
+			serving.DebugImageAnnotationKey: "busybox",
+		},
+		old: map[string]string{
+			serving.DebugImageAnnotationKey: "busybox",
+		},
+	}, {
+		name: "empty debug image",
+		anns: map[string]string{
+			serving.DebugImageAnnotationKey: " ",
+		},
+		wc:   debugEnabled,
+		want: apis.ErrInvalidValue(" ", "metadata.annotations."+serving.DebugImageAnnotationKey),
+	}, {
+		name: "debug pod without image",
+		anns: map[string]string{
+			serving.DebugPodAnnotationKey: "pod-1",
+		},
+		wc: debugEnabled,
+		want: &apis.FieldError{
+			Message: serving.DebugPodAnnotationKey + " requires " + serving.DebugImageAnnotationKey + " to be set",
+			Paths:   []string{"metadata.annotations." + serving.DebugPodAnnotationKey},
+		},
+	}, {
+		name: "invalid debug pod",
+		anns: map[string]string{
+			serving.DebugImageAnnotationKey: "busybox",
+			serving.DebugPodAnnotationKey:   "Not_A_Pod",
+		},
+		wc: debugEnabled,
+		want: apis.ErrInvalidValue(
+			fmt.Sprint("not a valid pod name: ", validation.NameIsDNSSubdomain("Not_A_Pod", false)),
+			"metadata.annotations."+serving.DebugPodAnnotationKey),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.wc != nil {
+				ctx = test.wc(ctx)
+			}
+			if test.old != nil {
+				ctx = apis.WithinUpdate(ctx, &Revision{
+					ObjectMeta: metav1.ObjectMeta{Name: "valid", Annotations: test.old},
+					Spec:       validRevisionSpec,
+				})
+			}
+			rev := &Revision{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid", Annotations: test.anns},
+				Spec:       validRevisionSpec,
+			}
+			if got, want := rev.Validate(ctx).Error(), test.want.Error(); got != want {
+				t.Errorf("Validate (-want, +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
 func TestImmutableFields(t *testing.T) {
 	tests := []struct {
 		name string
@@ -560,6 +657,40 @@ func TestImmutableFields(t *testing.T) {
 			return s.ToContext(ctx)
 		},
 		want: nil,
+	}, {
+		name: "bad (change cause modified)",
+		new: &Revision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Annotations: map[string]string{
+					serving.ChangeCauseAnnotationKey: "nothing changed",
+				},
+			},
+			Spec: RevisionSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Image: "helloworld",
+					}},
+				},
+			},
+		},
+		old: &Revision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Annotations: map[string]string{
+					serving.ChangeCauseAnnotationKey: "image changed",
+				},
+			},
+			Spec: RevisionSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Image: "helloworld",
+					}},
+				},
+			},
+		},
+		want: apis.ErrInvalidKeyName(serving.ChangeCauseAnnotationKey, "metadata.annotations",
+			"the annotation is reserved for the system"),
 	}, {
 		name: "bad (resources image change)",
 		new: &Revision{
@@ -961,6 +1092,201 @@ func TestRevisionTemplateSpecValidation(t *testing.T) {
 			},
 		},
 		want: nil,
+	}, {
+		name: "change cause on template",
+		rts: &RevisionTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					serving.ChangeCauseAnnotationKey: "image changed",
+				},
+			},
+			Spec: RevisionSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Image: "helloworld",
+					}},
+				},
+			},
+		},
+		want: apis.ErrInvalidKeyName(serving.ChangeCauseAnnotationKey, "metadata.annotations",
+			"the annotation is reserved for the system"),
+	}, {
+		name: "debug annotation on template",
+		rts: &RevisionTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					serving.DebugImageAnnotationKey: "busybox",
+				},
+			},
+			Spec: RevisionSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Image: "helloworld",
+					}},
+				},
+			},
+		},
+		want: apis.ErrInvalidKeyName(serving.DebugImageAnnotationKey, "metadata.annotations",
+			"debug containers must be requested on the Revision"),
+	}, {
+		name: "valid overflow target",
+		rts: &RevisionTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					serving.OverflowTargetAnnotationKey:            "service:fallback",
+					serving.OverflowActivationTimeoutAnnotationKey: "5s",
+				},
+			},
+			Spec: RevisionSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Image: "helloworld",
+					}},
+				},
+			},
+		},
+		want: nil,
+	}, {
+		name: "invalid overflow target kind",
+		rts: &RevisionTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					serving.OverflowTargetAnnotationKey: "route:fallback",
+				},
+			},
+			Spec: RevisionSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Image: "helloworld",
+					}},
+				},
+			},
+		},
+		want: (&apis.FieldError{
+			Message: "invalid value: route:fallback",
+			Paths:   []string{"metadata.annotations.[serving.knative.dev/overflow-target]"},
+			Details: `overflow target kind "route" must be one of "revision" or "service"`,
+		}),
+	}, {
+		name: "overflow target to itself",
+		rts: &RevisionTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "parent-foo",
+				Annotations: map[string]string{
+					serving.OverflowTargetAnnotationKey: "revision:parent-foo",
+				},
+			},
+			Spec: RevisionSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Image: "helloworld",
+					}},
+				},
+			},
+		},
+		want: (&apis.FieldError{
+			Message: "invalid value: revision:parent-foo",
+			Paths:   []string{"metadata.annotations.[serving.knative.dev/overflow-target]"},
+			Details: "a revision cannot overflow to itself",
+		}),
+	}, {
+		name: "overflow activation timeout without target",
+		rts: &RevisionTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					serving.OverflowActivationTimeoutAnnotationKey: "-1s",
+				},
+			},
+			Spec: RevisionSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Image: "helloworld",
+					}},
+				},
+			},
+		},
+		want: (&apis.FieldError{
+			Message: "overflow activation timeout requires an overflow target",
+			Paths:   []string{"metadata.annotations.[serving.knative.dev/overflow-activation-timeout]"},
+		}).Also(apis.ErrInvalidValue("-1s", "metadata.annotations.[serving.knative.dev/overflow-activation-timeout]")),
+	}, {
+		name: "valid response header policy",
+		rts: &RevisionTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					serving.ResponseHeaderPolicyAnnotationKey: `{"cors":{"allowOrigins":["https://example.com"]},"hsts":{"maxAgeSeconds":31536000}}`,
+				},
+			},
+			Spec: RevisionSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Image: "helloworld",
+					}},
+				},
+			},
+		},
+		want: nil,
+	}, {
+		name: "invalid response header policy",
+		rts: &RevisionTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					serving.ResponseHeaderPolicyAnnotationKey: `{"cors":{"allowOrigins":["*"],"allowCredentials":true}}`,
+				},
+			},
+			Spec: RevisionSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Image: "helloworld",
+					}},
+				},
+			},
+		},
+		want: (&apis.FieldError{
+			Message: `invalid value: {"cors":{"allowOrigins":["*"],"allowCredentials":true}}`,
+			Paths:   []string{"metadata.annotations.[serving.knative.dev/response-header-policy]"},
+			Details: `cors.allowOrigins must not contain "*" when cors.allowCredentials is set`,
+		}),
+	}, {
+		name: "valid warm-up requests",
+		rts: &RevisionTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					serving.WarmupRequestsAnnotationKey: `[{"method":"POST","path":"/predict","body":"{}","count":10}]`,
+					serving.WarmupTimeoutAnnotationKey:  "30s",
+				},
+			},
+			Spec: RevisionSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Image: "helloworld",
+					}},
+				},
+			},
+		},
+		want: nil,
+	}, {
+		name: "invalid warm-up requests",
+		rts: &RevisionTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					serving.WarmupRequestsAnnotationKey: `[{"path":"predict"}]`,
+					serving.WarmupTimeoutAnnotationKey:  "0s",
+				},
+			},
+			Spec: RevisionSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Image: "helloworld",
+					}},
+				},
+			},
+		},
+		want: (&apis.FieldError{
+			Message: `invalid value: [{"path":"predict"}]`,
+			Paths:   []string{"metadata.annotations.[serving.knative.dev/warmup-requests]"},
+			Details: `[0].path "predict" must start with "/"`,
+		}).Also(apis.ErrInvalidValue("0s", "metadata.annotations.[serving.knative.dev/warmup-timeout]")),
 	}}
 
 	for _, test := range tests {
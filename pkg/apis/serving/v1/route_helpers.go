@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/serving/pkg/apis/serving"
+)
+
+// TagExpiries returns the expiry times of the traffic tags set through the
+// serving.TagExpiryAnnotationPrefix annotations, keyed by tag. Invalid times
+// are skipped.
+func TagExpiries(annotations map[string]string) map[string]time.Time {
+	var expiries map[string]time.Time
+	for k, v := range annotations {
+		if !strings.HasPrefix(k, serving.TagExpiryAnnotationPrefix) {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			continue
+		}
+		if expiries == nil {
+			expiries = make(map[string]time.Time, 1)
+		}
+		expiries[strings.TrimPrefix(k, serving.TagExpiryAnnotationPrefix)] = t
+	}
+	return expiries
+}
+
+// ExpiredTags returns the tags of `traffic` that expired at `now`, along
+// with the time until the next of its tags expires, zero if none does.
+func ExpiredTags(annotations map[string]string, traffic []TrafficTarget, now time.Time) (sets.String, time.Duration) {
+	expiries := TagExpiries(annotations)
+	expired := sets.NewString()
+	var next time.Duration
+	for _, tt := range traffic {
+		t, ok := expiries[tt.Tag]
+		if tt.Tag == "" || !ok {
+			continue
+		}
+		if d := t.Sub(now); d <= 0 {
+			expired.Insert(tt.Tag)
+		} else if next == 0 || d < next {
+			next = d
+		}
+	}
+	return expired, next
+}
+
+// WithoutTags returns `traffic` without the targets carrying one of `tags`.
+func WithoutTags(traffic []TrafficTarget, tags sets.String) []TrafficTarget {
+	out := make([]TrafficTarget, 0, len(traffic))
+	for _, tt := range traffic {
+		if tt.Tag == "" || !tags.Has(tt.Tag) {
+			out = append(out, tt)
+		}
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/serving/pkg/apis/serving"
+)
+
+func TestExpiredTags(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	annotations := map[string]string{
+		serving.TagExpiryAnnotationPrefix + "old":     "2021-06-01T11:00:00Z",
+		serving.TagExpiryAnnotationPrefix + "now":     "2021-06-01T12:00:00Z",
+		serving.TagExpiryAnnotationPrefix + "soon":    "2021-06-01T12:05:00Z",
+		serving.TagExpiryAnnotationPrefix + "later":   "2021-06-01T13:00:00Z",
+		serving.TagExpiryAnnotationPrefix + "bogus":   "yesterday",
+		serving.TagExpiryAnnotationPrefix + "missing": "2021-06-01T11:00:00Z",
+		"some.other/annotation":                       "2021-06-01T11:00:00Z",
+	}
+	traffic := []TrafficTarget{{
+		RevisionName: "a",
+	}, {
+		Tag:          "old",
+		RevisionName: "b",
+	}, {
+		Tag:          "now",
+		RevisionName: "c",
+	}, {
+		Tag:          "soon",
+		RevisionName: "d",
+	}, {
+		Tag:          "later",
+		RevisionName: "e",
+	}, {
+		Tag:          "bogus",
+		RevisionName: "f",
+	}}
+
+	expired, next := ExpiredTags(annotations, traffic, now)
+	if want := sets.NewString("old", "now"); !expired.Equal(want) {
+		t.Errorf("ExpiredTags() = %v, want: %v", expired.List(), want.List())
+	}
+	if want := 5 * time.Minute; next != want {
+		t.Errorf("ExpiredTags() next = %v, want: %v", next, want)
+	}
+
+	got := WithoutTags(traffic, expired)
+	want := []TrafficTarget{traffic[0], traffic[3], traffic[4], traffic[5]}
+	if !cmp.Equal(got, want) {
+		t.Error("WithoutTags (-want, +got):", cmp.Diff(want, got))
+	}
+}
+
+func TestExpiredTagsNone(t *testing.T) {
+	traffic := []TrafficTarget{{
+		Tag:          "foo",
+		RevisionName: "a",
+	}}
+	expired, next := ExpiredTags(nil, traffic, time.Now())
+	if expired.Len() != 0 || next != 0 {
+		t.Errorf("ExpiredTags() = %v, %v, want nothing", expired.List(), next)
+	}
+}
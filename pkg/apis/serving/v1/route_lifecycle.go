@@ -142,6 +142,14 @@ func (rs *RouteStatus) MarkRevisionFailed(name string) {
 		"Revision %q failed to become ready.", name)
 }
 
+// MarkNoMatchingRevision marks the RouteConditionAllTrafficAssigned condition
+// to indicate that no ready Revision of the Configuration matches the selector.
+func (rs *RouteStatus) MarkNoMatchingRevision(config, selector string) {
+	routeCondSet.Manage(rs).MarkUnknown(RouteConditionAllTrafficAssigned,
+		"RevisionMissing",
+		"Configuration %q has no ready Revision matching %q.", config, selector)
+}
+
 // MarkMissingTrafficTarget marks the RouteConditionAllTrafficAssigned
 // condition to indicate a reference traffic target was not found.
 func (rs *RouteStatus) MarkMissingTrafficTarget(kind, name string) {
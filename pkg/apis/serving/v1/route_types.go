@@ -92,6 +92,14 @@ type TrafficTarget struct {
 	// +optional
 	LatestRevision *bool `json:"latestRevision,omitempty"`
 
+	// RevisionSelector may be optionally provided along with a Configuration
+	// to send this portion of traffic to the newest ready Revision of that
+	// Configuration whose labels match the selector, rather than to its latest
+	// ready Revision. This field is never set in Route's status, only its spec.
+	// This is mutually exclusive with RevisionName.
+	// +optional
+	RevisionSelector *metav1.LabelSelector `json:"revisionSelector,omitempty"`
+
 	// Percent indicates that percentage based routing should be used and
 	// the value indicates the percent of traffic that is be routed to this
 	// Revision or Configuration. `0` (zero) mean no traffic, `100` means all
@@ -108,6 +116,44 @@ type TrafficTarget struct {
 	// a hostname, but may not contain anything else (e.g. basic auth, url path, etc.)
 	// +optional
 	URL *apis.URL `json:"url,omitempty"`
+
+	// Headers may be optionally provided to set or remove headers of the
+	// requests sent to this target. When several targets end up on the same
+	// Revision, the options of the first one apply to the shared traffic.
+	// This field is never set in Route's status, only its spec.
+	// +optional
+	Headers *RequestHeaders `json:"headers,omitempty"`
+
+	// PathPrefixRewrite may be optionally provided to rewrite the path prefix
+	// of the requests sent to this target. The same caveat as for Headers
+	// applies. This field is never set in Route's status, only its spec.
+	// +optional
+	PathPrefixRewrite *PathPrefixRewrite `json:"pathPrefixRewrite,omitempty"`
+}
+
+// RequestHeaders declares the headers added to, or stripped from, the
+// requests sent to a traffic target.
+type RequestHeaders struct {
+	// Set holds request headers that are added, replacing any value sent
+	// by the client.
+	// +optional
+	Set map[string]string `json:"set,omitempty"`
+
+	// Remove holds request headers that are stripped from the request.
+	// +optional
+	Remove []string `json:"remove,omitempty"`
+}
+
+// PathPrefixRewrite declares how the path prefix of the requests sent to a
+// traffic target is rewritten.
+type PathPrefixRewrite struct {
+	// Prefix is the path prefix that is replaced. Requests whose path does
+	// not start with it are left untouched. Defaults to "/".
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// Replacement is what Prefix is replaced with.
+	Replacement string `json:"replacement"`
 }
 
 // RouteSpec holds the desired state of the Route (from the client).
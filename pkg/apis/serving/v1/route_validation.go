@@ -19,8 +19,12 @@ package v1
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	network "knative.dev/networking/pkg"
 	"knative.dev/pkg/apis"
@@ -33,8 +37,11 @@ func (r *Route) Validate(ctx context.Context) *apis.FieldError {
 		r.validateLabels().ViaField("labels"))
 	errs = errs.Also(serving.ValidateRolloutDurationAnnotation(
 		r.GetAnnotations()).ViaField("annotations"))
+	errs = errs.Also(validateResponseHeaderPolicyAnnotation(
+		r.GetAnnotations()).ViaField("annotations"))
 	errs = errs.ViaField("metadata")
 	errs = errs.Also(r.Spec.Validate(apis.WithinSpec(ctx)).ViaField("spec"))
+	errs = errs.Also(validateTagExpiries(r.GetAnnotations(), r.Spec.Traffic))
 
 	if apis.IsInUpdate(ctx) {
 		original := apis.GetBaseline(ctx).(*Route)
@@ -48,6 +55,37 @@ func (r *Route) Validate(ctx context.Context) *apis.FieldError {
 	return errs
 }
 
+// validateTagExpiries checks that the tag expiry annotations hold valid times
+// and that the targets whose tags expire carry no traffic, so that dropping
+// them never shifts traffic between revisions.
+func validateTagExpiries(annotations map[string]string, traffic []TrafficTarget) *apis.FieldError {
+	var errs *apis.FieldError
+	expiring := sets.NewString()
+	for k, v := range annotations {
+		if !strings.HasPrefix(k, serving.TagExpiryAnnotationPrefix) {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339, v); err != nil {
+			errs = errs.Also(&apis.FieldError{
+				Message: fmt.Sprintf("invalid value: %s", v),
+				Paths:   []string{k},
+				Details: "expected an RFC3339 time",
+			})
+		}
+		expiring.Insert(strings.TrimPrefix(k, serving.TagExpiryAnnotationPrefix))
+	}
+	errs = errs.ViaField("metadata", "annotations")
+
+	for i, tt := range traffic {
+		if tt.Tag != "" && expiring.Has(tt.Tag) && tt.Percent != nil && *tt.Percent > 0 {
+			errs = errs.Also(apis.ErrGeneric(
+				fmt.Sprintf("expiring tag %q must carry 0%% of the traffic", tt.Tag),
+				"percent").ViaIndex(i).ViaField("spec", "traffic"))
+		}
+	}
+	return errs
+}
+
 func validateTrafficList(ctx context.Context, traffic []TrafficTarget) *apis.FieldError {
 	var errs *apis.FieldError
 
@@ -104,6 +142,8 @@ func (tt *TrafficTarget) Validate(ctx context.Context) *apis.FieldError {
 	errs := tt.validateLatestRevision(ctx)
 	errs = tt.validateRevisionAndConfiguration(ctx, errs)
 	errs = tt.validateTrafficPercentage(errs)
+	errs = tt.validateRevisionSelector(ctx, errs)
+	errs = tt.validateRewrites(ctx, errs)
 	return tt.validateURL(ctx, errs)
 }
 
@@ -173,6 +213,37 @@ func (tt *TrafficTarget) validateLatestRevision(ctx context.Context) *apis.Field
 	return nil
 }
 
+func (tt *TrafficTarget) validateRevisionSelector(ctx context.Context, errs *apis.FieldError) *apis.FieldError {
+	if tt.RevisionSelector == nil {
+		return errs
+	}
+	switch {
+	// Like configurationName, the selector is resolved to a revisionName
+	// in status.
+	case apis.IsInStatus(ctx):
+		errs = errs.Also(apis.ErrDisallowedFields("revisionSelector"))
+
+	// A selector picks among the revisions of a configuration, so it
+	// cannot be combined with a particular revision.
+	case tt.RevisionName != "":
+		errs = errs.Also(apis.ErrMultipleOneOf("revisionName", "revisionSelector"))
+
+	case len(tt.RevisionSelector.MatchLabels) == 0 && len(tt.RevisionSelector.MatchExpressions) == 0:
+		errs = errs.Also(apis.ErrMissingOneOf(
+			"revisionSelector.matchLabels", "revisionSelector.matchExpressions"))
+
+	default:
+		if _, err := metav1.LabelSelectorAsSelector(tt.RevisionSelector); err != nil {
+			errs = errs.Also(&apis.FieldError{
+				Message: "invalid revision selector",
+				Paths:   []string{"revisionSelector"},
+				Details: err.Error(),
+			})
+		}
+	}
+	return errs
+}
+
 func (tt *TrafficTarget) validateURL(ctx context.Context, errs *apis.FieldError) *apis.FieldError {
 	// Check that we set the URL appropriately.
 	if tt.URL.String() != "" {
@@ -194,6 +265,78 @@ func (tt *TrafficTarget) validateURL(ctx context.Context, errs *apis.FieldError)
 	return errs
 }
 
+func (tt *TrafficTarget) validateRewrites(ctx context.Context, errs *apis.FieldError) *apis.FieldError {
+	// Rewrites are not reported in status.
+	if apis.IsInStatus(ctx) {
+		if tt.Headers != nil {
+			errs = errs.Also(apis.ErrDisallowedFields("headers"))
+		}
+		if tt.PathPrefixRewrite != nil {
+			errs = errs.Also(apis.ErrDisallowedFields("pathPrefixRewrite"))
+		}
+		return errs
+	}
+	if tt.Headers != nil {
+		errs = errs.Also(tt.Headers.validate().ViaField("headers"))
+	}
+	if tt.PathPrefixRewrite != nil {
+		errs = errs.Also(tt.PathPrefixRewrite.validate().ViaField("pathPrefixRewrite"))
+	}
+	return errs
+}
+
+// reservedRequestHeader returns whether the header is used by Knative
+// itself to route and account for requests, or by the ingress to pick
+// the target.
+func reservedRequestHeader(name string) bool {
+	name = http.CanonicalHeaderKey(name)
+	return name == "Host" ||
+		strings.HasPrefix(name, "Knative-Serving-") ||
+		strings.HasPrefix(name, "K-")
+}
+
+func (rh *RequestHeaders) validate() (errs *apis.FieldError) {
+	for name, value := range rh.Set {
+		switch {
+		case !serving.ValidHeaderName(name):
+			errs = errs.Also(apis.ErrInvalidKeyName(name, "set", "not a valid header name"))
+		case reservedRequestHeader(name):
+			errs = errs.Also(apis.ErrInvalidKeyName(name, "set", "header is reserved"))
+		case !serving.ValidHeaderValue(value):
+			errs = errs.Also(apis.ErrInvalidValue(value, apis.CurrentField).ViaKey(name).ViaField("set"))
+		}
+	}
+	for i, name := range rh.Remove {
+		switch {
+		case !serving.ValidHeaderName(name):
+			errs = errs.Also(apis.ErrInvalidArrayValue(name, "remove", i))
+		case reservedRequestHeader(name):
+			errs = errs.Also(apis.ErrInvalidArrayValue(name, "remove", i))
+		}
+		for set := range rh.Set {
+			if strings.EqualFold(set, name) {
+				errs = errs.Also(&apis.FieldError{
+					Message: fmt.Sprintf("Header %q is both set and removed", name),
+					Paths:   []string{"set", fmt.Sprintf("remove[%d]", i)},
+				})
+			}
+		}
+	}
+	return errs
+}
+
+func (pr *PathPrefixRewrite) validate() (errs *apis.FieldError) {
+	if pr.Prefix != "" && !strings.HasPrefix(pr.Prefix, "/") {
+		errs = errs.Also(apis.ErrInvalidValue(pr.Prefix, "prefix"))
+	}
+	if pr.Replacement == "" {
+		errs = errs.Also(apis.ErrMissingField("replacement"))
+	} else if !strings.HasPrefix(pr.Replacement, "/") {
+		errs = errs.Also(apis.ErrInvalidValue(pr.Replacement, "replacement"))
+	}
+	return errs
+}
+
 func validateClusterVisibilityLabel(label string) *apis.FieldError {
 	if label != serving.VisibilityClusterLocal {
 		return apis.ErrInvalidValue(label, network.VisibilityLabelKey)
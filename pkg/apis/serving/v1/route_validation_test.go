@@ -42,6 +42,138 @@ func TestTrafficTargetValidation(t *testing.T) {
 			Percent:      ptr.Int64(12),
 		},
 		wc: apis.WithinSpec,
+	}, {
+		name: "valid with configurationName and revisionSelector",
+		tt: &TrafficTarget{
+			ConfigurationName: "bar",
+			RevisionSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"release": "stable"},
+			},
+			Percent: ptr.Int64(12),
+		},
+		wc: apis.WithinSpec,
+	}, {
+		name: "valid with headers and pathPrefixRewrite",
+		tt: &TrafficTarget{
+			RevisionName: "bar",
+			Percent:      ptr.Int64(12),
+			Headers: &RequestHeaders{
+				Set:    map[string]string{"X-Api-Version": "2"},
+				Remove: []string{"Cookie"},
+			},
+			PathPrefixRewrite: &PathPrefixRewrite{
+				Prefix:      "/v2",
+				Replacement: "/",
+			},
+		},
+		wc: apis.WithinSpec,
+	}, {
+		name: "invalid headers",
+		tt: &TrafficTarget{
+			RevisionName: "bar",
+			Percent:      ptr.Int64(12),
+			Headers: &RequestHeaders{
+				Set: map[string]string{
+					"Knative-Serving-Revision": "baz",
+					"X Bad":                    "1",
+					"X-Api-Version":            "a\nb",
+				},
+				Remove: []string{"Host", "X-Api-Version"},
+			},
+		},
+		wc: apis.WithinSpec,
+		want: apis.ErrInvalidKeyName("Knative-Serving-Revision", "headers.set", "header is reserved").Also(
+			apis.ErrInvalidKeyName("X Bad", "headers.set", "not a valid header name"),
+			apis.ErrInvalidValue("a\nb", "headers.set[X-Api-Version]"),
+			apis.ErrInvalidArrayValue("Host", "headers.remove", 0),
+			&apis.FieldError{
+				Message: `Header "X-Api-Version" is both set and removed`,
+				Paths:   []string{"headers.set", "headers.remove[1]"},
+			}),
+	}, {
+		name: "invalid pathPrefixRewrite",
+		tt: &TrafficTarget{
+			RevisionName: "bar",
+			Percent:      ptr.Int64(12),
+			PathPrefixRewrite: &PathPrefixRewrite{
+				Prefix:      "v2",
+				Replacement: "v1",
+			},
+		},
+		wc: apis.WithinSpec,
+		want: apis.ErrInvalidValue("v2", "pathPrefixRewrite.prefix").Also(
+			apis.ErrInvalidValue("v1", "pathPrefixRewrite.replacement")),
+	}, {
+		name: "missing pathPrefixRewrite replacement",
+		tt: &TrafficTarget{
+			RevisionName:      "bar",
+			Percent:           ptr.Int64(12),
+			PathPrefixRewrite: &PathPrefixRewrite{Prefix: "/v2"},
+		},
+		wc:   apis.WithinSpec,
+		want: apis.ErrMissingField("pathPrefixRewrite.replacement"),
+	}, {
+		name: "rewrites disallowed in status",
+		tt: &TrafficTarget{
+			RevisionName: "bar",
+			Percent:      ptr.Int64(12),
+			Headers: &RequestHeaders{
+				Remove: []string{"Cookie"},
+			},
+			PathPrefixRewrite: &PathPrefixRewrite{Replacement: "/"},
+		},
+		wc:   apis.WithinStatus,
+		want: apis.ErrDisallowedFields("headers", "pathPrefixRewrite"),
+	}, {
+		name: "invalid with revisionName and revisionSelector",
+		tt: &TrafficTarget{
+			RevisionName: "bar",
+			RevisionSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"release": "stable"},
+			},
+			Percent: ptr.Int64(12),
+		},
+		wc:   apis.WithinSpec,
+		want: apis.ErrMultipleOneOf("revisionName", "revisionSelector"),
+	}, {
+		name: "invalid with empty revisionSelector",
+		tt: &TrafficTarget{
+			ConfigurationName: "bar",
+			RevisionSelector:  &metav1.LabelSelector{},
+			Percent:           ptr.Int64(12),
+		},
+		wc: apis.WithinSpec,
+		want: apis.ErrMissingOneOf(
+			"revisionSelector.matchLabels", "revisionSelector.matchExpressions"),
+	}, {
+		name: "invalid revisionSelector operator",
+		tt: &TrafficTarget{
+			ConfigurationName: "bar",
+			RevisionSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      "release",
+					Operator: "Near",
+				}},
+			},
+			Percent: ptr.Int64(12),
+		},
+		wc: apis.WithinSpec,
+		want: &apis.FieldError{
+			Message: "invalid revision selector",
+			Paths:   []string{"revisionSelector"},
+			Details: `"Near" is not a valid pod selector operator`,
+		},
+	}, {
+		name: "invalid revisionSelector in status",
+		tt: &TrafficTarget{
+			RevisionName: "bar",
+			RevisionSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"release": "stable"},
+			},
+			Percent: ptr.Int64(12),
+		},
+		wc:   apis.WithinStatus,
+		want: apis.ErrDisallowedFields("revisionSelector"),
 	}, {
 		name: "valid with revisionName and name (spec)",
 		tt: &TrafficTarget{
@@ -455,6 +587,69 @@ func TestRouteValidation(t *testing.T) {
 			Message: "invalid value: not a DNS 1035 label: [a DNS-1035 label must consist of lower case alphanumeric characters or '-', start with an alphabetic character, and end with an alphanumeric character (e.g. 'my-name',  or 'abc-123', regex used for validation is '[a-z]([-a-z0-9]*[a-z0-9])?')]",
 			Paths:   []string{"spec.traffic.tag[0]"},
 		},
+	}, {
+		name: "valid tag expiry",
+		r: &Route{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Annotations: map[string]string{
+					serving.TagExpiryAnnotationPrefix + "preview": "2021-06-01T12:00:00Z",
+				},
+			},
+			Spec: RouteSpec{
+				Traffic: []TrafficTarget{{
+					RevisionName: "foo",
+					Percent:      ptr.Int64(100),
+				}, {
+					Tag:          "preview",
+					RevisionName: "bar",
+					Percent:      ptr.Int64(0),
+				}},
+			},
+		},
+	}, {
+		name: "invalid tag expiry",
+		r: &Route{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Annotations: map[string]string{
+					serving.TagExpiryAnnotationPrefix + "preview": "tomorrow",
+				},
+			},
+			Spec: RouteSpec{
+				Traffic: []TrafficTarget{{
+					RevisionName: "foo",
+					Percent:      ptr.Int64(100),
+				}},
+			},
+		},
+		want: &apis.FieldError{
+			Message: "invalid value: tomorrow",
+			Paths:   []string{"metadata.annotations." + serving.TagExpiryAnnotationPrefix + "preview"},
+			Details: "expected an RFC3339 time",
+		},
+	}, {
+		name: "expiring tag with traffic",
+		r: &Route{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Annotations: map[string]string{
+					serving.TagExpiryAnnotationPrefix + "preview": "2021-06-01T12:00:00Z",
+				},
+			},
+			Spec: RouteSpec{
+				Traffic: []TrafficTarget{{
+					RevisionName: "foo",
+					Percent:      ptr.Int64(90),
+				}, {
+					Tag:          "preview",
+					RevisionName: "bar",
+					Percent:      ptr.Int64(10),
+				}},
+			},
+		},
+		want: apis.ErrGeneric(`expiring tag "preview" must carry 0% of the traffic`,
+			"spec.traffic[1].percent"),
 	}}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -745,6 +940,33 @@ func TestRouteAnnotationUpdate(t *testing.T) {
 			Spec: getRouteSpec("new"),
 		},
 		wantErr: apis.ErrInvalidValue("three hours and seventeen seconds", serving.RolloutDurationKey).ViaField("metadata.annotations"),
+	}, {
+		name: "response header policy validation",
+		this: &Route{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Annotations: map[string]string{
+					serving.ResponseHeaderPolicyAnnotationKey: `{"contentTypeNoSniff":true}`,
+				},
+			},
+			Spec: getRouteSpec("new"),
+		},
+	}, {
+		name: "response header policy validation, fail",
+		this: &Route{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Annotations: map[string]string{
+					serving.ResponseHeaderPolicyAnnotationKey: `{"hsts":{"maxAgeSeconds":-1}}`,
+				},
+			},
+			Spec: getRouteSpec("new"),
+		},
+		wantErr: &apis.FieldError{
+			Message: `invalid value: {"hsts":{"maxAgeSeconds":-1}}`,
+			Paths:   []string{"metadata.annotations.[" + serving.ResponseHeaderPolicyAnnotationKey + "]"},
+			Details: "hsts.maxAgeSeconds must not be negative, was -1",
+		},
 	}, {
 		name: "no validation for lastModifier annotation even after update without spec changes as route owned by service",
 		this: &Route{
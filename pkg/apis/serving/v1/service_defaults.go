@@ -37,6 +37,10 @@ func (s *Service) SetDefaults(ctx context.Context) {
 
 // SetDefaults implements apis.Defaultable
 func (ss *ServiceSpec) SetDefaults(ctx context.Context) {
-	ss.ConfigurationSpec.SetDefaults(ctx)
+	// The template of a Service built on a ServiceTemplate is partial,
+	// so it is defaulted once merged into the Configuration instead.
+	if apis.ParentMeta(ctx).Annotations[serving.ServiceTemplateAnnotationKey] == "" {
+		ss.ConfigurationSpec.SetDefaults(ctx)
+	}
 	ss.RouteSpec.SetDefaults(WithDefaultConfigurationName(ctx))
 }
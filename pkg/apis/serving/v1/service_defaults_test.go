@@ -23,6 +23,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	authv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/ptr"
@@ -259,6 +260,30 @@ func TestServiceDefaulting(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		name: "built on a service template",
+		in: &Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					serving.ServiceTemplateAnnotationKey: "base",
+				},
+			},
+		},
+		want: &Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					serving.ServiceTemplateAnnotationKey: "base",
+				},
+			},
+			Spec: ServiceSpec{
+				RouteSpec: RouteSpec{
+					Traffic: []TrafficTarget{{
+						Percent:        ptr.Int64(100),
+						LatestRevision: ptr.Bool(true),
+					}},
+				},
+			},
+		},
 	}}
 
 	for _, test := range tests {
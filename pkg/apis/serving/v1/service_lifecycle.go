@@ -73,6 +73,13 @@ func (ss *ServiceStatus) MarkConfigurationNotOwned(name string) {
 		fmt.Sprintf("There is an existing Configuration %q that we do not own.", name))
 }
 
+// MarkServiceTemplateMissing surfaces a failure via the ConfigurationsReady
+// status noting that the ServiceTemplate the Service references doesn't exist.
+func (ss *ServiceStatus) MarkServiceTemplateMissing(name string) {
+	serviceCondSet.Manage(ss).MarkFalse(ServiceConditionConfigurationsReady, "ServiceTemplateMissing",
+		fmt.Sprintf("ServiceTemplate %q does not exist.", name))
+}
+
 // MarkRouteNotOwned surfaces a failure via the RoutesReady status noting that the Route
 // with the name we want has already been created and we do not own it.
 func (ss *ServiceStatus) MarkRouteNotOwned(name string) {
@@ -124,6 +124,26 @@ type ServiceStatus struct {
 	// In addition to inlining RouteSpec, we also inline the fields
 	// specific to RouteStatus.
 	RouteStatusFields `json:",inline"`
+
+	// RevisionHistory describes the most recent Revisions of the Service's
+	// Configuration, newest first.
+	// +optional
+	RevisionHistory []RevisionHistoryEntry `json:"revisionHistory,omitempty"`
+}
+
+// RevisionHistoryEntry describes a Revision and why it was created.
+type RevisionHistoryEntry struct {
+	// RevisionName is the name of the Revision.
+	RevisionName string `json:"revisionName"`
+
+	// Creator is the user whose change created the Revision.
+	// +optional
+	Creator string `json:"creator,omitempty"`
+
+	// ChangeCause summarizes how the Revision's template differs from the
+	// one of the previous Revision.
+	// +optional
+	ChangeCause string `json:"changeCause,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -20,6 +20,7 @@ import (
 	"context"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/util/validation"
 	network "knative.dev/networking/pkg"
 	"knative.dev/pkg/apis"
 	"knative.dev/serving/pkg/apis/serving"
@@ -38,10 +39,15 @@ func (s *Service) Validate(ctx context.Context) (errs *apis.FieldError) {
 			s.GetAnnotations()).ViaField("annotations"))
 		errs = errs.Also(serving.ValidateHasNoAutoscalingAnnotation(
 			s.GetAnnotations()).ViaField("annotations"))
+		errs = errs.Also(validateServiceTemplateAnnotations(
+			s.GetAnnotations()).ViaField("annotations"))
+		errs = errs.Also(validateResponseHeaderPolicyAnnotation(
+			s.GetAnnotations()).ViaField("annotations"))
 		errs = errs.ViaField("metadata")
 
 		ctx = apis.WithinParent(ctx, s.ObjectMeta)
 		errs = errs.Also(s.Spec.Validate(apis.WithinSpec(ctx)).ViaField("spec"))
+		errs = errs.Also(validateTagExpiries(s.GetAnnotations(), s.Spec.Traffic))
 	}
 
 	if apis.IsInUpdate(ctx) {
@@ -59,6 +65,13 @@ func (s *Service) Validate(ctx context.Context) (errs *apis.FieldError) {
 
 // Validate implements apis.Validatable
 func (ss *ServiceSpec) Validate(ctx context.Context) *apis.FieldError {
+	if apis.ParentMeta(ctx).Annotations[serving.ServiceTemplateAnnotationKey] != "" {
+		// The template of a Service built on a ServiceTemplate is partial, so
+		// only its metadata is validated here. The webhook validates it once
+		// merged into the ServiceTemplate's.
+		return ss.ConfigurationSpec.Template.validateMetadata(ctx).ViaField("template").Also(
+			ss.RouteSpec.Validate(WithDefaultConfigurationName(ctx)))
+	}
 	return ss.ConfigurationSpec.Validate(ctx).Also(
 		// Within the context of Service, the RouteSpec has a default
 		// configurationName.
@@ -77,3 +90,23 @@ func (s *Service) validateLabels() (errs *apis.FieldError) {
 	}
 	return errs
 }
+
+// validateServiceTemplateAnnotations validates the annotations referencing a
+// ServiceTemplate.
+func validateServiceTemplateAnnotations(annos map[string]string) (errs *apis.FieldError) {
+	if name, ok := annos[serving.ServiceTemplateAnnotationKey]; ok {
+		if msgs := validation.IsDNS1123Subdomain(name); len(msgs) > 0 {
+			err := apis.ErrInvalidValue(name, serving.ServiceTemplateAnnotationKey)
+			err.Details = strings.Join(msgs, ", ")
+			errs = errs.Also(err)
+		}
+	}
+	if v, ok := annos[serving.ServiceTemplateRolloutAnnotationKey]; ok {
+		switch serving.ServiceTemplateRollout(v) {
+		case serving.ServiceTemplateRolloutAutomatic, serving.ServiceTemplateRolloutManual:
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(v, serving.ServiceTemplateRolloutAnnotationKey))
+		}
+	}
+	return errs
+}
@@ -280,6 +280,71 @@ func TestServiceValidation(t *testing.T) {
 			},
 		},
 		wantErr: apis.ErrInvalidKeyName("autoscaling.knative.dev/foo", "metadata.annotations", `autoscaling annotations must be put under "spec.template.metadata.annotations" to work`),
+	}, {
+		name: "valid partial template with service template",
+		r: &Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Annotations: map[string]string{
+					serving.ServiceTemplateAnnotationKey:        "base",
+					serving.ServiceTemplateRolloutAnnotationKey: "manual",
+				},
+			},
+			Spec: ServiceSpec{
+				ConfigurationSpec: ConfigurationSpec{
+					Template: RevisionTemplateSpec{
+						Spec: RevisionSpec{
+							PodSpec: corev1.PodSpec{
+								Containers: []corev1.Container{{
+									Env: []corev1.EnvVar{{Name: "FOO", Value: "bar"}},
+								}},
+							},
+						},
+					},
+				},
+				RouteSpec: goodRouteSpec,
+			},
+		},
+	}, {
+		name: "invalid service template annotations",
+		r: &Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Annotations: map[string]string{
+					serving.ServiceTemplateAnnotationKey:        "Not_Valid",
+					serving.ServiceTemplateRolloutAnnotationKey: "sometimes",
+				},
+			},
+			Spec: ServiceSpec{
+				RouteSpec: goodRouteSpec,
+			},
+		},
+		wantErr: (&apis.FieldError{
+			Message: `invalid value: Not_Valid`,
+			Paths:   []string{"metadata.annotations." + serving.ServiceTemplateAnnotationKey},
+			Details: `a DNS-1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`,
+		}).Also(apis.ErrInvalidValue("sometimes", "metadata.annotations."+serving.ServiceTemplateRolloutAnnotationKey)),
+	}, {
+		name: "invalid template metadata with service template",
+		r: &Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Annotations: map[string]string{
+					serving.ServiceTemplateAnnotationKey: "base",
+				},
+			},
+			Spec: ServiceSpec{
+				ConfigurationSpec: ConfigurationSpec{
+					Template: RevisionTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "foo",
+						},
+					},
+				},
+				RouteSpec: goodRouteSpec,
+			},
+		},
+		wantErr: apis.ErrInvalidValue(`"foo" must have prefix "valid-"`, "spec.template.metadata.name"),
 	}}
 
 	// TODO(dangerd): PodSpec validation failures.
@@ -21,6 +21,7 @@ limitations under the License.
 package v1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	apis "knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
@@ -154,6 +155,66 @@ func (in *ContainerStatus) DeepCopy() *ContainerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OverflowTarget) DeepCopyInto(out *OverflowTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OverflowTarget.
+func (in *OverflowTarget) DeepCopy() *OverflowTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(OverflowTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PathPrefixRewrite) DeepCopyInto(out *PathPrefixRewrite) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PathPrefixRewrite.
+func (in *PathPrefixRewrite) DeepCopy() *PathPrefixRewrite {
+	if in == nil {
+		return nil
+	}
+	out := new(PathPrefixRewrite)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequestHeaders) DeepCopyInto(out *RequestHeaders) {
+	*out = *in
+	if in.Set != nil {
+		in, out := &in.Set, &out.Set
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Remove != nil {
+		in, out := &in.Remove, &out.Remove
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequestHeaders.
+func (in *RequestHeaders) DeepCopy() *RequestHeaders {
+	if in == nil {
+		return nil
+	}
+	out := new(RequestHeaders)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Revision) DeepCopyInto(out *Revision) {
 	*out = *in
@@ -182,6 +243,22 @@ func (in *Revision) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevisionHistoryEntry) DeepCopyInto(out *RevisionHistoryEntry) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevisionHistoryEntry.
+func (in *RevisionHistoryEntry) DeepCopy() *RevisionHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(RevisionHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RevisionList) DeepCopyInto(out *RevisionList) {
 	*out = *in
@@ -502,6 +579,11 @@ func (in *ServiceStatus) DeepCopyInto(out *ServiceStatus) {
 	in.Status.DeepCopyInto(&out.Status)
 	out.ConfigurationStatusFields = in.ConfigurationStatusFields
 	in.RouteStatusFields.DeepCopyInto(&out.RouteStatusFields)
+	if in.RevisionHistory != nil {
+		in, out := &in.RevisionHistory, &out.RevisionHistory
+		*out = make([]RevisionHistoryEntry, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -523,6 +605,11 @@ func (in *TrafficTarget) DeepCopyInto(out *TrafficTarget) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.RevisionSelector != nil {
+		in, out := &in.RevisionSelector, &out.RevisionSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Percent != nil {
 		in, out := &in.Percent, &out.Percent
 		*out = new(int64)
@@ -533,6 +620,16 @@ func (in *TrafficTarget) DeepCopyInto(out *TrafficTarget) {
 		*out = new(apis.URL)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = new(RequestHeaders)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PathPrefixRewrite != nil {
+		in, out := &in.PathPrefixRewrite, &out.PathPrefixRewrite
+		*out = new(PathPrefixRewrite)
+		**out = **in
+	}
 	return
 }
 
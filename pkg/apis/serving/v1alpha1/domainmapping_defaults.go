@@ -18,15 +18,17 @@ package v1alpha1
 
 import (
 	"context"
+	"net/http"
 
 	"knative.dev/pkg/apis"
+	"knative.dev/pkg/ptr"
 	"knative.dev/serving/pkg/apis/serving"
 )
 
 // SetDefaults implements apis.Defaultable.
 func (dm *DomainMapping) SetDefaults(ctx context.Context) {
 	ctx = apis.WithinParent(ctx, dm.ObjectMeta)
-	dm.Spec.Ref.SetDefaults(apis.WithinSpec(ctx))
+	dm.Spec.SetDefaults(apis.WithinSpec(ctx))
 
 	if apis.IsInUpdate(ctx) {
 		serving.SetUserInfo(ctx, apis.GetBaseline(ctx).(*DomainMapping).Spec, dm.Spec, dm)
@@ -34,3 +36,22 @@ func (dm *DomainMapping) SetDefaults(ctx context.Context) {
 		serving.SetUserInfo(ctx, nil, dm.Spec, dm)
 	}
 }
+
+// SetDefaults implements apis.Defaultable.
+func (spec *DomainMappingSpec) SetDefaults(ctx context.Context) {
+	if spec.Redirect != nil {
+		spec.Redirect.SetDefaults(ctx)
+		return
+	}
+	spec.Ref.SetDefaults(ctx)
+}
+
+// SetDefaults implements apis.Defaultable.
+func (r *DomainMappingRedirect) SetDefaults(ctx context.Context) {
+	if r.Code == 0 {
+		r.Code = http.StatusPermanentRedirect
+	}
+	if r.PreservePath == nil {
+		r.PreservePath = ptr.Bool(true)
+	}
+}
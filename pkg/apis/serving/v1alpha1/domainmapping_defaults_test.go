@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -25,6 +26,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
 	"knative.dev/serving/pkg/apis/serving"
 )
 
@@ -75,6 +77,56 @@ func TestDomainMappingDefaulting(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		name: "redirect",
+		in: &DomainMapping{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "some-namespace",
+			},
+			Spec: DomainMappingSpec{
+				Redirect: &DomainMappingRedirect{
+					URL: &apis.URL{Scheme: "https", Host: "new.example.com"},
+				},
+			},
+		},
+		out: &DomainMapping{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "some-namespace",
+			},
+			Spec: DomainMappingSpec{
+				Redirect: &DomainMappingRedirect{
+					URL:          &apis.URL{Scheme: "https", Host: "new.example.com"},
+					Code:         http.StatusPermanentRedirect,
+					PreservePath: ptr.Bool(true),
+				},
+			},
+		},
+	}, {
+		name: "explicit redirect",
+		in: &DomainMapping{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "some-namespace",
+			},
+			Spec: DomainMappingSpec{
+				Redirect: &DomainMappingRedirect{
+					URL:          &apis.URL{Scheme: "https", Host: "new.example.com"},
+					Code:         http.StatusMovedPermanently,
+					PreservePath: ptr.Bool(false),
+				},
+			},
+		},
+		out: &DomainMapping{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "some-namespace",
+			},
+			Spec: DomainMappingSpec{
+				Redirect: &DomainMappingRedirect{
+					URL:          &apis.URL{Scheme: "https", Host: "new.example.com"},
+					Code:         http.StatusMovedPermanently,
+					PreservePath: ptr.Bool(false),
+				},
+			},
+		},
 	}}
 
 	for _, test := range tests {
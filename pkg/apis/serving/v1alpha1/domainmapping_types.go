@@ -81,7 +81,33 @@ type DomainMappingSpec struct {
 	//
 	// This contract is satisfied by Knative types such as Knative Services and
 	// Knative Routes, and by Kubernetes Services.
+	//
+	// Ref must be left empty when Redirect is set.
+	// +optional
 	Ref duckv1.KReference `json:"ref"`
+
+	// Redirect, when set, makes the DomainMapping answer every request with an
+	// HTTP redirect instead of proxying it to Ref. This keeps an old domain
+	// working after a Service has moved to a new one.
+	// +optional
+	Redirect *DomainMappingRedirect `json:"redirect,omitempty"`
+}
+
+// DomainMappingRedirect describes the redirect a redirect-only DomainMapping
+// answers requests with.
+type DomainMappingRedirect struct {
+	// URL is the absolute http or https URL requests are redirected to.
+	URL *apis.URL `json:"url"`
+
+	// Code is the HTTP status code of the redirect; one of 301, 302 or 308.
+	// Defaults to 308, which unlike 301 preserves the request method.
+	// +optional
+	Code int `json:"code,omitempty"`
+
+	// PreservePath controls whether the path and query of the request are
+	// appended to URL. Defaults to true.
+	// +optional
+	PreservePath *bool `json:"preservePath,omitempty"`
 }
 
 // DomainMappingStatus describes the current state of the DomainMapping.
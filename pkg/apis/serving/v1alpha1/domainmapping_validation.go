@@ -19,10 +19,12 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/serving/pkg/apis/serving"
 )
 
@@ -60,5 +62,46 @@ func (dm *DomainMapping) validateMetadata(ctx context.Context) (errs *apis.Field
 
 // Validate makes sure the DomainMappingSpec is properly configured.
 func (spec *DomainMappingSpec) Validate(ctx context.Context) *apis.FieldError {
+	if spec.Redirect != nil {
+		errs := spec.Redirect.Validate(ctx).ViaField("redirect")
+		if spec.Ref != (duckv1.KReference{}) {
+			errs = errs.Also(apis.ErrMultipleOneOf("ref", "redirect"))
+		}
+		return errs
+	}
 	return spec.Ref.Validate(ctx).ViaField("ref")
 }
+
+// Validate makes sure the DomainMappingRedirect is properly configured.
+func (r *DomainMappingRedirect) Validate(ctx context.Context) (errs *apis.FieldError) {
+	if r.URL == nil {
+		errs = errs.Also(apis.ErrMissingField("url"))
+	} else if details := validateRedirectURL(ctx, r.URL); details != "" {
+		err := apis.ErrInvalidValue(r.URL.String(), "url")
+		err.Details = details
+		errs = errs.Also(err)
+	}
+
+	switch r.Code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusPermanentRedirect:
+	default:
+		err := apis.ErrInvalidValue(r.Code, "code")
+		err.Details = "must be one of 301, 302 or 308"
+		errs = errs.Also(err)
+	}
+	return errs
+}
+
+// validateRedirectURL returns why the given redirect target is not
+// acceptable, or an empty string if it is.
+func validateRedirectURL(ctx context.Context, u *apis.URL) string {
+	switch {
+	case u.Scheme != "http" && u.Scheme != "https":
+		return "must be an absolute http or https URL"
+	case u.Host == "":
+		return "must have a host"
+	case u.Host == apis.ParentMeta(ctx).Name:
+		return "must not redirect to the mapped domain itself"
+	}
+	return ""
+}
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -120,6 +121,97 @@ func TestDomainMappingValidation(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		name: "valid redirect",
+		dm: &DomainMapping{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "old.example.com",
+				Namespace: "ns",
+			},
+			Spec: DomainMappingSpec{
+				Redirect: &DomainMappingRedirect{
+					URL:  &apis.URL{Scheme: "https", Host: "new.example.com", Path: "/base"},
+					Code: http.StatusMovedPermanently,
+				},
+			},
+		},
+	}, {
+		name: "redirect and ref",
+		want: apis.ErrMultipleOneOf("spec.ref", "spec.redirect"),
+		dm: &DomainMapping{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "old.example.com",
+				Namespace: "ns",
+			},
+			Spec: DomainMappingSpec{
+				Ref: duckv1.KReference{
+					Name:       "some-name",
+					Namespace:  "ns",
+					Kind:       "Service",
+					APIVersion: "serving.knative.dev/v1",
+				},
+				Redirect: &DomainMappingRedirect{
+					URL:  &apis.URL{Scheme: "https", Host: "new.example.com"},
+					Code: http.StatusPermanentRedirect,
+				},
+			},
+		},
+	}, {
+		name: "redirect missing url and bad code",
+		want: apis.ErrMissingField("spec.redirect.url").Also(&apis.FieldError{
+			Message: "invalid value: 307",
+			Paths:   []string{"spec.redirect.code"},
+			Details: "must be one of 301, 302 or 308",
+		}),
+		dm: &DomainMapping{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "old.example.com",
+				Namespace: "ns",
+			},
+			Spec: DomainMappingSpec{
+				Redirect: &DomainMappingRedirect{
+					Code: http.StatusTemporaryRedirect,
+				},
+			},
+		},
+	}, {
+		name: "redirect to relative url",
+		want: &apis.FieldError{
+			Message: "invalid value: /new",
+			Paths:   []string{"spec.redirect.url"},
+			Details: "must be an absolute http or https URL",
+		},
+		dm: &DomainMapping{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "old.example.com",
+				Namespace: "ns",
+			},
+			Spec: DomainMappingSpec{
+				Redirect: &DomainMappingRedirect{
+					URL:  &apis.URL{Path: "/new"},
+					Code: http.StatusPermanentRedirect,
+				},
+			},
+		},
+	}, {
+		name: "redirect to itself",
+		want: &apis.FieldError{
+			Message: "invalid value: https://old.example.com/new",
+			Paths:   []string{"spec.redirect.url"},
+			Details: "must not redirect to the mapped domain itself",
+		},
+		dm: &DomainMapping{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "old.example.com",
+				Namespace: "ns",
+			},
+			Spec: DomainMappingSpec{
+				Redirect: &DomainMappingRedirect{
+					URL:  &apis.URL{Scheme: "https", Host: "old.example.com", Path: "/new"},
+					Code: http.StatusPermanentRedirect,
+				},
+			},
+		},
 	}}
 
 	for _, test := range tests {
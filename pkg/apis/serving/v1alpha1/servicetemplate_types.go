@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/apis"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceTemplate is a reusable base for the revision template of Services in
+// the same namespace. Services reference it with the
+// serving.knative.dev/service-template annotation, and their own
+// spec.template is merged on top of it.
+type ServiceTemplate struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the desired state of the ServiceTemplate.
+	// More info: https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	// +optional
+	Spec ServiceTemplateSpec `json:"spec,omitempty"`
+}
+
+// Verify that ServiceTemplate adheres to the appropriate interfaces.
+var (
+	// Check that ServiceTemplate may be validated and defaulted.
+	_ apis.Validatable = (*ServiceTemplate)(nil)
+	_ apis.Defaultable = (*ServiceTemplate)(nil)
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceTemplateList is a collection of ServiceTemplate objects.
+type ServiceTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object metadata.
+	// More info: https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of ServiceTemplate objects.
+	Items []ServiceTemplate `json:"items"`
+}
+
+// ServiceTemplateSpec describes the base the referencing Services build on.
+type ServiceTemplateSpec struct {
+	// Template holds the base revision template. Labels and annotations
+	// are merged with the Service's, with the Service's values winning.
+	// Containers are merged by name using strategic merge semantics, and
+	// the remaining fields are used when the Service leaves them unset.
+	// The template must not be named; the Service controls revision names.
+	Template v1.RevisionTemplateSpec `json:"template"`
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	"knative.dev/serving/pkg/apis/config"
+	"knative.dev/serving/pkg/apis/serving"
+)
+
+// Validate makes sure that ServiceTemplate is properly configured.
+func (st *ServiceTemplate) Validate(ctx context.Context) *apis.FieldError {
+	errs := serving.ValidateObjectMetadata(ctx, st.GetObjectMeta()).ViaField("metadata")
+
+	ctx = apis.WithinParent(ctx, st.ObjectMeta)
+	errs = errs.Also(st.Spec.Validate(apis.WithinSpec(ctx)).ViaField("spec"))
+	return errs
+}
+
+// Validate makes sure the ServiceTemplateSpec is properly configured.
+// The template is only partially validated, since it need not be complete
+// on its own: the Configurations built from it are fully validated.
+func (spec *ServiceTemplateSpec) Validate(ctx context.Context) (errs *apis.FieldError) {
+	tmpl := &spec.Template
+	if tmpl.Name != "" {
+		errs = errs.Also(apis.ErrDisallowedFields("name"))
+	}
+	if tmpl.GenerateName != "" {
+		errs = errs.Also(apis.ErrDisallowedFields("generateName"))
+	}
+	errs = errs.Also(autoscaling.ValidateAnnotations(ctx, config.FromContextOrDefaults(ctx).Autoscaler,
+		tmpl.GetAnnotations()).ViaField("annotations"))
+	return errs.ViaField("template.metadata")
+}
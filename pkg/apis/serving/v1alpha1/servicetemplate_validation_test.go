@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+func TestServiceTemplateValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		st   *ServiceTemplate
+		want *apis.FieldError
+	}{{
+		name: "valid",
+		st: &ServiceTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "base",
+				Namespace: "ns",
+			},
+			Spec: ServiceTemplateSpec{
+				Template: v1.RevisionTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{
+							autoscaling.MinScaleAnnotationKey: "1",
+						},
+					},
+					Spec: v1.RevisionSpec{
+						PodSpec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Env: []corev1.EnvVar{{Name: "FOO", Value: "bar"}},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "partial template without image is valid",
+		st: &ServiceTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "base",
+				Namespace: "ns",
+			},
+		},
+	}, {
+		name: "named template",
+		st: &ServiceTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "base",
+				Namespace: "ns",
+			},
+			Spec: ServiceTemplateSpec{
+				Template: v1.RevisionTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:         "base-00001",
+						GenerateName: "base-",
+					},
+				},
+			},
+		},
+		want: apis.ErrDisallowedFields("spec.template.metadata.name").Also(
+			apis.ErrDisallowedFields("spec.template.metadata.generateName")),
+	}, {
+		name: "invalid autoscaling annotation",
+		st: &ServiceTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "base",
+				Namespace: "ns",
+			},
+			Spec: ServiceTemplateSpec{
+				Template: v1.RevisionTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{
+							autoscaling.MinScaleAnnotationKey: "-1",
+						},
+					},
+				},
+			},
+		},
+		want: apis.ErrOutOfBoundsValue("-1", 0, 2147483647,
+			"spec.template.metadata.annotations."+autoscaling.MinScaleAnnotationKey),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.st.Validate(context.Background())
+			if !cmp.Equal(test.want.Error(), got.Error()) {
+				t.Errorf("Validate (-want, +got):\n%s", cmp.Diff(test.want.Error(), got.Error()))
+			}
+		})
+	}
+}
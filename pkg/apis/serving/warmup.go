@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serving
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultWarmupTimeout is how long the queue-proxy spends on warm-up
+	// requests when WarmupTimeoutAnnotationKey is absent.
+	DefaultWarmupTimeout = time.Minute
+
+	// MaxWarmupRequests is the maximum number of warm-up requests, counts
+	// included, a Revision may declare.
+	MaxWarmupRequests = 1000
+)
+
+// WarmupRequest declares a request the queue-proxy sends to the user
+// container before reporting the pod ready.
+type WarmupRequest struct {
+	// Method is the HTTP method of the request, GET if empty.
+	Method string `json:"method,omitempty"`
+	// Path is the path, and optionally query, of the request, "/" if empty.
+	Path string `json:"path,omitempty"`
+	// Body is the body of the request.
+	Body string `json:"body,omitempty"`
+	// Count is how many times the request is sent, once if zero.
+	Count int `json:"count,omitempty"`
+}
+
+// ParseWarmupRequests decodes and validates the value of
+// WarmupRequestsAnnotationKey. Defaults are applied to the result.
+func ParseWarmupRequests(v string) ([]WarmupRequest, error) {
+	dec := json.NewDecoder(bytes.NewBufferString(v))
+	dec.DisallowUnknownFields()
+	var reqs []WarmupRequest
+	if err := dec.Decode(&reqs); err != nil {
+		return nil, fmt.Errorf("invalid warm-up requests: %w", err)
+	}
+
+	total := 0
+	for i := range reqs {
+		r := &reqs[i]
+		if r.Method == "" {
+			r.Method = http.MethodGet
+		}
+		if r.Path == "" {
+			r.Path = "/"
+		}
+		if r.Count == 0 {
+			r.Count = 1
+		}
+		if !ValidHeaderName(r.Method) {
+			return nil, fmt.Errorf("[%d].method %q is not a valid method", i, r.Method)
+		}
+		if !strings.HasPrefix(r.Path, "/") {
+			return nil, fmt.Errorf("[%d].path %q must start with \"/\"", i, r.Path)
+		}
+		if r.Count < 0 {
+			return nil, fmt.Errorf("[%d].count must not be negative, was %d", i, r.Count)
+		}
+		total += r.Count
+	}
+	if total > MaxWarmupRequests {
+		return nil, fmt.Errorf("at most %d warm-up requests are allowed, got %d", MaxWarmupRequests, total)
+	}
+	return reqs, nil
+}
+
+// ParseWarmupTimeout decodes and validates the value of
+// WarmupTimeoutAnnotationKey, returning DefaultWarmupTimeout if it is empty.
+func ParseWarmupTimeout(v string) (time.Duration, error) {
+	if v == "" {
+		return DefaultWarmupTimeout, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid warm-up timeout: %w", err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("warm-up timeout must be positive, was %v", d)
+	}
+	return d, nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serving
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseWarmupRequests(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []WarmupRequest
+		wantErr string
+	}{{
+		name:  "defaults",
+		value: `[{}]`,
+		want:  []WarmupRequest{{Method: "GET", Path: "/", Count: 1}},
+	}, {
+		name:  "full requests",
+		value: `[{"method":"POST","path":"/predict?warm=1","body":"{}","count":20},{"path":"/healthz"}]`,
+		want: []WarmupRequest{
+			{Method: "POST", Path: "/predict?warm=1", Body: "{}", Count: 20},
+			{Method: "GET", Path: "/healthz", Count: 1},
+		},
+	}, {
+		name:    "not a list",
+		value:   `{"path":"/"}`,
+		wantErr: "invalid warm-up requests: json: cannot unmarshal object into Go value of type []serving.WarmupRequest",
+	}, {
+		name:    "unknown field",
+		value:   `[{"paht":"/"}]`,
+		wantErr: `invalid warm-up requests: json: unknown field "paht"`,
+	}, {
+		name:    "invalid method",
+		value:   `[{"method":"GET POST"}]`,
+		wantErr: `[0].method "GET POST" is not a valid method`,
+	}, {
+		name:    "relative path",
+		value:   `[{},{"path":"predict"}]`,
+		wantErr: `[1].path "predict" must start with "/"`,
+	}, {
+		name:    "negative count",
+		value:   `[{"count":-1}]`,
+		wantErr: "[0].count must not be negative, was -1",
+	}, {
+		name:    "too many requests",
+		value:   `[{"count":600},{"count":401}]`,
+		wantErr: "at most 1000 warm-up requests are allowed, got 1001",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseWarmupRequests(test.value)
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("ParseWarmupRequests() = %v, want error: %s", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("ParseWarmupRequests() =", err)
+			}
+			if !cmp.Equal(got, test.want) {
+				t.Error("ParseWarmupRequests() (-want, +got):", cmp.Diff(test.want, got))
+			}
+		})
+	}
+}
+
+func TestParseWarmupTimeout(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "", want: DefaultWarmupTimeout},
+		{value: "90s", want: 90 * time.Second},
+		{value: "0s", wantErr: true},
+		{value: "-1s", wantErr: true},
+		{value: "soon", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := ParseWarmupTimeout(test.value)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParseWarmupTimeout(%q) = %v, wantErr %v", test.value, err, test.wantErr)
+		}
+		if got != test.want {
+			t.Errorf("ParseWarmupTimeout(%q) = %v, want %v", test.value, got, test.want)
+		}
+	}
+}
@@ -84,5 +84,10 @@ type Config struct {
 	// add an additional delay to the very last pod, if required.
 	ScaleDownDelay time.Duration
 
+	// PodImbalanceThreshold is the ratio of the busiest pod's concurrency to
+	// the average per-pod concurrency of a revision, at or above which the
+	// revision is reported as unevenly loaded. 0 disables the reporting.
+	PodImbalanceThreshold float64
+
 	PodAutoscalerClass string
 }
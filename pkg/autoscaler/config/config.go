@@ -18,13 +18,16 @@ package config
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	cm "knative.dev/pkg/configmap"
+	"knative.dev/pkg/kmeta"
 	"knative.dev/serving/pkg/apis/autoscaling"
 	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 const (
@@ -37,6 +40,27 @@ const (
 	BucketSize = 1 * time.Second
 
 	defaultTargetUtilization = 0.7
+
+	// NamespaceOverrideAnnotationPrefix is the prefix of the annotations
+	// through which a Namespace overrides a subset of config-autoscaler for
+	// its revisions, e.g. `config-autoscaler.autoscaling.knative.dev/stable-window`.
+	// Revision annotations still take precedence over these.
+	NamespaceOverrideAnnotationPrefix = "config-autoscaler." + autoscaling.GroupName + "/"
+
+	// NamespaceClassOverrideAnnotationKey is the annotation through which a
+	// Namespace overrides the class of the PodAutoscalers of its revisions.
+	NamespaceClassOverrideAnnotationKey = NamespaceOverrideAnnotationPrefix + "pod-autoscaler-class"
+)
+
+// namespaceOverrideKeys are the keys namespaces may override.
+// target-utilization-percentage applies to all the scaling metrics, like the
+// revision annotation it defaults.
+var namespaceOverrideKeys = sets.NewString(
+	"pod-autoscaler-class",
+	"max-scale",
+	"stable-window",
+	"scale-to-zero-grace-period",
+	"target-utilization-percentage",
 )
 
 func defaultConfig() *autoscalerconfig.Config {
@@ -57,6 +81,7 @@ func defaultConfig() *autoscalerconfig.Config {
 		ScaleToZeroGracePeriod:        30 * time.Second,
 		ScaleToZeroPodRetentionPeriod: 0 * time.Second,
 		ScaleDownDelay:                0 * time.Second,
+		PodImbalanceThreshold:         2,
 		PodAutoscalerClass:            autoscaling.KPA,
 		AllowZeroInitialScale:         false,
 		InitialScale:                  1,
@@ -84,6 +109,7 @@ func NewConfigFromMap(data map[string]string) (*autoscalerconfig.Config, error)
 		cm.AsFloat64("panic-window-percentage", &lc.PanicWindowPercentage),
 		cm.AsFloat64("activator-capacity", &lc.ActivatorCapacity),
 		cm.AsFloat64("panic-threshold-percentage", &lc.PanicThresholdPercentage),
+		cm.AsFloat64("pod-imbalance-threshold", &lc.PodImbalanceThreshold),
 
 		cm.AsInt32("initial-scale", &lc.InitialScale),
 		cm.AsInt32("max-scale", &lc.MaxScale),
@@ -141,6 +167,10 @@ func validate(lc *autoscalerconfig.Config) (*autoscalerconfig.Config, error) {
 		return nil, fmt.Errorf("requests-per-second-target-default must be at least %v, was: %v", autoscaling.TargetMin, lc.RPSTargetDefault)
 	}
 
+	if lc.PodImbalanceThreshold != 0 && lc.PodImbalanceThreshold <= 1 {
+		return nil, fmt.Errorf("pod-imbalance-threshold = %v, must be either 0 (disabled) or greater than 1.0", lc.PodImbalanceThreshold)
+	}
+
 	if lc.ActivatorCapacity < 1 {
 		return nil, fmt.Errorf("activator-capacity = %v, must be at least 1", lc.ActivatorCapacity)
 	}
@@ -186,6 +216,53 @@ func validate(lc *autoscalerconfig.Config) (*autoscalerconfig.Config, error) {
 	return lc, nil
 }
 
+// NamespaceOverrides returns the annotations through which a namespace
+// overrides config-autoscaler.
+func NamespaceOverrides(annotations map[string]string) map[string]string {
+	return kmeta.FilterMap(annotations, func(k string) bool {
+		return !strings.HasPrefix(k, NamespaceOverrideAnnotationPrefix)
+	})
+}
+
+// WithNamespaceOverrides returns the config with the overrides carried by
+// the namespace annotations applied. The result is validated like
+// config-autoscaler itself. The base config is returned as is when the
+// namespace carries no overrides.
+func WithNamespaceOverrides(base *autoscalerconfig.Config, annotations map[string]string) (*autoscalerconfig.Config, error) {
+	data := make(map[string]string, len(namespaceOverrideKeys))
+	for k, v := range annotations {
+		if !strings.HasPrefix(k, NamespaceOverrideAnnotationPrefix) {
+			continue
+		}
+		key := strings.TrimPrefix(k, NamespaceOverrideAnnotationPrefix)
+		if !namespaceOverrideKeys.Has(key) {
+			return nil, fmt.Errorf("%s cannot be overridden by namespaces, must be one of %v", key, namespaceOverrideKeys.List())
+		}
+		data[key] = v
+	}
+	if len(data) == 0 {
+		return base, nil
+	}
+
+	lc := base.DeepCopy()
+	tu := 0.0
+	if err := cm.Parse(data,
+		cm.AsString("pod-autoscaler-class", &lc.PodAutoscalerClass),
+		cm.AsInt32("max-scale", &lc.MaxScale),
+		cm.AsDuration("stable-window", &lc.StableWindow),
+		cm.AsDuration("scale-to-zero-grace-period", &lc.ScaleToZeroGracePeriod),
+		cm.AsFloat64("target-utilization-percentage", &tu),
+	); err != nil {
+		return nil, fmt.Errorf("failed to parse namespace overrides: %w", err)
+	}
+	if _, ok := data["target-utilization-percentage"]; ok {
+		// Unlike config-autoscaler, the legacy fraction form is not accepted.
+		lc.ContainerConcurrencyTargetFraction = tu / 100
+		lc.TargetUtilization = tu / 100
+	}
+	return validate(lc)
+}
+
 // NewConfigFromConfigMap creates a Config from the supplied ConfigMap
 func NewConfigFromConfigMap(configMap *corev1.ConfigMap) (*autoscalerconfig.Config, error) {
 	return NewConfigFromMap(configMap.Data)
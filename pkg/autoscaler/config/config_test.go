@@ -64,6 +64,7 @@ func TestNewConfig(t *testing.T) {
 			"pod-autoscaler-class":                    "some.class",
 			"activator-capacity":                      "905",
 			"scale-to-zero-pod-retention-period":      "2m3s",
+			"pod-imbalance-threshold":                 "3.5",
 		},
 		want: func() *autoscalerconfig.Config {
 			c := defaultConfig()
@@ -78,6 +79,7 @@ func TestNewConfig(t *testing.T) {
 			c.ActivatorCapacity = 905
 			c.PodAutoscalerClass = "some.class"
 			c.ScaleToZeroPodRetentionPeriod = 2*time.Minute + 3*time.Second
+			c.PodImbalanceThreshold = 3.5
 			return c
 		}(),
 	}, {
@@ -241,6 +243,22 @@ func TestNewConfig(t *testing.T) {
 			"scale-down-delay": "61984ms",
 		},
 		wantErr: true,
+	}, {
+		name: "pod-imbalance-threshold disabled",
+		input: map[string]string{
+			"pod-imbalance-threshold": "0",
+		},
+		want: func() *autoscalerconfig.Config {
+			c := defaultConfig()
+			c.PodImbalanceThreshold = 0
+			return c
+		}(),
+	}, {
+		name: "pod-imbalance-threshold too low",
+		input: map[string]string{
+			"pod-imbalance-threshold": "0.9",
+		},
+		wantErr: true,
 	}, {
 		name: "activator-capacity invalid",
 		input: map[string]string{
@@ -380,3 +398,77 @@ func TestNewConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestWithNamespaceOverrides(t *testing.T) {
+	base := defaultConfig()
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        *autoscalerconfig.Config
+		wantErr     bool
+	}{{
+		name: "no overrides",
+		annotations: map[string]string{
+			"some.other/annotation": "value",
+		},
+		want: base,
+	}, {
+		name: "overridden",
+		annotations: map[string]string{
+			NamespaceOverrideAnnotationPrefix + "pod-autoscaler-class":          "hpa.autoscaling.knative.dev",
+			NamespaceOverrideAnnotationPrefix + "max-scale":                     "10",
+			NamespaceOverrideAnnotationPrefix + "stable-window":                 "2m",
+			NamespaceOverrideAnnotationPrefix + "scale-to-zero-grace-period":    "45s",
+			NamespaceOverrideAnnotationPrefix + "target-utilization-percentage": "85",
+		},
+		want: func() *autoscalerconfig.Config {
+			c := defaultConfig()
+			c.PodAutoscalerClass = "hpa.autoscaling.knative.dev"
+			c.MaxScale = 10
+			c.StableWindow = 2 * time.Minute
+			c.ScaleToZeroGracePeriod = 45 * time.Second
+			c.ContainerConcurrencyTargetFraction = 0.85
+			c.TargetUtilization = 0.85
+			return c
+		}(),
+	}, {
+		name: "key not overridable",
+		annotations: map[string]string{
+			NamespaceOverrideAnnotationPrefix + "max-scale-up-rate": "10",
+		},
+		wantErr: true,
+	}, {
+		name: "malformed duration",
+		annotations: map[string]string{
+			NamespaceOverrideAnnotationPrefix + "stable-window": "a while",
+		},
+		wantErr: true,
+	}, {
+		name: "invalid stable window",
+		annotations: map[string]string{
+			NamespaceOverrideAnnotationPrefix + "stable-window": "1s",
+		},
+		wantErr: true,
+	}, {
+		name: "invalid target utilization",
+		annotations: map[string]string{
+			NamespaceOverrideAnnotationPrefix + "target-utilization-percentage": "142",
+		},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := WithNamespaceOverrides(base, test.annotations)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("WithNamespaceOverrides() = %v, want error: %v", err, test.wantErr)
+			}
+			if !cmp.Equal(got, test.want) {
+				t.Error("WithNamespaceOverrides (-want, +got):", cmp.Diff(test.want, got))
+			}
+		})
+	}
+	if !cmp.Equal(base, defaultConfig()) {
+		t.Error("WithNamespaceOverrides mutated the base config")
+	}
+}
@@ -79,6 +79,10 @@ type MetricClient interface {
 	// StableAndPanicRPS returns both the stable and the panic RPS
 	// for the given replica as of the given time.
 	StableAndPanicRPS(key types.NamespacedName, now time.Time) (float64, float64, error)
+
+	// StablePodConcurrency returns the average concurrency of each of the
+	// replica's scraped pods over the stable window, keyed by pod name.
+	StablePodConcurrency(key types.NamespacedName, now time.Time) (map[string]float64, error)
 }
 
 // MetricCollector manages collection of metrics for many entities.
@@ -216,6 +220,26 @@ func (c *MetricCollector) StableAndPanicRPS(key types.NamespacedName, now time.T
 		nil
 }
 
+// StablePodConcurrency returns the average concurrency of each scraped pod
+// over the stable window. Pods that were not sampled within the window are
+// not reported.
+func (c *MetricCollector) StablePodConcurrency(key types.NamespacedName, now time.Time) (map[string]float64, error) {
+	c.collectionsMutex.RLock()
+	defer c.collectionsMutex.RUnlock()
+
+	collection, exists := c.collections[key]
+	if !exists {
+		return nil, ErrNotCollecting
+	}
+	return collection.podConcurrencyAverages(now), nil
+}
+
+// timedFloat64 is a value observed at a certain time.
+type timedFloat64 struct {
+	time  time.Time
+	value float64
+}
+
 // collection represents the collection of metrics for one specific entity.
 type collection struct {
 	// mux guards access to all of the collection's state.
@@ -229,6 +253,12 @@ type collection struct {
 	rpsBuckets              *aggregation.TimedFloat64Buckets
 	rpsPanicBuckets         *aggregation.TimedFloat64Buckets
 
+	// podConcurrency holds the concurrency samples of the individual scraped
+	// pods over the stable window, keyed by pod name. Unlike the buckets above,
+	// pods are only sampled on some of the scrapes, so only the observed
+	// values are kept rather than zero-filling the gaps.
+	podConcurrency map[string][]timedFloat64
+
 	// Fields relevant for metric scraping specifically.
 	scraper StatsScraper
 	lastErr error
@@ -262,7 +292,8 @@ func newCollection(metric *autoscalingv1alpha1.Metric, scraper StatsScraper, clo
 			metric.Spec.StableWindow, config.BucketSize),
 		rpsPanicBuckets: aggregation.NewTimedFloat64Buckets(
 			metric.Spec.PanicWindow, config.BucketSize),
-		scraper: scraper,
+		podConcurrency: make(map[string][]timedFloat64),
+		scraper:        scraper,
 
 		stopCh: make(chan struct{}),
 	}
@@ -298,7 +329,11 @@ func newCollection(metric *autoscalingv1alpha1.Metric, scraper StatsScraper, clo
 					callback(key)
 				}
 				if stat != emptyStat {
-					c.record(clock.Now(), stat)
+					now := clock.Now()
+					c.record(now, stat)
+					if ps, ok := scraper.(PodStatsScraper); ok {
+						c.recordPods(now, ps.PodStats())
+					}
 				}
 			}
 		}
@@ -358,6 +393,13 @@ func (c *collection) record(now time.Time, stat Stat) {
 	// Proxied requests have been counted at the activator. Subtract
 	// them to avoid double counting.
 	concur := stat.AverageConcurrentRequests - stat.AverageProxiedConcurrentRequests
+	// When scaling on a percentile of the concurrency, use it in lieu of the
+	// average, discounted by the same proxied share. Stats that carry no
+	// percentile, e.g. the activator's, keep their average.
+	if _, ok := c.currentMetric().ConcurrencyPercentile(); ok &&
+		stat.PercentileConcurrentRequests > 0 && stat.AverageConcurrentRequests > 0 {
+		concur = stat.PercentileConcurrentRequests * concur / stat.AverageConcurrentRequests
+	}
 	c.concurrencyBuckets.Record(now, concur)
 	c.concurrencyPanicBuckets.Record(now, concur)
 	rps := stat.RequestCount - stat.ProxiedRequestCount
@@ -365,10 +407,63 @@ func (c *collection) record(now time.Time, stat Stat) {
 	c.rpsPanicBuckets.Record(now, rps)
 }
 
+// recordPods adds the individual pod stats of a scrape to the collection and
+// drops the samples that fell out of the stable window.
+func (c *collection) recordPods(now time.Time, stats []Stat) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for _, stat := range stats {
+		c.podConcurrency[stat.PodName] = append(c.podConcurrency[stat.PodName], timedFloat64{
+			time:  now,
+			value: stat.AverageConcurrentRequests - stat.AverageProxiedConcurrentRequests,
+		})
+	}
+	c.prunePodsLocked(now)
+}
+
+// prunePodsLocked drops the pod samples older than the stable window and
+// forgets the pods that have no samples left.
+// mux needs to be held.
+func (c *collection) prunePodsLocked(now time.Time) {
+	cutoff := now.Add(-c.metric.Spec.StableWindow)
+	for pod, samples := range c.podConcurrency {
+		i := 0
+		for i < len(samples) && !samples[i].time.After(cutoff) {
+			i++
+		}
+		if i == len(samples) {
+			delete(c.podConcurrency, pod)
+		} else if i > 0 {
+			c.podConcurrency[pod] = samples[i:]
+		}
+	}
+}
+
+// podConcurrencyAverages returns the average observed concurrency of each pod
+// sampled within the stable window.
+func (c *collection) podConcurrencyAverages(now time.Time) map[string]float64 {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.prunePodsLocked(now)
+	ret := make(map[string]float64, len(c.podConcurrency))
+	for pod, samples := range c.podConcurrency {
+		var total float64
+		for _, s := range samples {
+			total += s.value
+		}
+		ret[pod] = total / float64(len(samples))
+	}
+	return ret
+}
+
 // add adds the stats from `src` to `dst`.
 func (dst *Stat) add(src Stat) {
 	dst.AverageConcurrentRequests += src.AverageConcurrentRequests
 	dst.AverageProxiedConcurrentRequests += src.AverageProxiedConcurrentRequests
+	dst.MaxConcurrentRequests += src.MaxConcurrentRequests
+	dst.PercentileConcurrentRequests += src.PercentileConcurrentRequests
 	dst.RequestCount += src.RequestCount
 	dst.ProxiedRequestCount += src.ProxiedRequestCount
 }
@@ -387,6 +482,8 @@ func (dst *Stat) add(src Stat) {
 func (dst *Stat) average(sample, total float64) {
 	dst.AverageConcurrentRequests = dst.AverageConcurrentRequests / sample * total
 	dst.AverageProxiedConcurrentRequests = dst.AverageProxiedConcurrentRequests / sample * total
+	dst.MaxConcurrentRequests = dst.MaxConcurrentRequests / sample * total
+	dst.PercentileConcurrentRequests = dst.PercentileConcurrentRequests / sample * total
 	dst.RequestCount = dst.RequestCount / sample * total
 	dst.ProxiedRequestCount = dst.ProxiedRequestCount / sample * total
 }
@@ -31,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 
 	. "knative.dev/pkg/logging/testing"
+	"knative.dev/serving/pkg/apis/autoscaling"
 	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
 	"knative.dev/serving/pkg/apis/serving"
 	"knative.dev/serving/pkg/autoscaler/aggregation"
@@ -469,6 +470,59 @@ func TestMetricCollectorRecord(t *testing.T) {
 	}
 }
 
+func TestMetricCollectorPodConcurrency(t *testing.T) {
+	logger := TestLogger(t)
+
+	now := time.Now()
+	metricKey := types.NamespacedName{Namespace: defaultNamespace, Name: defaultName}
+	scraper := &testScraper{
+		s: func() (Stat, error) {
+			return emptyStat, nil
+		},
+	}
+	coll := NewMetricCollector(scraperFactory(scraper, nil), logger)
+	if _, err := coll.StablePodConcurrency(metricKey, now); !errors.Is(err, ErrNotCollecting) {
+		t.Errorf("StablePodConcurrency() = %v, want: %v", err, ErrNotCollecting)
+	}
+
+	coll.CreateOrUpdate(&defaultMetric)
+	c := coll.collections[metricKey]
+	c.recordPods(now.Add(-70*time.Second), []Stat{{
+		PodName:                   "gone",
+		AverageConcurrentRequests: 100,
+	}})
+	c.recordPods(now.Add(-2*time.Second), []Stat{{
+		PodName:                   "hot",
+		AverageConcurrentRequests: 10,
+	}, {
+		PodName:                   "cold",
+		AverageConcurrentRequests: 1,
+	}})
+	c.recordPods(now.Add(-time.Second), []Stat{{
+		PodName:                          "hot",
+		AverageConcurrentRequests:        16,
+		AverageProxiedConcurrentRequests: 2, // this should be subtracted from the above.
+	}})
+
+	got, err := coll.StablePodConcurrency(metricKey, now)
+	if err != nil {
+		t.Fatal("StablePodConcurrency:", err)
+	}
+	// The pod that was last seen out of the window is forgotten and the cold pod
+	// is averaged only over the scrapes it was sampled in.
+	if want := map[string]float64{"hot": 12, "cold": 1}; !cmp.Equal(got, want) {
+		t.Errorf("StablePodConcurrency() = %v, want: %v", got, want)
+	}
+
+	got, err = coll.StablePodConcurrency(metricKey, now.Add(time.Minute))
+	if err != nil {
+		t.Fatal("StablePodConcurrency:", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("StablePodConcurrency() = %v, wanted no pods after the window passed", got)
+	}
+}
+
 func TestDoubleWatch(t *testing.T) {
 	defer func() {
 		if x := recover(); x == nil {
@@ -616,3 +670,47 @@ func TestMetricCollectorAggregate(t *testing.T) {
 		t.Errorf("Stable Concurrency = %f, want: %f", got, want)
 	}
 }
+
+func TestMetricCollectorAggregatePercentile(t *testing.T) {
+	m := defaultMetric
+	m.Annotations = map[string]string{autoscaling.ConcurrencyPercentileAnnotationKey: "95"}
+	c := &collection{
+		metric:                  &m,
+		concurrencyBuckets:      aggregation.NewTimedFloat64Buckets(m.Spec.StableWindow, config.BucketSize),
+		concurrencyPanicBuckets: aggregation.NewTimedFloat64Buckets(m.Spec.PanicWindow, config.BucketSize),
+		rpsBuckets:              aggregation.NewTimedFloat64Buckets(m.Spec.StableWindow, config.BucketSize),
+		rpsPanicBuckets:         aggregation.NewTimedFloat64Buckets(m.Spec.PanicWindow, config.BucketSize),
+	}
+	now := time.Now()
+	// Scraped stat: a quarter of the requests were proxied, so only three
+	// quarters of the percentile are attributed to the pods.
+	c.record(now, Stat{
+		PodName:                          scraperPodName,
+		AverageConcurrentRequests:        4,
+		AverageProxiedConcurrentRequests: 1,
+		MaxConcurrentRequests:            20,
+		PercentileConcurrentRequests:     12,
+	})
+	// Activator stat: no percentile, the average is used.
+	c.record(now, Stat{
+		PodName:                   "activator",
+		AverageConcurrentRequests: 2,
+	})
+
+	if got, want := c.concurrencyBuckets.WindowAverage(now), 11.; got != want {
+		t.Errorf("Stable Concurrency = %f, want: %f", got, want)
+	}
+
+	// Without the annotation the average is used throughout.
+	m.Annotations = nil
+	c.concurrencyBuckets = aggregation.NewTimedFloat64Buckets(m.Spec.StableWindow, config.BucketSize)
+	c.record(now, Stat{
+		PodName:                          scraperPodName,
+		AverageConcurrentRequests:        4,
+		AverageProxiedConcurrentRequests: 1,
+		PercentileConcurrentRequests:     12,
+	})
+	if got, want := c.concurrencyBuckets.WindowAverage(now), 3.; got != want {
+		t.Errorf("Stable Concurrency = %f, want: %f", got, want)
+	}
+}
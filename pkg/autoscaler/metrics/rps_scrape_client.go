@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// counterSampleTTL is how long the last counter sample of a pod that isn't
+// scraped anymore is kept around.
+const counterSampleTTL = 5 * time.Minute
+
+type counterSample struct {
+	value float64
+	time  time.Time
+}
+
+// rpsScrapeClient scrapes a request counter that pods export in the Prometheus
+// text format, and reports its rate since the previous scrape of the same URL
+// as the request count of the returned Stat.
+type rpsScrapeClient struct {
+	httpClient *http.Client
+	metric     string
+	now        func() time.Time
+
+	mu   sync.Mutex
+	last map[string]counterSample
+}
+
+func newRPSScrapeClient(httpClient *http.Client, metric string) *rpsScrapeClient {
+	return &rpsScrapeClient{
+		httpClient: httpClient,
+		metric:     metric,
+		now:        time.Now,
+		last:       make(map[string]counterSample),
+	}
+}
+
+func (c *rpsScrapeClient) Scrape(ctx context.Context, target string) (Stat, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return emptyStat, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return emptyStat, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return emptyStat, fmt.Errorf("GET request for URL %q returned HTTP status %v", target, resp.StatusCode)
+	}
+	value, err := sumCounter(resp.Body, c.metric)
+	if err != nil {
+		return emptyStat, err
+	}
+
+	now := c.now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	prev, ok := c.last[target]
+	c.last[target] = counterSample{value: value, time: now}
+	for t, s := range c.last {
+		if now.Sub(s.time) > counterSampleTTL {
+			delete(c.last, t)
+		}
+	}
+
+	stat := Stat{PodName: target}
+	if u, err := url.Parse(target); err == nil {
+		stat.PodName = u.Hostname()
+	}
+	// The first scrape of a pod, and the one after its counter was reset, only
+	// record the baseline of the next rate.
+	if ok && value >= prev.value && now.After(prev.time) {
+		stat.RequestCount = (value - prev.value) / now.Sub(prev.time).Seconds()
+	}
+	return stat, nil
+}
+
+// sumCounter returns the sum of the samples of all the series of the named
+// metric in the Prometheus text exposition format read from r.
+func sumCounter(r io.Reader, name string) (float64, error) {
+	var (
+		sum   float64
+		found bool
+	)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, name) {
+			continue
+		}
+		rest := line[len(name):]
+		switch {
+		case strings.HasPrefix(rest, "{"):
+			end := strings.LastIndexByte(rest, '}')
+			if end < 0 {
+				return 0, fmt.Errorf("malformed sample of metric %q: %q", name, line)
+			}
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, " "), strings.HasPrefix(rest, "\t"):
+		default:
+			// Another metric whose name starts with the counter's.
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			return 0, fmt.Errorf("malformed sample of metric %q: %q", name, line)
+		}
+		v, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return 0, fmt.Errorf("malformed sample of metric %q: %w", name, err)
+		}
+		sum += v
+		found = true
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("reading metrics failed: %w", err)
+	}
+	if !found {
+		return 0, fmt.Errorf("metric %q not found", name)
+	}
+	return sum, nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestSumCounter(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    float64
+		wantErr bool
+	}{{
+		name: "single series",
+		body: "# TYPE http_requests_total counter\nhttp_requests_total 42\n",
+		want: 42,
+	}, {
+		name: "series are summed up",
+		body: `# HELP http_requests_total The served requests.
+# TYPE http_requests_total counter
+http_requests_total{code="200",path="/a{b}"} 40 1612137600000
+http_requests_total{code="500"} 2.5
+http_requests_total_created 1612137600
+http_request_duration_seconds_count 7
+`,
+		want: 42.5,
+	}, {
+		name:    "missing",
+		body:    "http_request_duration_seconds_count 7\n",
+		wantErr: true,
+	}, {
+		name:    "malformed value",
+		body:    "http_requests_total{} NaN-ish\n",
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := sumCounter(strings.NewReader(test.body), "http_requests_total")
+			if (err != nil) != test.wantErr {
+				t.Fatalf("sumCounter() = %v, wantErr: %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("sumCounter() = %v, want: %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRPSScrapeClient(t *testing.T) {
+	counter := 100
+	hClient := &http.Client{
+		Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader("requests " + strconv.Itoa(counter) + "\n")),
+			}, nil
+		}),
+	}
+	now := time.Now()
+	c := newRPSScrapeClient(hClient, "requests")
+	c.now = func() time.Time { return now }
+	const target = "http://10.0.0.1:8080/metrics"
+
+	scrape := func() Stat {
+		t.Helper()
+		stat, err := c.Scrape(context.Background(), target)
+		if err != nil {
+			t.Fatal("Scrape() =", err)
+		}
+		return stat
+	}
+
+	// The first scrape only records the baseline.
+	if got, want := scrape(), (Stat{PodName: "10.0.0.1"}); got != want {
+		t.Errorf("first Scrape() = %#v, want: %#v", got, want)
+	}
+
+	counter, now = 120, now.Add(2*time.Second)
+	if got, want := scrape(), (Stat{PodName: "10.0.0.1", RequestCount: 10}); got != want {
+		t.Errorf("Scrape() = %#v, want: %#v", got, want)
+	}
+
+	// The counter was reset, e.g. by a container restart.
+	counter, now = 5, now.Add(time.Second)
+	if got, want := scrape(), (Stat{PodName: "10.0.0.1"}); got != want {
+		t.Errorf("Scrape() after reset = %#v, want: %#v", got, want)
+	}
+
+	counter, now = 11, now.Add(time.Second)
+	if got, want := scrape(), (Stat{PodName: "10.0.0.1", RequestCount: 6}); got != want {
+		t.Errorf("Scrape() = %#v, want: %#v", got, want)
+	}
+
+	// Samples of pods that aren't scraped anymore are dropped.
+	now = now.Add(counterSampleTTL + time.Second)
+	if _, err := c.Scrape(context.Background(), "http://10.0.0.2:8080/metrics"); err != nil {
+		t.Fatal("Scrape() =", err)
+	}
+	if _, ok := c.last[target]; ok {
+		t.Error("The stale sample was not dropped")
+	}
+}
@@ -57,6 +57,13 @@ type Stat struct {
 	// Time/date that the stat was generated in seconds since
 	// 1970-01-01 00:00:00.000 UTC.
 	Timestamp int64 `protobuf:"varint,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Maximum number of requests handled concurrently by this pod during the
+	// reporting period.
+	MaxConcurrentRequests float64 `protobuf:"fixed64,8,opt,name=max_concurrent_requests,json=maxConcurrentRequests,proto3" json:"max_concurrent_requests,omitempty"`
+	// Concurrency at the percentile configured on the queue-proxy, i.e. the
+	// number of requests that this pod was handling at most during that
+	// percentage of the reporting period. Zero if no percentile is configured.
+	PercentileConcurrentRequests float64 `protobuf:"fixed64,9,opt,name=percentile_concurrent_requests,json=percentileConcurrentRequests,proto3" json:"percentile_concurrent_requests,omitempty"`
 }
 
 func (m *Stat) Reset()         { *m = Stat{} }
@@ -141,6 +148,20 @@ func (m *Stat) GetTimestamp() int64 {
 	return 0
 }
 
+func (m *Stat) GetMaxConcurrentRequests() float64 {
+	if m != nil {
+		return m.MaxConcurrentRequests
+	}
+	return 0
+}
+
+func (m *Stat) GetPercentileConcurrentRequests() float64 {
+	if m != nil {
+		return m.PercentileConcurrentRequests
+	}
+	return 0
+}
+
 // WireStatMessage is a copy of the StatMessage Golang type, exploding the fields of
 // `types.NamespacedName` to make it compatible with protobufs.
 type WireStatMessage struct {
@@ -261,30 +282,32 @@ func init() {
 func init() { proto.RegisterFile("pkg/autoscaler/metrics/stat.proto", fileDescriptor_cf216df9f6fff44c) }
 
 var fileDescriptor_cf216df9f6fff44c = []byte{
-	// 359 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0x4f, 0x4b, 0xc3, 0x30,
-	0x18, 0xc6, 0x97, 0xad, 0xee, 0x4f, 0xe6, 0x54, 0x22, 0x42, 0x86, 0x52, 0xba, 0x0d, 0xa1, 0xa7,
-	0x0d, 0xa6, 0x67, 0x0f, 0xee, 0xe2, 0x65, 0x22, 0x15, 0xf1, 0x58, 0x62, 0xf6, 0x3a, 0x8a, 0xb6,
-	0x89, 0x49, 0x2a, 0x7e, 0x0c, 0x3f, 0x96, 0x27, 0xd9, 0xd1, 0xa3, 0x6c, 0x5f, 0x44, 0x1a, 0xb3,
-	0x4d, 0xc7, 0x4e, 0x0d, 0xcf, 0xfb, 0x7b, 0x9e, 0x17, 0x9e, 0xb7, 0xb8, 0x23, 0x9f, 0xa6, 0x03,
-	0x96, 0x1b, 0xa1, 0x39, 0x7b, 0x06, 0x35, 0x48, 0xc1, 0xa8, 0x84, 0xeb, 0x81, 0x36, 0xcc, 0xf4,
-	0xa5, 0x12, 0x46, 0x90, 0x9a, 0xd3, 0xba, 0x9f, 0x65, 0xec, 0xdd, 0x1a, 0x66, 0x48, 0x1b, 0xd7,
-	0xa5, 0x98, 0xc4, 0x19, 0x4b, 0x81, 0xa2, 0x00, 0x85, 0x8d, 0xa8, 0x26, 0xc5, 0xe4, 0x9a, 0xa5,
-	0x40, 0x2e, 0xf0, 0x31, 0x7b, 0x05, 0xc5, 0xa6, 0x10, 0x73, 0x91, 0xf1, 0x5c, 0x29, 0xc8, 0x4c,
-	0xac, 0xe0, 0x25, 0x07, 0x6d, 0x34, 0x2d, 0x07, 0x28, 0x44, 0x51, 0xdb, 0x21, 0xa3, 0x15, 0x11,
-	0x39, 0x80, 0x8c, 0x71, 0x6f, 0xe9, 0x97, 0x4a, 0xbc, 0x25, 0x30, 0xd9, 0x9a, 0x53, 0xb1, 0x39,
-	0x81, 0x43, 0x6f, 0x7e, 0xc9, 0x2d, 0x71, 0x3d, 0xdc, 0x72, 0x9e, 0x98, 0x8b, 0x3c, 0x33, 0xd4,
-	0xb3, 0xc6, 0x5d, 0x27, 0x8e, 0x0a, 0x8d, 0x0c, 0xf1, 0xd1, 0x72, 0xd7, 0x7f, 0x78, 0xc7, 0xc2,
-	0x87, 0x6e, 0x18, 0xfd, 0xf5, 0x9c, 0xe2, 0x3d, 0xa9, 0x04, 0x07, 0xad, 0xe3, 0x5c, 0x9a, 0x24,
-	0x05, 0x5a, 0xb5, 0x70, 0xcb, 0xa9, 0x77, 0x56, 0x24, 0x27, 0xb8, 0x51, 0x7c, 0xb5, 0x61, 0xa9,
-	0xa4, 0xb5, 0x00, 0x85, 0x95, 0x68, 0x2d, 0x74, 0x1f, 0xf1, 0xfe, 0x7d, 0xa2, 0xa0, 0xe8, 0x74,
-	0x0c, 0x5a, 0xb3, 0xa9, 0x35, 0x14, 0xb5, 0x6a, 0xc9, 0xf8, 0xb2, 0xdb, 0xb5, 0x40, 0x08, 0xf6,
-	0x6c, 0xe9, 0x65, 0x3b, 0xb0, 0x6f, 0xd2, 0xc1, 0x5e, 0x71, 0x2c, 0x5b, 0x49, 0x73, 0xd8, 0xea,
-	0xbb, 0x6b, 0xf5, 0x8b, 0xd4, 0xc8, 0x8e, 0xba, 0x57, 0xf8, 0x60, 0x63, 0x8f, 0x26, 0xe7, 0xb8,
-	0x9e, 0xba, 0x37, 0x45, 0x41, 0x25, 0x6c, 0x0e, 0xe9, 0xca, 0xba, 0x01, 0x47, 0x2b, 0xf2, 0x92,
-	0x7e, 0xcc, 0x7d, 0x34, 0x9b, 0xfb, 0xe8, 0x7b, 0xee, 0xa3, 0xf7, 0x85, 0x5f, 0x9a, 0x2d, 0xfc,
-	0xd2, 0xd7, 0xc2, 0x2f, 0x3d, 0x54, 0xed, 0xcf, 0x72, 0xf6, 0x13, 0x00, 0x00, 0xff, 0xff, 0x6d,
-	0xc3, 0xa5, 0x6a, 0x51, 0x02, 0x00, 0x00,
+	// 397 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0x41, 0xaf, 0x12, 0x31,
+	0x14, 0x85, 0xe9, 0x9b, 0xf1, 0x01, 0xf7, 0x89, 0x9a, 0x9a, 0x17, 0xfb, 0xe2, 0xcb, 0x64, 0x80,
+	0x98, 0xcc, 0x0a, 0x12, 0x34, 0x2e, 0x5d, 0x88, 0x0b, 0x37, 0x18, 0x33, 0xc6, 0xb8, 0x9c, 0xd4,
+	0x72, 0x25, 0x13, 0xe9, 0xb4, 0xb6, 0x1d, 0xc3, 0xcf, 0xf0, 0x67, 0xb9, 0x70, 0xc1, 0xd2, 0xa5,
+	0x81, 0x3f, 0x62, 0xa6, 0x16, 0x50, 0x9c, 0x15, 0xcd, 0xb9, 0xdf, 0x39, 0xa5, 0x73, 0x2e, 0x0c,
+	0xf5, 0xe7, 0xd5, 0x94, 0xd7, 0x4e, 0x59, 0xc1, 0xd7, 0x68, 0xa6, 0x12, 0x9d, 0x29, 0x85, 0x9d,
+	0x5a, 0xc7, 0xdd, 0x44, 0x1b, 0xe5, 0x14, 0xed, 0x06, 0x6d, 0xf4, 0x23, 0x82, 0xf8, 0x9d, 0xe3,
+	0x8e, 0xde, 0x40, 0x4f, 0xab, 0x65, 0x51, 0x71, 0x89, 0x8c, 0xa4, 0x24, 0xeb, 0xe7, 0x5d, 0xad,
+	0x96, 0x6f, 0xb8, 0x44, 0xfa, 0x02, 0x1e, 0xf3, 0xaf, 0x68, 0xf8, 0x0a, 0x0b, 0xa1, 0x2a, 0x51,
+	0x1b, 0x83, 0x95, 0x2b, 0x0c, 0x7e, 0xa9, 0xd1, 0x3a, 0xcb, 0x2e, 0x52, 0x92, 0x91, 0xfc, 0x26,
+	0x20, 0xf3, 0x23, 0x91, 0x07, 0x80, 0x2e, 0x60, 0x7c, 0xf0, 0x6b, 0xa3, 0x36, 0x25, 0x2e, 0x5b,
+	0x73, 0x22, 0x9f, 0x93, 0x06, 0xf4, 0xed, 0x1f, 0xb2, 0x25, 0x6e, 0x0c, 0x83, 0xe0, 0x29, 0x84,
+	0xaa, 0x2b, 0xc7, 0x62, 0x6f, 0xbc, 0x1b, 0xc4, 0x79, 0xa3, 0xd1, 0x19, 0x5c, 0x1f, 0xee, 0xfa,
+	0x17, 0xbe, 0xe3, 0xe1, 0x87, 0x61, 0x98, 0xff, 0xed, 0x79, 0x02, 0xf7, 0xb4, 0x51, 0x02, 0xad,
+	0x2d, 0x6a, 0xed, 0x4a, 0x89, 0xec, 0xd2, 0xc3, 0x83, 0xa0, 0xbe, 0xf7, 0x22, 0xbd, 0x85, 0x7e,
+	0xf3, 0x6b, 0x1d, 0x97, 0x9a, 0x75, 0x53, 0x92, 0x45, 0xf9, 0x49, 0xa0, 0xcf, 0xe1, 0x91, 0xe4,
+	0x9b, 0xd6, 0x07, 0xf6, 0x7c, 0xda, 0xb5, 0xe4, 0x9b, 0x96, 0x57, 0xbd, 0x82, 0x44, 0xa3, 0x11,
+	0x58, 0xb9, 0x72, 0xdd, 0xfe, 0x9d, 0xfb, 0xde, 0x7e, 0x7b, 0xa2, 0xfe, 0x4f, 0x19, 0x7d, 0x82,
+	0xfb, 0x1f, 0x4a, 0x83, 0x4d, 0xa3, 0x0b, 0xb4, 0x96, 0xaf, 0xfc, 0xdf, 0x6d, 0x4a, 0xb5, 0x9a,
+	0x8b, 0x43, 0xb3, 0x27, 0x81, 0x52, 0x88, 0x7d, 0xe5, 0x17, 0x7e, 0xe0, 0xcf, 0x74, 0x08, 0x71,
+	0xb3, 0x2a, 0xbe, 0x90, 0xab, 0xd9, 0x60, 0x12, 0x76, 0x65, 0xd2, 0xa4, 0xe6, 0x7e, 0x34, 0x7a,
+	0x0d, 0x0f, 0xce, 0xee, 0xb1, 0xf4, 0x19, 0xf4, 0x64, 0x38, 0x33, 0x92, 0x46, 0xd9, 0xd5, 0x8c,
+	0x1d, 0xad, 0x67, 0x70, 0x7e, 0x24, 0x5f, 0xb2, 0xef, 0xbb, 0x84, 0x6c, 0x77, 0x09, 0xf9, 0xb5,
+	0x4b, 0xc8, 0xb7, 0x7d, 0xd2, 0xd9, 0xee, 0x93, 0xce, 0xcf, 0x7d, 0xd2, 0xf9, 0x78, 0xe9, 0x57,
+	0xf5, 0xe9, 0xef, 0x01, 0x00, 0x1e, 0xc7, 0xe6, 0x53, 0xcf, 0x02, 0x00, 0x00,
 }
 
 func (m *Stat) Marshal() (dAtA []byte, err error) {
@@ -307,6 +330,18 @@ func (m *Stat) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.PercentileConcurrentRequests != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.PercentileConcurrentRequests))))
+		i--
+		dAtA[i] = 0x49
+	}
+	if m.MaxConcurrentRequests != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.MaxConcurrentRequests))))
+		i--
+		dAtA[i] = 0x41
+	}
 	if m.Timestamp != 0 {
 		i = encodeVarintStat(dAtA, i, uint64(m.Timestamp))
 		i--
@@ -477,6 +512,12 @@ func (m *Stat) Size() (n int) {
 	if m.Timestamp != 0 {
 		n += 1 + sovStat(uint64(m.Timestamp))
 	}
+	if m.MaxConcurrentRequests != 0 {
+		n += 9
+	}
+	if m.PercentileConcurrentRequests != 0 {
+		n += 9
+	}
 	return n
 }
 
@@ -657,6 +698,28 @@ func (m *Stat) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 8:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxConcurrentRequests", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.MaxConcurrentRequests = float64(math.Float64frombits(v))
+		case 9:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PercentileConcurrentRequests", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.PercentileConcurrentRequests = float64(math.Float64frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStat(dAtA[iNdEx:])
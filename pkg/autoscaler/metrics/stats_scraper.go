@@ -33,6 +33,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	pkgmetrics "knative.dev/pkg/metrics"
+	"knative.dev/serving/pkg/apis/autoscaling"
 	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
 	"knative.dev/serving/pkg/apis/serving"
 	"knative.dev/serving/pkg/metrics"
@@ -95,6 +96,13 @@ type StatsScraper interface {
 	Scrape(time.Duration) (Stat, error)
 }
 
+// PodStatsScraper is implemented by the StatsScrapers that retain the
+// individual pod stats that were averaged into the last scraped Stat.
+type PodStatsScraper interface {
+	// PodStats returns the stats of the pods sampled by the last Scrape call.
+	PodStats() []Stat
+}
+
 // scrapeClient defines the interface for collecting Revision metrics for a given
 // URL. Internal used only.
 type scrapeClient interface {
@@ -147,12 +155,20 @@ type serviceScraper struct {
 
 	url      string
 	statsCtx context.Context
-	logger   *zap.SugaredLogger
+
+	// portAndPath is where the pods are scraped.
+	portAndPath string
+	logger      *zap.SugaredLogger
 
 	podAccessor     resources.PodAccessor
 	podsAddressable bool
+
+	// podStats are the individual pod stats sampled by the last scrape.
+	podStats []Stat
 }
 
+var _ PodStatsScraper = (*serviceScraper)(nil)
+
 // NewStatsScraper creates a new StatsScraper for the Revision which
 // the given Metric is responsible for.
 func NewStatsScraper(metric *autoscalingv1alpha1.Metric, revisionName string, podAccessor resources.PodAccessor,
@@ -177,6 +193,7 @@ func newServiceScraperWithClient(
 		directClient:    directClient,
 		meshClient:      meshClient,
 		url:             urlFromTarget(metric.Spec.ScrapeTarget, metric.ObjectMeta.Namespace),
+		portAndPath:     portAndPath,
 		podAccessor:     podAccessor,
 		podsAddressable: true,
 		statsCtx:        ctx,
@@ -184,6 +201,19 @@ func newServiceScraperWithClient(
 	}
 }
 
+// NewRPSStatsScraper creates a new StatsScraper for the pods of the standalone
+// PodAutoscaler which the given Metric is responsible for, that derives their
+// requests per second from the request counter they export themselves.
+// The pods have to be scraped directly, since the rates are computed from
+// consecutive scrapes of the same pod.
+func NewRPSStatsScraper(metric *autoscalingv1alpha1.Metric, paName string, podAccessor resources.PodAccessor,
+	logger *zap.SugaredLogger) StatsScraper {
+	directClient := newRPSScrapeClient(client, metric.Annotations[autoscaling.RPSMetricAnnotationKey])
+	s := newServiceScraperWithClient(metric, paName, podAccessor, directClient, nil /*meshClient*/, logger)
+	s.portAndPath = metric.Annotations[autoscaling.RPSMetricPortAnnotationKey] + "/metrics"
+	return s
+}
+
 var portAndPath = strconv.Itoa(networking.AutoscalingQueueMetricsPort) + "/metrics"
 
 func urlFromTarget(t, ns string) string {
@@ -194,6 +224,7 @@ func urlFromTarget(t, ns string) string {
 // to the given stats channel.
 func (s *serviceScraper) Scrape(window time.Duration) (stat Stat, err error) {
 	startTime := time.Now()
+	s.podStats = nil
 	defer func() {
 		// No errors and an empty stat? We didn't scrape at all because
 		// we're scaled to 0.
@@ -206,8 +237,9 @@ func (s *serviceScraper) Scrape(window time.Duration) (stat Stat, err error) {
 
 	if s.podsAddressable {
 		stat, err := s.scrapePods(window)
-		// Some pods were scraped, but not enough.
-		if !errors.Is(err, errNoPodsScraped) {
+		// Some pods were scraped, but not enough, or the pods can't be scraped
+		// through the service.
+		if !errors.Is(err, errNoPodsScraped) || s.meshClient == nil {
 			return stat, err
 		}
 		// Else fall back to service scrape.
@@ -279,7 +311,7 @@ func (s *serviceScraper) scrapePods(window time.Duration) (Stat, error) {
 				}
 
 				// Scrape!
-				target := "http://" + pods[myIdx] + ":" + portAndPath
+				target := "http://" + pods[myIdx] + ":" + s.portAndPath
 				stat, err := s.directClient.Scrape(egCtx, target)
 				if err == nil {
 					results <- stat
@@ -307,16 +339,21 @@ func (s *serviceScraper) scrapePods(window time.Duration) (Stat, error) {
 		return emptyStat, errNoPodsScraped
 	}
 
-	return computeAverages(results, sampleSizeF, frpc), nil
+	stats := make([]Stat, 0, len(results))
+	for stat := range results {
+		stats = append(stats, stat)
+	}
+	s.podStats = stats
+	return computeAverages(stats, sampleSizeF, frpc), nil
 }
 
-func computeAverages(results <-chan Stat, sample, total float64) Stat {
+func computeAverages(stats []Stat, sample, total float64) Stat {
 	ret := Stat{
 		PodName: scraperPodName,
 	}
 
 	// Sum the stats from individual pods.
-	for stat := range results {
+	for _, stat := range stats {
 		ret.add(stat)
 	}
 
@@ -392,22 +429,23 @@ func (s *serviceScraper) scrapeService(window time.Duration, readyPods int) (Sta
 	close(oldStatCh)
 	close(youngStatCh)
 
-	ret := Stat{
-		PodName: scraperPodName,
-	}
-
-	// Sum the stats from individual pods.
+	stats := make([]Stat, 0, sampleSize)
 	oldCnt := len(oldStatCh)
 	for stat := range oldStatCh {
-		ret.add(stat)
+		stats = append(stats, stat)
 	}
 	for i := oldCnt; i < sampleSize; i++ {
 		// This will always succeed, see reasoning above.
-		ret.add(<-youngStatCh)
+		stats = append(stats, <-youngStatCh)
 	}
 
-	ret.average(sampleSizeF, frpc)
-	return ret, nil
+	s.podStats = stats
+	return computeAverages(stats, sampleSizeF, frpc), nil
+}
+
+// PodStats implements PodStatsScraper.
+func (s *serviceScraper) PodStats() []Stat {
+	return s.podStats
 }
 
 // tryScrape runs a single scrape and returns stat if this is a pod that has not been
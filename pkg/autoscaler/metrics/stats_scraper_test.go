@@ -31,6 +31,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
+	"knative.dev/serving/pkg/apis/autoscaling"
 	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
 
 	fakepodsinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/pod/fake"
@@ -48,18 +49,24 @@ var (
 		PodName:                          "pod-1",
 		AverageConcurrentRequests:        3.0,
 		AverageProxiedConcurrentRequests: 2.0,
+		MaxConcurrentRequests:            6,
+		PercentileConcurrentRequests:     4,
 		RequestCount:                     5,
 		ProxiedRequestCount:              4,
 	}, {
 		PodName:                          "pod-2",
 		AverageConcurrentRequests:        5.0,
 		AverageProxiedConcurrentRequests: 4.0,
+		MaxConcurrentRequests:            9,
+		PercentileConcurrentRequests:     7,
 		RequestCount:                     7,
 		ProxiedRequestCount:              6,
 	}, {
 		PodName:                          "pod-3",
 		AverageConcurrentRequests:        3.0,
 		AverageProxiedConcurrentRequests: 2.0,
+		MaxConcurrentRequests:            6,
+		PercentileConcurrentRequests:     4,
 		RequestCount:                     5,
 		ProxiedRequestCount:              4,
 	}}
@@ -126,6 +133,14 @@ func checkBaseStat(t *testing.T, got Stat) {
 	if got.ProxiedRequestCount != 14 {
 		t.Errorf("stat.ProxiedRequestCount=%v, want %v", got.ProxiedRequestCount, 14)
 	}
+	// (6 + 9 + 6) / 3.0 * 3 = 21
+	if got.MaxConcurrentRequests != 21 {
+		t.Errorf("stat.MaxConcurrentRequests=%v, want %v", got.MaxConcurrentRequests, 21)
+	}
+	// (4 + 7 + 4) / 3.0 * 3 = 15
+	if got.PercentileConcurrentRequests != 15 {
+		t.Errorf("stat.PercentileConcurrentRequests=%v, want %v", got.PercentileConcurrentRequests, 15)
+	}
 }
 
 func TestPodDirectScrapeSuccess(t *testing.T) {
@@ -150,6 +165,10 @@ func TestPodDirectScrapeSuccess(t *testing.T) {
 		t.Errorf("Wanted empty stat got: %#v", stat)
 	}
 
+	if got := scraper.PodStats(); len(got) != 0 {
+		t.Errorf("PodStats() = %v, wanted none", got)
+	}
+
 	makePods(ctx, "pods-", 3, metav1.Now())
 	if _, err := scraper.Scrape(defaultMetric.Spec.StableWindow); err != nil {
 		t.Fatal("Unexpected error from scraper.Scrape():", err)
@@ -158,6 +177,9 @@ func TestPodDirectScrapeSuccess(t *testing.T) {
 	if !scraper.podsAddressable {
 		t.Error("PodAddressable switched to false")
 	}
+	if got, want := len(scraper.PodStats()), 3; got != want {
+		t.Errorf("len(PodStats()) = %d, want: %d", got, want)
+	}
 }
 
 func TestPodDirectScrapeSomeFailButSuccess(t *testing.T) {
@@ -231,6 +253,58 @@ func TestPodDirectScrapeNoneSucceed(t *testing.T) {
 	}
 }
 
+func TestPodDirectScrapeNoneSucceedWithoutService(t *testing.T) {
+	testStats := testStatsWithTime(4, youngPodCutOffDuration.Seconds() /*youngest*/)
+	direct := newTestScrapeClient(testStats, []error{
+		// Pods fail.
+		errors.New("okay"), errors.New("okay"), errors.New("okay"), errors.New("okay"),
+	})
+	ctx, cancel, informers := SetupFakeContextWithCancel(t)
+	wf, err := controller.RunInformers(ctx.Done(), informers...)
+	if err != nil {
+		cancel()
+		t.Fatal("StartInformers() =", err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		wf()
+	})
+	makePods(ctx, "pods-", 4, metav1.Now())
+
+	// Like those of the rps metric source, the pods can't be scraped through
+	// the service.
+	scraper := serviceScraperForTest(ctx, t, direct, nil /*meshClient*/, true)
+	if _, err := scraper.Scrape(defaultMetric.Spec.StableWindow); !errors.Is(err, errNoPodsScraped) {
+		t.Errorf("scraper.Scrape() = %v, want: %v", err, errNoPodsScraped)
+	}
+	if !scraper.podsAddressable {
+		t.Error("PodAddressable switched to false")
+	}
+}
+
+func TestNewRPSStatsScraper(t *testing.T) {
+	metric := testMetric()
+	metric.Annotations = map[string]string{
+		autoscaling.MetricSourceAnnotationKey:  autoscaling.MetricSourceRPS,
+		autoscaling.RPSMetricAnnotationKey:     "http_requests_total",
+		autoscaling.RPSMetricPortAnnotationKey: "8080",
+	}
+	ctx, cancel, _ := SetupFakeContextWithCancel(t)
+	t.Cleanup(cancel)
+	accessor := resources.NewStandalonePodAccessor(fakepodsinformer.Get(ctx).Lister(), testNamespace, testRevision)
+
+	s := NewRPSStatsScraper(metric, testRevision, accessor, logtesting.TestLogger(t)).(*serviceScraper)
+	if got, want := s.portAndPath, "8080/metrics"; got != want {
+		t.Errorf("portAndPath = %q, want: %q", got, want)
+	}
+	if got, want := s.directClient.(*rpsScrapeClient).metric, "http_requests_total"; got != want {
+		t.Errorf("metric = %q, want: %q", got, want)
+	}
+	if s.meshClient != nil {
+		t.Error("meshClient is set, want: nil")
+	}
+}
+
 func TestPodDirectScrapePodsExhausted(t *testing.T) {
 	ctx, cancel, informers := SetupFakeContextWithCancel(t)
 	wf, err := controller.RunInformers(ctx.Done(), informers...)
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"sync"
 	"time"
 
@@ -31,6 +32,7 @@ import (
 	"knative.dev/serving/pkg/apis/autoscaling"
 	"knative.dev/serving/pkg/autoscaler/aggregation/max"
 	"knative.dev/serving/pkg/autoscaler/metrics"
+	smetrics "knative.dev/serving/pkg/metrics"
 	"knative.dev/serving/pkg/resources"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -106,9 +108,9 @@ func newAutoscaler(
 	if curC > 1 {
 		pt = time.Now()
 		// A new instance of autoscaler is created in panic mode.
-		pkgmetrics.Record(reporterCtx, panicM.M(1))
+		smetrics.RecordGauges(reporterCtx, !deciderSpec.Reachable, panicM.M(1))
 	} else {
-		pkgmetrics.Record(reporterCtx, panicM.M(0))
+		smetrics.RecordGauges(reporterCtx, !deciderSpec.Reachable, panicM.M(0))
 	}
 
 	return &autoscaler{
@@ -207,7 +209,7 @@ func (a *autoscaler) Scale(logger *zap.SugaredLogger, now time.Time) ScaleResult
 		// Begin panicking when we cross the threshold in the panic window.
 		logger.Info("PANICKING.")
 		a.panicTime = now
-		pkgmetrics.Record(a.reporterCtx, panicM.M(1))
+		smetrics.RecordGauges(a.reporterCtx, !spec.Reachable, panicM.M(1))
 	} else if isOverPanicThreshold {
 		// If we're still over panic threshold right now — extend the panic window.
 		a.panicTime = now
@@ -216,7 +218,7 @@ func (a *autoscaler) Scale(logger *zap.SugaredLogger, now time.Time) ScaleResult
 		logger.Info("Un-panicking.")
 		a.panicTime = time.Time{}
 		a.maxPanicPods = 0
-		pkgmetrics.Record(a.reporterCtx, panicM.M(0))
+		smetrics.RecordGauges(a.reporterCtx, !spec.Reachable, panicM.M(0))
 	}
 
 	desiredPodCount := desiredStablePodCount
@@ -296,23 +298,27 @@ func (a *autoscaler) Scale(logger *zap.SugaredLogger, now time.Time) ScaleResult
 			observedPanicValue, a.deciderSpec.TargetBurstCapacity, excessBCF, numAct))
 	}
 
-	switch spec.ScalingMetric {
-	case autoscaling.RPS:
-		pkgmetrics.RecordBatch(a.reporterCtx,
-			excessBurstCapacityM.M(excessBCF),
-			desiredPodCountM.M(int64(desiredPodCount)),
-			stableRPSM.M(observedStableValue),
-			panicRPSM.M(observedStableValue),
-			targetRPSM.M(spec.TargetValue),
-		)
-	default:
-		pkgmetrics.RecordBatch(a.reporterCtx,
-			excessBurstCapacityM.M(excessBCF),
-			desiredPodCountM.M(int64(desiredPodCount)),
-			stableRequestConcurrencyM.M(observedStableValue),
-			panicRequestConcurrencyM.M(observedPanicValue),
-			targetRequestConcurrencyM.M(spec.TargetValue),
-		)
+	smetrics.RecordGauges(a.reporterCtx, !spec.Reachable,
+		excessBurstCapacityM.M(excessBCF),
+		desiredPodCountM.M(int64(desiredPodCount)),
+	)
+	// The per-pod values don't add up, so they aren't reported for the
+	// revisions whose gauges are aggregated.
+	if !smetrics.AggregatesRevision(!spec.Reachable) {
+		switch spec.ScalingMetric {
+		case autoscaling.RPS:
+			pkgmetrics.RecordBatch(a.reporterCtx,
+				stableRPSM.M(observedStableValue),
+				panicRPSM.M(observedStableValue),
+				targetRPSM.M(spec.TargetValue),
+			)
+		default:
+			pkgmetrics.RecordBatch(a.reporterCtx,
+				stableRequestConcurrencyM.M(observedStableValue),
+				panicRequestConcurrencyM.M(observedPanicValue),
+				targetRequestConcurrencyM.M(spec.TargetValue),
+			)
+		}
 	}
 
 	return ScaleResult{
@@ -323,6 +329,66 @@ func (a *autoscaler) Scale(logger *zap.SugaredLogger, now time.Time) ScaleResult
 	}
 }
 
+// PodImbalance computes how unevenly the load is spread across the ready
+// pods of the revision over the stable window.
+func (a *autoscaler) PodImbalance(logger *zap.SugaredLogger, now time.Time) PodImbalance {
+	spec := a.currentSpec()
+	metricKey := types.NamespacedName{Namespace: a.namespace, Name: a.revision}
+	podLoads, err := a.metricClient.StablePodConcurrency(metricKey, now)
+	if err != nil {
+		logger.Errorw("Failed to obtain per-pod metrics", zap.Error(err))
+		return PodImbalance{}
+	}
+	if len(podLoads) == 0 {
+		return PodImbalance{}
+	}
+
+	var imb PodImbalance
+	imb.Index, imb.HotPods = podImbalance(podLoads, spec.PodImbalanceThreshold)
+	// The per-pod values don't add up, so they aren't reported for the
+	// revisions whose gauges are aggregated.
+	if !smetrics.AggregatesRevision(!spec.Reachable) {
+		pkgmetrics.Record(a.reporterCtx, podImbalanceIndexM.M(imb.Index))
+	}
+	if len(imb.HotPods) > 0 {
+		logger.Debugf("Pod load imbalance index = %0.3f, hot pods: %v", imb.Index, imb.HotPods)
+	}
+	return imb
+}
+
+// podImbalance returns the imbalance index of the given per-pod loads, i.e.
+// the ratio of the busiest pod's load to the mean load, together with the pods
+// whose load is at least threshold times the mean, sorted by name.
+// The index is 1 when there are fewer than two pods or no load at all, and
+// no pods are reported as hot when threshold is 0.
+func podImbalance(podLoads map[string]float64, threshold float64) (float64, []string) {
+	if len(podLoads) < 2 {
+		return 1, nil
+	}
+	var total, busiest float64
+	for _, l := range podLoads {
+		total += l
+		busiest = math.Max(busiest, l)
+	}
+	if total <= 0 {
+		return 1, nil
+	}
+	mean := total / float64(len(podLoads))
+	index := busiest / mean
+	if threshold == 0 || index < threshold {
+		return index, nil
+	}
+
+	var hot []string
+	for pod, l := range podLoads {
+		if l >= threshold*mean {
+			hot = append(hot, pod)
+		}
+	}
+	sort.Strings(hot)
+	return index, hot
+}
+
 func (a *autoscaler) currentSpec() *DeciderSpec {
 	a.specMux.RLock()
 	defer a.specMux.RUnlock()
@@ -222,6 +222,36 @@ func TestAutoscalerMetrics(t *testing.T) {
 	metricstest.AssertMetric(t, wantMetrics...)
 }
 
+func TestAutoscalerMetricsRetired(t *testing.T) {
+	defer reset()
+	smetrics.SetCardinalityConfig(&smetrics.CardinalityConfig{AggregateRetiredRevisions: true})
+	defer smetrics.SetCardinalityConfig(&smetrics.CardinalityConfig{})
+	defer smetrics.ForgetGauges(testNamespace, "testSvc", "testConfig", testRevision)
+
+	metrics := &metricClient{StableConcurrency: 50.0, PanicConcurrency: 50.0}
+	a := newTestAutoscalerNoPC(10, 100, metrics)
+	a.deciderSpec.Reachable = false
+	ebc := expectedEBC(10, 100, 50, 1)
+	na := expectedNA(a, 1)
+	expectScale(t, a, time.Now(), ScaleResult{5, ebc, na, true})
+
+	otherResource := &resource.Resource{
+		Type: "knative_revision",
+		Labels: map[string]string{
+			metricskey.LabelConfigurationName: "testConfig",
+			metricskey.LabelNamespaceName:     testNamespace,
+			metricskey.LabelRevisionName:      smetrics.OtherValue,
+			metricskey.LabelServiceName:       "testSvc",
+		},
+	}
+	metricstest.AssertMetric(t,
+		metricstest.IntMetric(desiredPodCountM.Name(), 5, nil).WithResource(otherResource),
+		metricstest.FloatMetric(excessBurstCapacityM.Name(), float64(ebc), nil).WithResource(otherResource),
+	)
+	// The per-pod values aren't aggregated.
+	metricstest.AssertNoMetric(t, stableRequestConcurrencyM.Name(), targetRequestConcurrencyM.Name())
+}
+
 func TestAutoscalerMetricsWithRPS(t *testing.T) {
 	defer reset()
 	metrics := &metricClient{PanicRPS: 99.0, StableRPS: 100}
@@ -243,6 +273,86 @@ func TestAutoscalerMetricsWithRPS(t *testing.T) {
 	metricstest.AssertMetric(t, wantMetrics...)
 }
 
+func TestAutoscalerPodImbalance(t *testing.T) {
+	defer reset()
+	metrics := &metricClient{
+		StableConcurrency: 30,
+		PanicConcurrency:  30,
+		PodConcurrency: map[string]float64{
+			"pod-a": 24,
+			"pod-b": 3,
+			"pod-c": 3,
+		},
+	}
+	a, _ := newTestAutoscaler(10, 0, metrics)
+
+	got := a.PodImbalance(logtesting.TestLogger(t), time.Now())
+	if got.Index != 2.4 || len(got.HotPods) != 0 {
+		t.Errorf("PodImbalance() = %v, %v; want: 2.4 and no hot pods when disabled", got.Index, got.HotPods)
+	}
+	metricstest.AssertMetric(t, metricstest.FloatMetric(podImbalanceIndexM.Name(), 2.4, nil).WithResource(wantResource))
+
+	spec := *a.currentSpec()
+	spec.PodImbalanceThreshold = 2
+	a.Update(&spec)
+	got = a.PodImbalance(logtesting.TestLogger(t), time.Now())
+	if want := []string{"pod-a"}; got.Index != 2.4 || !cmp.Equal(got.HotPods, want) {
+		t.Errorf("PodImbalance() = %v, %v; want: 2.4, %v", got.Index, got.HotPods, want)
+	}
+}
+
+func TestPodImbalance(t *testing.T) {
+	tests := []struct {
+		name      string
+		loads     map[string]float64
+		threshold float64
+		wantIndex float64
+		wantHot   []string
+	}{{
+		name:      "single pod",
+		loads:     map[string]float64{"a": 10},
+		threshold: 2,
+		wantIndex: 1,
+	}, {
+		name:      "no load",
+		loads:     map[string]float64{"a": 0, "b": 0},
+		threshold: 2,
+		wantIndex: 1,
+	}, {
+		name:      "balanced",
+		loads:     map[string]float64{"a": 5, "b": 5, "c": 5},
+		threshold: 2,
+		wantIndex: 1,
+	}, {
+		name:      "below threshold",
+		loads:     map[string]float64{"a": 6, "b": 4},
+		threshold: 2,
+		wantIndex: 1.2,
+	}, {
+		name:      "hot pods",
+		loads:     map[string]float64{"d": 9, "c": 9, "b": 1, "a": 1},
+		threshold: 1.5,
+		wantIndex: 1.8,
+		wantHot:   []string{"c", "d"},
+	}, {
+		name:      "disabled",
+		loads:     map[string]float64{"a": 9, "b": 1},
+		wantIndex: 1.8,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			index, hot := podImbalance(tc.loads, tc.threshold)
+			if math.Abs(index-tc.wantIndex) > 1e-9 {
+				t.Errorf("index = %v, want: %v", index, tc.wantIndex)
+			}
+			if !cmp.Equal(hot, tc.wantHot) {
+				t.Errorf("hot pods = %v, want: %v", hot, tc.wantHot)
+			}
+		})
+	}
+}
+
 func TestAutoscalerStableModeIncreaseWithConcurrencyDefault(t *testing.T) {
 	metrics := &metricClient{StableConcurrency: 50.0, PanicConcurrency: 10}
 	a := newTestAutoscalerNoPC(10, 101, metrics)
@@ -712,7 +822,8 @@ func reset() {
 		panicRequestConcurrencyM.Name(),
 		targetRequestConcurrencyM.Name(),
 		stableRPSM.Name(), panicRPSM.Name(),
-		targetRPSM.Name(), panicM.Name())
+		targetRPSM.Name(), panicM.Name(),
+		podImbalanceIndexM.Name())
 	register()
 }
 
@@ -730,6 +841,7 @@ type metricClient struct {
 	PanicConcurrency  float64
 	StableRPS         float64
 	PanicRPS          float64
+	PodConcurrency    map[string]float64
 	ErrF              func(key types.NamespacedName, now time.Time) error
 }
 
@@ -758,6 +870,16 @@ func (mc *metricClient) StableAndPanicRPS(key types.NamespacedName, now time.Tim
 	return mc.StableRPS, mc.PanicRPS, err
 }
 
+// StablePodConcurrency returns the per-pod concurrency stored in the object
+// and the result of Errf as the error.
+func (mc *metricClient) StablePodConcurrency(key types.NamespacedName, now time.Time) (map[string]float64, error) {
+	var err error
+	if mc.ErrF != nil {
+		err = mc.ErrF(key, now)
+	}
+	return mc.PodConcurrency, err
+}
+
 func BenchmarkAutoscaler(b *testing.B) {
 	metrics := &metricClient{StableConcurrency: 50.0, PanicConcurrency: 10}
 	a := newTestAutoscalerNoPC(10, 101, metrics)
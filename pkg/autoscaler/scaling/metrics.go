@@ -56,6 +56,10 @@ var (
 		"target_requests_per_second",
 		"The desired requests-per-second for each pod",
 		stats.UnitDimensionless)
+	podImbalanceIndexM = stats.Float64(
+		"pod_imbalance_index",
+		"Ratio of the busiest pod's concurrency to the average per-pod concurrency over the stable window",
+		stats.UnitDimensionless)
 	panicM = stats.Int64(
 		"panic_mode",
 		"1 if autoscaler is in panic mode, 0 otherwise",
@@ -116,6 +120,11 @@ func register() {
 			Measure:     targetRPSM,
 			Aggregation: view.LastValue(),
 		},
+		&view.View{
+			Description: "Ratio of the busiest pod's concurrency to the average per-pod concurrency over the stable window",
+			Measure:     podImbalanceIndexM,
+			Aggregation: view.LastValue(),
+		},
 	); err != nil {
 		panic(err)
 	}
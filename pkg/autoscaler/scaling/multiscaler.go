@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -70,6 +71,10 @@ type DeciderSpec struct {
 	// ScaleDownDelay is the time that must pass at reduced concurrency before a
 	// scale-down decision is applied.
 	ScaleDownDelay time.Duration
+	// PodImbalanceThreshold is the ratio of the busiest pod's concurrency to the
+	// average per-pod concurrency at or above which pods are reported as hot.
+	// 0 disables the reporting.
+	PodImbalanceThreshold float64
 	// InitialScale is the calculated initial scale of the revision, taking both
 	// revision initial scale and cluster initial scale into account. Revision initial
 	// scale overrides cluster initial scale.
@@ -79,6 +84,7 @@ type DeciderSpec struct {
 }
 
 // DeciderStatus is the current scale recommendation.
+// +k8s:deepcopy-gen=true
 type DeciderStatus struct {
 	// DesiredScale is the target number of instances that autoscaler
 	// this revision needs.
@@ -94,6 +100,14 @@ type DeciderStatus struct {
 	// NumActivators is the computed number of activators
 	// necessary to back the revision.
 	NumActivators int32
+
+	// ImbalanceIndex is the ratio of the busiest pod's concurrency to the
+	// average per-pod concurrency of the revision. 1 means evenly loaded.
+	ImbalanceIndex float64
+
+	// HotPods are the pods whose concurrency is at least the imbalance
+	// threshold times the average per-pod concurrency, sorted by name.
+	HotPods []string
 }
 
 // ScaleResult holds the scale result of the UniScaler evaluation cycle.
@@ -124,6 +138,23 @@ type UniScaler interface {
 	Update(*DeciderSpec)
 }
 
+// PodImbalance holds how unevenly the load is spread across the pods of a
+// revision.
+type PodImbalance struct {
+	// Index is the ratio of the busiest pod's concurrency to the average
+	// per-pod concurrency of the revision.
+	Index float64
+	// HotPods are the pods whose concurrency exceeds the imbalance threshold.
+	HotPods []string
+}
+
+// imbalanceScaler is implemented by the UniScalers that observe the load of
+// the individual pods of their revision.
+type imbalanceScaler interface {
+	// PodImbalance computes how unevenly the revision's pods are loaded.
+	PodImbalance(*zap.SugaredLogger, time.Time) PodImbalance
+}
+
 // UniScalerFactory creates a UniScaler for a given PA using the given dynamic configuration.
 type UniScalerFactory func(*Decider) (UniScaler, error)
 
@@ -177,6 +208,19 @@ func (sr *scalerRunner) updateLatestScale(sRes ScaleResult) bool {
 	return ret
 }
 
+// updateImbalance records the latest pod imbalance, and returns whether the
+// hot pods changed.
+func (sr *scalerRunner) updateImbalance(imb PodImbalance) bool {
+	sr.mux.Lock()
+	defer sr.mux.Unlock()
+	sr.decider.Status.ImbalanceIndex = imb.Index
+	if equality.Semantic.DeepEqual(sr.decider.Status.HotPods, imb.HotPods) {
+		return false
+	}
+	sr.decider.Status.HotPods = imb.HotPods
+	return true
+}
+
 // MultiScaler maintains a collection of UniScalers.
 type MultiScaler struct {
 	scalersMutex sync.RWMutex
@@ -343,7 +387,11 @@ func (m *MultiScaler) tickScaler(scaler UniScaler, runner *scalerRunner, metricK
 		return
 	}
 
-	if runner.updateLatestScale(sr) {
+	changed := runner.updateLatestScale(sr)
+	if is, ok := scaler.(imbalanceScaler); ok {
+		changed = runner.updateImbalance(is.PodImbalance(runner.logger, time.Now())) || changed
+	}
+	if changed {
 		m.Inform(metricKey)
 	}
 }
@@ -445,6 +445,33 @@ func newDecider() *Decider {
 	}
 }
 
+func TestUpdateImbalance(t *testing.T) {
+	sr := &scalerRunner{decider: &Decider{}}
+
+	// Only the index changed, which does not warrant a PA update.
+	if sr.updateImbalance(PodImbalance{Index: 1.5}) {
+		t.Error("updateImbalance() = true when only the imbalance index changed")
+	}
+	if got, want := sr.decider.Status.ImbalanceIndex, 1.5; got != want {
+		t.Errorf("ImbalanceIndex = %v, want: %v", got, want)
+	}
+
+	hot := PodImbalance{Index: 2.5, HotPods: []string{"pod-a"}}
+	if !sr.updateImbalance(hot) {
+		t.Error("updateImbalance() = false when pods got hot")
+	}
+	if sr.updateImbalance(hot) {
+		t.Error("updateImbalance() = true for the same hot pods")
+	}
+
+	if !sr.updateImbalance(PodImbalance{Index: 1.1}) {
+		t.Error("updateImbalance() = false when pods cooled down")
+	}
+	if got := sr.decider.Status.HotPods; len(got) != 0 {
+		t.Errorf("HotPods = %v, wanted none", got)
+	}
+}
+
 func TestSameSign(t *testing.T) {
 	tests := []struct {
 		a, b int32
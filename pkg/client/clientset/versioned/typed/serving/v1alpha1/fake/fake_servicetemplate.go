@@ -0,0 +1,130 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+	v1alpha1 "knative.dev/serving/pkg/apis/serving/v1alpha1"
+)
+
+// FakeServiceTemplates implements ServiceTemplateInterface
+type FakeServiceTemplates struct {
+	Fake *FakeServingV1alpha1
+	ns   string
+}
+
+var servicetemplatesResource = schema.GroupVersionResource{Group: "serving.knative.dev", Version: "v1alpha1", Resource: "servicetemplates"}
+
+var servicetemplatesKind = schema.GroupVersionKind{Group: "serving.knative.dev", Version: "v1alpha1", Kind: "ServiceTemplate"}
+
+// Get takes name of the serviceTemplate, and returns the corresponding serviceTemplate object, and an error if there is any.
+func (c *FakeServiceTemplates) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.ServiceTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(servicetemplatesResource, c.ns, name), &v1alpha1.ServiceTemplate{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ServiceTemplate), err
+}
+
+// List takes label and field selectors, and returns the list of ServiceTemplates that match those selectors.
+func (c *FakeServiceTemplates) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ServiceTemplateList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(servicetemplatesResource, servicetemplatesKind, c.ns, opts), &v1alpha1.ServiceTemplateList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.ServiceTemplateList{ListMeta: obj.(*v1alpha1.ServiceTemplateList).ListMeta}
+	for _, item := range obj.(*v1alpha1.ServiceTemplateList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested serviceTemplates.
+func (c *FakeServiceTemplates) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(servicetemplatesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a serviceTemplate and creates it.  Returns the server's representation of the serviceTemplate, and an error, if there is any.
+func (c *FakeServiceTemplates) Create(ctx context.Context, serviceTemplate *v1alpha1.ServiceTemplate, opts v1.CreateOptions) (result *v1alpha1.ServiceTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(servicetemplatesResource, c.ns, serviceTemplate), &v1alpha1.ServiceTemplate{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ServiceTemplate), err
+}
+
+// Update takes the representation of a serviceTemplate and updates it. Returns the server's representation of the serviceTemplate, and an error, if there is any.
+func (c *FakeServiceTemplates) Update(ctx context.Context, serviceTemplate *v1alpha1.ServiceTemplate, opts v1.UpdateOptions) (result *v1alpha1.ServiceTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(servicetemplatesResource, c.ns, serviceTemplate), &v1alpha1.ServiceTemplate{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ServiceTemplate), err
+}
+
+// Delete takes name of the serviceTemplate and deletes it. Returns an error if one occurs.
+func (c *FakeServiceTemplates) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(servicetemplatesResource, c.ns, name), &v1alpha1.ServiceTemplate{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeServiceTemplates) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(servicetemplatesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.ServiceTemplateList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched serviceTemplate.
+func (c *FakeServiceTemplates) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ServiceTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(servicetemplatesResource, c.ns, name, pt, data, subresources...), &v1alpha1.ServiceTemplate{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ServiceTemplate), err
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+	v1alpha1 "knative.dev/serving/pkg/apis/serving/v1alpha1"
+	scheme "knative.dev/serving/pkg/client/clientset/versioned/scheme"
+)
+
+// ServiceTemplatesGetter has a method to return a ServiceTemplateInterface.
+// A group's client should implement this interface.
+type ServiceTemplatesGetter interface {
+	ServiceTemplates(namespace string) ServiceTemplateInterface
+}
+
+// ServiceTemplateInterface has methods to work with ServiceTemplate resources.
+type ServiceTemplateInterface interface {
+	Create(ctx context.Context, serviceTemplate *v1alpha1.ServiceTemplate, opts v1.CreateOptions) (*v1alpha1.ServiceTemplate, error)
+	Update(ctx context.Context, serviceTemplate *v1alpha1.ServiceTemplate, opts v1.UpdateOptions) (*v1alpha1.ServiceTemplate, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.ServiceTemplate, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.ServiceTemplateList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ServiceTemplate, err error)
+	ServiceTemplateExpansion
+}
+
+// serviceTemplates implements ServiceTemplateInterface
+type serviceTemplates struct {
+	client rest.Interface
+	ns     string
+}
+
+// newServiceTemplates returns a ServiceTemplates
+func newServiceTemplates(c *ServingV1alpha1Client, namespace string) *serviceTemplates {
+	return &serviceTemplates{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the serviceTemplate, and returns the corresponding serviceTemplate object, and an error if there is any.
+func (c *serviceTemplates) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.ServiceTemplate, err error) {
+	result = &v1alpha1.ServiceTemplate{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("servicetemplates").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ServiceTemplates that match those selectors.
+func (c *serviceTemplates) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ServiceTemplateList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.ServiceTemplateList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("servicetemplates").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested serviceTemplates.
+func (c *serviceTemplates) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("servicetemplates").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a serviceTemplate and creates it.  Returns the server's representation of the serviceTemplate, and an error, if there is any.
+func (c *serviceTemplates) Create(ctx context.Context, serviceTemplate *v1alpha1.ServiceTemplate, opts v1.CreateOptions) (result *v1alpha1.ServiceTemplate, err error) {
+	result = &v1alpha1.ServiceTemplate{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("servicetemplates").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(serviceTemplate).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a serviceTemplate and updates it. Returns the server's representation of the serviceTemplate, and an error, if there is any.
+func (c *serviceTemplates) Update(ctx context.Context, serviceTemplate *v1alpha1.ServiceTemplate, opts v1.UpdateOptions) (result *v1alpha1.ServiceTemplate, err error) {
+	result = &v1alpha1.ServiceTemplate{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("servicetemplates").
+		Name(serviceTemplate.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(serviceTemplate).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the serviceTemplate and deletes it. Returns an error if one occurs.
+func (c *serviceTemplates) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("servicetemplates").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *serviceTemplates) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("servicetemplates").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched serviceTemplate.
+func (c *serviceTemplates) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ServiceTemplate, err error) {
+	result = &v1alpha1.ServiceTemplate{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("servicetemplates").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -27,6 +27,7 @@ import (
 type ServingV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	DomainMappingsGetter
+	ServiceTemplatesGetter
 }
 
 // ServingV1alpha1Client is used to interact with features provided by the serving.knative.dev group.
@@ -38,6 +39,10 @@ func (c *ServingV1alpha1Client) DomainMappings(namespace string) DomainMappingIn
 	return newDomainMappings(c, namespace)
 }
 
+func (c *ServingV1alpha1Client) ServiceTemplates(namespace string) ServiceTemplateInterface {
+	return newServiceTemplates(c, namespace)
+}
+
 // NewForConfig creates a new ServingV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*ServingV1alpha1Client, error) {
 	config := *c
@@ -26,6 +26,8 @@ import (
 type Interface interface {
 	// DomainMappings returns a DomainMappingInformer.
 	DomainMappings() DomainMappingInformer
+	// ServiceTemplates returns a ServiceTemplateInformer.
+	ServiceTemplates() ServiceTemplateInformer
 }
 
 type version struct {
@@ -43,3 +45,8 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (v *version) DomainMappings() DomainMappingInformer {
 	return &domainMappingInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// ServiceTemplates returns a ServiceTemplateInformer.
+func (v *version) ServiceTemplates() ServiceTemplateInformer {
+	return &serviceTemplateInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	time "time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+	servingv1alpha1 "knative.dev/serving/pkg/apis/serving/v1alpha1"
+	versioned "knative.dev/serving/pkg/client/clientset/versioned"
+	internalinterfaces "knative.dev/serving/pkg/client/informers/externalversions/internalinterfaces"
+	v1alpha1 "knative.dev/serving/pkg/client/listers/serving/v1alpha1"
+)
+
+// ServiceTemplateInformer provides access to a shared informer and lister for
+// ServiceTemplates.
+type ServiceTemplateInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1alpha1.ServiceTemplateLister
+}
+
+type serviceTemplateInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewServiceTemplateInformer constructs a new informer for ServiceTemplate type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewServiceTemplateInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredServiceTemplateInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredServiceTemplateInformer constructs a new informer for ServiceTemplate type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredServiceTemplateInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.ServingV1alpha1().ServiceTemplates(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.ServingV1alpha1().ServiceTemplates(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&servingv1alpha1.ServiceTemplate{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *serviceTemplateInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredServiceTemplateInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *serviceTemplateInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&servingv1alpha1.ServiceTemplate{}, f.defaultInformer)
+}
+
+func (f *serviceTemplateInformer) Lister() v1alpha1.ServiceTemplateLister {
+	return v1alpha1.NewServiceTemplateLister(f.Informer().GetIndexer())
+}
@@ -25,3 +25,11 @@ type DomainMappingListerExpansion interface{}
 // DomainMappingNamespaceListerExpansion allows custom methods to be added to
 // DomainMappingNamespaceLister.
 type DomainMappingNamespaceListerExpansion interface{}
+
+// ServiceTemplateListerExpansion allows custom methods to be added to
+// ServiceTemplateLister.
+type ServiceTemplateListerExpansion interface{}
+
+// ServiceTemplateNamespaceListerExpansion allows custom methods to be added to
+// ServiceTemplateNamespaceLister.
+type ServiceTemplateNamespaceListerExpansion interface{}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	v1alpha1 "knative.dev/serving/pkg/apis/serving/v1alpha1"
+)
+
+// ServiceTemplateLister helps list ServiceTemplates.
+// All objects returned here must be treated as read-only.
+type ServiceTemplateLister interface {
+	// List lists all ServiceTemplates in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.ServiceTemplate, err error)
+	// ServiceTemplates returns an object that can list and get ServiceTemplates.
+	ServiceTemplates(namespace string) ServiceTemplateNamespaceLister
+	ServiceTemplateListerExpansion
+}
+
+// serviceTemplateLister implements the ServiceTemplateLister interface.
+type serviceTemplateLister struct {
+	indexer cache.Indexer
+}
+
+// NewServiceTemplateLister returns a new ServiceTemplateLister.
+func NewServiceTemplateLister(indexer cache.Indexer) ServiceTemplateLister {
+	return &serviceTemplateLister{indexer: indexer}
+}
+
+// List lists all ServiceTemplates in the indexer.
+func (s *serviceTemplateLister) List(selector labels.Selector) (ret []*v1alpha1.ServiceTemplate, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.ServiceTemplate))
+	})
+	return ret, err
+}
+
+// ServiceTemplates returns an object that can list and get ServiceTemplates.
+func (s *serviceTemplateLister) ServiceTemplates(namespace string) ServiceTemplateNamespaceLister {
+	return serviceTemplateNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// ServiceTemplateNamespaceLister helps list and get ServiceTemplates.
+// All objects returned here must be treated as read-only.
+type ServiceTemplateNamespaceLister interface {
+	// List lists all ServiceTemplates in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.ServiceTemplate, err error)
+	// Get retrieves the ServiceTemplate from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.ServiceTemplate, error)
+	ServiceTemplateNamespaceListerExpansion
+}
+
+// serviceTemplateNamespaceLister implements the ServiceTemplateNamespaceLister
+// interface.
+type serviceTemplateNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all ServiceTemplates in the indexer for a given namespace.
+func (s serviceTemplateNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.ServiceTemplate, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.ServiceTemplate))
+	})
+	return ret, err
+}
+
+// Get retrieves the ServiceTemplate from the indexer for a given namespace and name.
+func (s serviceTemplateNamespaceLister) Get(name string) (*v1alpha1.ServiceTemplate, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("servicetemplate"), name)
+	}
+	return obj.(*v1alpha1.ServiceTemplate), nil
+}
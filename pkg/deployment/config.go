@@ -99,6 +99,14 @@ func NewConfigFromMap(configMap map[string]string) (*Config, error) {
 		return nil, err
 	}
 
+	if raw, ok := configMap[deploymentPatchesKey]; ok && raw != "" {
+		patches, err := parseDeploymentPatches(raw)
+		if err != nil {
+			return nil, err
+		}
+		nc.DeploymentPatches = patches
+	}
+
 	if nc.QueueSidecarImage == "" {
 		return nil, errors.New("queueSidecarImage cannot be empty or unset")
 	}
@@ -158,4 +166,8 @@ type Config struct {
 	// QueueSidecarEphemeralStorageLimit is the Ephemeral Storage Limit to set
 	// for the queue proxy sidecar container.
 	QueueSidecarEphemeralStorageLimit *resource.Quantity
+
+	// DeploymentPatches are applied, in order, to the Deployments generated
+	// for Revisions in namespaces matched by each patch's selector.
+	DeploymentPatches []DeploymentPatch
 }
@@ -68,6 +68,10 @@ func TestControllerConfigurationFromFile(t *testing.T) {
 		got.QueueSidecarCPULimit = nil
 		got.QueueSidecarMemoryRequest, got.QueueSidecarMemoryLimit = nil, nil
 		got.QueueSidecarEphemeralStorageRequest, got.QueueSidecarEphemeralStorageLimit = nil, nil
+		if len(got.DeploymentPatches) == 0 {
+			t.Error("Example stanza should demonstrate deploymentPatches")
+		}
+		got.DeploymentPatches = nil
 		if !cmp.Equal(got, want) {
 			t.Error("Example stanza does not match default, diff(-want,+got):", cmp.Diff(want, got))
 		}
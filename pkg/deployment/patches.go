@@ -0,0 +1,253 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"sigs.k8s.io/yaml"
+)
+
+// PatchType is the kind of patch carried by a DeploymentPatch.
+type PatchType string
+
+const (
+	// StrategicMergePatchType applies the patch as a Kubernetes
+	// strategic merge patch. This is the default.
+	StrategicMergePatchType PatchType = "strategic"
+
+	// JSONPatchType applies the patch as an RFC 6902 JSON patch.
+	JSONPatchType PatchType = "json"
+
+	// deploymentPatchesKey is the config map key holding the list of
+	// patches applied to every generated Deployment.
+	deploymentPatchesKey = "deploymentPatches"
+)
+
+// DeploymentPatch is an admin-defined patch applied to the Deployments
+// generated for Revisions.
+type DeploymentPatch struct {
+	// Name identifies the patch in errors and logs.
+	Name string `json:"name"`
+
+	// NamespaceSelector restricts the patch to Revisions in namespaces
+	// whose labels match. A nil selector matches every namespace.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Type is the kind of patch, defaulting to StrategicMergePatchType.
+	Type PatchType `json:"type,omitempty"`
+
+	// Patch is the body of the patch. It may be written as YAML or JSON
+	// and is stored normalized to JSON.
+	Patch string `json:"patch"`
+}
+
+// Matches returns whether the patch applies to a namespace with the given labels.
+func (p *DeploymentPatch) Matches(nsLabels map[string]string) bool {
+	if p.NamespaceSelector == nil {
+		return true
+	}
+	// The selector was validated when the config was loaded.
+	selector, err := metav1.LabelSelectorAsSelector(p.NamespaceSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(nsLabels))
+}
+
+// Apply applies the patch to the given Deployment, returning the patched copy.
+func (p *DeploymentPatch) Apply(d *appsv1.Deployment) (*appsv1.Deployment, error) {
+	orig, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	switch p.Type {
+	case JSONPatchType:
+		jp, err := jsonpatch.DecodePatch([]byte(p.Patch))
+		if err != nil {
+			return nil, err
+		}
+		if patched, err = jp.Apply(orig); err != nil {
+			return nil, err
+		}
+	default:
+		if patched, err = strategicpatch.StrategicMergePatch(orig, []byte(p.Patch), appsv1.Deployment{}); err != nil {
+			return nil, err
+		}
+	}
+
+	out := &appsv1.Deployment{}
+	if err := json.Unmarshal(patched, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ApplyPatches applies, in order, every configured patch whose namespace
+// selector matches the given namespace labels.
+func (c *Config) ApplyPatches(d *appsv1.Deployment, nsLabels map[string]string) (*appsv1.Deployment, error) {
+	for i := range c.DeploymentPatches {
+		p := &c.DeploymentPatches[i]
+		if !p.Matches(nsLabels) {
+			continue
+		}
+		patched, err := p.Apply(d)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply deployment patch %q: %w", p.Name, err)
+		}
+		d = patched
+	}
+	return d, nil
+}
+
+// parseDeploymentPatches parses and validates the list of patches in the
+// given config map value.
+func parseDeploymentPatches(raw string) ([]DeploymentPatch, error) {
+	var patches []DeploymentPatch
+	if err := yaml.UnmarshalStrict([]byte(raw), &patches); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", deploymentPatchesKey, err)
+	}
+
+	names := make(sets.String, len(patches))
+	for i := range patches {
+		p := &patches[i]
+		if err := p.init(); err != nil {
+			return nil, fmt.Errorf("invalid %s[%d]: %w", deploymentPatchesKey, i, err)
+		}
+		if names.Has(p.Name) {
+			return nil, fmt.Errorf("invalid %s[%d]: duplicate name %q", deploymentPatchesKey, i, p.Name)
+		}
+		names.Insert(p.Name)
+		if err := p.validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", deploymentPatchesKey, p.Name, err)
+		}
+	}
+	return patches, nil
+}
+
+// init defaults and normalizes the patch.
+func (p *DeploymentPatch) init() error {
+	if p.Name == "" {
+		return errors.New("name must be set")
+	}
+	switch p.Type {
+	case "":
+		p.Type = StrategicMergePatchType
+	case StrategicMergePatchType, JSONPatchType:
+	default:
+		return fmt.Errorf("unknown patch type %q", p.Type)
+	}
+	if p.Patch == "" {
+		return errors.New("patch must be set")
+	}
+	body, err := yaml.YAMLToJSON([]byte(p.Patch))
+	if err != nil {
+		return fmt.Errorf("failed to parse patch: %w", err)
+	}
+	p.Patch = string(body)
+
+	if p.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(p.NamespaceSelector); err != nil {
+			return fmt.Errorf("invalid namespaceSelector: %w", err)
+		}
+	}
+	return nil
+}
+
+// validate dry-applies the patch to a sample Deployment and rejects patches
+// that fail to apply or that change the fields the reconciler relies on to
+// identify and own the Deployment.
+func (p *DeploymentPatch) validate() error {
+	sample := sampleDeployment()
+	patched, err := p.Apply(sample)
+	if err != nil {
+		return fmt.Errorf("failed to apply to a sample revision: %w", err)
+	}
+	switch {
+	case patched.Name != sample.Name || patched.Namespace != sample.Namespace:
+		return errors.New("patch must not change the name or namespace")
+	case !equality.Semantic.DeepEqual(patched.OwnerReferences, sample.OwnerReferences):
+		return errors.New("patch must not change the owner references")
+	case !equality.Semantic.DeepEqual(patched.Spec.Selector, sample.Spec.Selector):
+		return errors.New("patch must not change the selector")
+	case !labels.SelectorFromSet(sample.Spec.Selector.MatchLabels).Matches(labels.Set(patched.Spec.Template.Labels)):
+		return errors.New("patch must not change the pod labels matched by the selector")
+	}
+	return nil
+}
+
+// sampleDeployment returns a Deployment shaped like the ones generated for
+// Revisions, used to validate patches when the config is loaded.
+func sampleDeployment() *appsv1.Deployment {
+	const name = "sample-deployment"
+	podLabels := map[string]string{
+		"serving.knative.dev/revision":    "sample",
+		"serving.knative.dev/revisionUID": "1234",
+		"app":                             "sample",
+	}
+	isTrue := true
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "sample-namespace",
+			Labels:    podLabels,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         "serving.knative.dev/v1",
+				Kind:               "Revision",
+				Name:               "sample",
+				UID:                "1234",
+				Controller:         &isTrue,
+				BlockOwnerDeletion: &isTrue,
+			}},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"serving.knative.dev/revisionUID": "1234"},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podLabels,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "user-container",
+						Image: "sample-image",
+					}, {
+						Name:  "queue-proxy",
+						Image: "sample-queue",
+					}},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployment
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeploymentPatchesParsing(t *testing.T) {
+	tests := []struct {
+		name    string
+		patches string
+		want    []DeploymentPatch
+		wantErr string
+	}{{
+		name: "strategic patch written as yaml",
+		patches: `
+- name: tolerations
+  patch: |
+    spec:
+      template:
+        spec:
+          tolerations:
+          - key: dedicated
+            operator: Exists
+`,
+		want: []DeploymentPatch{{
+			Name:  "tolerations",
+			Type:  StrategicMergePatchType,
+			Patch: `{"spec":{"template":{"spec":{"tolerations":[{"key":"dedicated","operator":"Exists"}]}}}}`,
+		}},
+	}, {
+		name: "json patch with namespace selector",
+		patches: `
+- name: min-ready
+  type: json
+  namespaceSelector:
+    matchLabels:
+      tier: gold
+  patch: '[{"op": "add", "path": "/spec/minReadySeconds", "value": 10}]'
+`,
+		want: []DeploymentPatch{{
+			Name: "min-ready",
+			Type: JSONPatchType,
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"tier": "gold"},
+			},
+			Patch: `[{"op":"add","path":"/spec/minReadySeconds","value":10}]`,
+		}},
+	}, {
+		name:    "not a list",
+		patches: `name: foo`,
+		wantErr: "failed to parse",
+	}, {
+		name:    "unknown field",
+		patches: `[{"name": "foo", "patch": "{}", "selector": {}}]`,
+		wantErr: "unknown field",
+	}, {
+		name:    "missing name",
+		patches: `[{"patch": "{}"}]`,
+		wantErr: "name must be set",
+	}, {
+		name:    "missing patch",
+		patches: `[{"name": "foo"}]`,
+		wantErr: "patch must be set",
+	}, {
+		name:    "unknown type",
+		patches: `[{"name": "foo", "type": "merge", "patch": "{}"}]`,
+		wantErr: `unknown patch type "merge"`,
+	}, {
+		name:    "duplicate name",
+		patches: `[{"name": "foo", "patch": "{}"}, {"name": "foo", "patch": "{}"}]`,
+		wantErr: `duplicate name "foo"`,
+	}, {
+		name:    "invalid selector",
+		patches: `[{"name": "foo", "patch": "{}", "namespaceSelector": {"matchExpressions": [{"key": "a", "operator": "Bogus"}]}}]`,
+		wantErr: "invalid namespaceSelector",
+	}, {
+		name:    "json patch does not apply",
+		patches: `[{"name": "foo", "type": "json", "patch": "[{\"op\": \"remove\", \"path\": \"/spec/paused\"}]"}]`,
+		wantErr: "failed to apply to a sample revision",
+	}, {
+		name:    "changes the selector",
+		patches: `[{"name": "foo", "patch": "{\"spec\": {\"selector\": {\"matchLabels\": {\"app\": \"foo\"}}}}"}]`,
+		wantErr: "must not change the selector",
+	}, {
+		name:    "changes the selected pod labels",
+		patches: `[{"name": "foo", "patch": "{\"spec\": {\"template\": {\"metadata\": {\"labels\": {\"serving.knative.dev/revisionUID\": \"foo\"}}}}}"}]`,
+		wantErr: "must not change the pod labels matched by the selector",
+	}, {
+		name:    "drops the selected pod labels",
+		patches: `[{"name": "foo", "type": "json", "patch": "[{\"op\": \"remove\", \"path\": \"/spec/template/metadata/labels\"}]"}]`,
+		wantErr: "must not change the pod labels matched by the selector",
+	}, {
+		name:    "changes the name",
+		patches: `[{"name": "foo", "type": "json", "patch": "[{\"op\": \"replace\", \"path\": \"/metadata/name\", \"value\": \"bar\"}]"}]`,
+		wantErr: "must not change the name or namespace",
+	}, {
+		name:    "drops the owner",
+		patches: `[{"name": "foo", "type": "json", "patch": "[{\"op\": \"remove\", \"path\": \"/metadata/ownerReferences\"}]"}]`,
+		wantErr: "must not change the owner references",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewConfigFromMap(map[string]string{
+				QueueSidecarImageKey: defaultSidecarImage,
+				deploymentPatchesKey: tt.patches,
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewConfigFromMap() = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("NewConfigFromMap() =", err)
+			}
+			if !cmp.Equal(got.DeploymentPatches, tt.want) {
+				t.Error("DeploymentPatches mismatch, diff(-want,+got):", cmp.Diff(tt.want, got.DeploymentPatches))
+			}
+		})
+	}
+}
+
+func TestApplyPatches(t *testing.T) {
+	cfg, err := NewConfigFromMap(map[string]string{
+		QueueSidecarImageKey: defaultSidecarImage,
+		deploymentPatchesKey: `
+- name: all
+  patch: '{"metadata": {"labels": {"patched": "all"}}}'
+- name: gold
+  namespaceSelector:
+    matchLabels:
+      tier: gold
+  patch: '{"metadata": {"labels": {"patched": "gold"}}}'
+`,
+	})
+	if err != nil {
+		t.Fatal("NewConfigFromMap() =", err)
+	}
+
+	tests := []struct {
+		name     string
+		nsLabels map[string]string
+		want     string
+	}{{
+		name: "no namespace labels",
+		want: "all",
+	}, {
+		name:     "non-matching namespace",
+		nsLabels: map[string]string{"tier": "silver"},
+		want:     "all",
+	}, {
+		name:     "matching namespace, later patches win",
+		nsLabels: map[string]string{"tier": "gold"},
+		want:     "gold",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := sampleDeployment()
+			got, err := cfg.ApplyPatches(in, tt.nsLabels)
+			if err != nil {
+				t.Fatal("ApplyPatches() =", err)
+			}
+			if got := got.Labels["patched"]; got != tt.want {
+				t.Errorf("patched label = %q, want: %q", got, tt.want)
+			}
+			if _, ok := in.Labels["patched"]; ok {
+				t.Error("ApplyPatches() mutated its input")
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ limitations under the License.
 package deployment
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	sets "k8s.io/apimachinery/pkg/util/sets"
 )
 
@@ -64,6 +65,13 @@ func (in *Config) DeepCopyInto(out *Config) {
 		x := (*in).DeepCopy()
 		*out = &x
 	}
+	if in.DeploymentPatches != nil {
+		in, out := &in.DeploymentPatches, &out.DeploymentPatches
+		*out = make([]DeploymentPatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -76,3 +84,24 @@ func (in *Config) DeepCopy() *Config {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeploymentPatch) DeepCopyInto(out *DeploymentPatch) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentPatch.
+func (in *DeploymentPatch) DeepCopy() *DeploymentPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(DeploymentPatch)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -25,6 +25,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	cm "knative.dev/pkg/configmap"
+	"knative.dev/serving/pkg/apis/serving"
 )
 
 const (
@@ -54,14 +55,21 @@ type Config struct {
 	// regardless of creation or staleness time-bounds.
 	// Set Disabled (-1) to disable/ignore max.
 	MaxNonActiveRevisions int64
+	// Maximum number of non-active revisions to keep across all the
+	// Configurations of a namespace, regardless of creation or staleness
+	// time-bounds and of MinNonActiveRevisions. It may be overridden per
+	// namespace with serving.MaxNonActiveRevisionsAnnotationKey.
+	// Set Disabled (-1) to disable/ignore max.
+	MaxNonActiveRevisionsPerNamespace int64
 }
 
 func defaultConfig() *Config {
 	return &Config{
-		RetainSinceCreateTime:     48 * time.Hour,
-		RetainSinceLastActiveTime: 15 * time.Hour,
-		MinNonActiveRevisions:     20,
-		MaxNonActiveRevisions:     1000,
+		RetainSinceCreateTime:             48 * time.Hour,
+		RetainSinceLastActiveTime:         15 * time.Hour,
+		MinNonActiveRevisions:             20,
+		MaxNonActiveRevisions:             1000,
+		MaxNonActiveRevisionsPerNamespace: Disabled,
 	}
 }
 
@@ -70,12 +78,13 @@ func NewConfigFromConfigMapFunc(ctx context.Context) func(configMap *corev1.Conf
 	return func(configMap *corev1.ConfigMap) (*Config, error) {
 		c := defaultConfig()
 
-		var retainCreate, retainActive, max string
+		var retainCreate, retainActive, max, nsMax string
 		if err := cm.Parse(configMap.Data,
 			cm.AsString("retain-since-create-time", &retainCreate),
 			cm.AsString("retain-since-last-active-time", &retainActive),
 			cm.AsInt64("min-non-active-revisions", &c.MinNonActiveRevisions),
 			cm.AsString("max-non-active-revisions", &max),
+			cm.AsString("max-non-active-revisions-per-namespace", &nsMax),
 		); err != nil {
 			return nil, fmt.Errorf("failed to parse data: %w", err)
 		}
@@ -90,6 +99,9 @@ func NewConfigFromConfigMapFunc(ctx context.Context) func(configMap *corev1.Conf
 		if err := parseDisabledOrInt64(max, &c.MaxNonActiveRevisions); err != nil {
 			return nil, fmt.Errorf("failed to parse max-non-active-revisions: %w", err)
 		}
+		if err := parseDisabledOrInt64(nsMax, &c.MaxNonActiveRevisionsPerNamespace); err != nil {
+			return nil, fmt.Errorf("failed to parse max-non-active-revisions-per-namespace: %w", err)
+		}
 		if c.MinNonActiveRevisions < 0 {
 			return nil, fmt.Errorf("min-non-active-revisions must be non-negative, was: %d", c.MinNonActiveRevisions)
 		}
@@ -100,6 +112,17 @@ func NewConfigFromConfigMapFunc(ctx context.Context) func(configMap *corev1.Conf
 	}
 }
 
+// NamespaceMaxNonActiveRevisions returns the maximum number of non-active
+// revisions to keep in the namespace with the given annotations.
+func (c *Config) NamespaceMaxNonActiveRevisions(annotations map[string]string) (int64, error) {
+	max := c.MaxNonActiveRevisionsPerNamespace
+	if err := parseDisabledOrInt64(annotations[serving.MaxNonActiveRevisionsAnnotationKey], &max); err != nil {
+		return c.MaxNonActiveRevisionsPerNamespace, fmt.Errorf("failed to parse %s: %w",
+			serving.MaxNonActiveRevisionsAnnotationKey, err)
+	}
+	return max, nil
+}
+
 func parseDisabledOrInt64(val string, toSet *int64) error {
 	switch {
 	case val == "":
@@ -22,6 +22,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
+	"knative.dev/serving/pkg/apis/serving"
 
 	. "knative.dev/pkg/configmap/testing"
 	logtesting "knative.dev/pkg/logging/testing"
@@ -47,16 +48,18 @@ func TestOurConfig(t *testing.T) {
 	}, {
 		name: "with value overrides",
 		want: &Config{
-			RetainSinceCreateTime:     17 * time.Hour,
-			RetainSinceLastActiveTime: 16 * time.Hour,
-			MinNonActiveRevisions:     5,
-			MaxNonActiveRevisions:     500,
+			RetainSinceCreateTime:             17 * time.Hour,
+			RetainSinceLastActiveTime:         16 * time.Hour,
+			MinNonActiveRevisions:             5,
+			MaxNonActiveRevisions:             500,
+			MaxNonActiveRevisionsPerNamespace: 2000,
 		},
 		data: map[string]string{
-			"retain-since-create-time":      "17h",
-			"retain-since-last-active-time": "16h",
-			"min-non-active-revisions":      "5",
-			"max-non-active-revisions":      "500",
+			"retain-since-create-time":               "17h",
+			"retain-since-last-active-time":          "16h",
+			"min-non-active-revisions":               "5",
+			"max-non-active-revisions":               "500",
+			"max-non-active-revisions-per-namespace": "2000",
 		},
 	}, {
 		name: "Invalid negative min stale",
@@ -121,6 +124,12 @@ func TestOurConfig(t *testing.T) {
 		data: map[string]string{
 			"max-non-active-revisions": "invalid",
 		},
+	}, {
+		name: "max-non-active-per-namespace unparsable",
+		fail: true,
+		data: map[string]string{
+			"max-non-active-revisions-per-namespace": "-1",
+		},
 	}, {
 		name: "max-non-active disabled",
 		want: func() *Config {
@@ -145,3 +154,40 @@ func TestOurConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestNamespaceMaxNonActiveRevisions(t *testing.T) {
+	c := defaultConfig()
+	c.MaxNonActiveRevisionsPerNamespace = 100
+	for _, tt := range []struct {
+		name        string
+		annotations map[string]string
+		want        int64
+		fail        bool
+	}{{
+		name: "no annotation",
+		want: 100,
+	}, {
+		name:        "override",
+		annotations: map[string]string{serving.MaxNonActiveRevisionsAnnotationKey: "10"},
+		want:        10,
+	}, {
+		name:        "disabled",
+		annotations: map[string]string{serving.MaxNonActiveRevisionsAnnotationKey: disabled},
+		want:        Disabled,
+	}, {
+		name:        "invalid",
+		annotations: map[string]string{serving.MaxNonActiveRevisionsAnnotationKey: "lots"},
+		want:        100,
+		fail:        true,
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.NamespaceMaxNonActiveRevisions(tt.annotations)
+			if tt.fail != (err != nil) {
+				t.Fatal("Unexpected error value:", err)
+			}
+			if got != tt.want {
+				t.Errorf("NamespaceMaxNonActiveRevisions = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// GRPCTimeoutHeaderName is the header through which gRPC clients pass
+	// their deadline, e.g. `100m` for 100 milliseconds.
+	GRPCTimeoutHeaderName = "Grpc-Timeout"
+
+	// RequestTimeoutHeaderName is the header through which HTTP clients can
+	// pass their deadline, as a Go duration, e.g. `1.5s`.
+	RequestTimeoutHeaderName = "X-Request-Timeout"
+
+	// maxGRPCTimeoutDigits is the maximum number of digits of a grpc-timeout
+	// value, as per the gRPC over HTTP2 protocol.
+	maxGRPCTimeoutDigits = 8
+
+	// grpcContentType is the content type of gRPC requests, possibly
+	// followed by a subtype, e.g. `application/grpc+proto`.
+	grpcContentType = "application/grpc"
+
+	// grpcStatusDeadlineExceeded is the DEADLINE_EXCEEDED gRPC status code.
+	grpcStatusDeadlineExceeded = "4"
+)
+
+// grpcTimeoutUnits are the units of grpc-timeout values, from the smallest
+// to the largest.
+var grpcTimeoutUnits = []struct {
+	unit byte
+	d    time.Duration
+}{
+	{'n', time.Nanosecond},
+	{'u', time.Microsecond},
+	{'m', time.Millisecond},
+	{'S', time.Second},
+	{'M', time.Minute},
+	{'H', time.Hour},
+}
+
+// QueueWaitFunc returns how long the request is expected to wait in the
+// queue before being handled.
+type QueueWaitFunc func(req *http.Request) time.Duration
+
+type deadlineKey struct{}
+
+// NewDeadlineHandler returns a Handler that applies the deadline the client
+// passed through the grpc-timeout or X-Request-Timeout header to the request
+// context, capped by the timeout from the timeout function. Requests whose
+// deadline would pass before they leave the queue, as estimated by the wait
+// function, are rejected with a 504 Gateway Timeout right away instead of
+// occupying a spot in the queue for nothing. gRPC requests are rejected
+// with a DEADLINE_EXCEEDED gRPC status instead, which gRPC clients expect
+// in a 200 response.
+//
+// Requests without a client deadline are passed through as-is.
+func NewDeadlineHandler(h http.Handler, timeoutFunc TimeoutFunc, waitFunc QueueWaitFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		budget, ok := clientBudget(r.Header)
+		if !ok {
+			h.ServeHTTP(w, r)
+			return
+		}
+		if timeoutFunc != nil {
+			if timeout := timeoutFunc(r); timeout > 0 && timeout < budget {
+				budget = timeout
+			}
+		}
+		var wait time.Duration
+		if waitFunc != nil {
+			wait = waitFunc(r)
+		}
+		if budget <= wait {
+			rejectDoomed(w, r)
+			return
+		}
+
+		deadline := time.Now().Add(budget)
+		ctx, cancel := context.WithDeadline(r.Context(), deadline)
+		defer cancel()
+		ctx = context.WithValue(ctx, deadlineKey{}, deadline)
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// rejectDoomed answers a request whose deadline would pass before it leaves
+// the queue.
+func rejectDoomed(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), grpcContentType) {
+		http.Error(w, "deadline exceeded", http.StatusGatewayTimeout)
+		return
+	}
+	// A trailers-only response, whose status is carried by the headers.
+	w.Header().Set("Content-Type", grpcContentType)
+	w.Header().Set("Grpc-Status", grpcStatusDeadlineExceeded)
+	w.Header().Set("Grpc-Message", "deadline exceeded")
+	w.WriteHeader(http.StatusOK)
+}
+
+// NewDeadlineForwardingHandler returns a Handler that calls ForwardDeadline
+// before calling `h`.
+func NewDeadlineForwardingHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ForwardDeadline(r)
+		h.ServeHTTP(w, r)
+	})
+}
+
+// ForwardDeadline rewrites the deadline headers the client sent to the
+// budget remaining from the deadline applied by the deadline handler, so
+// that the next hop does not wait for longer than the client will.
+func ForwardDeadline(r *http.Request) {
+	deadline, ok := r.Context().Value(deadlineKey{}).(time.Time)
+	if !ok {
+		return
+	}
+	remaining := time.Until(deadline)
+	if r.Header.Get(GRPCTimeoutHeaderName) != "" {
+		r.Header.Set(GRPCTimeoutHeaderName, encodeGRPCTimeout(remaining))
+	}
+	if r.Header.Get(RequestTimeoutHeaderName) != "" {
+		r.Header.Set(RequestTimeoutHeaderName, remaining.Truncate(time.Millisecond).String())
+	}
+}
+
+// clientBudget returns the time the client is willing to wait for the
+// response, from the smallest of its deadline headers.
+func clientBudget(h http.Header) (time.Duration, bool) {
+	var (
+		budget time.Duration
+		found  bool
+	)
+	if v := h.Get(GRPCTimeoutHeaderName); v != "" {
+		if d, ok := parseGRPCTimeout(v); ok {
+			budget, found = d, true
+		}
+	}
+	if v := h.Get(RequestTimeoutHeaderName); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && (!found || d < budget) {
+			budget, found = d, true
+		}
+	}
+	return budget, found
+}
+
+// parseGRPCTimeout parses a grpc-timeout value, an integer of at most 8
+// digits followed by a unit.
+func parseGRPCTimeout(v string) (time.Duration, bool) {
+	if len(v) < 2 || len(v) > maxGRPCTimeoutDigits+1 {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(v[:len(v)-1], 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	for _, u := range grpcTimeoutUnits {
+		if u.unit == v[len(v)-1] {
+			return time.Duration(n) * u.d, true
+		}
+	}
+	return 0, false
+}
+
+// encodeGRPCTimeout encodes d as a grpc-timeout value, in the smallest unit
+// that fits, rounding down.
+func encodeGRPCTimeout(d time.Duration) string {
+	if d <= 0 {
+		return "0n"
+	}
+	const maxValue = 99999999
+	for _, u := range grpcTimeoutUnits {
+		if v := d / u.d; v <= maxValue {
+			return strconv.FormatInt(int64(v), 10) + string(u.unit)
+		}
+	}
+	return strconv.Itoa(maxValue) + "H"
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeadlineHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     http.Header
+		timeout    time.Duration
+		wait       time.Duration
+		wantStatus int
+		// wantBudget is the expected budget of the request, zero when no
+		// deadline must be applied.
+		wantBudget time.Duration
+	}{{
+		name:       "no client deadline",
+		wait:       time.Hour,
+		wantStatus: http.StatusOK,
+	}, {
+		name:       "invalid client deadline",
+		header:     http.Header{GRPCTimeoutHeaderName: []string{"10x"}},
+		wantStatus: http.StatusOK,
+	}, {
+		name:       "grpc deadline",
+		header:     http.Header{GRPCTimeoutHeaderName: []string{"10S"}},
+		wantStatus: http.StatusOK,
+		wantBudget: 10 * time.Second,
+	}, {
+		name:       "request timeout deadline",
+		header:     http.Header{RequestTimeoutHeaderName: []string{"1.5s"}},
+		wantStatus: http.StatusOK,
+		wantBudget: 1500 * time.Millisecond,
+	}, {
+		name: "smallest client deadline",
+		header: http.Header{
+			GRPCTimeoutHeaderName:    []string{"2M"},
+			RequestTimeoutHeaderName: []string{"1m"},
+		},
+		wantStatus: http.StatusOK,
+		wantBudget: time.Minute,
+	}, {
+		name:       "capped by timeout",
+		header:     http.Header{GRPCTimeoutHeaderName: []string{"1H"}},
+		timeout:    5 * time.Minute,
+		wantStatus: http.StatusOK,
+		wantBudget: 5 * time.Minute,
+	}, {
+		name:       "wait within budget",
+		header:     http.Header{GRPCTimeoutHeaderName: []string{"10S"}},
+		wait:       time.Second,
+		wantStatus: http.StatusOK,
+		wantBudget: 10 * time.Second,
+	}, {
+		name:       "wait exceeds budget",
+		header:     http.Header{GRPCTimeoutHeaderName: []string{"100m"}},
+		wait:       time.Second,
+		wantStatus: http.StatusGatewayTimeout,
+	}, {
+		name:       "expired budget",
+		header:     http.Header{RequestTimeoutHeaderName: []string{"0s"}},
+		wantStatus: http.StatusGatewayTimeout,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				called   bool
+				deadline time.Time
+				hasDL    bool
+			)
+			h := NewDeadlineHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				deadline, hasDL = r.Context().Deadline()
+			}), StaticTimeoutFunc(test.timeout), func(*http.Request) time.Duration {
+				return test.wait
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+			for k, v := range test.header {
+				req.Header[k] = v
+			}
+			start := time.Now()
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != test.wantStatus {
+				t.Errorf("Status = %d, want %d", rec.Code, test.wantStatus)
+			}
+			if called != (test.wantStatus == http.StatusOK) {
+				t.Errorf("Handler called = %v, want %v", called, !called)
+			}
+			if hasDL != (test.wantBudget > 0) {
+				t.Fatalf("Has deadline = %v, want %v", hasDL, !hasDL)
+			}
+			if hasDL {
+				if budget := deadline.Sub(start); budget < test.wantBudget || budget > test.wantBudget+time.Second {
+					t.Errorf("Budget = %v, want %v", budget, test.wantBudget)
+				}
+			}
+		})
+	}
+}
+
+func TestDeadlineHandlerGRPC(t *testing.T) {
+	h := NewDeadlineHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("Doomed request was handled")
+	}), nil, func(*http.Request) time.Duration {
+		return time.Second
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "http://example.com", nil)
+	req.Header.Set("Content-Type", "application/grpc+proto")
+	req.Header.Set(GRPCTimeoutHeaderName, "100m")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if got, want := rec.Code, http.StatusOK; got != want {
+		t.Errorf("Status = %d, want %d", got, want)
+	}
+	if got, want := rec.Header().Get("Grpc-Status"), "4"; got != want {
+		t.Errorf("Grpc-Status = %q, want %q", got, want)
+	}
+	if got, want := rec.Header().Get("Content-Type"), "application/grpc"; got != want {
+		t.Errorf("Content-Type = %q, want %q", got, want)
+	}
+}
+
+func TestForwardDeadline(t *testing.T) {
+	var got http.Header
+	h := NewDeadlineHandler(NewDeadlineForwardingHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+	})), StaticTimeoutFunc(time.Minute), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+	req.Header.Set(GRPCTimeoutHeaderName, "1H")
+	req.Header.Set(RequestTimeoutHeaderName, "1h")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	grpc := got.Get(GRPCTimeoutHeaderName)
+	if !strings.HasSuffix(grpc, "u") {
+		t.Fatalf("grpc-timeout = %q, want microseconds", grpc)
+	}
+	if v, err := strconv.Atoi(strings.TrimSuffix(grpc, "u")); err != nil || v > 60000000 || v < 59000000 {
+		t.Errorf("grpc-timeout = %q, want about a minute", grpc)
+	}
+	if d, err := time.ParseDuration(got.Get(RequestTimeoutHeaderName)); err != nil || d > time.Minute || d < 59*time.Second {
+		t.Errorf("X-Request-Timeout = %q, want about a minute", got.Get(RequestTimeoutHeaderName))
+	}
+
+	// Headers the client did not send are not added.
+	got = nil
+	req = httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+	req.Header.Set(GRPCTimeoutHeaderName, "1S")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if v, ok := got[RequestTimeoutHeaderName]; ok {
+		t.Errorf("X-Request-Timeout = %q, want none", v)
+	}
+}
+
+func TestGRPCTimeout(t *testing.T) {
+	for _, v := range []string{"", "1", "S", "-1S", "123456789S", "1s"} {
+		if d, ok := parseGRPCTimeout(v); ok {
+			t.Errorf("parseGRPCTimeout(%q) = %v, want invalid", v, d)
+		}
+	}
+
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0n"},
+		{999 * time.Nanosecond, "999n"},
+		{100 * time.Millisecond, "100000u"},
+		{time.Minute, "60000000u"},
+		{time.Hour, "3600000m"},
+		{200 * 24 * time.Hour, "17280000S"},
+		{2000 * 24 * time.Hour, "2880000M"},
+	}
+	for _, test := range tests {
+		got := encodeGRPCTimeout(test.d)
+		if got != test.want {
+			t.Errorf("encodeGRPCTimeout(%v) = %q, want %q", test.d, got, test.want)
+		}
+		if d, ok := parseGRPCTimeout(got); !ok || d != test.d {
+			t.Errorf("parseGRPCTimeout(%q) = %v, want %v", got, d, test.d)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"knative.dev/serving/pkg/activator"
+)
+
+// rewriteHeaders are the headers through which the ingress hands the
+// request rewrites of a traffic target down to the data-plane.
+var rewriteHeaders = []string{
+	activator.RemoveHeadersHeaderName,
+	activator.PathPrefixHeaderName,
+	activator.PathReplacementHeaderName,
+}
+
+// NewRewriteHandler returns a Handler that applies the header removal and
+// path prefix rewrite the ingress could not apply itself, before calling
+// `h`. The headers carrying the rewrites are stripped, so that the rewrites
+// are applied once, whichever of the activator or the queue-proxy sees the
+// request first. The ingress sets these headers on every request, to
+// activator.NoRewrite for the targets without rewrites, so their values
+// never come from the client.
+func NewRewriteHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remove := r.Header.Get(activator.RemoveHeadersHeaderName)
+		prefix := r.Header.Get(activator.PathPrefixHeaderName)
+		replacement := r.Header.Get(activator.PathReplacementHeaderName)
+		for _, k := range rewriteHeaders {
+			r.Header.Del(k)
+		}
+
+		if remove != activator.NoRewrite {
+			for _, k := range strings.Split(remove, ",") {
+				if k = strings.TrimSpace(k); k != "" {
+					r.Header.Del(k)
+				}
+			}
+		}
+		if replacement != "" && replacement != activator.NoRewrite {
+			if path, ok := rewritePathPrefix(r.URL.Path, prefix, replacement); ok {
+				r.URL.Path = path
+				r.URL.RawPath = ""
+				r.RequestURI = r.URL.RequestURI()
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// rewritePathPrefix replaces `prefix` with `replacement` in `path`. The
+// prefix only matches whole path segments.
+func rewritePathPrefix(path, prefix, replacement string) (string, bool) {
+	if prefix == "" {
+		prefix = "/"
+	}
+	if !strings.HasPrefix(path, prefix) {
+		return "", false
+	}
+	rest := path[len(prefix):]
+	if rest != "" && !strings.HasSuffix(prefix, "/") && rest[0] != '/' {
+		return "", false
+	}
+	if strings.HasSuffix(replacement, "/") {
+		rest = strings.TrimPrefix(rest, "/")
+	} else if rest != "" && rest[0] != '/' {
+		rest = "/" + rest
+	}
+	return replacement + rest, true
+}
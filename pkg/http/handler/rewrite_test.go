@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"knative.dev/serving/pkg/activator"
+)
+
+func TestRewriteHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		header      http.Header
+		wantPath    string
+		wantHeaders http.Header
+		wantGone    []string
+	}{{
+		name:     "no rewrites",
+		path:     "/foo",
+		header:   http.Header{"X-Foo": []string{"bar"}},
+		wantPath: "/foo",
+		wantHeaders: http.Header{
+			"X-Foo": []string{"bar"},
+		},
+	}, {
+		name: "no rewrites set by the ingress",
+		path: "/foo",
+		header: http.Header{
+			"X-Foo":                             []string{"bar"},
+			activator.RemoveHeadersHeaderName:   []string{activator.NoRewrite},
+			activator.PathPrefixHeaderName:      []string{activator.NoRewrite},
+			activator.PathReplacementHeaderName: []string{activator.NoRewrite},
+		},
+		wantPath: "/foo",
+		wantHeaders: http.Header{
+			"X-Foo": []string{"bar"},
+		},
+		wantGone: []string{
+			activator.RemoveHeadersHeaderName,
+			activator.PathPrefixHeaderName,
+			activator.PathReplacementHeaderName,
+		},
+	}, {
+		name: "remove headers",
+		path: "/foo",
+		header: http.Header{
+			"X-Foo":                           []string{"bar"},
+			"X-Baz":                           []string{"bar"},
+			"X-Keep":                          []string{"bar"},
+			activator.RemoveHeadersHeaderName: []string{"x-foo, X-Baz"},
+		},
+		wantPath: "/foo",
+		wantHeaders: http.Header{
+			"X-Keep": []string{"bar"},
+		},
+		wantGone: []string{"X-Foo", "X-Baz", activator.RemoveHeadersHeaderName},
+	}, {
+		name: "rewrite root",
+		path: "/foo",
+		header: http.Header{
+			activator.PathReplacementHeaderName: []string{"/api"},
+		},
+		wantPath: "/api/foo",
+		wantGone: []string{activator.PathReplacementHeaderName},
+	}, {
+		name: "rewrite prefix",
+		path: "/v1/foo",
+		header: http.Header{
+			activator.PathPrefixHeaderName:      []string{"/v1"},
+			activator.PathReplacementHeaderName: []string{"/"},
+		},
+		wantPath: "/foo",
+		wantGone: []string{activator.PathPrefixHeaderName, activator.PathReplacementHeaderName},
+	}, {
+		name: "rewrite whole path",
+		path: "/v1",
+		header: http.Header{
+			activator.PathPrefixHeaderName:      []string{"/v1"},
+			activator.PathReplacementHeaderName: []string{"/v2"},
+		},
+		wantPath: "/v2",
+	}, {
+		name: "prefix matches segments only",
+		path: "/v10/foo",
+		header: http.Header{
+			activator.PathPrefixHeaderName:      []string{"/v1"},
+			activator.PathReplacementHeaderName: []string{"/v2"},
+		},
+		wantPath: "/v10/foo",
+		wantGone: []string{activator.PathPrefixHeaderName, activator.PathReplacementHeaderName},
+	}, {
+		name: "prefix with trailing slash",
+		path: "/v1/foo",
+		header: http.Header{
+			activator.PathPrefixHeaderName:      []string{"/v1/"},
+			activator.PathReplacementHeaderName: []string{"/v2"},
+		},
+		wantPath: "/v2/foo",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got *http.Request
+			h := NewRewriteHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com"+test.path+"?a=b", nil)
+			for k, v := range test.header {
+				req.Header[k] = v
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got.URL.Path != test.wantPath {
+				t.Errorf("Path = %q, want %q", got.URL.Path, test.wantPath)
+			}
+			if want := test.wantPath + "?a=b"; got.URL.RequestURI() != want {
+				t.Errorf("RequestURI = %q, want %q", got.URL.RequestURI(), want)
+			}
+			for k, v := range test.wantHeaders {
+				if g := got.Header.Get(k); g != v[0] {
+					t.Errorf("Header %q = %q, want %q", k, g, v[0])
+				}
+			}
+			for _, k := range test.wantGone {
+				if _, ok := got.Header[k]; ok {
+					t.Errorf("Header %q was not removed", k)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	cm "knative.dev/pkg/configmap"
+)
+
+const (
+	// DropPodTagKey is the config-observability key to drop the pod name
+	// tag from request metrics.
+	DropPodTagKey = "metrics.request-drop-pod-tag"
+
+	// ResponseCodeClassOnlyKey is the config-observability key to tag
+	// request metrics with the response code class (e.g. 5xx) only.
+	ResponseCodeClassOnlyKey = "metrics.request-response-code-class-only"
+
+	// MaxRouteTagsKey is the config-observability key for the number of
+	// distinct route tags request metrics are tagged with. Further route
+	// tags are reported as OtherValue. Zero disables the route tag.
+	MaxRouteTagsKey = "metrics.request-max-route-tags"
+
+	// MaxSourceServicesKey is the config-observability key for the number
+	// of distinct calling services caller metrics are tagged with. Further
+	// calling services are reported as OtherValue, as are all of them if zero.
+	MaxSourceServicesKey = "metrics.request-max-source-services"
+
+	// AggregateRetiredRevisionsKey is the config-observability key to report
+	// metrics of revisions that no longer receive traffic as OtherValue.
+	AggregateRetiredRevisionsKey = "metrics.request-aggregate-retired-revisions"
+
+	// OtherValue is the tag value that aggregated values are reported as.
+	OtherValue = "other"
+
+	// DefaultMaxSourceServices is the default number of distinct calling
+	// services caller metrics are tagged with.
+	DefaultMaxSourceServices = 100
+)
+
+// CardinalityConfig controls the number of series request metrics produce.
+type CardinalityConfig struct {
+	DropPodTag                bool
+	ResponseCodeClassOnly     bool
+	MaxRouteTags              int
+	MaxSourceServices         int
+	AggregateRetiredRevisions bool
+}
+
+// NewCardinalityConfigFromMap creates a CardinalityConfig from the supplied map.
+func NewCardinalityConfigFromMap(data map[string]string) (*CardinalityConfig, error) {
+	c := &CardinalityConfig{MaxSourceServices: DefaultMaxSourceServices}
+	if err := cm.Parse(data,
+		cm.AsBool(DropPodTagKey, &c.DropPodTag),
+		cm.AsBool(ResponseCodeClassOnlyKey, &c.ResponseCodeClassOnly),
+		cm.AsInt(MaxRouteTagsKey, &c.MaxRouteTags),
+		cm.AsInt(MaxSourceServicesKey, &c.MaxSourceServices),
+		cm.AsBool(AggregateRetiredRevisionsKey, &c.AggregateRetiredRevisions),
+	); err != nil {
+		return nil, err
+	}
+	if c.MaxRouteTags < 0 {
+		c.MaxRouteTags = 0
+	}
+	if c.MaxSourceServices < 0 {
+		c.MaxSourceServices = 0
+	}
+	return c, nil
+}
+
+// NewCardinalityConfigFromConfigMap creates a CardinalityConfig from the supplied ConfigMap.
+func NewCardinalityConfigFromConfigMap(config *corev1.ConfigMap) (*CardinalityConfig, error) {
+	return NewCardinalityConfigFromMap(config.Data)
+}
+
+var (
+	cardinality atomic.Value
+
+	routeTagsMu sync.Mutex
+	routeTags   = sets.NewString()
+
+	sourceServicesMu sync.Mutex
+	sourceServices   = sets.NewString()
+)
+
+func init() {
+	cardinality.Store(&CardinalityConfig{MaxSourceServices: DefaultMaxSourceServices})
+}
+
+// SetCardinalityConfig sets the config applied to the metric contexts
+// created by this package from now on.
+func SetCardinalityConfig(c *CardinalityConfig) {
+	cardinality.Store(c)
+	// Cached contexts were created with the previous config.
+	contextCache.Purge()
+	routeTagsMu.Lock()
+	routeTags = sets.NewString()
+	routeTagsMu.Unlock()
+	sourceServicesMu.Lock()
+	sourceServices = sets.NewString()
+	sourceServicesMu.Unlock()
+}
+
+// UpdateCardinalityFromConfigMap returns a function that updates the
+// cardinality config from config-observability.
+func UpdateCardinalityFromConfigMap(logger *zap.SugaredLogger) func(*corev1.ConfigMap) {
+	return func(configMap *corev1.ConfigMap) {
+		c, err := NewCardinalityConfigFromConfigMap(configMap)
+		if err != nil {
+			logger.Errorw("Failed to parse metric cardinality config", zap.Error(err))
+			return
+		}
+		SetCardinalityConfig(c)
+	}
+}
+
+func currentCardinality() *CardinalityConfig {
+	return cardinality.Load().(*CardinalityConfig)
+}
+
+// RouteTagsEnabled returns whether request metrics are tagged with the route tag.
+func RouteTagsEnabled() bool {
+	return currentCardinality().MaxRouteTags > 0
+}
+
+// AggregatesRevision returns whether the metrics of a revision, which is
+// retired or not, are aggregated into OtherValue.
+func AggregatesRevision(retired bool) bool {
+	return retired && currentCardinality().AggregateRetiredRevisions
+}
+
+// RevisionName returns the revision name request metrics of a revision
+// are reported with, which is OtherValue for retired revisions if so configured.
+// Gauges must be recorded with RecordGauges instead.
+func RevisionName(rev string, retired bool) string {
+	if AggregatesRevision(retired) {
+		return OtherValue
+	}
+	return rev
+}
+
+// cappedRouteTag returns routeTag while fewer than the configured number of
+// distinct route tags were seen, and OtherValue after that.
+func cappedRouteTag(routeTag string) string {
+	max := currentCardinality().MaxRouteTags
+	routeTagsMu.Lock()
+	defer routeTagsMu.Unlock()
+	if routeTags.Has(routeTag) {
+		return routeTag
+	}
+	if routeTags.Len() >= max {
+		return OtherValue
+	}
+	routeTags.Insert(routeTag)
+	return routeTag
+}
+
+// cappedSourceService returns source while fewer than the configured number
+// of distinct calling services were seen, and OtherValue after that.
+func cappedSourceService(source string) string {
+	max := currentCardinality().MaxSourceServices
+	sourceServicesMu.Lock()
+	defer sourceServicesMu.Unlock()
+	if sourceServices.Has(source) {
+		return source
+	}
+	if sourceServices.Len() >= max {
+		return OtherValue
+	}
+	sourceServices.Insert(source)
+	return source
+}
+
+// DeepCopy returns a copy of the CardinalityConfig.
+func (c *CardinalityConfig) DeepCopy() *CardinalityConfig {
+	if c == nil {
+		return nil
+	}
+	out := *c
+	return &out
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opencensus.io/resource"
+	"go.opencensus.io/tag"
+	pkgmetrics "knative.dev/pkg/metrics"
+	"knative.dev/pkg/metrics/metricskey"
+	"knative.dev/pkg/metrics/metricstest"
+)
+
+func TestNewCardinalityConfigFromMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		want    *CardinalityConfig
+		wantErr bool
+	}{{
+		name: "defaults",
+		data: map[string]string{},
+		want: &CardinalityConfig{MaxSourceServices: DefaultMaxSourceServices},
+	}, {
+		name: "all options",
+		data: map[string]string{
+			DropPodTagKey:                "true",
+			ResponseCodeClassOnlyKey:     "true",
+			MaxRouteTagsKey:              "10",
+			MaxSourceServicesKey:         "20",
+			AggregateRetiredRevisionsKey: "true",
+		},
+		want: &CardinalityConfig{
+			DropPodTag:                true,
+			ResponseCodeClassOnly:     true,
+			MaxRouteTags:              10,
+			MaxSourceServices:         20,
+			AggregateRetiredRevisions: true,
+		},
+	}, {
+		name: "negative max route tags",
+		data: map[string]string{MaxRouteTagsKey: "-1"},
+		want: &CardinalityConfig{MaxSourceServices: DefaultMaxSourceServices},
+	}, {
+		name: "negative max source services",
+		data: map[string]string{MaxSourceServicesKey: "-1"},
+		want: &CardinalityConfig{},
+	}, {
+		name:    "invalid max route tags",
+		data:    map[string]string{MaxRouteTagsKey: "many"},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := NewCardinalityConfigFromMap(test.data)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("NewCardinalityConfigFromMap() = %v, wantErr: %v", err, test.wantErr)
+			}
+			if !cmp.Equal(got, test.want) {
+				t.Error("NewCardinalityConfigFromMap() (-want, +got):", cmp.Diff(test.want, got))
+			}
+		})
+	}
+}
+
+func TestReducedCardinalityContexts(t *testing.T) {
+	SetCardinalityConfig(&CardinalityConfig{
+		DropPodTag:                true,
+		ResponseCodeClassOnly:     true,
+		AggregateRetiredRevisions: true,
+	})
+	defer SetCardinalityConfig(&CardinalityConfig{})
+	cancel := register(t)
+	defer cancel()
+
+	ctx, err := PodRevisionContext("testpod", "testcontainer", "testns", "testsvc", "testcfg",
+		RevisionName("testrev", true /*retired*/))
+	if err != nil {
+		t.Fatal("PodRevisionContext() =", err)
+	}
+	pkgmetrics.Record(AugmentWithResponse(ctx, 503), testM.M(42))
+
+	metricstest.AssertMetric(t, metricstest.IntMetric("test_metric", 42, map[string]string{
+		metricskey.ContainerName:          "testcontainer",
+		metricskey.LabelResponseCodeClass: "5xx",
+	}).WithResource(&resource.Resource{
+		Type: "knative_revision",
+		Labels: map[string]string{
+			metricskey.LabelNamespaceName:     "testns",
+			metricskey.LabelServiceName:       "testsvc",
+			metricskey.LabelConfigurationName: "testcfg",
+			metricskey.LabelRevisionName:      OtherValue,
+		},
+	}))
+}
+
+func TestRevisionName(t *testing.T) {
+	if got, want := RevisionName("rev", true), "rev"; got != want {
+		t.Errorf("RevisionName() = %q, want: %q", got, want)
+	}
+
+	SetCardinalityConfig(&CardinalityConfig{AggregateRetiredRevisions: true})
+	defer SetCardinalityConfig(&CardinalityConfig{})
+	if got, want := RevisionName("rev", false), "rev"; got != want {
+		t.Errorf("RevisionName() = %q, want: %q", got, want)
+	}
+	if got, want := RevisionName("rev", true), OtherValue; got != want {
+		t.Errorf("RevisionName() = %q, want: %q", got, want)
+	}
+}
+
+func TestCappedRouteTags(t *testing.T) {
+	SetCardinalityConfig(&CardinalityConfig{MaxRouteTags: 2})
+	defer SetCardinalityConfig(&CardinalityConfig{})
+	if !RouteTagsEnabled() {
+		t.Error("RouteTagsEnabled() = false, want: true")
+	}
+
+	for _, tc := range []struct {
+		tag, want string
+	}{{
+		tag: "a", want: "a",
+	}, {
+		tag: "b", want: "b",
+	}, {
+		tag: "c", want: OtherValue,
+	}, {
+		tag: "a", want: "a",
+	}} {
+		ctx := AugmentWithResponseAndRouteTag(context.Background(), 200, tc.tag)
+		if got, _ := tag.FromContext(ctx).Value(RouteTagKey); got != tc.want {
+			t.Errorf("route tag for %q = %q, want: %q", tc.tag, got, tc.want)
+		}
+	}
+}
+
+func TestCappedSourceServices(t *testing.T) {
+	SetCardinalityConfig(&CardinalityConfig{MaxSourceServices: 2})
+	defer SetCardinalityConfig(&CardinalityConfig{MaxSourceServices: DefaultMaxSourceServices})
+
+	for _, tc := range []struct {
+		source, want string
+	}{{
+		source: "ns/a", want: "ns/a",
+	}, {
+		source: "ns/b", want: "ns/b",
+	}, {
+		source: "ns/c", want: OtherValue,
+	}, {
+		source: "ns/a", want: "ns/a",
+	}, {
+		source: "", want: metricskey.ValueUnknown,
+	}} {
+		ctx := AugmentWithSourceService(context.Background(), tc.source)
+		if got, _ := tag.FromContext(ctx).Value(SourceServiceKey); got != tc.want {
+			t.Errorf("source service for %q = %q, want: %q", tc.source, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"sync"
+
+	"go.opencensus.io/stats"
+	pkgmetrics "knative.dev/pkg/metrics"
+)
+
+// revisionCtxKey is the context key of the revisionCtx of the contexts
+// created by RevisionContext.
+type revisionCtxKey struct{}
+
+var (
+	retiredGaugesMu sync.Mutex
+	// retiredGauges holds the last recorded gauges of the retired revisions
+	// by the OtherValue revision they're aggregated into, then by revision.
+	retiredGauges = map[revisionCtx]map[string]map[stats.Measure]float64{}
+)
+
+// RecordGauges records the measurements of LastValue views with ctx, a
+// context created by RevisionContext. If the revision is retired and such
+// revisions are aggregated, the measurements are summed up with the ones of
+// the other retired revisions of the same configuration and recorded as
+// OtherValue, since recording them as OtherValue as they are would report
+// the value of whichever retired revision was recorded last.
+func RecordGauges(ctx context.Context, retired bool, ms ...stats.Measurement) {
+	key, ok := ctx.Value(revisionCtxKey{}).(revisionCtx)
+	if !ok {
+		pkgmetrics.RecordBatch(ctx, ms...)
+		return
+	}
+
+	retiredGaugesMu.Lock()
+	defer retiredGaugesMu.Unlock()
+	if !AggregatesRevision(retired) {
+		forgetGauges(key)
+		pkgmetrics.RecordBatch(ctx, ms...)
+		return
+	}
+
+	other := key
+	other.revision = OtherValue
+	revisions, ok := retiredGauges[other]
+	if !ok {
+		revisions = map[string]map[stats.Measure]float64{}
+		retiredGauges[other] = revisions
+	}
+	values, ok := revisions[key.revision]
+	if !ok {
+		values = make(map[stats.Measure]float64, len(ms))
+		revisions[key.revision] = values
+	}
+	measures := make([]stats.Measure, 0, len(ms))
+	for _, m := range ms {
+		values[m.Measure()] = m.Value()
+		measures = append(measures, m.Measure())
+	}
+	recordSums(other, measures)
+}
+
+// ForgetGauges drops the gauges recorded for the revision from the
+// aggregated ones, e.g. because the revision was deleted.
+func ForgetGauges(ns, svc, cfg, rev string) {
+	retiredGaugesMu.Lock()
+	defer retiredGaugesMu.Unlock()
+	forgetGauges(revisionCtx{namespace: ns, service: svc, configuration: cfg, revision: rev})
+}
+
+// forgetGauges drops the gauges recorded for key and records the sums of
+// the remaining ones. retiredGaugesMu must be held.
+func forgetGauges(key revisionCtx) {
+	other := key
+	other.revision = OtherValue
+	values, ok := retiredGauges[other][key.revision]
+	if !ok {
+		return
+	}
+	delete(retiredGauges[other], key.revision)
+
+	measures := make([]stats.Measure, 0, len(values))
+	for m := range values {
+		measures = append(measures, m)
+	}
+	recordSums(other, measures)
+	if len(retiredGauges[other]) == 0 {
+		delete(retiredGauges, other)
+	}
+}
+
+// recordSums records the sums of the given measures over the revisions
+// aggregated into other. retiredGaugesMu must be held.
+func recordSums(other revisionCtx, measures []stats.Measure) {
+	sums := make([]stats.Measurement, 0, len(measures))
+	for _, m := range measures {
+		var sum float64
+		for _, values := range retiredGauges[other] {
+			sum += values[m]
+		}
+		sums = append(sums, measurement(m, sum))
+	}
+	pkgmetrics.RecordBatch(
+		RevisionContext(other.namespace, other.service, other.configuration, other.revision), sums...)
+}
+
+func measurement(m stats.Measure, v float64) stats.Measurement {
+	if m, ok := m.(*stats.Int64Measure); ok {
+		return m.M(int64(v))
+	}
+	return m.(*stats.Float64Measure).M(v)
+}
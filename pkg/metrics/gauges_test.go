@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	"knative.dev/pkg/metrics/metricskey"
+	"knative.dev/pkg/metrics/metricstest"
+)
+
+// gaugeValue returns the value of test_metric recorded for the revision.
+func gaugeValue(t *testing.T, rev string) (int64, bool) {
+	t.Helper()
+	metricstest.EnsureRecorded()
+	for _, m := range metricstest.GetMetric(testM.Name()) {
+		if m.Resource.Labels[metricskey.LabelRevisionName] == rev && len(m.Values) == 1 {
+			return *m.Values[0].Int64, true
+		}
+	}
+	return 0, false
+}
+
+func TestRecordGauges(t *testing.T) {
+	SetCardinalityConfig(&CardinalityConfig{AggregateRetiredRevisions: true})
+	defer SetCardinalityConfig(&CardinalityConfig{})
+	cancel := register(t)
+	defer cancel()
+
+	RecordGauges(RevisionContext("testns", "testsvc", "testcfg", "rev-1"), true /*retired*/, testM.M(2))
+	RecordGauges(RevisionContext("testns", "testsvc", "testcfg", "rev-2"), true /*retired*/, testM.M(3))
+	RecordGauges(RevisionContext("testns", "testsvc", "testcfg", "rev-3"), false /*retired*/, testM.M(7))
+
+	if got, _ := gaugeValue(t, OtherValue); got != 5 {
+		t.Errorf("Aggregated gauge = %d, want: 5", got)
+	}
+	if got, _ := gaugeValue(t, "rev-3"); got != 7 {
+		t.Errorf("Gauge of rev-3 = %d, want: 7", got)
+	}
+	if _, ok := gaugeValue(t, "rev-1"); ok {
+		t.Error("Gauge of the retired rev-1 was reported on its own")
+	}
+
+	// Updates replace the revision's previous value.
+	RecordGauges(RevisionContext("testns", "testsvc", "testcfg", "rev-2"), true /*retired*/, testM.M(1))
+	if got, _ := gaugeValue(t, OtherValue); got != 3 {
+		t.Errorf("Aggregated gauge = %d, want: 3", got)
+	}
+
+	// Revisions that are routed to again are reported on their own.
+	RecordGauges(RevisionContext("testns", "testsvc", "testcfg", "rev-1"), false /*retired*/, testM.M(4))
+	if got, _ := gaugeValue(t, OtherValue); got != 1 {
+		t.Errorf("Aggregated gauge = %d, want: 1", got)
+	}
+	if got, _ := gaugeValue(t, "rev-1"); got != 4 {
+		t.Errorf("Gauge of rev-1 = %d, want: 4", got)
+	}
+
+	ForgetGauges("testns", "testsvc", "testcfg", "rev-2")
+	if got, _ := gaugeValue(t, OtherValue); got != 0 {
+		t.Errorf("Aggregated gauge = %d, want: 0", got)
+	}
+}
+
+func TestRecordGaugesNotAggregated(t *testing.T) {
+	cancel := register(t)
+	defer cancel()
+
+	RecordGauges(RevisionContext("testns", "testsvc", "testcfg", "rev-1"), true /*retired*/, testM.M(2))
+	if got, _ := gaugeValue(t, "rev-1"); got != 2 {
+		t.Errorf("Gauge of rev-1 = %d, want: 2", got)
+	}
+	if _, ok := gaugeValue(t, OtherValue); ok {
+		t.Error("Gauge of rev-1 was aggregated")
+	}
+}
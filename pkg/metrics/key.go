@@ -32,4 +32,5 @@ var (
 	ResponseCodeKey      = tag.MustNewKey(metricskey.LabelResponseCode)
 	ResponseCodeClassKey = tag.MustNewKey(metricskey.LabelResponseCodeClass)
 	RouteTagKey          = tag.MustNewKey("tag")
+	SourceServiceKey     = tag.MustNewKey("source_service")
 )
@@ -19,9 +19,10 @@ package metrics
 import (
 	"context"
 	"strconv"
+	"strings"
 
 	lru "github.com/hashicorp/golang-lru"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/metrics/metricskey"
 
 	"go.opencensus.io/resource"
@@ -57,16 +58,21 @@ func valueOrUnknown(v string) string {
 // RevisionContext generates a new base metric reporting context containing
 // the respective revision specific tags.
 func RevisionContext(ns, svc, cfg, rev string) context.Context {
-	key := types.NamespacedName{Namespace: ns, Name: rev}
+	key := revisionCtx{namespace: ns, service: svc, configuration: cfg, revision: rev}
 	ctx, ok := contextCache.Get(key)
 	if !ok {
-		rctx := AugmentWithRevision(context.Background(), ns, svc, cfg, rev)
+		rctx := context.WithValue(AugmentWithRevision(context.Background(), ns, svc, cfg, rev), revisionCtxKey{}, key)
 		contextCache.Add(key, rctx)
 		ctx = rctx
 	}
 	return ctx.(context.Context)
 }
 
+// revisionCtx is keyed by all labels since aggregated revisions share a name.
+type revisionCtx struct {
+	namespace, service, configuration, revision string
+}
+
 type podCtx struct {
 	pod, container string
 }
@@ -77,10 +83,11 @@ func PodContext(pod, container string) (context.Context, error) {
 	key := podCtx{pod: pod, container: container}
 	ctx, ok := contextCache.Get(key)
 	if !ok {
-		rctx, err := tag.New(
-			context.Background(),
-			tag.Upsert(PodTagKey, pod),
-			tag.Upsert(ContainerTagKey, container))
+		mutators := []tag.Mutator{tag.Upsert(ContainerTagKey, container)}
+		if !currentCardinality().DropPodTag {
+			mutators = append(mutators, tag.Upsert(PodTagKey, pod))
+		}
+		rctx, err := tag.New(context.Background(), mutators...)
 		if err != nil {
 			return rctx, err
 		}
@@ -92,7 +99,7 @@ func PodContext(pod, container string) (context.Context, error) {
 
 type podRevisionCtx struct {
 	pod      podCtx
-	revision types.NamespacedName
+	revision revisionCtx
 }
 
 // PodRevisionContext generates a new base metric reporting context containing
@@ -100,7 +107,7 @@ type podRevisionCtx struct {
 func PodRevisionContext(pod, container, ns, svc, cfg, rev string) (context.Context, error) {
 	key := podRevisionCtx{
 		pod:      podCtx{pod: pod, container: container},
-		revision: types.NamespacedName{Namespace: ns, Name: rev},
+		revision: revisionCtx{namespace: ns, service: svc, configuration: cfg, revision: rev},
 	}
 	ctx, ok := contextCache.Get(key)
 	if !ok {
@@ -131,23 +138,48 @@ func AugmentWithRevision(baseCtx context.Context, ns, svc, cfg, rev string) cont
 
 // AugmentWithResponse augments the given context with response-code specific tags.
 func AugmentWithResponse(baseCtx context.Context, responseCode int) context.Context {
-	ctx, _ := tag.New(
-		baseCtx,
-		tag.Upsert(ResponseCodeKey, strconv.Itoa(responseCode)),
-		tag.Upsert(ResponseCodeClassKey, responseCodeClass(responseCode)))
+	ctx, _ := tag.New(baseCtx, responseMutators(responseCode)...)
 	return ctx
 }
 
 // AugmentWithResponseAndRouteTag augments the given context with response-code and route-tag specific tags.
 func AugmentWithResponseAndRouteTag(baseCtx context.Context, responseCode int, routeTag string) context.Context {
-	ctx, _ := tag.New(
-		baseCtx,
-		tag.Upsert(ResponseCodeKey, strconv.Itoa(responseCode)),
-		tag.Upsert(ResponseCodeClassKey, responseCodeClass(responseCode)),
-		tag.Upsert(RouteTagKey, routeTag))
+	ctx, _ := tag.New(baseCtx,
+		append(responseMutators(responseCode), tag.Upsert(RouteTagKey, cappedRouteTag(routeTag)))...)
+	return ctx
+}
+
+// AugmentWithSourceService augments the given context with the calling
+// service tag, reported as unknown if source is empty.
+func AugmentWithSourceService(baseCtx context.Context, source string) context.Context {
+	if source != "" {
+		source = cappedSourceService(source)
+	}
+	ctx, _ := tag.New(baseCtx, tag.Upsert(SourceServiceKey, valueOrUnknown(source)))
 	return ctx
 }
 
+// SourceService validates the value of a source service header, returning
+// it if it is of the form "<namespace>/<name>" and the empty string otherwise.
+func SourceService(header string) string {
+	i := strings.IndexByte(header, '/')
+	if i < 0 {
+		return ""
+	}
+	if len(validation.IsDNS1123Label(header[:i])) != 0 || len(validation.IsDNS1123Label(header[i+1:])) != 0 {
+		return ""
+	}
+	return header
+}
+
+func responseMutators(responseCode int) []tag.Mutator {
+	mutators := []tag.Mutator{tag.Upsert(ResponseCodeClassKey, responseCodeClass(responseCode))}
+	if !currentCardinality().ResponseCodeClassOnly {
+		mutators = append(mutators, tag.Upsert(ResponseCodeKey, strconv.Itoa(responseCode)))
+	}
+	return mutators
+}
+
 // responseCodeClass converts response code to a string of response code class.
 // e.g. The response code class is "5xx" for response code 503.
 func responseCodeClass(responseCode int) string {
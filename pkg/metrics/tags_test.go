@@ -252,6 +252,24 @@ func BenchmarkPodRevisionContext(b *testing.B) {
 	}
 }
 
+func TestSourceService(t *testing.T) {
+	for header, want := range map[string]string{
+		"":                 "",
+		"default/caller":   "default/caller",
+		"caller":           "",
+		"default/":         "",
+		"/caller":          "",
+		"default/a/b":      "",
+		"Default/caller":   "",
+		"default/caller!":  "",
+		"my-ns/my-service": "my-ns/my-service",
+	} {
+		if got := SourceService(header); got != want {
+			t.Errorf("SourceService(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
 func mustCtx(t *testing.T, f func() (context.Context, error)) context.Context {
 	t.Helper()
 
@@ -16,7 +16,11 @@ limitations under the License.
 
 package networking
 
-import "knative.dev/networking/pkg/apis/networking"
+import (
+	"strings"
+
+	"knative.dev/networking/pkg/apis/networking"
+)
 
 // The ports we setup on our services.
 const (
@@ -30,6 +34,11 @@ const (
 	// health check and lifecycle hooks for queue-proxy.
 	QueueAdminPort = 8022
 
+	// QueueOutboundPort specifies the port number the queue-proxy proxies
+	// the user-container's outbound HTTP calls on. It is only bound on
+	// localhost and is never exposed as a container or service port.
+	QueueOutboundPort = 8014
+
 	// AutoscalingQueueMetricsPort specifies the port number for metrics emitted
 	// by queue-proxy for autoscaler.
 	AutoscalingQueueMetricsPort = 9090
@@ -41,6 +50,11 @@ const (
 	// ActivatorServiceName is the name of the activator Kubernetes service.
 	ActivatorServiceName = "activator-service"
 
+	// ActivatorPoolLabelKey is the namespace label selecting a dedicated
+	// activator pool for the revisions in that namespace. Namespaces
+	// without the label are served by the default activator pool.
+	ActivatorPoolLabelKey = networking.GroupName + "/activator-pool"
+
 	// SKSLabelKey is the label key that SKS Controller attaches to the
 	// underlying resources it controls.
 	SKSLabelKey = networking.GroupName + "/serverlessservice"
@@ -62,3 +76,19 @@ const (
 	// services for user applications.
 	ServiceTypePublic ServiceType = "Public"
 )
+
+// ActivatorPoolServiceName returns the name of the Kubernetes service
+// fronting the activators of the given pool. The empty pool is the
+// default activator pool.
+func ActivatorPoolServiceName(pool string) string {
+	if pool == "" {
+		return ActivatorServiceName
+	}
+	return ActivatorServiceName + "-" + pool
+}
+
+// IsActivatorServiceName returns true if name is the service name of the
+// default activator pool or of any dedicated pool.
+func IsActivatorServiceName(name string) bool {
+	return name == ActivatorServiceName || strings.HasPrefix(name, ActivatorServiceName+"-")
+}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"time"
 
 	"go.uber.org/atomic"
 )
@@ -52,6 +53,10 @@ type Breaker struct {
 	totalSlots int64
 	sem        *semaphore
 
+	// latency is the moving average, in nanoseconds, of the time thunks
+	// hold their slot, used to estimate the wait for capacity.
+	latency atomic.Int64
+
 	// release is the callback function returned to callers by Reserve to
 	// allow the reservation made by Reserve to be released.
 	release func()
@@ -154,11 +159,42 @@ func (b *Breaker) Maybe(ctx context.Context, thunk func()) error {
 	defer b.sem.release()
 
 	// Do the thing.
+	start := time.Now()
 	thunk()
+	b.observeLatency(time.Since(start))
 	// Report success
 	return nil
 }
 
+// observeLatency folds d into the moving average of the time thunks hold
+// their slot.
+func (b *Breaker) observeLatency(d time.Duration) {
+	for {
+		cur := b.latency.Load()
+		next := int64(d)
+		if cur > 0 {
+			// Weigh the new sample by 1/8th.
+			next = cur + (int64(d)-cur)/8
+		}
+		if b.latency.CAS(cur, next) {
+			return
+		}
+	}
+}
+
+// ExpectedWait estimates how long a request arriving now would wait for
+// capacity, from the number of requests ahead of it and the average time
+// requests hold their slot. It is zero while there is spare capacity, or
+// no capacity at all, since the wait is unknown then.
+func (b *Breaker) ExpectedWait() time.Duration {
+	capacity := int64(b.Capacity())
+	ahead := int64(b.InFlight()) - capacity + 1
+	if capacity == 0 || ahead <= 0 {
+		return 0
+	}
+	return time.Duration(ahead * b.latency.Load() / capacity)
+}
+
 // InFlight returns the number of requests currently in flight in this breaker.
 func (b *Breaker) InFlight() int {
 	return int(b.inFlight.Load())
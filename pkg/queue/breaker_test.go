@@ -223,6 +223,38 @@ func TestBreakerUpdateConcurrency(t *testing.T) {
 
 }
 
+func TestBreakerExpectedWait(t *testing.T) {
+	b := NewBreaker(BreakerParams{QueueDepth: 10, MaxConcurrency: 2, InitialCapacity: 2})
+	if got := b.ExpectedWait(); got != 0 {
+		t.Errorf("ExpectedWait() without requests = %v, want 0", got)
+	}
+
+	b.observeLatency(100 * time.Millisecond)
+	b.observeLatency(180 * time.Millisecond)
+	if got, want := time.Duration(b.latency.Load()), 110*time.Millisecond; got != want {
+		t.Errorf("latency = %v, want %v", got, want)
+	}
+
+	// One request in flight leaves a free slot.
+	b.inFlight.Store(1)
+	if got := b.ExpectedWait(); got != 0 {
+		t.Errorf("ExpectedWait() with spare capacity = %v, want 0", got)
+	}
+
+	// Two in flight and two queued: the next request waits for three
+	// requests over two slots.
+	b.inFlight.Store(4)
+	if got, want := b.ExpectedWait(), 165*time.Millisecond; got != want {
+		t.Errorf("ExpectedWait() = %v, want %v", got, want)
+	}
+
+	// The wait is unknown without capacity.
+	b.UpdateConcurrency(0)
+	if got := b.ExpectedWait(); got != 0 {
+		t.Errorf("ExpectedWait() without capacity = %v, want 0", got)
+	}
+}
+
 // Test empty semaphore, token cannot be acquired
 func TestSemaphoreAcquireHasNoCapacity(t *testing.T) {
 	gotChan := make(chan struct{}, 1)
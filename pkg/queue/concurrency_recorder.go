@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"sync"
+	"time"
+
+	network "knative.dev/networking/pkg"
+)
+
+// ConcurrencyRecorder records the distribution of the concurrency within each
+// reporting period, so that short spikes that are averaged away in the
+// period's mean concurrency remain visible. It is fed the same events as
+// network.RequestStats.
+type ConcurrencyRecorder struct {
+	// percentile is the percentile of the concurrency to report, 0 if none.
+	percentile float64
+
+	mux sync.Mutex
+
+	// State variables that track the current state. Not reset after reporting.
+	concurrency int
+	lastChange  time.Time
+
+	// Reporting variables that track state over the current window. Reset after
+	// reporting.
+	// timeAt holds the time spent at each concurrency level, indexed by level.
+	timeAt         []time.Duration
+	maxConcurrency int
+}
+
+// ConcurrencyReport reports the distribution of the concurrency over the
+// reporting timeframe.
+type ConcurrencyReport struct {
+	// MaxConcurrency is the highest concurrency observed in the reporting timeframe.
+	MaxConcurrency float64
+	// PercentileConcurrency is the concurrency at the configured percentile, i.e.
+	// the number of requests in flight that was not exceeded for that percentage
+	// of the reporting timeframe. Zero if no percentile is configured.
+	PercentileConcurrency float64
+}
+
+// NewConcurrencyRecorder builds a ConcurrencyRecorder instance, started at the
+// given time. percentile is the percentile of the concurrency to report in
+// addition to its maximum, in the (0, 100) range, or 0 to only report the maximum.
+func NewConcurrencyRecorder(percentile float64, startedAt time.Time) *ConcurrencyRecorder {
+	return &ConcurrencyRecorder{
+		percentile: percentile,
+		lastChange: startedAt,
+	}
+}
+
+// compute updates the time spent at the current concurrency since the last
+// computed change. Like network.RequestStats, it ignores negative changes.
+func (r *ConcurrencyRecorder) compute(now time.Time) {
+	if d := now.Sub(r.lastChange); d > 0 {
+		for len(r.timeAt) <= r.concurrency {
+			r.timeAt = append(r.timeAt, 0)
+		}
+		r.timeAt[r.concurrency] += d
+		r.lastChange = now
+	}
+}
+
+// HandleEvent handles an incoming or outgoing request event and updates
+// the state accordingly.
+func (r *ConcurrencyRecorder) HandleEvent(event network.ReqEvent) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.compute(event.Time)
+
+	switch event.Type {
+	case network.ReqIn, network.ProxiedIn:
+		r.concurrency++
+		if r.concurrency > r.maxConcurrency {
+			r.maxConcurrency = r.concurrency
+		}
+	case network.ReqOut, network.ProxiedOut:
+		if r.concurrency > 0 {
+			r.concurrency--
+		}
+	}
+}
+
+// Report returns a ConcurrencyReport relative to the given time. The state
+// will be reset for another reporting cycle afterwards.
+func (r *ConcurrencyRecorder) Report(now time.Time) ConcurrencyReport {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.compute(now)
+	defer r.reset()
+
+	report := ConcurrencyReport{
+		MaxConcurrency: float64(r.maxConcurrency),
+	}
+	if r.percentile > 0 {
+		report.PercentileConcurrency = float64(r.percentileConcurrency())
+	}
+	return report
+}
+
+// percentileConcurrency returns the lowest concurrency level at or below which
+// the configured percentile of the reporting timeframe was spent.
+// mux needs to be held.
+func (r *ConcurrencyRecorder) percentileConcurrency() int {
+	var total time.Duration
+	for _, d := range r.timeAt {
+		total += d
+	}
+	if total == 0 {
+		return r.concurrency
+	}
+
+	threshold := float64(total) * r.percentile / 100
+	var cumulative time.Duration
+	for level, d := range r.timeAt {
+		cumulative += d
+		if float64(cumulative) >= threshold {
+			return level
+		}
+	}
+	return len(r.timeAt) - 1
+}
+
+// reset resets the state so a new reporting cycle can start. The requests that
+// are still in flight carry over into the next cycle's maximum.
+// mux needs to be held.
+func (r *ConcurrencyRecorder) reset() {
+	for i := range r.timeAt {
+		r.timeAt[i] = 0
+	}
+	r.maxConcurrency = r.concurrency
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	network "knative.dev/networking/pkg"
+)
+
+func TestConcurrencyRecorderDistribution(t *testing.T) {
+	start := time.Now()
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	recorder := NewConcurrencyRecorder(90, start)
+	// 1 request for the first 500ms, a spike to 4 for 100ms and back to 1
+	// for the remaining 400ms.
+	recorder.HandleEvent(network.ReqEvent{Time: at(0), Type: network.ReqIn})
+	for i := 0; i < 3; i++ {
+		recorder.HandleEvent(network.ReqEvent{Time: at(500 * time.Millisecond), Type: network.ReqIn})
+	}
+	for i := 0; i < 3; i++ {
+		recorder.HandleEvent(network.ReqEvent{Time: at(600 * time.Millisecond), Type: network.ProxiedOut})
+	}
+
+	got := recorder.Report(at(time.Second))
+	if got, want := got.MaxConcurrency, 4.; got != want {
+		t.Errorf("MaxConcurrency = %v, want: %v", got, want)
+	}
+	if got, want := got.PercentileConcurrency, 1.; got != want {
+		t.Errorf("PercentileConcurrency = %v, want: %v", got, want)
+	}
+
+	// The in-flight request carries over into the next period.
+	got = recorder.Report(at(2 * time.Second))
+	if got, want := got.MaxConcurrency, 1.; got != want {
+		t.Errorf("MaxConcurrency after reset = %v, want: %v", got, want)
+	}
+	if got, want := got.PercentileConcurrency, 1.; got != want {
+		t.Errorf("PercentileConcurrency after reset = %v, want: %v", got, want)
+	}
+}
+
+func TestConcurrencyRecorderPercentile(t *testing.T) {
+	tests := []struct {
+		name       string
+		percentile float64
+		want       float64
+	}{{
+		name: "no percentile",
+	}, {
+		name:       "median",
+		percentile: 50,
+		want:       1,
+	}, {
+		name:       "p75",
+		percentile: 75,
+		want:       2,
+	}, {
+		name:       "p99",
+		percentile: 99,
+		want:       3,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start := time.Now()
+			recorder := NewConcurrencyRecorder(test.percentile, start)
+			// Concurrency 0 for 250ms, then 1, 2 and 3 for 250ms each.
+			for i := 1; i <= 3; i++ {
+				recorder.HandleEvent(network.ReqEvent{Time: start.Add(time.Duration(i) * 250 * time.Millisecond), Type: network.ReqIn})
+			}
+			got := recorder.Report(start.Add(time.Second))
+			if got.PercentileConcurrency != test.want {
+				t.Errorf("PercentileConcurrency = %v, want: %v", got.PercentileConcurrency, test.want)
+			}
+			if got.MaxConcurrency != 3 {
+				t.Errorf("MaxConcurrency = %v, want: 3", got.MaxConcurrency)
+			}
+		})
+	}
+}
+
+func TestConcurrencyRecordingProxyHandler(t *testing.T) {
+	stats := network.NewRequestStats(time.Now())
+	recorder := NewConcurrencyRecorder(0, time.Now())
+	h := ConcurrencyRecordingProxyHandler(nil, stats, recorder, false, /*tracingEnabled*/
+		http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	for i := 0; i < 2; i++ {
+		h(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://example.com", nil))
+	}
+
+	now := time.Now()
+	if got, want := stats.Report(now).RequestCount, 2.; got != want {
+		t.Errorf("RequestCount = %v, want: %v", got, want)
+	}
+	if got, want := recorder.Report(now).MaxConcurrency, 1.; got != want {
+		t.Errorf("MaxConcurrency = %v, want: %v", got, want)
+	}
+}
@@ -26,4 +26,8 @@ const (
 	// Main usage is to delay the termination of user-container until all
 	// accepted requests have been processed.
 	RequestQueueDrainPath = "/wait-for-drain"
+
+	// WarmupUserAgent is the user agent of the warm-up requests the
+	// queue-proxy sends to the user-container before reporting it ready.
+	WarmupUserAgent = "Knative-Warmup"
 )
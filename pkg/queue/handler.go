@@ -30,6 +30,20 @@ import (
 // ProxyHandler sends requests to the `next` handler at a rate controlled by
 // the passed `breaker`, while recording stats to `stats`.
 func ProxyHandler(breaker *Breaker, stats *network.RequestStats, tracingEnabled bool, next http.Handler) http.HandlerFunc {
+	return proxyHandler(breaker, stats.HandleEvent, tracingEnabled, next)
+}
+
+// ConcurrencyRecordingProxyHandler is like ProxyHandler, but also records
+// the distribution of the concurrency to `recorder`, from the same events.
+func ConcurrencyRecordingProxyHandler(breaker *Breaker, stats *network.RequestStats, recorder *ConcurrencyRecorder,
+	tracingEnabled bool, next http.Handler) http.HandlerFunc {
+	return proxyHandler(breaker, func(event network.ReqEvent) {
+		stats.HandleEvent(event)
+		recorder.HandleEvent(event)
+	}, tracingEnabled, next)
+}
+
+func proxyHandler(breaker *Breaker, handleEvent func(network.ReqEvent), tracingEnabled bool, next http.Handler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if network.IsKubeletProbe(r) {
 			next.ServeHTTP(w, r)
@@ -47,9 +61,9 @@ func ProxyHandler(breaker *Breaker, stats *network.RequestStats, tracingEnabled
 		if activator.Name == network.KnativeProxyHeader(r) {
 			in, out = network.ProxiedIn, network.ProxiedOut
 		}
-		stats.HandleEvent(network.ReqEvent{Time: time.Now(), Type: in})
+		handleEvent(network.ReqEvent{Time: time.Now(), Type: in})
 		defer func() {
-			stats.HandleEvent(network.ReqEvent{Time: time.Now(), Type: out})
+			handleEvent(network.ReqEvent{Time: time.Now(), Type: out})
 		}()
 		network.RewriteHostOut(r)
 
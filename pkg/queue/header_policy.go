@@ -0,0 +1,156 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"knative.dev/pkg/websocket"
+	"knative.dev/serving/pkg/apis/serving"
+)
+
+// ResponseHeaderPolicyHandler applies the given policy to every response
+// of `next`. CORS preflight requests are answered directly, so it must be
+// placed in front of the ProxyHandler for them not to take a breaker slot.
+func ResponseHeaderPolicyHandler(policy *serving.ResponseHeaderPolicy, next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if policy.CORS != nil && isPreflight(r) {
+			applyHeaderPolicy(policy, r, w.Header())
+			writePreflight(policy.CORS, r, w)
+			return
+		}
+		next.ServeHTTP(&headerPolicyWriter{ResponseWriter: w, policy: policy, r: r}, r)
+	}
+}
+
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions &&
+		r.Header.Get("Origin") != "" &&
+		r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// writePreflight answers a CORS preflight request. Disallowed requests get
+// no CORS headers, which makes the browser fail the actual request.
+func writePreflight(cors *serving.CORSPolicy, r *http.Request, w http.ResponseWriter) {
+	h := w.Header()
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+	if cors.AllowsOrigin(r.Header.Get("Origin")) && cors.AllowsMethod(r.Header.Get("Access-Control-Request-Method")) {
+		if len(cors.AllowMethods) > 0 {
+			h.Set("Access-Control-Allow-Methods", strings.Join(cors.AllowMethods, ", "))
+		} else {
+			h.Set("Access-Control-Allow-Methods", r.Header.Get("Access-Control-Request-Method"))
+		}
+		if len(cors.AllowHeaders) > 0 {
+			h.Set("Access-Control-Allow-Headers", strings.Join(cors.AllowHeaders, ", "))
+		} else if rh := r.Header.Get("Access-Control-Request-Headers"); rh != "" {
+			h.Set("Access-Control-Allow-Headers", rh)
+		}
+		if cors.MaxAgeSeconds > 0 {
+			h.Set("Access-Control-Max-Age", strconv.Itoa(cors.MaxAgeSeconds))
+		}
+	} else {
+		h.Del("Access-Control-Allow-Origin")
+		h.Del("Access-Control-Allow-Credentials")
+		h.Del("Access-Control-Expose-Headers")
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// applyHeaderPolicy applies the policy to the response headers of r.
+func applyHeaderPolicy(policy *serving.ResponseHeaderPolicy, r *http.Request, h http.Header) {
+	for _, k := range policy.Remove {
+		h.Del(k)
+	}
+	for k, v := range policy.Set {
+		h.Set(k, v)
+	}
+	if policy.HSTS != nil {
+		h.Set("Strict-Transport-Security", policy.HSTS.HeaderValue())
+	}
+	if policy.ContentTypeNoSniff {
+		h.Set("X-Content-Type-Options", "nosniff")
+	}
+	if cors := policy.CORS; cors != nil {
+		// Unless any origin gets the same answer, responses depend on the origin.
+		anyOrigin := cors.AllowsOrigin("*") && !cors.AllowCredentials
+		if !anyOrigin {
+			h.Add("Vary", "Origin")
+		}
+		origin := r.Header.Get("Origin")
+		if origin == "" || !cors.AllowsOrigin(origin) {
+			return
+		}
+		if anyOrigin {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+		}
+		if cors.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+		if len(cors.ExposeHeaders) > 0 {
+			h.Set("Access-Control-Expose-Headers", strings.Join(cors.ExposeHeaders, ", "))
+		}
+	}
+}
+
+// headerPolicyWriter applies the policy to the response headers right
+// before they are written.
+type headerPolicyWriter struct {
+	http.ResponseWriter
+	policy      *serving.ResponseHeaderPolicy
+	r           *http.Request
+	wroteHeader bool
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *headerPolicyWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		applyHeaderPolicy(w.policy, w.r, w.ResponseWriter.Header())
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write implements http.ResponseWriter.
+func (w *headerPolicyWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush implements http.Flusher.
+func (w *headerPolicyWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack calls Hijack() on the wrapped http.ResponseWriter if it implements
+// http.Hijacker interface, which is required for connection upgrades.
+func (w *headerPolicyWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return websocket.HijackIfPossible(w.ResponseWriter)
+}
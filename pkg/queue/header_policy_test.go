@@ -0,0 +1,191 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"knative.dev/serving/pkg/apis/serving"
+)
+
+func TestResponseHeaderPolicyHandler(t *testing.T) {
+	policy := &serving.ResponseHeaderPolicy{
+		CORS: &serving.CORSPolicy{
+			AllowOrigins:  []string{"https://example.com"},
+			AllowMethods:  []string{http.MethodPut, http.MethodDelete},
+			ExposeHeaders: []string{"X-Request-Id"},
+			MaxAgeSeconds: 600,
+		},
+		HSTS:               &serving.HSTSPolicy{MaxAgeSeconds: 3600, IncludeSubDomains: true},
+		ContentTypeNoSniff: true,
+		Set:                map[string]string{"X-Frame-Options": "DENY"},
+		Remove:             []string{"Server"},
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		reqHeaders map[string]string
+		wantNext   bool
+		wantCode   int
+		want       http.Header
+	}{{
+		name:     "plain request",
+		method:   http.MethodGet,
+		wantNext: true,
+		wantCode: http.StatusOK,
+		want: http.Header{
+			"Content-Type":              {"text/plain"},
+			"Strict-Transport-Security": {"max-age=3600; includeSubDomains"},
+			"Vary":                      {"Origin"},
+			"X-Content-Type-Options":    {"nosniff"},
+			"X-Frame-Options":           {"DENY"},
+		},
+	}, {
+		name:       "allowed cross-origin request",
+		method:     http.MethodGet,
+		reqHeaders: map[string]string{"Origin": "https://example.com"},
+		wantNext:   true,
+		wantCode:   http.StatusOK,
+		want: http.Header{
+			"Access-Control-Allow-Origin":   {"https://example.com"},
+			"Access-Control-Expose-Headers": {"X-Request-Id"},
+			"Content-Type":                  {"text/plain"},
+			"Strict-Transport-Security":     {"max-age=3600; includeSubDomains"},
+			"Vary":                          {"Origin"},
+			"X-Content-Type-Options":        {"nosniff"},
+			"X-Frame-Options":               {"DENY"},
+		},
+	}, {
+		name:       "disallowed cross-origin request",
+		method:     http.MethodGet,
+		reqHeaders: map[string]string{"Origin": "https://evil.com"},
+		wantNext:   true,
+		wantCode:   http.StatusOK,
+		want: http.Header{
+			"Content-Type":              {"text/plain"},
+			"Strict-Transport-Security": {"max-age=3600; includeSubDomains"},
+			"Vary":                      {"Origin"},
+			"X-Content-Type-Options":    {"nosniff"},
+			"X-Frame-Options":           {"DENY"},
+		},
+	}, {
+		name:   "allowed preflight",
+		method: http.MethodOptions,
+		reqHeaders: map[string]string{
+			"Origin":                         "https://example.com",
+			"Access-Control-Request-Method":  http.MethodPut,
+			"Access-Control-Request-Headers": "X-Custom",
+		},
+		wantCode: http.StatusNoContent,
+		want: http.Header{
+			"Access-Control-Allow-Headers":  {"X-Custom"},
+			"Access-Control-Allow-Methods":  {"PUT, DELETE"},
+			"Access-Control-Allow-Origin":   {"https://example.com"},
+			"Access-Control-Expose-Headers": {"X-Request-Id"},
+			"Access-Control-Max-Age":        {"600"},
+			"Strict-Transport-Security":     {"max-age=3600; includeSubDomains"},
+			"Vary":                          {"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+			"X-Content-Type-Options":        {"nosniff"},
+			"X-Frame-Options":               {"DENY"},
+		},
+	}, {
+		name:   "preflight for disallowed method",
+		method: http.MethodOptions,
+		reqHeaders: map[string]string{
+			"Origin":                        "https://example.com",
+			"Access-Control-Request-Method": http.MethodPatch,
+		},
+		wantCode: http.StatusNoContent,
+		want: http.Header{
+			"Strict-Transport-Security": {"max-age=3600; includeSubDomains"},
+			"Vary":                      {"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+			"X-Content-Type-Options":    {"nosniff"},
+			"X-Frame-Options":           {"DENY"},
+		},
+	}, {
+		name:       "options without preflight headers",
+		method:     http.MethodOptions,
+		reqHeaders: map[string]string{"Origin": "https://example.com"},
+		wantNext:   true,
+		wantCode:   http.StatusOK,
+		want: http.Header{
+			"Access-Control-Allow-Origin":   {"https://example.com"},
+			"Access-Control-Expose-Headers": {"X-Request-Id"},
+			"Content-Type":                  {"text/plain"},
+			"Strict-Transport-Security":     {"max-age=3600; includeSubDomains"},
+			"Vary":                          {"Origin"},
+			"X-Content-Type-Options":        {"nosniff"},
+			"X-Frame-Options":               {"DENY"},
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calledNext := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calledNext = true
+				w.Header().Set("Content-Type", "text/plain")
+				w.Header().Set("Server", "app")
+				w.Header().Set("X-Frame-Options", "SAMEORIGIN")
+				w.Write([]byte("hello"))
+			})
+
+			req := httptest.NewRequest(test.method, "http://example.com", nil)
+			for k, v := range test.reqHeaders {
+				req.Header.Set(k, v)
+			}
+			resp := httptest.NewRecorder()
+			ResponseHeaderPolicyHandler(policy, next).ServeHTTP(resp, req)
+
+			if calledNext != test.wantNext {
+				t.Errorf("next called = %v, want: %v", calledNext, test.wantNext)
+			}
+			if resp.Code != test.wantCode {
+				t.Errorf("Code = %d, want: %d", resp.Code, test.wantCode)
+			}
+			if !cmp.Equal(resp.Header(), test.want) {
+				t.Error("Headers mismatch (-want, +got):", cmp.Diff(test.want, resp.Header()))
+			}
+		})
+	}
+}
+
+func TestResponseHeaderPolicyHandlerWildcardOrigin(t *testing.T) {
+	policy := &serving.ResponseHeaderPolicy{
+		CORS: &serving.CORSPolicy{AllowOrigins: []string{"*"}},
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "http://example.com", nil)
+	req.Header.Set("Origin", "https://any.com")
+	resp := httptest.NewRecorder()
+	ResponseHeaderPolicyHandler(policy, next).ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusAccepted {
+		t.Errorf("Code = %d, want: %d", resp.Code, http.StatusAccepted)
+	}
+	if got, want := resp.Header().Get("Access-Control-Allow-Origin"), "*"; got != want {
+		t.Errorf("Access-Control-Allow-Origin = %q, want: %q", got, want)
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"net/http"
+	"net/http/httputil"
+
+	"knative.dev/serving/pkg/activator"
+)
+
+// OutboundHandler serves the plain HTTP calls the user-container sends
+// through the queue-proxy, using it as its HTTP proxy. The calls are
+// forwarded with the source service header naming the calling service,
+// `source`, replacing any value the user-container set. It must only be
+// served on a listener bound to localhost, which no other pod can reach.
+func OutboundHandler(source string, transport http.RoundTripper) http.Handler {
+	proxy := &httputil.ReverseProxy{
+		Director: func(r *http.Request) {
+			if source != "" {
+				r.Header.Set(activator.SourceServiceHeaderName, source)
+			} else {
+				r.Header.Del(activator.SourceServiceHeaderName)
+			}
+		},
+		Transport: transport,
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Proxied requests carry the absolute target URL, which is reused as is.
+		if r.URL.Scheme != "http" || r.URL.Host == "" {
+			http.Error(w, "only absolute http URLs are proxied", http.StatusBadRequest)
+			return
+		}
+		r.RequestURI = ""
+		proxy.ServeHTTP(w, r)
+	})
+}
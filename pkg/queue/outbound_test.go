@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"knative.dev/serving/pkg/activator"
+)
+
+func TestOutboundHandler(t *testing.T) {
+	var gotSource string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSource = r.Header.Get(activator.SourceServiceHeaderName)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer upstream.Close()
+
+	tests := []struct {
+		name       string
+		url        string
+		source     string
+		wantStatus int
+		wantSource string
+	}{{
+		name:       "outbound call",
+		url:        upstream.URL + "/foo",
+		source:     "ns/caller",
+		wantStatus: http.StatusAccepted,
+		wantSource: "ns/caller",
+	}, {
+		name:       "outbound call without a service",
+		url:        upstream.URL + "/foo",
+		wantStatus: http.StatusAccepted,
+	}, {
+		name:       "not a proxy request",
+		url:        "/foo",
+		source:     "ns/caller",
+		wantStatus: http.StatusBadRequest,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotSource = ""
+			h := OutboundHandler(test.source, http.DefaultTransport)
+
+			req := httptest.NewRequest(http.MethodGet, test.url, nil)
+			req.Header.Set(activator.SourceServiceHeaderName, "ns/spoofed")
+			resp := httptest.NewRecorder()
+			h.ServeHTTP(resp, req)
+
+			if resp.Code != test.wantStatus {
+				t.Errorf("Status = %d, want: %d", resp.Code, test.wantStatus)
+			}
+			if gotSource != test.wantSource {
+				t.Errorf("Source service = %q, want: %q", gotSource, test.wantSource)
+			}
+		})
+	}
+}
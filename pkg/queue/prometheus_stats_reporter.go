@@ -55,6 +55,12 @@ var (
 	averageProxiedConcurrentRequestsGV = newGV(
 		"queue_average_proxied_concurrent_requests",
 		"Number of proxied requests currently being handled by this pod")
+	maxConcurrentRequestsGV = newGV(
+		"queue_max_concurrent_requests",
+		"Maximum number of requests handled concurrently by this pod during the reporting period")
+	percentileConcurrentRequestsGV = newGV(
+		"queue_percentile_concurrent_requests",
+		"Number of concurrent requests at the configured percentile of the reporting period")
 	processUptimeGV = newGV(
 		"process_uptime",
 		"The number of seconds that the process has been up")
@@ -80,6 +86,8 @@ type PrometheusStatsReporter struct {
 	proxiedRequestsPerSecond         prometheus.Gauge
 	averageConcurrentRequests        prometheus.Gauge
 	averageProxiedConcurrentRequests prometheus.Gauge
+	maxConcurrentRequests            prometheus.Gauge
+	percentileConcurrentRequests     prometheus.Gauge
 	processUptime                    prometheus.Gauge
 }
 
@@ -102,6 +110,7 @@ func NewPrometheusStatsReporter(namespace, config, revision, pod string, reporti
 	for _, gv := range []*prometheus.GaugeVec{
 		requestsPerSecondGV, proxiedRequestsPerSecondGV,
 		averageConcurrentRequestsGV, averageProxiedConcurrentRequestsGV,
+		maxConcurrentRequestsGV, percentileConcurrentRequestsGV,
 		processUptimeGV} {
 		if err := registry.Register(gv); err != nil {
 			return nil, fmt.Errorf("register metric failed: %w", err)
@@ -125,17 +134,27 @@ func NewPrometheusStatsReporter(namespace, config, revision, pod string, reporti
 		proxiedRequestsPerSecond:         proxiedRequestsPerSecondGV.With(labels),
 		averageConcurrentRequests:        averageConcurrentRequestsGV.With(labels),
 		averageProxiedConcurrentRequests: averageProxiedConcurrentRequestsGV.With(labels),
+		maxConcurrentRequests:            maxConcurrentRequestsGV.With(labels),
+		percentileConcurrentRequests:     percentileConcurrentRequestsGV.With(labels),
 		processUptime:                    processUptimeGV.With(labels),
 	}, nil
 }
 
 // Report captures request metrics.
 func (r *PrometheusStatsReporter) Report(stats network.RequestStatsReport) {
+	r.ReportWithConcurrency(stats, ConcurrencyReport{})
+}
+
+// ReportWithConcurrency captures request metrics, along with the distribution
+// of the concurrency over the same reporting period.
+func (r *PrometheusStatsReporter) ReportWithConcurrency(stats network.RequestStatsReport, concurrency ConcurrencyReport) {
 	// Requests per second is a rate over time while concurrency is not.
 	r.requestsPerSecond.Set(stats.RequestCount / r.reportingPeriodSeconds)
 	r.proxiedRequestsPerSecond.Set(stats.ProxiedRequestCount / r.reportingPeriodSeconds)
 	r.averageConcurrentRequests.Set(stats.AverageConcurrency)
 	r.averageProxiedConcurrentRequests.Set(stats.AverageProxiedConcurrency)
+	r.maxConcurrentRequests.Set(concurrency.MaxConcurrency)
+	r.percentileConcurrentRequests.Set(concurrency.PercentileConcurrency)
 	r.processUptime.Set(time.Since(r.startTime).Seconds())
 }
 
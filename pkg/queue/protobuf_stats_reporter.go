@@ -61,6 +61,12 @@ func NewProtobufStatsReporter(pod string, reportingPeriod time.Duration) *Protob
 
 // Report captures request metrics.
 func (r *ProtobufStatsReporter) Report(stats network.RequestStatsReport) {
+	r.ReportWithConcurrency(stats, ConcurrencyReport{})
+}
+
+// ReportWithConcurrency captures request metrics, along with the distribution
+// of the concurrency over the same reporting period.
+func (r *ProtobufStatsReporter) ReportWithConcurrency(stats network.RequestStatsReport, concurrency ConcurrencyReport) {
 	r.stat.Store(metrics.Stat{
 		PodName:       r.podName,
 		ProcessUptime: time.Since(r.startTime).Seconds(),
@@ -70,6 +76,8 @@ func (r *ProtobufStatsReporter) Report(stats network.RequestStatsReport) {
 		ProxiedRequestCount:              stats.ProxiedRequestCount / r.reportingPeriodSeconds,
 		AverageConcurrentRequests:        stats.AverageConcurrency,
 		AverageProxiedConcurrentRequests: stats.AverageProxiedConcurrency,
+		MaxConcurrentRequests:            concurrency.MaxConcurrency,
+		PercentileConcurrentRequests:     concurrency.PercentileConcurrency,
 	})
 }
 
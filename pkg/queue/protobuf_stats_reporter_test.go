@@ -25,6 +25,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 
+	network "knative.dev/networking/pkg"
 	"knative.dev/serving/pkg/autoscaler/metrics"
 )
 
@@ -47,6 +48,28 @@ func TestProtobufStatsReporterReport(t *testing.T) {
 	}
 }
 
+func TestProtobufStatsReporterReportWithConcurrency(t *testing.T) {
+	reporter := NewProtobufStatsReporter(pod, 1*time.Second)
+	reporter.ReportWithConcurrency(network.RequestStatsReport{
+		AverageConcurrency: 3,
+		RequestCount:       39,
+	}, ConcurrencyReport{
+		MaxConcurrency:        10,
+		PercentileConcurrency: 7,
+	})
+
+	want := metrics.Stat{
+		PodName:                      pod,
+		AverageConcurrentRequests:    3,
+		RequestCount:                 39,
+		MaxConcurrentRequests:        10,
+		PercentileConcurrentRequests: 7,
+	}
+	if got := scrapeProtobufStat(t, reporter); !cmp.Equal(want, got, ignoreStatFields) {
+		t.Errorf("Scraped stat mismatch; diff(-want,+got):\n%s", cmp.Diff(want, got))
+	}
+}
+
 func TestInitialProtobufStateValid(t *testing.T) {
 	r := NewProtobufStatsReporter(pod, 1*time.Second)
 	emptyStat := metrics.Stat{
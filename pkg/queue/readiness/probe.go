@@ -23,6 +23,7 @@ import (
 	"sync"
 	"time"
 
+	"go.uber.org/atomic"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"knative.dev/serving/pkg/queue/health"
@@ -48,6 +49,12 @@ type Probe struct {
 	// When the probe finishes the `gv` will be reset to nil.
 	mu sync.RWMutex
 	gv *gateValue
+
+	// warmup is run once the container first passes the probe. The probe
+	// fails until it has returned.
+	warmup     func()
+	warmupOnce sync.Once
+	warmedUp   atomic.Bool
 }
 
 // gateValue is a write-once boolean impl.
@@ -90,6 +97,14 @@ func (p *Probe) IsAggressive() bool {
 	return p.PeriodSeconds == 0
 }
 
+// SetWarmup sets a function that is run in the background once the
+// user-container first passes the probe. The probe keeps failing until it
+// returns, so that the pod is only reported ready once warmed up.
+// It must be called before the probe is first executed.
+func (p *Probe) SetWarmup(warmup func()) {
+	p.warmup = warmup
+}
+
 // ProbeContainer executes the defined Probe against the user-container
 func (p *Probe) ProbeContainer() bool {
 	gv, writer := func() (*gateValue, bool) {
@@ -104,7 +119,7 @@ func (p *Probe) ProbeContainer() bool {
 	}()
 
 	if writer {
-		res := p.probeContainerImpl()
+		res := p.probeContainerImpl() && p.isWarmedUp()
 		gv.write(res)
 		p.mu.Lock()
 		defer p.mu.Unlock()
@@ -114,6 +129,21 @@ func (p *Probe) ProbeContainer() bool {
 	return gv.read()
 }
 
+// isWarmedUp starts the warm-up the first time it is called and returns
+// whether it has finished.
+func (p *Probe) isWarmedUp() bool {
+	if p.warmup == nil {
+		return true
+	}
+	p.warmupOnce.Do(func() {
+		go func() {
+			p.warmup()
+			p.warmedUp.Store(true)
+		}()
+	})
+	return p.warmedUp.Load()
+}
+
 func (p *Probe) probeContainerImpl() bool {
 	var err error
 
@@ -34,6 +34,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 func TestNewProbe(t *testing.T) {
@@ -655,6 +656,48 @@ func TestKnTCPProbeSuccessThresholdIncludesFailure(t *testing.T) {
 	}
 }
 
+func TestProbeWaitsForWarmup(t *testing.T) {
+	tsURL := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	pb := NewProbe(&corev1.Probe{
+		PeriodSeconds:    1,
+		TimeoutSeconds:   1,
+		SuccessThreshold: 1,
+		FailureThreshold: 1,
+		Handler: corev1.Handler{
+			TCPSocket: &corev1.TCPSocketAction{
+				Host: tsURL.Hostname(),
+				Port: intstr.FromString(tsURL.Port()),
+			},
+		},
+	})
+	var runs atomic.Int32
+	release := make(chan struct{})
+	pb.SetWarmup(func() {
+		runs.Inc()
+		<-release
+	})
+
+	if pb.ProbeContainer() {
+		t.Error("Probe reported success before warm-up was done")
+	}
+	if pb.ProbeContainer() {
+		t.Error("Probe reported success before warm-up was done")
+	}
+	close(release)
+
+	if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return pb.ProbeContainer(), nil
+	}); err != nil {
+		t.Error("Probe never reported success after warm-up")
+	}
+	if got := runs.Load(); got != 1 {
+		t.Errorf("Warm-up ran %d times, want 1", got)
+	}
+}
+
 func newTestServer(t *testing.T, h http.HandlerFunc) *url.URL {
 	t.Helper()
 
@@ -27,6 +27,7 @@ import (
 
 	network "knative.dev/networking/pkg"
 	pkgmetrics "knative.dev/pkg/metrics"
+	"knative.dev/serving/pkg/activator"
 	pkghttp "knative.dev/serving/pkg/http"
 	"knative.dev/serving/pkg/metrics"
 )
@@ -55,6 +56,14 @@ var (
 		"app_request_latencies",
 		"The response time in millisecond",
 		stats.UnitMilliseconds)
+	callerRequestCountM = stats.Int64(
+		"caller_request_count",
+		"The number of requests that are routed to queue-proxy by calling service",
+		stats.UnitDimensionless)
+	callerResponseTimeInMsecM = stats.Float64(
+		"caller_request_latencies",
+		"The response time in millisecond by calling service",
+		stats.UnitMilliseconds)
 	queueDepthM = stats.Int64(
 		"queue_depth",
 		"The current number of items in the serving and waiting queue, or not reported if unlimited concurrency.",
@@ -62,8 +71,9 @@ var (
 )
 
 type requestMetricsHandler struct {
-	next     http.Handler
-	statsCtx context.Context
+	next      http.Handler
+	statsCtx  context.Context
+	routeTags bool
 }
 
 type appRequestMetricsHandler struct {
@@ -75,7 +85,13 @@ type appRequestMetricsHandler struct {
 // NewRequestMetricsHandler creates an http.Handler that emits request metrics.
 func NewRequestMetricsHandler(next http.Handler,
 	ns, service, config, rev, pod string) (http.Handler, error) {
-	keys := []tag.Key{metrics.PodTagKey, metrics.ContainerTagKey, metrics.ResponseCodeKey, metrics.ResponseCodeClassKey /*, metrics.RouteTagKey*/}
+	keys := []tag.Key{metrics.PodTagKey, metrics.ContainerTagKey, metrics.ResponseCodeKey, metrics.ResponseCodeClassKey}
+	// The route tag is only recorded if the number of distinct tags is capped.
+	routeTags := metrics.RouteTagsEnabled()
+	callerKeys := []tag.Key{metrics.PodTagKey, metrics.ContainerTagKey, metrics.SourceServiceKey, metrics.ResponseCodeClassKey}
+	if routeTags {
+		keys = append(keys, metrics.RouteTagKey)
+	}
 	if err := pkgmetrics.RegisterResourceView(
 		&view.View{
 			Description: "The number of requests that are routed to queue-proxy",
@@ -89,6 +105,18 @@ func NewRequestMetricsHandler(next http.Handler,
 			Aggregation: defaultLatencyDistribution,
 			TagKeys:     keys,
 		},
+		&view.View{
+			Description: "The number of requests that are routed to queue-proxy by calling service",
+			Measure:     callerRequestCountM,
+			Aggregation: view.Count(),
+			TagKeys:     callerKeys,
+		},
+		&view.View{
+			Description: "The response time in millisecond by calling service",
+			Measure:     callerResponseTimeInMsecM,
+			Aggregation: defaultLatencyDistribution,
+			TagKeys:     callerKeys,
+		},
 	); err != nil {
 		return nil, err
 	}
@@ -99,8 +127,9 @@ func NewRequestMetricsHandler(next http.Handler,
 	}
 
 	return &requestMetricsHandler{
-		next:     next,
-		statsCtx: ctx,
+		next:      next,
+		statsCtx:  ctx,
+		routeTags: routeTags,
 	}, nil
 }
 
@@ -117,29 +146,51 @@ func (h *requestMetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		// If ServeHTTP panics, recover, record the failure and panic again.
 		err := recover()
 		latency := time.Since(startTime)
-		// routeTag := GetRouteTagNameFromRequest(r)
 		if err != nil {
-			ctx := metrics.AugmentWithResponse(h.statsCtx, http.StatusInternalServerError)
-			// TODO: add the routeTag back after stackdriver adds support for it.
-			// https://github.com/knative/serving/issues/8970
-			// ctx := metrics.AugmentWithResponseAndRouteTag(h.statsCtx,
-			// http.StatusInternalServerError, routeTag)
+			ctx := h.augment(r, http.StatusInternalServerError)
 			pkgmetrics.RecordBatch(ctx, requestCountM.M(1),
 				responseTimeInMsecM.M(float64(latency.Milliseconds())))
+			h.recordCaller(r, http.StatusInternalServerError, latency)
 			panic(err)
 		}
-		ctx := metrics.AugmentWithResponse(h.statsCtx, rr.ResponseCode)
-		// TODO: add the routeTag back after stackdriver adds support for it.
-		// https://github.com/knative/serving/issues/8970
-		// ctx := metrics.AugmentWithResponseAndRouteTag(h.statsCtx,
-		// rr.ResponseCode, routeTag)
+		ctx := h.augment(r, rr.ResponseCode)
 		pkgmetrics.RecordBatch(ctx, requestCountM.M(1),
 			responseTimeInMsecM.M(float64(latency.Milliseconds())))
+		h.recordCaller(r, rr.ResponseCode, latency)
 	}()
 
 	h.next.ServeHTTP(rr, r)
 }
 
+func (h *requestMetricsHandler) augment(r *http.Request, responseCode int) context.Context {
+	// The route tag is opt-in, see https://github.com/knative/serving/issues/8970.
+	if h.routeTags {
+		return metrics.AugmentWithResponseAndRouteTag(h.statsCtx, responseCode, GetRouteTagNameFromRequest(r))
+	}
+	return metrics.AugmentWithResponse(h.statsCtx, responseCode)
+}
+
+// recordCaller records the request metrics by calling service, as named
+// by the source service header.
+func (h *requestMetricsHandler) recordCaller(r *http.Request, responseCode int, latency time.Duration) {
+	source := ""
+	if throughProxy(r) {
+		source = metrics.SourceService(r.Header.Get(activator.SourceServiceHeaderName))
+	}
+	ctx := metrics.AugmentWithResponse(metrics.AugmentWithSourceService(h.statsCtx, source), responseCode)
+	pkgmetrics.RecordBatch(ctx, callerRequestCountM.M(1),
+		callerResponseTimeInMsecM.M(float64(latency.Milliseconds())))
+}
+
+// throughProxy returns whether the request went through the activator, which
+// resolves its caller from the pod it originates from, or the ingress, which
+// neutralizes the source service header of requests from outside the
+// cluster. The header of requests sent directly to the pod is not trusted.
+func throughProxy(r *http.Request) bool {
+	return r.Header.Get(network.ProxyHeaderName) == activator.Name ||
+		r.Header.Get("X-Forwarded-For") != ""
+}
+
 // NewAppRequestMetricsHandler creates an http.Handler that emits request metrics.
 func NewAppRequestMetricsHandler(next http.Handler, b *Breaker,
 	ns, service, config, rev, pod string) (http.Handler, error) {
@@ -205,10 +256,6 @@ func (h *appRequestMetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 	h.next.ServeHTTP(rr, r)
 }
 
-/*
-TODO: add the routeTag back after stackdriver adds support for it.
-https://github.com/knative/serving/issues/8970
-
 const (
 	defaultTagName   = "DEFAULT"
 	undefinedTagName = "UNDEFINED"
@@ -235,4 +282,4 @@ func GetRouteTagNameFromRequest(r *http.Request) string {
 	}
 	// Otherwise, returns the value of the tag header.
 	return name
-}*/
+}
@@ -27,6 +27,8 @@ import (
 	"knative.dev/pkg/metrics/metricskey"
 	"knative.dev/pkg/metrics/metricstest"
 	_ "knative.dev/pkg/metrics/testing"
+	"knative.dev/serving/pkg/activator"
+	"knative.dev/serving/pkg/metrics"
 )
 
 const targetURI = "http://example.com"
@@ -77,8 +79,11 @@ func TestRequestMetricsHandler(t *testing.T) {
 	metricstest.AssertMetric(t, metricstest.DistributionCountOnlyMetric("request_latencies", 1, wantTags).WithResource(wantResource))
 }
 
-/* func TestRequestMetricsHandlerWithEnablingTagOnRequestMetrics(t *testing.T) {
+func TestRequestMetricsHandlerWithEnablingTagOnRequestMetrics(t *testing.T) {
 	defer reset()
+	metrics.SetCardinalityConfig(&metrics.CardinalityConfig{MaxRouteTags: 3})
+	defer metrics.SetCardinalityConfig(&metrics.CardinalityConfig{})
+
 	baseHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
 	handler, err := NewRequestMetricsHandler(baseHandler, "ns", "svc", "cfg", "rev", "pod")
 	if err != nil {
@@ -134,13 +139,102 @@ func TestRequestMetricsHandler(t *testing.T) {
 	handler.ServeHTTP(resp, req)
 	wantTags["tag"] = "test-tag"
 	metricstest.AssertMetric(t, metricstest.IntMetric("request_count", 1, wantTags).WithResource(wantResource))
-} */
+
+	// Three distinct tags were seen, further tags are aggregated.
+	reset()
+	handler, _ = NewRequestMetricsHandler(baseHandler, "ns", "svc", "cfg", "rev", "pod")
+	req.Header.Set(network.TagHeaderName, "another-tag")
+	handler.ServeHTTP(resp, req)
+	wantTags["tag"] = metrics.OtherValue
+	metricstest.AssertMetric(t, metricstest.IntMetric("request_count", 1, wantTags).WithResource(wantResource))
+}
+
+func TestRequestMetricsHandlerReducedCardinality(t *testing.T) {
+	defer reset()
+	metrics.SetCardinalityConfig(&metrics.CardinalityConfig{DropPodTag: true, ResponseCodeClassOnly: true})
+	defer metrics.SetCardinalityConfig(&metrics.CardinalityConfig{})
+
+	baseHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	handler, err := NewRequestMetricsHandler(baseHandler, "ns", "svc", "cfg", "rev", "pod")
+	if err != nil {
+		t.Fatal("Failed to create handler:", err)
+	}
+
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, targetURI, bytes.NewBufferString("test"))
+	handler.ServeHTTP(resp, req)
+
+	wantTags := map[string]string{
+		metricskey.ContainerName:          "queue-proxy",
+		metricskey.LabelResponseCodeClass: "4xx",
+	}
+	wantResource := &resource.Resource{
+		Type: "knative_revision",
+		Labels: map[string]string{
+			metricskey.LabelNamespaceName:     "ns",
+			metricskey.LabelRevisionName:      "rev",
+			metricskey.LabelServiceName:       "svc",
+			metricskey.LabelConfigurationName: "cfg",
+		},
+	}
+	metricstest.AssertMetric(t, metricstest.IntMetric("request_count", 1, wantTags).WithResource(wantResource))
+}
+
+func TestRequestMetricsHandlerCaller(t *testing.T) {
+	defer reset()
+	metrics.SetCardinalityConfig(&metrics.CardinalityConfig{MaxSourceServices: 1})
+	defer metrics.SetCardinalityConfig(&metrics.CardinalityConfig{})
+	baseHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler, err := NewRequestMetricsHandler(baseHandler, "ns", "svc", "cfg", "rev", "pod")
+	if err != nil {
+		t.Fatal("Failed to create handler:", err)
+	}
+
+	for _, source := range []string{"default/caller", "default/caller", "not a service", ""} {
+		req := httptest.NewRequest(http.MethodPost, targetURI, nil)
+		req.Header.Set("X-Forwarded-For", "10.0.0.1")
+		if source != "" {
+			req.Header.Set(activator.SourceServiceHeaderName, source)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	// The header of requests sent directly to the pod is ignored.
+	req := httptest.NewRequest(http.MethodPost, targetURI, nil)
+	req.Header.Set(activator.SourceServiceHeaderName, "default/caller")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	wantResource := &resource.Resource{
+		Type: "knative_revision",
+		Labels: map[string]string{
+			metricskey.LabelNamespaceName:     "ns",
+			metricskey.LabelRevisionName:      "rev",
+			metricskey.LabelServiceName:       "svc",
+			metricskey.LabelConfigurationName: "cfg",
+		},
+	}
+	want := metricstest.IntMetric("caller_request_count", 2, map[string]string{
+		metricskey.PodName:                "pod",
+		metricskey.ContainerName:          "queue-proxy",
+		"source_service":                  "default/caller",
+		metricskey.LabelResponseCodeClass: "2xx",
+	}).WithResource(wantResource)
+	want.Values = append(want.Values, metricstest.IntMetric("caller_request_count", 3, map[string]string{
+		metricskey.PodName:                "pod",
+		metricskey.ContainerName:          "queue-proxy",
+		"source_service":                  metricskey.ValueUnknown,
+		metricskey.LabelResponseCodeClass: "2xx",
+	}).Values...)
+	metricstest.AssertMetric(t, want)
+	metricstest.AssertMetricExists(t, "caller_request_latencies")
+}
 
 func reset() {
 	metricstest.Unregister(
 		requestCountM.Name(), appRequestCountM.Name(),
 		responseTimeInMsecM.Name(), appResponseTimeInMsecM.Name(),
-		queueDepthM.Name())
+		queueDepthM.Name(), callerRequestCountM.Name(), callerResponseTimeInMsecM.Name())
 }
 
 func TestRequestMetricsHandlerPanickingHandler(t *testing.T) {
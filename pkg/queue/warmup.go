@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+
+	network "knative.dev/networking/pkg"
+	pkgmetrics "knative.dev/pkg/metrics"
+	"knative.dev/serving/pkg/apis/serving"
+	"knative.dev/serving/pkg/metrics"
+)
+
+var (
+	warmupTimeInMsecM = stats.Float64(
+		"warmup_latencies",
+		"The time spent warming up the user-container in millisecond",
+		stats.UnitMilliseconds)
+	warmupRequestCountM = stats.Int64(
+		"warmup_request_count",
+		"The number of warm-up requests sent to user-container",
+		stats.UnitDimensionless)
+)
+
+// Warmer sends the warm-up requests of a revision to the user-container.
+type Warmer struct {
+	logger    *zap.SugaredLogger
+	transport http.RoundTripper
+	target    string
+	requests  []serving.WarmupRequest
+	timeout   time.Duration
+	statsCtx  context.Context
+}
+
+// NewWarmer creates a Warmer sending the given requests to the
+// user-container listening on `target`, a host:port pair, giving up after
+// `timeout`.
+func NewWarmer(logger *zap.SugaredLogger, transport http.RoundTripper, target string,
+	requests []serving.WarmupRequest, timeout time.Duration,
+	ns, service, config, rev, pod string) (*Warmer, error) {
+	keys := []tag.Key{metrics.PodTagKey, metrics.ContainerTagKey}
+	if err := pkgmetrics.RegisterResourceView(&view.View{
+		Description: "The time spent warming up the user-container in millisecond",
+		Measure:     warmupTimeInMsecM,
+		Aggregation: view.LastValue(),
+		TagKeys:     keys,
+	}, &view.View{
+		Description: "The number of warm-up requests sent to user-container",
+		Measure:     warmupRequestCountM,
+		Aggregation: view.Count(),
+		TagKeys:     append(keys, metrics.ResponseCodeKey, metrics.ResponseCodeClassKey),
+	}); err != nil {
+		return nil, err
+	}
+
+	ctx, err := metrics.PodRevisionContext(pod, "queue-proxy", ns, service, config, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Warmer{
+		logger:    logger,
+		transport: transport,
+		target:    target,
+		requests:  requests,
+		timeout:   timeout,
+		statsCtx:  ctx,
+	}, nil
+}
+
+// Run sends the warm-up requests one after the other, until they are all
+// sent or the timeout passes. Failed requests are not retried.
+func (w *Warmer) Run(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	start := time.Now()
+	var sent, failed int
+	defer func() {
+		latency := time.Since(start)
+		pkgmetrics.Record(w.statsCtx, warmupTimeInMsecM.M(float64(latency.Milliseconds())))
+		if ctx.Err() != nil {
+			w.logger.Warnf("Warm-up timed out after %v, sent %d requests, %d failed", latency, sent, failed)
+			return
+		}
+		w.logger.Infof("Warm-up finished in %v, sent %d requests, %d failed", latency, sent, failed)
+	}()
+
+	for _, r := range w.requests {
+		for i := 0; i < r.Count; i++ {
+			if ctx.Err() != nil {
+				return
+			}
+			sent++
+			if err := w.send(ctx, r); err != nil {
+				failed++
+				w.logger.Debugw("Warm-up request failed", zap.String("path", r.Path), zap.Error(err))
+			}
+		}
+	}
+}
+
+func (w *Warmer) send(ctx context.Context, r serving.WarmupRequest) error {
+	req, err := http.NewRequestWithContext(ctx, r.Method, "http://"+w.target+r.Path, strings.NewReader(r.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(network.UserAgentKey, WarmupUserAgent)
+
+	resp, err := w.transport.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain the body so that the connection is reused.
+	io.Copy(ioutil.Discard, resp.Body)
+
+	pkgmetrics.Record(metrics.AugmentWithResponse(w.statsCtx, resp.StatusCode), warmupRequestCountM.M(1))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opencensus.io/resource"
+	"go.uber.org/atomic"
+
+	network "knative.dev/networking/pkg"
+	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/metrics/metricskey"
+	"knative.dev/pkg/metrics/metricstest"
+	"knative.dev/serving/pkg/apis/serving"
+)
+
+func resetWarmup() {
+	metricstest.Unregister(warmupTimeInMsecM.Name(), warmupRequestCountM.Name())
+}
+
+func TestWarmer(t *testing.T) {
+	t.Cleanup(resetWarmup)
+
+	var predicts, others atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get(network.UserAgentKey); got != WarmupUserAgent {
+			t.Errorf("User-Agent = %q, want %q", got, WarmupUserAgent)
+		}
+		if r.URL.Path != "/predict" {
+			others.Inc()
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method != http.MethodPost || string(body) != `{"x":1}` {
+			t.Errorf("Got %s request with body %q, want POST with %q", r.Method, body, `{"x":1}`)
+		}
+		predicts.Inc()
+	}))
+	t.Cleanup(s.Close)
+
+	w, err := NewWarmer(logtesting.TestLogger(t), http.DefaultTransport, strings.TrimPrefix(s.URL, "http://"),
+		[]serving.WarmupRequest{
+			{Method: http.MethodPost, Path: "/predict", Body: `{"x":1}`, Count: 3},
+			{Method: http.MethodGet, Path: "/", Count: 1},
+		}, time.Minute, "ns", "svc", "cfg", "rev", "pod")
+	if err != nil {
+		t.Fatal("NewWarmer() =", err)
+	}
+	w.Run(context.Background())
+
+	if got := predicts.Load(); got != 3 {
+		t.Errorf("Predict requests = %d, want 3", got)
+	}
+	if got := others.Load(); got != 1 {
+		t.Errorf("Other requests = %d, want 1", got)
+	}
+	want := metricstest.IntMetric("warmup_request_count", 3, map[string]string{
+		metricskey.PodName:                "pod",
+		metricskey.ContainerName:          "queue-proxy",
+		metricskey.LabelResponseCode:      "200",
+		metricskey.LabelResponseCodeClass: "2xx",
+	}).WithResource(&resource.Resource{
+		Type: "knative_revision",
+		Labels: map[string]string{
+			metricskey.LabelNamespaceName:     "ns",
+			metricskey.LabelRevisionName:      "rev",
+			metricskey.LabelServiceName:       "svc",
+			metricskey.LabelConfigurationName: "cfg",
+		},
+	})
+	want.Values = append(want.Values, metricstest.IntMetric("warmup_request_count", 1, map[string]string{
+		metricskey.PodName:                "pod",
+		metricskey.ContainerName:          "queue-proxy",
+		metricskey.LabelResponseCode:      "500",
+		metricskey.LabelResponseCodeClass: "5xx",
+	}).Values...)
+	metricstest.AssertMetric(t, want)
+	metricstest.AssertMetricExists(t, "warmup_latencies")
+}
+
+func TestWarmerTimeout(t *testing.T) {
+	t.Cleanup(resetWarmup)
+
+	var requests atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Inc()
+		<-r.Context().Done()
+	}))
+	t.Cleanup(s.Close)
+
+	w, err := NewWarmer(logtesting.TestLogger(t), http.DefaultTransport, strings.TrimPrefix(s.URL, "http://"),
+		[]serving.WarmupRequest{{Method: http.MethodGet, Path: "/", Count: 10}},
+		100*time.Millisecond, "ns", "svc", "cfg", "rev", "pod")
+	if err != nil {
+		t.Fatal("NewWarmer() =", err)
+	}
+
+	start := time.Now()
+	w.Run(context.Background())
+	if took := time.Since(start); took > 5*time.Second {
+		t.Errorf("Run() took %v, want it to stop at the timeout", took)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("Requests = %d, want 1", got)
+	}
+}
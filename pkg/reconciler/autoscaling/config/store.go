@@ -19,6 +19,7 @@ package config
 import (
 	"context"
 
+	corev1 "k8s.io/api/core/v1"
 	"knative.dev/pkg/configmap"
 	asconfig "knative.dev/serving/pkg/autoscaler/config"
 	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
@@ -44,6 +45,23 @@ func ToContext(ctx context.Context, c *Config) context.Context {
 	return context.WithValue(ctx, cfgKey{}, c)
 }
 
+// WithNamespaceOverrides returns a context whose autoscaler config carries
+// the overrides of the given namespace.
+func WithNamespaceOverrides(ctx context.Context, ns *corev1.Namespace) (context.Context, error) {
+	cfg := FromContext(ctx)
+	as, err := asconfig.WithNamespaceOverrides(cfg.Autoscaler, ns.Annotations)
+	if err != nil {
+		return ctx, err
+	}
+	if as == cfg.Autoscaler {
+		return ctx, nil
+	}
+	return ToContext(ctx, &Config{
+		Autoscaler: as,
+		Deployment: cfg.Deployment,
+	}), nil
+}
+
 // Store is configmap.UntypedStore based config store.
 // +k8s:deepcopy-gen=false
 type Store struct {
@@ -20,10 +20,18 @@ import (
 	"context"
 
 	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/cache"
 
 	networkingclient "knative.dev/networking/pkg/client/injection/client"
 	sksinformer "knative.dev/networking/pkg/client/injection/informers/networking/v1alpha1/serverlessservice"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	deploymentinformer "knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment"
+	endpointsinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/endpoints"
+	namespaceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
 	podinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/pod"
 	servingclient "knative.dev/serving/pkg/client/injection/client"
 	"knative.dev/serving/pkg/client/injection/ducks/autoscaling/v1alpha1/podscalable"
@@ -36,14 +44,18 @@ import (
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/tracker"
 	"knative.dev/serving/pkg/apis/autoscaling"
 	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
 	"knative.dev/serving/pkg/apis/serving"
+	asconfig "knative.dev/serving/pkg/autoscaler/config"
 	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
 	"knative.dev/serving/pkg/deployment"
+	"knative.dev/serving/pkg/metrics"
 	areconciler "knative.dev/serving/pkg/reconciler/autoscaling"
 	"knative.dev/serving/pkg/reconciler/autoscaling/config"
 	"knative.dev/serving/pkg/reconciler/autoscaling/kpa/resources"
+	revisionconfig "knative.dev/serving/pkg/reconciler/revision/config"
 )
 
 // NewController returns a new KPA reconcile controller.
@@ -59,6 +71,7 @@ func NewController(
 	podsInformer := podinformer.Get(ctx)
 	metricInformer := metricinformer.Get(ctx)
 	psInformerFactory := podscalable.Get(ctx)
+	deploymentInformer := deploymentinformer.Get(ctx)
 
 	onlyKPAClass := pkgreconciler.AnnotationFilterFunc(
 		autoscaling.ClassAnnotationKey, autoscaling.KPA, false /*allowUnset*/)
@@ -70,8 +83,12 @@ func NewController(
 			SKSLister:        sksInformer.Lister(),
 			MetricLister:     metricInformer.Lister(),
 		},
-		podsLister: podsInformer.Lister(),
-		deciders:   deciders,
+		kubeClient:       kubeclient.Get(ctx),
+		podsLister:       podsInformer.Lister(),
+		namespaceLister:  namespaceinformer.Get(ctx).Lister(),
+		endpointsLister:  endpointsinformer.Get(ctx).Lister(),
+		deploymentLister: deploymentInformer.Lister(),
+		deciders:         deciders,
 	}
 	impl := pareconciler.NewImpl(ctx, c, autoscaling.KPA, func(impl *controller.Impl) controller.Options {
 		logger.Info("Setting up ConfigMap receivers")
@@ -84,9 +101,15 @@ func NewController(
 		})
 		configStore := config.NewStore(logger.Named("config-store"), resync)
 		configStore.WatchConfigs(cmw)
+
+		// The queue-proxy injected into the Deployments scaled by standalone
+		// PAs is configured like those of revisions.
+		c.queueConfigStore = revisionconfig.NewStore(logger.Named("queue-config-store"))
+		c.queueConfigStore.WatchConfigs(cmw)
 		return controller.Options{ConfigStore: configStore}
 	})
 	c.scaler = newScaler(ctx, psInformerFactory, impl.EnqueueAfter)
+	c.tracker = tracker.New(impl.EnqueueKey, controller.GetTrackerLease(ctx))
 
 	logger.Info("Setting up KPA-Class event handlers")
 
@@ -95,7 +118,8 @@ func NewController(
 		Handler:    controller.HandleAll(impl.Enqueue),
 	})
 
-	// When we see PodAutoscalers deleted, clean up the decider.
+	// When we see PodAutoscalers deleted, clean up the decider and the
+	// gauges aggregated from it.
 	paInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		DeleteFunc: func(obj interface{}) {
 			accessor, err := kmeta.DeletionHandlingAccessor(obj)
@@ -104,6 +128,9 @@ func NewController(
 				return
 			}
 			deciders.Delete(ctx, accessor.GetNamespace(), accessor.GetName())
+			labels := accessor.GetLabels()
+			metrics.ForgetGauges(accessor.GetNamespace(), labels[serving.ServiceLabelKey],
+				labels[serving.ConfigurationLabelKey], accessor.GetName())
 		},
 	})
 
@@ -121,6 +148,45 @@ func NewController(
 		Handler:    controller.HandleAll(impl.EnqueueLabelOfNamespaceScopedResource("", serving.RevisionLabelKey)),
 	})
 
+	// Watch the pods of user-managed workloads scaled by standalone PAs.
+	podsInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: pkgreconciler.LabelExistsFilterFunc(autoscaling.PodAutoscalerLabelKey),
+		Handler:    controller.HandleAll(impl.EnqueueLabelOfNamespaceScopedResource("", autoscaling.PodAutoscalerLabelKey)),
+	})
+
+	// Watch the Deployments scaled by standalone PAs.
+	deploymentInformer.Informer().AddEventHandler(controller.HandleAll(
+		controller.EnsureTypeMeta(
+			c.tracker.OnChanged,
+			appsv1.SchemeGroupVersion.WithKind("Deployment"),
+		),
+	))
+
+	// Namespaces may override the autoscaler config of their PAs.
+	namespaceinformer.Get(ctx).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, obj interface{}) {
+			ons, ok := old.(*corev1.Namespace)
+			if !ok {
+				return
+			}
+			ns, ok := obj.(*corev1.Namespace)
+			if !ok || equality.Semantic.DeepEqual(
+				asconfig.NamespaceOverrides(ons.Annotations), asconfig.NamespaceOverrides(ns.Annotations)) {
+				return
+			}
+			pas, err := paInformer.Lister().PodAutoscalers(ns.Name).List(labels.Everything())
+			if err != nil {
+				logger.Errorw("Error listing PodAutoscalers of namespace "+ns.Name, zap.Error(err))
+				return
+			}
+			for _, pa := range pas {
+				if onlyKPAClass(pa) {
+					impl.Enqueue(pa)
+				}
+			}
+		},
+	})
+
 	// Have the Deciders enqueue the PAs whose decisions have changed.
 	deciders.Watch(impl.EnqueueKey)
 
@@ -25,9 +25,9 @@ import (
 
 	nv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
 	"knative.dev/pkg/logging"
-	pkgmetrics "knative.dev/pkg/metrics"
 	"knative.dev/pkg/ptr"
 	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/tracker"
 	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
 	"knative.dev/serving/pkg/apis/serving"
 	"knative.dev/serving/pkg/autoscaler/scaling"
@@ -37,10 +37,14 @@ import (
 	"knative.dev/serving/pkg/reconciler/autoscaling/config"
 	"knative.dev/serving/pkg/reconciler/autoscaling/kpa/resources"
 	anames "knative.dev/serving/pkg/reconciler/autoscaling/resources/names"
+	revisionconfig "knative.dev/serving/pkg/reconciler/revision/config"
 	resourceutil "knative.dev/serving/pkg/resources"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 )
 
@@ -64,9 +68,18 @@ type podCounts struct {
 type Reconciler struct {
 	*areconciler.Base
 
-	podsLister corev1listers.PodLister
-	deciders   resources.Deciders
-	scaler     *scaler
+	kubeClient       kubernetes.Interface
+	podsLister       corev1listers.PodLister
+	namespaceLister  corev1listers.NamespaceLister
+	endpointsLister  corev1listers.EndpointsLister
+	deploymentLister appsv1listers.DeploymentLister
+	deciders         resources.Deciders
+	scaler           *scaler
+	tracker          tracker.Interface
+
+	// queueConfigStore provides the configuration of the queue-proxy injected
+	// into the Deployments scaled by standalone PAs.
+	queueConfigStore *revisionconfig.Store
 }
 
 // Check that our Reconciler implements pareconciler.Interface
@@ -75,6 +88,15 @@ var _ pareconciler.Interface = (*Reconciler)(nil)
 // ReconcileKind implements Interface.ReconcileKind.
 func (c *Reconciler) ReconcileKind(ctx context.Context, pa *autoscalingv1alpha1.PodAutoscaler) pkgreconciler.Event {
 	logger := logging.FromContext(ctx)
+	ctx = c.withNamespaceOverrides(ctx, pa.Namespace)
+
+	// Standalone PAs scale user-managed Deployments, whose pods we have to
+	// label, so that we can count them, and make scrapable.
+	if pa.IsStandalone() {
+		if err := c.reconcileScaleTarget(ctx, pa); err != nil {
+			return err
+		}
+	}
 
 	// We need the SKS object in order to optimize scale to zero
 	// performance. It is OK if SKS is nil at this point.
@@ -121,16 +143,19 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, pa *autoscalingv1alpha1.
 	//			this revision, e.g. after a restart) but PA status is inactive (it was
 	//			already scaled to 0).
 	// 2. The excess burst capacity is negative.
-	if want == 0 || decider.Status.ExcessBurstCapacity < 0 || want == scaleUnknown && pa.Status.IsInactive() {
+	// Standalone PAs have no revision the activator could buffer requests for,
+	// so they are always served directly.
+	if !pa.IsStandalone() && (want == 0 || decider.Status.ExcessBurstCapacity < 0 || want == scaleUnknown && pa.Status.IsInactive()) {
 		mode = nv1alpha1.SKSOperationModeProxy
 	}
+	numActivators := c.numActivators(ctx, pa, decider.Status.NumActivators)
 	logger.Infof("SKS should be in %s mode: want = %d, ebc = %d, #act's = %d PA Inactive? = %v",
-		mode, want, decider.Status.ExcessBurstCapacity, decider.Status.NumActivators,
+		mode, want, decider.Status.ExcessBurstCapacity, numActivators,
 		pa.Status.IsInactive())
 
 	// If we have not successfully reconciled Decider yet, NumActivators will be 0 and
 	// we'll use all activators to back this revision.
-	sks, err = c.ReconcileSKS(ctx, pa, mode, decider.Status.NumActivators)
+	sks, err = c.ReconcileSKS(ctx, pa, mode, numActivators)
 	if err != nil {
 		return fmt.Errorf("error reconciling SKS: %w", err)
 	}
@@ -138,7 +163,7 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, pa *autoscalingv1alpha1.
 	pa.Status.ServiceName = sks.Status.ServiceName
 
 	// Compare the desired and observed resources to determine our situation.
-	podCounter := resourceutil.NewPodAccessor(c.podsLister, pa.Namespace, pa.Labels[serving.RevisionLabelKey])
+	podCounter := podAccessor(c.podsLister, pa)
 	ready, notReady, pending, terminating, err := podCounter.PodCountsByState()
 	if err != nil {
 		return fmt.Errorf("error getting pod counts %s: %w", sks.Status.PrivateServiceName, err)
@@ -153,6 +178,14 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, pa *autoscalingv1alpha1.
 		pa.Status.MarkSKSNotReady(sks.Status.GetCondition(nv1alpha1.ServerlessServiceConditionReady).GetMessage())
 	}
 
+	// The LoadBalanced condition is only surfaced once the pods were observed
+	// to be unevenly loaded, and flips back to true after they recover.
+	if hot := decider.Status.HotPods; len(hot) > 0 {
+		pa.Status.MarkLoadImbalanced(decider.Status.ImbalanceIndex, hot)
+	} else if pa.Status.GetCondition(autoscalingv1alpha1.PodAutoscalerConditionLoadBalanced).IsFalse() {
+		pa.Status.MarkLoadBalanced()
+	}
+
 	logger.Infof("PA scale got=%d, want=%d, desiredPods=%d ebc=%d", ready, want,
 		decider.Status.DesiredScale, decider.Status.ExcessBurstCapacity)
 
@@ -168,6 +201,68 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, pa *autoscalingv1alpha1.
 	return nil
 }
 
+// reconcileScaleTarget makes the pod template of the Deployment scaled by the
+// standalone PA carry what the autoscaler needs to scale its pods.
+func (c *Reconciler) reconcileScaleTarget(ctx context.Context, pa *autoscalingv1alpha1.PodAutoscaler) error {
+	ref := pa.Spec.ScaleTargetRef
+	if err := c.tracker.TrackReference(tracker.Reference{
+		APIVersion: ref.APIVersion,
+		Kind:       ref.Kind,
+		Namespace:  pa.Namespace,
+		Name:       ref.Name,
+	}, pa); err != nil {
+		return fmt.Errorf("error tracking scale target: %w", err)
+	}
+
+	deploy, err := c.deploymentLister.Deployments(pa.Namespace).Get(ref.Name)
+	if err != nil {
+		pa.Status.MarkScaleTargetNotPodScalable(err.Error())
+		return fmt.Errorf("error getting scale target: %w", err)
+	}
+	template, err := resources.MakeStandalonePodTemplate(pa, &deploy.Spec.Template, c.queueConfigStore.Load())
+	if err != nil {
+		return fmt.Errorf("error making scale target pod template: %w", err)
+	}
+	if equality.Semantic.DeepEqual(template, &deploy.Spec.Template) {
+		return nil
+	}
+
+	want := deploy.DeepCopy()
+	want.Spec.Template = *template
+	if _, err := c.kubeClient.AppsV1().Deployments(pa.Namespace).Update(ctx, want, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating scale target pod template: %w", err)
+	}
+	logging.FromContext(ctx).Info("Updated the scale target pod template")
+	return nil
+}
+
+// podAccessor returns the PodAccessor for the pods scaled by the PA.
+func podAccessor(podsLister corev1listers.PodLister, pa *autoscalingv1alpha1.PodAutoscaler) resourceutil.PodAccessor {
+	if pa.IsStandalone() {
+		return resourceutil.NewStandalonePodAccessor(podsLister, pa.Namespace, pa.Name)
+	}
+	return resourceutil.NewPodAccessor(podsLister, pa.Namespace, pa.Labels[serving.RevisionLabelKey])
+}
+
+// numActivators caps the number of activators computed by the decider to the
+// size of the activator pool serving the PA's namespace. Namespaces may
+// select a dedicated pool, which is usually much smaller than the default one.
+func (c *Reconciler) numActivators(ctx context.Context, pa *autoscalingv1alpha1.PodAutoscaler, want int32) int32 {
+	if want == 0 {
+		// 0 means all the activators of the pool.
+		return want
+	}
+	eps, _, err := resourceutil.ActivatorEndpoints(c.namespaceLister, c.endpointsLister, pa.Namespace)
+	if err != nil {
+		logging.FromContext(ctx).Warnw("Error retrieving activator pool endpoints", zap.Error(err))
+		return want
+	}
+	if size := int32(resourceutil.ReadyAddressCount(eps)); size > 0 && size < want {
+		return size
+	}
+	return want
+}
+
 func (c *Reconciler) reconcileDecider(ctx context.Context, pa *autoscalingv1alpha1.PodAutoscaler) (*scaling.Decider, error) {
 	desiredDecider := resources.MakeDecider(pa, config.FromContext(ctx).Autoscaler)
 	decider, err := c.deciders.Get(ctx, desiredDecider.Namespace, desiredDecider.Name)
@@ -213,7 +308,7 @@ func reportMetrics(pa *autoscalingv1alpha1.PodAutoscaler, pc podCounts) {
 	if pc.want >= 0 {
 		stats = append(stats, requestedPodCountM.M(int64(pc.want)))
 	}
-	pkgmetrics.RecordBatch(ctx, stats...)
+	metrics.RecordGauges(ctx, pa.Spec.Reachability == autoscalingv1alpha1.ReachabilityUnreachable, stats...)
 }
 
 // computeActiveCondition updates the status of a PA given the current scale (got), desired scale (want)
@@ -269,6 +364,24 @@ func computeActiveCondition(ctx context.Context, pa *autoscalingv1alpha1.PodAuto
 	}
 }
 
+// withNamespaceOverrides returns a context whose autoscaler config carries
+// the overrides of the namespace. Invalid overrides are ignored, so that
+// they don't stop the revisions of the namespace from scaling.
+func (c *Reconciler) withNamespaceOverrides(ctx context.Context, namespace string) context.Context {
+	ns, err := c.namespaceLister.Get(namespace)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			logging.FromContext(ctx).Warnw("Error retrieving namespace", zap.Error(err))
+		}
+		return ctx
+	}
+	octx, err := config.WithNamespaceOverrides(ctx, ns)
+	if err != nil {
+		logging.FromContext(ctx).Warnw("Ignoring invalid namespace autoscaler overrides", zap.Error(err))
+	}
+	return octx
+}
+
 // activeThreshold returns the scale required for the pa to be marked Active
 func activeThreshold(ctx context.Context, pa *autoscalingv1alpha1.PodAutoscaler) int {
 	asConfig := config.FromContext(ctx).Autoscaler
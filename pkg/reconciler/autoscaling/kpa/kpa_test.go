@@ -32,6 +32,9 @@ import (
 	fakenetworkingclient "knative.dev/networking/pkg/client/injection/client/fake"
 	fakesksinformer "knative.dev/networking/pkg/client/injection/informers/networking/v1alpha1/serverlessservice/fake"
 	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
+	_ "knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment/fake"
+	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/endpoints/fake"
+	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace/fake"
 	fakepodsinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/pod/fake"
 	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/service/fake"
 	fakedynamicclient "knative.dev/pkg/injection/clients/dynamicclient/fake"
@@ -58,12 +61,15 @@ import (
 	"go.uber.org/atomic"
 	"golang.org/x/sync/errgroup"
 
+	network "knative.dev/networking/pkg"
 	nv1a1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/logging"
+	logtesting "knative.dev/pkg/logging/testing"
+	pkgmetrics "knative.dev/pkg/metrics"
 	"knative.dev/pkg/metrics/metricskey"
 	"knative.dev/pkg/metrics/metricstest"
 	_ "knative.dev/pkg/metrics/testing"
@@ -71,18 +77,22 @@ import (
 	"knative.dev/pkg/reconciler"
 	"knative.dev/pkg/system"
 	_ "knative.dev/pkg/system/testing"
+	tracingconfig "knative.dev/pkg/tracing/config"
 	"knative.dev/serving/pkg/apis/autoscaling"
 	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
+	apicfg "knative.dev/serving/pkg/apis/config"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	asconfig "knative.dev/serving/pkg/autoscaler/config"
 	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
 	"knative.dev/serving/pkg/autoscaler/scaling"
 	"knative.dev/serving/pkg/deployment"
+	"knative.dev/serving/pkg/networking"
 	areconciler "knative.dev/serving/pkg/reconciler/autoscaling"
 	"knative.dev/serving/pkg/reconciler/autoscaling/config"
 	"knative.dev/serving/pkg/reconciler/autoscaling/kpa/resources"
 	aresources "knative.dev/serving/pkg/reconciler/autoscaling/resources"
+	revisionconfig "knative.dev/serving/pkg/reconciler/revision/config"
 	revisionresources "knative.dev/serving/pkg/reconciler/revision/resources"
 
 	. "knative.dev/pkg/reconciler/testing"
@@ -132,13 +142,17 @@ func defaultConfig() *config.Config {
 }
 
 func newConfigWatcher() configmap.Watcher {
-	return configmap.NewStaticWatcher(&corev1.ConfigMap{
+	return configmap.NewStaticWatcher(configMaps()...)
+}
+
+func configMaps() []*corev1.ConfigMap {
+	cms := []*corev1.ConfigMap{{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: system.Namespace(),
 			Name:      asconfig.ConfigName,
 		},
 		Data: defaultConfigMapData(),
-	}, &corev1.ConfigMap{
+	}, {
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: system.Namespace(),
 			Name:      deployment.ConfigName,
@@ -146,7 +160,31 @@ func newConfigWatcher() configmap.Watcher {
 		Data: map[string]string{
 			deployment.QueueSidecarImageKey: "covid is here",
 		},
-	})
+	}}
+	// The configuration of the queue-proxy injected by standalone PAs.
+	for _, name := range []string{
+		apicfg.DefaultsConfigName,
+		apicfg.FeaturesConfigName,
+		apicfg.MetadataPropagationConfigName,
+		logging.ConfigMapName(),
+		pkgmetrics.ConfigMapName(),
+		network.ConfigName,
+		tracingconfig.ConfigName,
+	} {
+		cms = append(cms, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      name,
+			},
+		})
+	}
+	return cms
+}
+
+func newQueueConfigStore(t *testing.T) *revisionconfig.Store {
+	store := revisionconfig.NewStore(logtesting.TestLogger(t))
+	store.WatchConfigs(newConfigWatcher())
+	return store
 }
 
 func withScales(g, w int32) PodAutoscalerOption {
@@ -222,6 +260,7 @@ func TestReconcile(t *testing.T) {
 		underscale   = defaultScale - 1
 		overscale    = defaultScale + 1
 	)
+	queueConfigStore := newQueueConfigStore(t)
 
 	// Set up a default deployment with the appropriate scale so that we don't
 	// see patches to correct that scale.
@@ -308,6 +347,96 @@ func TestReconcile(t *testing.T) {
 			sks(testNamespace, testRevision, WithDeployRef(deployName), WithSKSReady),
 			metric(testNamespace, testRevision),
 			defaultDeployment, defaultReady},
+	}, {
+		Name: "steady state, hot pods",
+		Key:  key,
+		Ctx: context.WithValue(context.Background(), deciderKey{},
+			withHotPods(decider(testNamespace, testRevision, defaultScale, 0 /* ebc */, scaling.MinActivators),
+				2.5, "pod-a")),
+		Objects: []runtime.Object{
+			kpa(testNamespace, testRevision, WithPASKSReady, WithTraffic,
+				markScaleTargetInitialized, WithPAMetricsService(privateSvc),
+				withScales(1, defaultScale), WithPAStatusService(testRevision), WithObservedGeneration(1)),
+			sks(testNamespace, testRevision, WithDeployRef(deployName), WithSKSReady),
+			metric(testNamespace, testRevision),
+			defaultDeployment, defaultReady},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: kpa(testNamespace, testRevision, WithPASKSReady, WithTraffic,
+				markScaleTargetInitialized, WithPAMetricsService(privateSvc),
+				withScales(1, defaultScale), WithPAStatusService(testRevision), WithObservedGeneration(1),
+				func(pa *autoscalingv1alpha1.PodAutoscaler) {
+					pa.Status.MarkLoadImbalanced(2.5, []string{"pod-a"})
+				}),
+		}},
+	}, {
+		Name: "steady state, hot pods cooled down",
+		Key:  key,
+		Objects: []runtime.Object{
+			kpa(testNamespace, testRevision, WithPASKSReady, WithTraffic,
+				markScaleTargetInitialized, WithPAMetricsService(privateSvc),
+				withScales(1, defaultScale), WithPAStatusService(testRevision), WithObservedGeneration(1),
+				func(pa *autoscalingv1alpha1.PodAutoscaler) {
+					pa.Status.MarkLoadImbalanced(2.5, []string{"pod-a"})
+				}),
+			sks(testNamespace, testRevision, WithDeployRef(deployName), WithSKSReady),
+			metric(testNamespace, testRevision),
+			defaultDeployment, defaultReady},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: kpa(testNamespace, testRevision, WithPASKSReady, WithTraffic,
+				markScaleTargetInitialized, WithPAMetricsService(privateSvc),
+				withScales(1, defaultScale), WithPAStatusService(testRevision), WithObservedGeneration(1),
+				func(pa *autoscalingv1alpha1.PodAutoscaler) {
+					pa.Status.MarkLoadBalanced()
+				}),
+		}},
+	}, {
+		Name: "standalone steady state",
+		Key:  key,
+		Objects: append([]runtime.Object{
+			kpa(testNamespace, testRevision, withStandalone, WithPASKSReady, WithTraffic,
+				markScaleTargetInitialized, WithPAMetricsService(privateSvc),
+				withScales(1, defaultScale), WithPAStatusService(testRevision), WithObservedGeneration(1)),
+			sks(testNamespace, testRevision, WithDeployRef(deployName), WithSKSReady),
+			metric(testNamespace, testRevision),
+			deploy(testNamespace, testRevision, withStandaloneTemplate(t, queueConfigStore), func(d *appsv1.Deployment) {
+				d.Spec.Replicas = ptr.Int32(defaultScale)
+			}),
+		}, makeStandaloneReadyPods(1, testNamespace, testRevision)...),
+	}, {
+		Name: "standalone target gets the queue-proxy injected",
+		Key:  key,
+		Objects: append([]runtime.Object{
+			kpa(testNamespace, testRevision, withStandalone, WithPASKSReady, WithTraffic,
+				markScaleTargetInitialized, WithPAMetricsService(privateSvc),
+				withScales(1, defaultScale), WithPAStatusService(testRevision), WithObservedGeneration(1)),
+			sks(testNamespace, testRevision, WithDeployRef(deployName), WithSKSReady),
+			metric(testNamespace, testRevision),
+			deploy(testNamespace, testRevision, withUserContainer, func(d *appsv1.Deployment) {
+				d.Spec.Replicas = ptr.Int32(defaultScale)
+			}),
+		}, makeStandaloneReadyPods(1, testNamespace, testRevision)...),
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: deploy(testNamespace, testRevision, withStandaloneTemplate(t, queueConfigStore), func(d *appsv1.Deployment) {
+				d.Spec.Replicas = ptr.Int32(defaultScale)
+			}),
+		}},
+	}, {
+		Name: "standalone target is missing",
+		Key:  key,
+		Objects: []runtime.Object{
+			kpa(testNamespace, testRevision, withStandalone),
+		},
+		WantErr: true,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: kpa(testNamespace, testRevision, withStandalone, WithObservedGeneration(1),
+				func(pa *autoscalingv1alpha1.PodAutoscaler) {
+					pa.Status.MarkScaleTargetNotPodScalable(`deployment.apps "` + deployName + `" not found`)
+				}),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, "InternalError",
+				`error getting scale target: deployment.apps "`+deployName+`" not found`),
+		},
 	}, {
 		Name: "status update retry",
 		Key:  key,
@@ -376,6 +505,31 @@ func TestReconcile(t *testing.T) {
 				`error reconciling Metric: error updating metric: inducing failure for update metrics`),
 		},
 		WantErr: true,
+	}, {
+		Name: "namespace overrides stable window",
+		Key:  key,
+		Objects: []runtime.Object{
+			&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: testNamespace,
+					Annotations: map[string]string{
+						asconfig.NamespaceOverrideAnnotationPrefix + "stable-window": "2m",
+					},
+				},
+			},
+			kpa(testNamespace, testRevision, WithPASKSReady, WithTraffic,
+				markScaleTargetInitialized, WithPAMetricsService(privateSvc),
+				withScales(1, defaultScale), WithPAStatusService(testRevision), WithObservedGeneration(1)),
+			sks(testNamespace, testRevision, WithDeployRef(deployName), WithSKSReady),
+			metric(testNamespace, testRevision),
+			defaultDeployment, defaultReady},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: func() *autoscalingv1alpha1.Metric {
+				cfg := defaultConfig().Autoscaler.DeepCopy()
+				cfg.StableWindow = 2 * time.Minute
+				return aresources.MakeMetric(kpa(testNamespace, testRevision), privateSvc, cfg)
+			}(),
+		}},
 	}, {
 		Name: "create metric",
 		Key:  key,
@@ -596,6 +750,40 @@ func TestReconcile(t *testing.T) {
 			Object: sks(testNamespace, testRevision, WithPubService, WithPrivateService,
 				WithDeployRef(deployName)),
 		}},
+	}, {
+		Name: "sks sized to dedicated activator pool",
+		Key:  key,
+		Objects: []runtime.Object{
+			kpa(testNamespace, testRevision, WithPASKSReady,
+				withScales(0, defaultScale), WithPAMetricsService(privateSvc), WithTraffic),
+			sks(testNamespace, testRevision, WithDeployRef(deployName), WithPubService, WithPrivateService),
+			metric(testNamespace, testRevision),
+			defaultDeployment,
+			&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   testNamespace,
+					Labels: map[string]string{networking.ActivatorPoolLabelKey: "tenant"},
+				},
+			},
+			&corev1.Endpoints{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: system.Namespace(),
+					Name:      networking.ActivatorPoolServiceName("tenant"),
+				},
+				Subsets: []corev1.EndpointSubset{{
+					Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}},
+				}},
+			},
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: kpa(testNamespace, testRevision, WithPASKSNotReady(""),
+				WithBufferedTraffic, withScales(0, defaultScale), WithPAStatusService(testRevision),
+				WithPAMetricsService(privateSvc), WithObservedGeneration(1)),
+		}},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: sks(testNamespace, testRevision, WithPubService, WithPrivateService,
+				WithDeployRef(deployName), WithNumActivators(1)),
+		}},
 	}, {
 		Name: "sks cannot be created",
 		Key:  key,
@@ -1165,9 +1353,15 @@ func TestReconcile(t *testing.T) {
 				SKSLister:        listers.GetServerlessServiceLister(),
 				MetricLister:     listers.GetMetricLister(),
 			},
-			podsLister: listers.GetPodsLister(),
-			deciders:   fakeDeciders,
-			scaler:     scaler,
+			kubeClient:       fakekubeclient.Get(ctx),
+			podsLister:       listers.GetPodsLister(),
+			namespaceLister:  listers.GetNamespaceLister(),
+			endpointsLister:  listers.GetEndpointsLister(),
+			deploymentLister: listers.GetDeploymentLister(),
+			deciders:         fakeDeciders,
+			scaler:           scaler,
+			tracker:          &NullTracker{},
+			queueConfigStore: queueConfigStore,
 		}
 		return pareconciler.NewReconciler(ctx, logging.FromContext(ctx),
 			servingclient.Get(ctx), listers.GetPodAutoscalerLister(),
@@ -1711,6 +1905,38 @@ func newTestRevision(namespace, name string) *v1.Revision {
 	}
 }
 
+func withStandalone(pa *autoscalingv1alpha1.PodAutoscaler) {
+	delete(pa.Labels, serving.RevisionLabelKey)
+	pa.Annotations[autoscaling.StandaloneAnnotationKey] = "true"
+}
+
+func withUserContainer(d *appsv1.Deployment) {
+	d.Spec.Template.Spec.Containers = []corev1.Container{{
+		Name:  "app",
+		Image: "app",
+	}}
+}
+
+func withStandaloneTemplate(t *testing.T, store *revisionconfig.Store) deploymentOption {
+	return func(d *appsv1.Deployment) {
+		withUserContainer(d)
+		template, err := resources.MakeStandalonePodTemplate(kpa(testNamespace, testRevision, withStandalone),
+			&d.Spec.Template, store.Load())
+		if err != nil {
+			t.Fatal("MakeStandalonePodTemplate() =", err)
+		}
+		d.Spec.Template = *template
+	}
+}
+
+func makeStandaloneReadyPods(num int, ns, n string) []runtime.Object {
+	pods := makeReadyPods(num, ns, n)
+	for _, p := range pods {
+		p.(*corev1.Pod).Labels = map[string]string{autoscaling.PodAutoscalerLabelKey: n}
+	}
+	return pods
+}
+
 func makeReadyPods(num int, ns, n string) []runtime.Object {
 	r := make([]runtime.Object, num)
 	for i := 0; i < num; i++ {
@@ -1767,6 +1993,12 @@ func decider(ns, name string, desiredScale, ebc, nact int32) *scaling.Decider {
 	}
 }
 
+func withHotPods(d *scaling.Decider, index float64, pods ...string) *scaling.Decider {
+	d.Status.ImbalanceIndex = index
+	d.Status.HotPods = pods
+	return d
+}
+
 type testConfigStore struct {
 	config *config.Config
 }
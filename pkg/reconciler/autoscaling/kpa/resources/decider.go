@@ -66,21 +66,24 @@ func MakeDecider(pa *autoscalingv1alpha1.PodAutoscaler, config *autoscalerconfig
 		scaleDownDelay = sdd
 	}
 
+	om := pa.ObjectMeta.DeepCopy()
+	om.Labels = resources.MakeLabels(pa)
 	return &scaling.Decider{
-		ObjectMeta: *pa.ObjectMeta.DeepCopy(),
+		ObjectMeta: *om,
 		Spec: scaling.DeciderSpec{
-			MaxScaleUpRate:      config.MaxScaleUpRate,
-			MaxScaleDownRate:    config.MaxScaleDownRate,
-			ScalingMetric:       pa.Metric(),
-			TargetValue:         target,
-			TotalValue:          total,
-			TargetBurstCapacity: tbc,
-			ActivatorCapacity:   config.ActivatorCapacity,
-			PanicThreshold:      panicThreshold,
-			StableWindow:        resources.StableWindow(pa, config),
-			ScaleDownDelay:      scaleDownDelay,
-			InitialScale:        GetInitialScale(config, pa),
-			Reachable:           pa.Spec.Reachability != autoscalingv1alpha1.ReachabilityUnreachable,
+			MaxScaleUpRate:        config.MaxScaleUpRate,
+			MaxScaleDownRate:      config.MaxScaleDownRate,
+			ScalingMetric:         pa.Metric(),
+			TargetValue:           target,
+			TotalValue:            total,
+			TargetBurstCapacity:   tbc,
+			ActivatorCapacity:     config.ActivatorCapacity,
+			PanicThreshold:        panicThreshold,
+			StableWindow:          resources.StableWindow(pa, config),
+			ScaleDownDelay:        scaleDownDelay,
+			PodImbalanceThreshold: config.PodImbalanceThreshold,
+			InitialScale:          GetInitialScale(config, pa),
+			Reachable:             pa.Spec.Reachability != autoscalingv1alpha1.ReachabilityUnreachable,
 		},
 	}
 }
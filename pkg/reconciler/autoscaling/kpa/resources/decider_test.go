@@ -26,6 +26,7 @@ import (
 
 	"knative.dev/serving/pkg/apis/autoscaling"
 	"knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
+	"knative.dev/serving/pkg/apis/serving"
 	asconfig "knative.dev/serving/pkg/autoscaler/config"
 	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
 	"knative.dev/serving/pkg/autoscaler/scaling"
@@ -42,6 +43,16 @@ func TestMakeDecider(t *testing.T) {
 		name: "defaults",
 		pa:   pa(),
 		want: decider(withTarget(100.0), withPanicThreshold(2.0), withTotal(100)),
+	}, {
+		name: "standalone",
+		pa: pa(func(pa *v1alpha1.PodAutoscaler) {
+			pa.Labels = nil
+			pa.Annotations[autoscaling.StandaloneAnnotationKey] = "true"
+		}),
+		want: decider(withTarget(100.0), withPanicThreshold(2.0), withTotal(100), func(d *scaling.Decider) {
+			d.Labels = map[string]string{autoscaling.PodAutoscalerLabelKey: "test-name"}
+			d.Annotations[autoscaling.StandaloneAnnotationKey] = "true"
+		}),
 	}, {
 		name: "unreachable",
 		pa: pa(func(pa *v1alpha1.PodAutoscaler) {
@@ -234,6 +245,9 @@ func pa(options ...PodAutoscalerOption) *v1alpha1.PodAutoscaler {
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "test-namespace",
 			Name:      "test-name",
+			Labels: map[string]string{
+				serving.RevisionLabelKey: "test-name",
+			},
 			Annotations: map[string]string{
 				autoscaling.ClassAnnotationKey:  autoscaling.KPA,
 				autoscaling.MetricAnnotationKey: autoscaling.Concurrency,
@@ -281,6 +295,9 @@ func decider(options ...deciderOption) *scaling.Decider {
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "test-namespace",
 			Name:      "test-name",
+			Labels: map[string]string{
+				serving.RevisionLabelKey: "test-name",
+			},
 			Annotations: map[string]string{
 				autoscaling.ClassAnnotationKey:  autoscaling.KPA,
 				autoscaling.MetricAnnotationKey: autoscaling.Concurrency,
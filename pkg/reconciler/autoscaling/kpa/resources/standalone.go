@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
+	revisionconfig "knative.dev/serving/pkg/reconciler/revision/config"
+	revisionresources "knative.dev/serving/pkg/reconciler/revision/resources"
+)
+
+// MakeStandalonePodTemplate returns the pod template of the Deployment scaled
+// by the standalone PA, with what the autoscaler needs to scale its pods: the
+// PA label, by which the pods are found, and, for the queue-proxy metric
+// source, the queue-proxy sidecar that is scraped for their stats.
+func MakeStandalonePodTemplate(pa *autoscalingv1alpha1.PodAutoscaler, in *corev1.PodTemplateSpec,
+	cfg *revisionconfig.Config) (*corev1.PodTemplateSpec, error) {
+	out := in.DeepCopy()
+	out.Labels = kmeta.UnionMaps(out.Labels, map[string]string{
+		autoscaling.PodAutoscalerLabelKey: pa.Name,
+	})
+
+	queueIdx := -1
+	userContainers := make([]corev1.Container, 0, len(out.Spec.Containers))
+	for i, c := range out.Spec.Containers {
+		if c.Name == revisionresources.QueueContainerName {
+			queueIdx = i
+		} else {
+			userContainers = append(userContainers, c)
+		}
+	}
+
+	if pa.MetricSource() != autoscaling.MetricSourceQueueProxy {
+		// Drop the sidecar injected for a previous metric source.
+		out.Spec.Containers = userContainers
+		return out, nil
+	}
+
+	podSpec := out.Spec.DeepCopy()
+	podSpec.Containers = userContainers
+	queue, err := revisionresources.MakeStandaloneQueueContainer(pa, podSpec, cfg)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case queueIdx < 0:
+		out.Spec.Containers = append(out.Spec.Containers, *queue)
+	case !sameQueueContainer(&out.Spec.Containers[queueIdx], queue):
+		out.Spec.Containers[queueIdx] = *queue
+	}
+	return out, nil
+}
+
+// sameQueueContainer returns whether the injected queue-proxy is up to date.
+// Only the image and the literal environment values are compared, since the
+// API server defaults the other fields.
+func sameQueueContainer(got, want *corev1.Container) bool {
+	if got.Image != want.Image || len(got.Env) != len(want.Env) {
+		return false
+	}
+	values := make(map[string]string, len(got.Env))
+	for _, e := range got.Env {
+		values[e.Name] = e.Value
+	}
+	for _, e := range want.Env {
+		if v, ok := values[e.Name]; !ok || v != e.Value {
+			return false
+		}
+	}
+	return true
+}
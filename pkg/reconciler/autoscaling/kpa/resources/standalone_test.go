@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	network "knative.dev/networking/pkg"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/metrics"
+	_ "knative.dev/pkg/metrics/testing"
+	_ "knative.dev/pkg/system/testing"
+	tracingconfig "knative.dev/pkg/tracing/config"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	"knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
+	apicfg "knative.dev/serving/pkg/apis/config"
+	"knative.dev/serving/pkg/deployment"
+	revisionconfig "knative.dev/serving/pkg/reconciler/revision/config"
+	revisionresources "knative.dev/serving/pkg/reconciler/revision/resources"
+)
+
+func TestMakeStandalonePodTemplate(t *testing.T) {
+	defaults, _ := apicfg.NewDefaultsConfigFromMap(nil)
+	cfg := &revisionconfig.Config{
+		Config:        &apicfg.Config{Defaults: defaults},
+		Deployment:    &deployment.Config{QueueSidecarImage: "queue:v2"},
+		Logging:       &logging.Config{},
+		Network:       &network.Config{},
+		Observability: &metrics.ObservabilityConfig{},
+		Tracing:       &tracingconfig.Config{},
+	}
+	standalonePA := func(source string) *v1alpha1.PodAutoscaler {
+		pa := &v1alpha1.PodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      "user-pa",
+			},
+		}
+		if source != "" {
+			pa.Annotations = map[string]string{autoscaling.MetricSourceAnnotationKey: source}
+		}
+		return pa
+	}
+	userContainer := corev1.Container{
+		Name:  "app",
+		Image: "app:v1",
+		Ports: []corev1.ContainerPort{{ContainerPort: 8080}},
+	}
+	template := func(containers ...corev1.Container) *corev1.PodTemplateSpec {
+		return &corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"app": "user"},
+			},
+			Spec: corev1.PodSpec{
+				Containers: append([]corev1.Container{userContainer}, containers...),
+			},
+		}
+	}
+	labeled := func(t *corev1.PodTemplateSpec) *corev1.PodTemplateSpec {
+		t.Labels[autoscaling.PodAutoscalerLabelKey] = "user-pa"
+		return t
+	}
+	queue, err := revisionresources.MakeStandaloneQueueContainer(standalonePA(""),
+		&corev1.PodSpec{Containers: []corev1.Container{userContainer}}, cfg)
+	if err != nil {
+		t.Fatal("MakeStandaloneQueueContainer() =", err)
+	}
+	// The API server defaults the fields of the injected container.
+	defaultedQueue := queue.DeepCopy()
+	defaultedQueue.ImagePullPolicy = corev1.PullIfNotPresent
+	defaultedQueue.TerminationMessagePath = corev1.TerminationMessagePathDefault
+	staleQueue := queue.DeepCopy()
+	staleQueue.Image = "queue:v1"
+
+	tests := []struct {
+		name string
+		pa   *v1alpha1.PodAutoscaler
+		in   *corev1.PodTemplateSpec
+		want *corev1.PodTemplateSpec
+	}{{
+		name: "inject queue-proxy",
+		pa:   standalonePA(""),
+		in:   template(),
+		want: labeled(template(*queue)),
+	}, {
+		name: "injected queue-proxy is kept",
+		pa:   standalonePA(autoscaling.MetricSourceQueueProxy),
+		in:   labeled(template(*defaultedQueue)),
+		want: labeled(template(*defaultedQueue)),
+	}, {
+		name: "stale queue-proxy is replaced",
+		pa:   standalonePA(""),
+		in:   labeled(template(*staleQueue)),
+		want: labeled(template(*queue)),
+	}, {
+		name: "rps source",
+		pa:   standalonePA(autoscaling.MetricSourceRPS),
+		in:   template(),
+		want: labeled(template()),
+	}, {
+		name: "rps source drops the queue-proxy",
+		pa:   standalonePA(autoscaling.MetricSourceRPS),
+		in:   labeled(template(*defaultedQueue)),
+		want: labeled(template()),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := MakeStandalonePodTemplate(test.pa, test.in, cfg)
+			if err != nil {
+				t.Fatal("MakeStandalonePodTemplate() =", err)
+			}
+			if !cmp.Equal(got, test.want) {
+				t.Error("MakeStandalonePodTemplate (-want, +got):", cmp.Diff(test.want, got))
+			}
+		})
+	}
+}
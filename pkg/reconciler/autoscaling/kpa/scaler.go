@@ -181,7 +181,9 @@ func (ks *scaler) handleScaleToZero(ctx context.Context, pa *autoscalingv1alpha1
 	cfgs := config.FromContext(ctx)
 	cfgAS := cfgs.Autoscaler
 
-	if !cfgAS.EnableScaleToZero {
+	// Standalone PAs cannot scale to zero, since without a revision there is
+	// nothing the activator could buffer requests for.
+	if !cfgAS.EnableScaleToZero || pa.IsStandalone() {
 		return 1, true
 	}
 	cfgD := cfgs.Deployment
@@ -214,6 +214,17 @@ func TestScaler(t *testing.T) {
 		paMutation: func(k *autoscalingv1alpha1.PodAutoscaler) {
 			paMarkInactive(k, time.Now().Add(-gracePeriod))
 		},
+	}, {
+		label:         "standalone PA does not scale to zero",
+		startReplicas: 3,
+		scaleTo:       0,
+		wantReplicas:  1,
+		wantScaling:   true,
+		paMutation: func(k *autoscalingv1alpha1.PodAutoscaler) {
+			paMarkInactive(k, time.Now().Add(-gracePeriod))
+			delete(k.Labels, serving.RevisionLabelKey)
+			k.Annotations[autoscaling.StandaloneAnnotationKey] = "true"
+		},
 	}, {
 		label:         "waits to scale to zero (just before grace period)",
 		startReplicas: 1,
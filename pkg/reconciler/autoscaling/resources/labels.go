@@ -0,0 +1,35 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"knative.dev/pkg/kmeta"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	"knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
+)
+
+// MakeLabels returns the labels of the resources derived from the PA.
+// Those of standalone PAs are marked with autoscaling.PodAutoscalerLabelKey,
+// since there is no revision to identify their pods by.
+func MakeLabels(pa *v1alpha1.PodAutoscaler) map[string]string {
+	if !pa.IsStandalone() {
+		return kmeta.CopyMap(pa.Labels)
+	}
+	return kmeta.UnionMaps(pa.Labels, map[string]string{
+		autoscaling.PodAutoscalerLabelKey: pa.Name,
+	})
+}
@@ -55,7 +55,7 @@ func MakeMetric(pa *v1alpha1.PodAutoscaler, metricSvc string, config *autoscaler
 			Namespace:       pa.Namespace,
 			Name:            pa.Name,
 			Annotations:     kmeta.CopyMap(pa.Annotations),
-			Labels:          kmeta.CopyMap(pa.Labels),
+			Labels:          MakeLabels(pa),
 			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(pa)},
 		},
 		Spec: v1alpha1.MetricSpec{
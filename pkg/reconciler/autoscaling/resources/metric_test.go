@@ -25,6 +25,7 @@ import (
 	"knative.dev/pkg/kmeta"
 	"knative.dev/serving/pkg/apis/autoscaling"
 	"knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
+	"knative.dev/serving/pkg/apis/serving"
 	asconfig "knative.dev/serving/pkg/autoscaler/config"
 	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
 	. "knative.dev/serving/pkg/testing"
@@ -41,6 +42,17 @@ func TestMakeMetric(t *testing.T) {
 		pa:   pa(),
 		msn:  "ik",
 		want: metric(withScrapeTarget("ik")),
+	}, {
+		name: "standalone",
+		pa: pa(func(pa *v1alpha1.PodAutoscaler) {
+			pa.Labels = nil
+			pa.Annotations[autoscaling.StandaloneAnnotationKey] = "true"
+		}),
+		msn: "ik",
+		want: metric(withScrapeTarget("ik"), func(m *v1alpha1.Metric) {
+			m.Labels = map[string]string{autoscaling.PodAutoscalerLabelKey: "test-name"}
+			m.Annotations[autoscaling.StandaloneAnnotationKey] = "true"
+		}),
 	}, {
 		name: "with too short panic window",
 		pa:   pa(WithWindowAnnotation("10s"), WithPanicWindowPercentageAnnotation("10")),
@@ -136,7 +148,9 @@ func metric(options ...MetricOption) *v1alpha1.Metric {
 			Annotations: map[string]string{
 				autoscaling.ClassAnnotationKey: autoscaling.KPA,
 			},
-			Labels:          map[string]string{},
+			Labels: map[string]string{
+				serving.RevisionLabelKey: "test-name",
+			},
 			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(pa())},
 		},
 		Spec: v1alpha1.MetricSpec{
@@ -155,6 +169,9 @@ func pa(options ...PodAutoscalerOption) *v1alpha1.PodAutoscaler {
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "test-namespace",
 			Name:      "test-name",
+			Labels: map[string]string{
+				serving.RevisionLabelKey: "test-name",
+			},
 			Annotations: map[string]string{
 				autoscaling.ClassAnnotationKey: autoscaling.KPA,
 			},
@@ -28,8 +28,9 @@ type cfgKey struct{}
 // Config holds the collection of configurations that we attach to contexts.
 // +k8s:deepcopy-gen=false
 type Config struct {
-	Defaults *cfgmap.Defaults
-	Features *cfgmap.Features
+	Defaults            *cfgmap.Defaults
+	Features            *cfgmap.Features
+	MetadataPropagation *cfgmap.MetadataPropagation
 }
 
 // FromContext extracts a Config from the provided context.
@@ -58,6 +59,10 @@ func FromContextOrDefaults(ctx context.Context) *Config {
 		cfg.Features, _ = cfgmap.NewFeaturesConfigFromMap(nil)
 	}
 
+	if cfg.MetadataPropagation == nil {
+		cfg.MetadataPropagation, _ = cfgmap.NewMetadataPropagationFromMap(nil)
+	}
+
 	return cfg
 }
 
@@ -80,8 +85,9 @@ func NewStore(logger configmap.Logger, onAfterStore ...func(name string, value i
 			"apis",
 			logger,
 			configmap.Constructors{
-				cfgmap.DefaultsConfigName: cfgmap.NewDefaultsConfigFromConfigMap,
-				cfgmap.FeaturesConfigName: cfgmap.NewFeaturesConfigFromConfigMap,
+				cfgmap.DefaultsConfigName:            cfgmap.NewDefaultsConfigFromConfigMap,
+				cfgmap.FeaturesConfigName:            cfgmap.NewFeaturesConfigFromConfigMap,
+				cfgmap.MetadataPropagationConfigName: cfgmap.NewMetadataPropagationFromConfigMap,
 			},
 			onAfterStore...,
 		),
@@ -104,6 +110,9 @@ func (s *Store) Load() *Config {
 	if feat, ok := s.UntypedLoad(cfgmap.FeaturesConfigName).(*cfgmap.Features); ok {
 		cfg.Features = feat.DeepCopy()
 	}
+	if mp, ok := s.UntypedLoad(cfgmap.MetadataPropagationConfigName).(*cfgmap.MetadataPropagation); ok {
+		cfg.MetadataPropagation = mp.DeepCopy()
+	}
 
 	return cfg
 }
@@ -38,9 +38,11 @@ func TestStoreLoadWithContext(t *testing.T) {
 
 	defaultsConfig := ConfigMapFromTestFile(t, cfgmap.DefaultsConfigName)
 	featuresConfig := ConfigMapFromTestFile(t, cfgmap.FeaturesConfigName)
+	propagationConfig := ConfigMapFromTestFile(t, cfgmap.MetadataPropagationConfigName)
 
 	store.OnConfigChanged(defaultsConfig)
 	store.OnConfigChanged(featuresConfig)
+	store.OnConfigChanged(propagationConfig)
 
 	config := FromContextOrDefaults(store.ToContext(context.Background()))
 
@@ -57,6 +59,13 @@ func TestStoreLoadWithContext(t *testing.T) {
 			t.Errorf("Unexpected features config = %v, want: %v, diff (-want, +got):\n%s", got, want, cmp.Diff(want, got, ignoreStuff...))
 		}
 	})
+
+	t.Run("metadata-propagation", func(t *testing.T) {
+		expected, _ := cfgmap.NewMetadataPropagationFromConfigMap(propagationConfig)
+		if got, want := config.MetadataPropagation, expected; !cmp.Equal(got, want) {
+			t.Errorf("Unexpected metadata propagation config = %v, want: %v, diff (-want, +got):\n%s", got, want, cmp.Diff(want, got))
+		}
+	})
 }
 
 func TestStoreLoadWithContextOrDefaults(t *testing.T) {
@@ -280,6 +280,17 @@ func (c *Reconciler) createRevision(ctx context.Context, config *v1.Configuratio
 	logger := logging.FromContext(ctx)
 
 	rev := resources.MakeRevision(ctx, config, c.clock)
+	// Record why this Revision exists, relative to the one it replaces,
+	// replacing whatever the template might carry over.
+	var cause string
+	if prev := c.previousRevision(config); prev != nil {
+		cause = resources.ChangeCause(prev, rev)
+	}
+	if cause != "" {
+		rev.Annotations[serving.ChangeCauseAnnotationKey] = cause
+	} else {
+		delete(rev.Annotations, serving.ChangeCauseAnnotationKey)
+	}
 	created, err := c.client.ServingV1().Revisions(config.Namespace).Create(ctx, rev, metav1.CreateOptions{})
 	if err != nil {
 		return nil, err
@@ -289,3 +300,17 @@ func (c *Reconciler) createRevision(ctx context.Context, config *v1.Configuratio
 
 	return created, nil
 }
+
+// previousRevision returns the latest Revision created for the Configuration
+// before its current generation, or nil if there is none.
+func (c *Reconciler) previousRevision(config *v1.Configuration) *v1.Revision {
+	name := config.Status.LatestCreatedRevisionName
+	if name == "" {
+		return nil
+	}
+	rev, err := c.revisionLister.Revisions(config.Namespace).Get(name)
+	if err != nil {
+		return nil
+	}
+	return rev
+}
@@ -37,6 +37,7 @@ import (
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/ptr"
+	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	servingclient "knative.dev/serving/pkg/client/injection/client/fake"
 	configreconciler "knative.dev/serving/pkg/client/injection/reconciler/serving/v1/configuration"
@@ -118,6 +119,45 @@ func test(t *testing.T) {
 			Eventf(corev1.EventTypeNormal, "Created", "Created Revision %q", "no-revisions-yet-01234"),
 		},
 		Key: "foo/no-revisions-yet",
+	}, {
+		Name: "create revision records change cause",
+		Ctx:  config.ToContext(context.Background(), config.FromContext(testCtx)),
+		Objects: []runtime.Object{
+			cfg("change-cause", "foo", 2,
+				WithLatestCreated("change-cause-00001"),
+				WithLatestReady("change-cause-00001"),
+				WithConfigObservedGen,
+				func(cfg *v1.Configuration) {
+					c := &cfg.Spec.Template.Spec.Containers[0]
+					c.Image = "busybox:2"
+					c.Env = []corev1.EnvVar{{Name: "FOO", Value: "bar"}}
+				}),
+			rev("change-cause", "foo", 1,
+				WithCreationTimestamp(now), MarkRevisionReady),
+		},
+		WantCreates: []runtime.Object{
+			rev("change-cause", "foo", 2, func(rev *v1.Revision) {
+				c := &rev.Spec.Containers[0]
+				c.Image = "busybox:2"
+				c.Env = []corev1.EnvVar{{Name: "FOO", Value: "bar"}}
+				rev.Annotations[serving.ChangeCauseAnnotationKey] = "image changed, env FOO added"
+			}),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: cfg("change-cause", "foo", 2,
+				WithLatestCreated("change-cause-00002"),
+				WithLatestReady("change-cause-00001"),
+				WithConfigObservedGen,
+				func(cfg *v1.Configuration) {
+					c := &cfg.Spec.Template.Spec.Containers[0]
+					c.Image = "busybox:2"
+					c.Env = []corev1.EnvVar{{Name: "FOO", Value: "bar"}}
+				}),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Created", "Created Revision %q", "change-cause-00002"),
+		},
+		Key: "foo/change-cause",
 	}, {
 		Name: "create revision byo name",
 		Ctx:  config.ToContext(context.Background(), config.FromContext(testCtx)),
@@ -98,6 +98,12 @@ func TestNewConfigurationCallsSyncHandler(t *testing.T) {
 			Namespace: system.Namespace(),
 		},
 		Data: map[string]string{},
+	}, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cfgmap.MetadataPropagationConfigName,
+			Namespace: system.Namespace(),
+		},
+		Data: map[string]string{},
 	}, &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cfgmap.DefaultsConfigName,
@@ -0,0 +1,203 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/serving/pkg/apis/serving"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// maxChangeCauseLength bounds the length of the change cause annotation,
+// so that sweeping template changes don't produce unwieldy summaries.
+const maxChangeCauseLength = 256
+
+// systemAnnotations are maintained by the system rather than by the users
+// changing the template, so they don't contribute to the change cause.
+var systemAnnotations = sets.NewString(
+	serving.ChangeCauseAnnotationKey,
+	serving.CreatorAnnotation,
+	serving.RevisionLastPinnedAnnotationKey,
+	serving.RouteResponseHeaderPolicyAnnotationKey,
+	serving.RoutesAnnotationKey,
+	serving.RoutingStateModifiedAnnotationKey,
+	serving.UpdaterAnnotation,
+)
+
+// ChangeCause returns a concise summary of the differences between the
+// templates the previous and the new Revision were stamped out from, e.g.
+// "image changed, env FOO added, annotation bar modified".
+func ChangeCause(prev, rev *v1.Revision) string {
+	changes := containerChanges(prev.Spec.Containers, rev.Spec.Containers)
+	changes = append(changes, specChanges(&prev.Spec, &rev.Spec)...)
+	changes = append(changes, mapChanges("annotation",
+		userKeys(prev.Annotations, systemAnnotations.Has),
+		userKeys(rev.Annotations, systemAnnotations.Has))...)
+	changes = append(changes, mapChanges("label",
+		userKeys(prev.Labels, isSystemLabel), userKeys(rev.Labels, isSystemLabel))...)
+	if len(changes) == 0 {
+		return ""
+	}
+
+	cause := strings.Join(changes, ", ")
+	if len(cause) > maxChangeCauseLength {
+		cause = cause[:maxChangeCauseLength-3] + "..."
+	}
+	return cause
+}
+
+// containerChanges summarizes the changes to the containers, matched by
+// name. When both templates have a single container, it is compared
+// regardless of its name and the summary omits it.
+func containerChanges(prev, cur []corev1.Container) []string {
+	if len(prev) == 1 && len(cur) == 1 {
+		return singleContainerChanges("", &prev[0], &cur[0])
+	}
+
+	var changes []string
+	prevByName := make(map[string]*corev1.Container, len(prev))
+	for i := range prev {
+		prevByName[prev[i].Name] = &prev[i]
+	}
+	for i := range cur {
+		c := &cur[i]
+		p, ok := prevByName[c.Name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("container %s added", c.Name))
+			continue
+		}
+		delete(prevByName, c.Name)
+		changes = append(changes, singleContainerChanges("container "+c.Name+" ", p, c)...)
+	}
+	for _, name := range sets.StringKeySet(prevByName).List() {
+		changes = append(changes, fmt.Sprintf("container %s removed", name))
+	}
+	return changes
+}
+
+func singleContainerChanges(prefix string, prev, cur *corev1.Container) []string {
+	var changes []string
+	if prev.Image != cur.Image {
+		changes = append(changes, prefix+"image changed")
+	}
+
+	prevEnv := make(map[string]corev1.EnvVar, len(prev.Env))
+	for _, e := range prev.Env {
+		prevEnv[e.Name] = e
+	}
+	curEnv := make(map[string]corev1.EnvVar, len(cur.Env))
+	for _, e := range cur.Env {
+		curEnv[e.Name] = e
+	}
+	changes = append(changes, keyedChanges(prefix+"env", sets.StringKeySet(prevEnv).List(), sets.StringKeySet(curEnv).List(),
+		func(name string) bool { return !equality.Semantic.DeepEqual(prevEnv[name], curEnv[name]) })...)
+
+	for _, f := range []struct {
+		name      string
+		prev, cur interface{}
+	}{
+		{"command", prev.Command, cur.Command},
+		{"args", prev.Args, cur.Args},
+		{"ports", prev.Ports, cur.Ports},
+		{"resources", prev.Resources, cur.Resources},
+		{"readiness probe", prev.ReadinessProbe, cur.ReadinessProbe},
+		{"liveness probe", prev.LivenessProbe, cur.LivenessProbe},
+	} {
+		if !equality.Semantic.DeepEqual(f.prev, f.cur) {
+			changes = append(changes, prefix+f.name+" changed")
+		}
+	}
+
+	if len(changes) == 0 && !equality.Semantic.DeepEqual(prev, cur) {
+		changes = append(changes, prefix+"settings changed")
+	}
+	return changes
+}
+
+// specChanges summarizes the changes to the Revision spec outside of the
+// containers.
+func specChanges(prev, cur *v1.RevisionSpec) []string {
+	var changes []string
+	for _, f := range []struct {
+		name      string
+		prev, cur interface{}
+	}{
+		{"container concurrency", prev.ContainerConcurrency, cur.ContainerConcurrency},
+		{"timeout", prev.TimeoutSeconds, cur.TimeoutSeconds},
+		{"service account", prev.ServiceAccountName, cur.ServiceAccountName},
+		{"volumes", prev.Volumes, cur.Volumes},
+	} {
+		if !equality.Semantic.DeepEqual(f.prev, f.cur) {
+			changes = append(changes, f.name+" changed")
+		}
+	}
+
+	if len(changes) == 0 {
+		p, c := prev.DeepCopy(), cur.DeepCopy()
+		p.Containers, c.Containers = nil, nil
+		if !equality.Semantic.DeepEqual(p, c) {
+			changes = append(changes, "pod spec changed")
+		}
+	}
+	return changes
+}
+
+func mapChanges(kind string, prev, cur map[string]string) []string {
+	return keyedChanges(kind, sets.StringKeySet(prev).List(), sets.StringKeySet(cur).List(),
+		func(k string) bool { return prev[k] != cur[k] })
+}
+
+// keyedChanges summarizes the added, removed and modified keys, in order.
+func keyedChanges(kind string, prev, cur []string, modified func(string) bool) []string {
+	var changes []string
+	prevSet, curSet := sets.NewString(prev...), sets.NewString(cur...)
+	for _, k := range cur {
+		switch {
+		case !prevSet.Has(k):
+			changes = append(changes, fmt.Sprintf("%s %s added", kind, k))
+		case modified(k):
+			changes = append(changes, fmt.Sprintf("%s %s modified", kind, k))
+		}
+	}
+	for _, k := range prev {
+		if !curSet.Has(k) {
+			changes = append(changes, fmt.Sprintf("%s %s removed", kind, k))
+		}
+	}
+	return changes
+}
+
+func userKeys(m map[string]string, system func(string) bool) map[string]string {
+	ret := make(map[string]string, len(m))
+	for k, v := range m {
+		if !system(k) {
+			ret[k] = v
+		}
+	}
+	return ret
+}
+
+// isSystemLabel returns true for the labels stamped on the Revisions by
+// the system.
+func isSystemLabel(key string) bool {
+	return strings.HasPrefix(key, serving.GroupNamePrefix)
+}
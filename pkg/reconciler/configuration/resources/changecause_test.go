@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/ptr"
+	"knative.dev/serving/pkg/apis/serving"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+func TestChangeCause(t *testing.T) {
+	base := func(mods ...func(*v1.Revision)) *v1.Revision {
+		r := &v1.Revision{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					serving.CreatorAnnotation: "someone",
+					"keep":                    "me",
+				},
+				Labels: map[string]string{
+					serving.ConfigurationGenerationLabelKey: "1",
+				},
+			},
+			Spec: v1.RevisionSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "user",
+						Image: "busybox",
+						Env:   []corev1.EnvVar{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}},
+					}},
+				},
+				TimeoutSeconds: ptr.Int64(60),
+			},
+		}
+		for _, mod := range mods {
+			mod(r)
+		}
+		return r
+	}
+
+	tests := []struct {
+		name string
+		rev  *v1.Revision
+		want string
+	}{{
+		name: "no changes",
+		rev:  base(),
+		want: "",
+	}, {
+		name: "system metadata changes are ignored",
+		rev: base(func(r *v1.Revision) {
+			r.Annotations[serving.CreatorAnnotation] = "someone-else"
+			r.Annotations[serving.RoutesAnnotationKey] = "route"
+			r.Labels[serving.ConfigurationGenerationLabelKey] = "2"
+		}),
+		want: "",
+	}, {
+		name: "image and env",
+		rev: base(func(r *v1.Revision) {
+			r.Spec.Containers[0].Image = "busybox:2"
+			r.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "A", Value: "3"}, {Name: "C", Value: "4"}}
+		}),
+		want: "image changed, env A modified, env C added, env B removed",
+	}, {
+		name: "annotations and labels",
+		rev: base(func(r *v1.Revision) {
+			r.Annotations["keep"] = "changed"
+			r.Annotations["new"] = "annotation"
+			r.Labels["app"] = "foo"
+		}),
+		want: "annotation keep modified, annotation new added, label app added",
+	}, {
+		name: "spec fields",
+		rev: base(func(r *v1.Revision) {
+			r.Spec.TimeoutSeconds = ptr.Int64(30)
+			r.Spec.ContainerConcurrency = ptr.Int64(1)
+		}),
+		want: "container concurrency changed, timeout changed",
+	}, {
+		name: "other pod spec fields",
+		rev: base(func(r *v1.Revision) {
+			r.Spec.EnableServiceLinks = ptr.Bool(true)
+		}),
+		want: "pod spec changed",
+	}, {
+		name: "other container fields",
+		rev: base(func(r *v1.Revision) {
+			r.Spec.Containers[0].WorkingDir = "/tmp"
+		}),
+		want: "settings changed",
+	}, {
+		name: "multiple containers",
+		rev: base(func(r *v1.Revision) {
+			r.Spec.Containers = append(r.Spec.Containers, corev1.Container{
+				Name:  "sidecar",
+				Image: "envoy",
+			})
+		}),
+		want: "container sidecar added",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ChangeCause(base(), test.rev); got != test.want {
+				t.Errorf("ChangeCause() = %q, want: %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestChangeCauseTruncated(t *testing.T) {
+	prev := &v1.Revision{}
+	rev := &v1.Revision{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{},
+		},
+	}
+	for i := 0; i < 50; i++ {
+		rev.Annotations[strings.Repeat("a", i+1)] = "x"
+	}
+
+	got := ChangeCause(prev, rev)
+	if len(got) != maxChangeCauseLength || !strings.HasSuffix(got, "...") {
+		t.Errorf("ChangeCause() = %q, want a truncated summary of %d characters", got, maxChangeCauseLength)
+	}
+}
@@ -23,8 +23,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/clock"
 	"knative.dev/pkg/kmeta"
+	cfgmap "knative.dev/serving/pkg/apis/config"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/reconciler/configuration/config"
 )
 
 // MakeRevision creates a revision object from configuration.
@@ -44,6 +46,7 @@ func MakeRevision(ctx context.Context, configuration *v1.Configuration, clock cl
 	// Pending tells the labeler that we have not processed this revision.
 	rev.SetRoutingState(v1.RoutingStatePending, clock)
 
+	propagateMetadata(ctx, rev, configuration)
 	updateRevisionLabels(rev, configuration)
 	updateRevisionAnnotations(rev, configuration)
 
@@ -53,6 +56,21 @@ func MakeRevision(ctx context.Context, configuration *v1.Configuration, clock cl
 	return rev
 }
 
+// propagateMetadata copies the labels and annotations of the Configuration
+// that the metadata propagation policy allows onto the revision. Those
+// set in the revision template take precedence.
+func propagateMetadata(ctx context.Context, rev *v1.Revision, cfg metav1.Object) {
+	propagation := config.FromContextOrDefaults(ctx).MetadataPropagation
+	// Nothing flows from the Configuration to its revisions by default.
+	none := func(string) bool { return false }
+	rev.SetLabels(kmeta.UnionMaps(
+		propagation.FilterLabels(cfgmap.PropagateToRevision, cfg.GetLabels(), none),
+		rev.GetLabels()))
+	rev.SetAnnotations(kmeta.UnionMaps(
+		propagation.FilterAnnotations(cfgmap.PropagateToRevision, cfg.GetAnnotations(), none),
+		rev.GetAnnotations()))
+}
+
 // updateRevisionLabels sets the revisions labels given a Configuration.
 func updateRevisionLabels(rev, config metav1.Object) {
 	labels := rev.GetLabels()
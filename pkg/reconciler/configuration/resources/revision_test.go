@@ -27,8 +27,10 @@ import (
 	"k8s.io/apimachinery/pkg/util/clock"
 
 	"knative.dev/pkg/ptr"
+	cfgmap "knative.dev/serving/pkg/apis/config"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/reconciler/configuration/config"
 )
 
 var fakeCurTime = time.Unix(1e9, 0)
@@ -39,6 +41,7 @@ func TestMakeRevisions(t *testing.T) {
 	tests := []struct {
 		name          string
 		responsiveGC  bool
+		propagation   *cfgmap.MetadataPropagation
 		configuration *v1.Configuration
 		want          *v1.Revision
 	}{{
@@ -324,11 +327,94 @@ func TestMakeRevisions(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		name: "with metadata propagation",
+		propagation: &cfgmap.MetadataPropagation{
+			Labels: map[cfgmap.PropagationTarget]cfgmap.PropagationRule{
+				cfgmap.PropagateToRevision: {Allow: []string{"cost.example.com/*"}},
+			},
+			Annotations: map[cfgmap.PropagationTarget]cfgmap.PropagationRule{
+				cfgmap.PropagateToRevision: {Allow: []string{"prometheus.io/*"}},
+			},
+		},
+		configuration: &v1.Configuration{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:  "propagated",
+				Name:       "config",
+				Generation: 10,
+				Labels: map[string]string{
+					"cost.example.com/center": "1234",
+					"cost.example.com/owner":  "hannibal",
+					"team":                    "a-team",
+					serving.ServiceLabelKey:   "svc",
+				},
+				Annotations: map[string]string{
+					"prometheus.io/scrape": "true",
+					"prometheus.io/port":   "9090",
+				},
+			},
+			Spec: v1.ConfigurationSpec{
+				Template: v1.RevisionTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: map[string]string{
+							// The template wins over the Configuration.
+							"cost.example.com/owner": "murdock",
+						},
+						Annotations: map[string]string{
+							"prometheus.io/port": "8080",
+						},
+					},
+					Spec: v1.RevisionSpec{
+						PodSpec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Image: "busybox",
+							}},
+						},
+					},
+				},
+			},
+		},
+		want: &v1.Revision{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "propagated",
+				Name:      "config-00010",
+				Annotations: map[string]string{
+					"prometheus.io/scrape":                    "true",
+					"prometheus.io/port":                      "8080",
+					serving.RoutingStateModifiedAnnotationKey: v1.RoutingStateModifiedString(clock),
+				},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion:         v1.SchemeGroupVersion.String(),
+					Kind:               "Configuration",
+					Name:               "config",
+					Controller:         ptr.Bool(true),
+					BlockOwnerDeletion: ptr.Bool(true),
+				}},
+				Labels: map[string]string{
+					"cost.example.com/center":               "1234",
+					"cost.example.com/owner":                "murdock",
+					serving.ConfigurationLabelKey:           "config",
+					serving.ConfigurationGenerationLabelKey: "10",
+					serving.RoutingStateLabelKey:            "pending",
+					serving.ServiceLabelKey:                 "svc",
+				},
+			},
+			Spec: v1.RevisionSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Image: "busybox",
+					}},
+				},
+			},
+		},
 	}}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			got := MakeRevision(context.Background(), test.configuration, clock)
+			ctx := config.ToContext(context.Background(), &config.Config{
+				MetadataPropagation: test.propagation,
+			})
+			got := MakeRevision(ctx, test.configuration, clock)
 			if diff := cmp.Diff(test.want, got); diff != "" {
 				t.Error("MakeRevision (-want, +got) =", diff)
 			}
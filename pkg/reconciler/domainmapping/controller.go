@@ -25,6 +25,8 @@ import (
 	certificateinformer "knative.dev/networking/pkg/client/injection/informers/networking/v1alpha1/certificate"
 	domainclaiminformer "knative.dev/networking/pkg/client/injection/informers/networking/v1alpha1/clusterdomainclaim"
 	ingressinformer "knative.dev/networking/pkg/client/injection/informers/networking/v1alpha1/ingress"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	serviceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/service"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
@@ -42,11 +44,14 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 	domainmappingInformer := domainmapping.Get(ctx)
 	ingressInformer := ingressinformer.Get(ctx)
 	domainClaimInformer := domainclaiminformer.Get(ctx)
+	serviceInformer := serviceinformer.Get(ctx)
 
 	r := &Reconciler{
 		certificateLister: certificateInformer.Lister(),
 		ingressLister:     ingressInformer.Lister(),
 		domainClaimLister: domainClaimInformer.Lister(),
+		serviceLister:     serviceInformer.Lister(),
+		kubeclient:        kubeclient.Get(ctx),
 		netclient:         netclient.Get(ctx),
 	}
 
@@ -71,8 +76,11 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 	}
 	certificateInformer.Informer().AddEventHandler(handleControllerOf)
 	ingressInformer.Informer().AddEventHandler(handleControllerOf)
+	serviceInformer.Informer().AddEventHandler(handleControllerOf)
 
 	r.resolver = resolver.NewURIResolver(ctx, impl.EnqueueKey)
 
+	go serveRedirects(ctx, domainmappingInformer.Lister())
+
 	return impl
 }
@@ -30,6 +30,8 @@ import (
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 
 	networkingpkg "knative.dev/networking/pkg"
 	"knative.dev/networking/pkg/apis/networking"
@@ -56,6 +58,8 @@ type Reconciler struct {
 	certificateLister networkinglisters.CertificateLister
 	ingressLister     networkinglisters.IngressLister
 	domainClaimLister networkinglisters.ClusterDomainClaimLister
+	serviceLister     corev1listers.ServiceLister
+	kubeclient        kubernetes.Interface
 	netclient         netclientset.Interface
 	resolver          *resolver.URIResolver
 }
@@ -111,14 +115,29 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, dm *v1alpha1.DomainMappi
 		return err
 	}
 
-	// Resolve the spec.Ref to a URI following the Addressable contract.
-	targetHost, targetBackendSvc, err := r.resolveRef(ctx, dm)
-	if err != nil {
-		return err
+	var targetHost, targetBackendSvc string
+	if dm.Spec.Redirect != nil {
+		// Redirect-only mappings have no ref; their Ingress points at the
+		// redirect backend instead.
+		targetBackendSvc, err = r.reconcileRedirectService(ctx, dm)
+		if err != nil {
+			return err
+		}
+		logger.Debugf("Redirecting %s to %s (svc: %q)", url, dm.Spec.Redirect.URL, targetBackendSvc)
+	} else {
+		if err := r.deleteRedirectService(ctx, dm); err != nil {
+			return err
+		}
+
+		// Resolve the spec.Ref to a URI following the Addressable contract.
+		targetHost, targetBackendSvc, err = r.resolveRef(ctx, dm)
+		if err != nil {
+			return err
+		}
+		logger.Debugf("Mapping %s to ref %s/%s (host: %q, svc: %q)", url, dm.Spec.Ref.Namespace, dm.Spec.Ref.Name, targetHost, targetBackendSvc)
 	}
 
 	// Reconcile the Ingress resource corresponding to the requested Mapping.
-	logger.Debugf("Mapping %s to ref %s/%s (host: %q, svc: %q)", url, dm.Spec.Ref.Namespace, dm.Spec.Ref.Name, targetHost, targetBackendSvc)
 	desired := resources.MakeIngress(dm, targetBackendSvc, targetHost, ingressClass, tls, acmeChallenges...)
 	ingress, err := r.reconcileIngress(ctx, dm, desired)
 	if err != nil {
@@ -296,6 +315,63 @@ func (r *Reconciler) resolveRef(ctx context.Context, dm *v1alpha1.DomainMapping)
 	return resolved.Host, parts[0], nil
 }
 
+// reconcileRedirectService makes sure the Service backing the Ingress of a
+// redirect-only DomainMapping exists, and returns its name.
+func (r *Reconciler) reconcileRedirectService(ctx context.Context, dm *v1alpha1.DomainMapping) (string, error) {
+	recorder := controller.GetEventRecorder(ctx)
+	desired := resources.MakeRedirectService(dm)
+
+	service, err := r.serviceLister.Services(desired.Namespace).Get(desired.Name)
+	if apierrs.IsNotFound(err) {
+		if _, err := r.kubeclient.CoreV1().Services(desired.Namespace).Create(ctx, desired, metav1.CreateOptions{}); err != nil {
+			recorder.Eventf(dm, corev1.EventTypeWarning, "CreationFailed", "Failed to create redirect Service %q: %v", desired.Name, err)
+			return "", fmt.Errorf("failed to create redirect Service: %w", err)
+		}
+		recorder.Eventf(dm, corev1.EventTypeNormal, "Created", "Created redirect Service %q", desired.Name)
+	} else if err != nil {
+		return "", err
+	} else if !metav1.IsControlledBy(service, dm) {
+		dm.Status.MarkReferenceNotResolved(fmt.Sprintf("DomainMapping does not own Service %q", desired.Name))
+		return "", fmt.Errorf("DomainMapping %q does not own Service %q", dm.Name, desired.Name)
+	} else if service.Spec.Type != desired.Spec.Type ||
+		service.Spec.ExternalName != desired.Spec.ExternalName ||
+		!equality.Semantic.DeepEqual(service.Spec.Ports, desired.Spec.Ports) {
+
+		// Don't modify the informers copy
+		existing := service.DeepCopy()
+		existing.Spec.Type = desired.Spec.Type
+		existing.Spec.ExternalName = desired.Spec.ExternalName
+		existing.Spec.Ports = desired.Spec.Ports
+		if _, err := r.kubeclient.CoreV1().Services(existing.Namespace).Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+			return "", fmt.Errorf("failed to update redirect Service: %w", err)
+		}
+	}
+
+	// There is no reference to resolve for a redirect.
+	dm.Status.MarkReferenceResolved()
+	return desired.Name, nil
+}
+
+// deleteRedirectService removes the redirect Service left behind when a
+// redirect-only DomainMapping is turned into a regular one.
+func (r *Reconciler) deleteRedirectService(ctx context.Context, dm *v1alpha1.DomainMapping) error {
+	name := resources.RedirectServiceName(dm)
+	service, err := r.serviceLister.Services(dm.Namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	} else if !metav1.IsControlledBy(service, dm) {
+		return nil
+	}
+
+	err = r.kubeclient.CoreV1().Services(dm.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("failed to delete redirect Service: %w", err)
+	}
+	return nil
+}
+
 func (r *Reconciler) reconcileDomainClaim(ctx context.Context, dm *v1alpha1.DomainMapping) error {
 	dc, err := r.domainClaimLister.Get(dm.Name)
 	if err != nil && !apierrs.IsNotFound(err) {
@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domainmapping
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+
+	network "knative.dev/networking/pkg"
+	"knative.dev/pkg/logging"
+	servingv1alpha1listers "knative.dev/serving/pkg/client/listers/serving/v1alpha1"
+	"knative.dev/serving/pkg/reconciler/domainmapping/resources"
+)
+
+// redirectHandler serves as the redirect backend of redirect-only
+// DomainMappings, since the networking Ingress has no way of expressing a
+// redirect itself.
+type redirectHandler struct {
+	domainMappingLister servingv1alpha1listers.DomainMappingLister
+	logger              *zap.SugaredLogger
+}
+
+func (h *redirectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	namespace := r.Header.Get(resources.RedirectHeaderNamespace)
+	name := r.Header.Get(resources.RedirectHeaderName)
+
+	// The redirect is always looked up rather than taken from the request,
+	// so only redirects configured on a DomainMapping can ever be served.
+	dm, err := h.domainMappingLister.DomainMappings(namespace).Get(name)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			http.Error(w, "Unknown domain mapping", http.StatusNotFound)
+			return
+		}
+		h.logger.Errorw("Error while getting domain mapping", zap.Error(err))
+		http.Error(w, "Error getting domain mapping", http.StatusInternalServerError)
+		return
+	}
+	redirect := dm.Spec.Redirect
+	if redirect == nil || redirect.URL == nil {
+		http.Error(w, "Domain mapping is not a redirect", http.StatusNotFound)
+		return
+	}
+
+	target := *redirect.URL.URL()
+	if redirect.PreservePath == nil || *redirect.PreservePath {
+		target.RawPath = strings.TrimSuffix(target.EscapedPath(), "/") + r.URL.EscapedPath()
+		target.Path = strings.TrimSuffix(target.Path, "/") + r.URL.Path
+		if r.URL.RawQuery != "" {
+			if target.RawQuery != "" {
+				target.RawQuery += "&" + r.URL.RawQuery
+			} else {
+				target.RawQuery = r.URL.RawQuery
+			}
+		}
+	}
+	code := redirect.Code
+	if code == 0 {
+		code = http.StatusPermanentRedirect
+	}
+	http.Redirect(w, r, target.String(), code)
+}
+
+// serveRedirects runs the redirect server until the context is cancelled.
+// Every replica serves redirects, not just the leader.
+func serveRedirects(ctx context.Context, lister servingv1alpha1listers.DomainMappingLister) {
+	logger := logging.FromContext(ctx)
+	server := &http.Server{
+		Addr: ":" + strconv.Itoa(resources.RedirectBackendPort),
+		// The Ingress probes its backends to find out when it is ready.
+		Handler: network.NewProbeHandler(&redirectHandler{
+			domainMappingLister: lister,
+			logger:              logger,
+		}),
+	}
+
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logger.Errorw("Redirect server failed", zap.Error(err))
+	}
+}
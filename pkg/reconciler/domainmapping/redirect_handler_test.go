@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domainmapping
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/ptr"
+	rtesting "knative.dev/pkg/reconciler/testing"
+	"knative.dev/serving/pkg/apis/serving/v1alpha1"
+	fakedomainmappinginformer "knative.dev/serving/pkg/client/injection/informers/serving/v1alpha1/domainmapping/fake"
+	"knative.dev/serving/pkg/reconciler/domainmapping/resources"
+)
+
+func TestRedirectHandler(t *testing.T) {
+	ctx, cancel, _ := rtesting.SetupFakeContextWithCancel(t)
+	defer cancel()
+
+	target, _ := apis.ParseURL("https://new.example.com/base/?from=old")
+	redirect := func(name string, code int, preservePath bool) *v1alpha1.DomainMapping {
+		return &v1alpha1.DomainMapping{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      name,
+			},
+			Spec: v1alpha1.DomainMappingSpec{
+				Redirect: &v1alpha1.DomainMappingRedirect{
+					URL:          target,
+					Code:         code,
+					PreservePath: ptr.Bool(preservePath),
+				},
+			},
+		}
+	}
+	dms := fakedomainmappinginformer.Get(ctx).Informer().GetIndexer()
+	dms.Add(redirect("preserve.example.com", http.StatusPermanentRedirect, true))
+	dms.Add(redirect("drop.example.com", http.StatusFound, false))
+	dms.Add(&v1alpha1.DomainMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "proxy.example.com",
+		},
+	})
+
+	handler := &redirectHandler{
+		domainMappingLister: fakedomainmappinginformer.Get(ctx).Lister(),
+		logger:              logging.FromContext(ctx),
+	}
+
+	tests := []struct {
+		name         string
+		dm           string
+		url          string
+		wantCode     int
+		wantLocation string
+	}{{
+		name:     "not a domain mapping request",
+		url:      "http://example.com/foo",
+		wantCode: http.StatusNotFound,
+	}, {
+		name:         "preserve path and query",
+		dm:           "preserve.example.com",
+		url:          "http://preserve.example.com/foo/bar%2Fbaz?a=b",
+		wantCode:     http.StatusPermanentRedirect,
+		wantLocation: "https://new.example.com/base/foo/bar%2Fbaz?from=old&a=b",
+	}, {
+		name:         "drop path and query",
+		dm:           "drop.example.com",
+		url:          "http://drop.example.com/foo?a=b",
+		wantCode:     http.StatusFound,
+		wantLocation: "https://new.example.com/base/?from=old",
+	}, {
+		name:     "not a redirect",
+		dm:       "proxy.example.com",
+		url:      "http://proxy.example.com/foo",
+		wantCode: http.StatusNotFound,
+	}, {
+		name:     "unknown domain mapping",
+		dm:       "unknown.example.com",
+		url:      "http://unknown.example.com/foo",
+		wantCode: http.StatusNotFound,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, test.url, nil)
+			if test.dm != "" {
+				req.Header.Set(resources.RedirectHeaderName, test.dm)
+				req.Header.Set(resources.RedirectHeaderNamespace, "default")
+			}
+			handler.ServeHTTP(resp, req)
+
+			if got, want := resp.Code, test.wantCode; got != want {
+				t.Errorf("StatusCode = %d, want %d, body: %s", got, want, resp.Body.String())
+			}
+			if got, want := resp.Header().Get("Location"), test.wantLocation; got != want {
+				t.Errorf("Location = %q, want %q", got, want)
+			}
+		})
+	}
+}
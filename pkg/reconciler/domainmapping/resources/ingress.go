@@ -25,6 +25,7 @@ import (
 	"knative.dev/networking/pkg/apis/networking"
 	netv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
 	"knative.dev/pkg/kmeta"
+	"knative.dev/serving/pkg/activator"
 	"knative.dev/serving/pkg/apis/serving"
 	servingv1alpha1 "knative.dev/serving/pkg/apis/serving/v1alpha1"
 	routeresources "knative.dev/serving/pkg/reconciler/route/resources"
@@ -34,8 +35,20 @@ import (
 // always created in the same namespace as the DomainMapping, and the ingress
 // backend is always in the same namespace also (as this is required by
 // KIngress).  The created ingress will contain a RewriteHost rule to cause the
-// given hostName to be used as the host.
+// given hostName to be used as the host. Requests to a redirect-only
+// DomainMapping are tagged with headers identifying it, so the redirect server
+// behind its backend can serve the redirect.
 func MakeIngress(dm *servingv1alpha1.DomainMapping, backendServiceName, hostName, ingressClass string, tls []netv1alpha1.IngressTLS, acmeChallenges ...netv1alpha1.HTTP01Challenge) *netv1alpha1.Ingress {
+	headers := map[string]string{
+		network.OriginalHostHeader: dm.Name,
+		// The requests don't come from a Knative Service nor from an activator.
+		activator.SourceServiceHeaderName: activator.NoSourceService,
+		activator.OverflowHeaderName:      activator.NoOverflow,
+	}
+	if dm.Spec.Redirect != nil {
+		headers[RedirectHeaderName] = dm.Name
+		headers[RedirectHeaderNamespace] = dm.Namespace
+	}
 	return &netv1alpha1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      kmeta.ChildName(dm.GetName(), ""),
@@ -59,10 +72,8 @@ func MakeIngress(dm *servingv1alpha1.DomainMapping, backendServiceName, hostName
 					Paths: append([]netv1alpha1.HTTPIngressPath{{
 						RewriteHost: hostName,
 						Splits: []netv1alpha1.IngressBackendSplit{{
-							Percent: 100,
-							AppendHeaders: map[string]string{
-								network.OriginalHostHeader: dm.Name,
-							},
+							Percent:       100,
+							AppendHeaders: headers,
 							IngressBackend: netv1alpha1.IngressBackend{
 								ServiceNamespace: dm.Namespace,
 								ServiceName:      backendServiceName,
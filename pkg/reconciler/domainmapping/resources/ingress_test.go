@@ -28,6 +28,7 @@ import (
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/kmeta"
+	"knative.dev/serving/pkg/activator"
 	"knative.dev/serving/pkg/apis/serving"
 	"knative.dev/serving/pkg/apis/serving/v1alpha1"
 )
@@ -76,7 +77,9 @@ func TestMakeIngress(t *testing.T) {
 							Splits: []netv1alpha1.IngressBackendSplit{{
 								Percent: 100,
 								AppendHeaders: map[string]string{
-									network.OriginalHostHeader: "mapping.com",
+									network.OriginalHostHeader:        "mapping.com",
+									activator.SourceServiceHeaderName: activator.NoSourceService,
+									activator.OverflowHeaderName:      activator.NoOverflow,
 								},
 								IngressBackend: netv1alpha1.IngressBackend{
 									ServiceName:      "the-target-svc",
@@ -130,7 +133,9 @@ func TestMakeIngress(t *testing.T) {
 							Splits: []netv1alpha1.IngressBackendSplit{{
 								Percent: 100,
 								AppendHeaders: map[string]string{
-									network.OriginalHostHeader: "mapping.com",
+									network.OriginalHostHeader:        "mapping.com",
+									activator.SourceServiceHeaderName: activator.NoSourceService,
+									activator.OverflowHeaderName:      activator.NoOverflow,
 								},
 								IngressBackend: netv1alpha1.IngressBackend{
 									ServiceName:      "the-target-svc",
@@ -194,7 +199,9 @@ func TestMakeIngress(t *testing.T) {
 							Splits: []netv1alpha1.IngressBackendSplit{{
 								Percent: 100,
 								AppendHeaders: map[string]string{
-									network.OriginalHostHeader: "mapping.com",
+									network.OriginalHostHeader:        "mapping.com",
+									activator.SourceServiceHeaderName: activator.NoSourceService,
+									activator.OverflowHeaderName:      activator.NoOverflow,
 								},
 								IngressBackend: netv1alpha1.IngressBackend{
 									ServiceName:      "the-target-svc",
@@ -217,6 +224,55 @@ func TestMakeIngress(t *testing.T) {
 				}},
 			},
 		},
+	}, {
+		name: "redirect",
+		dm: v1alpha1.DomainMapping{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "mapping.com",
+				Namespace: "the-namespace",
+			},
+			Spec: v1alpha1.DomainMappingSpec{
+				Redirect: &v1alpha1.DomainMappingRedirect{
+					URL:  &apis.URL{Scheme: "https", Host: "new.mapping.com"},
+					Code: 308,
+				},
+			},
+		},
+		want: netv1alpha1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "mapping.com",
+				Namespace: "the-namespace",
+				Annotations: map[string]string{
+					"networking.knative.dev/ingress.class": "the-ingress-class",
+				},
+			},
+			Spec: netv1alpha1.IngressSpec{
+				Rules: []netv1alpha1.IngressRule{{
+					Hosts:      []string{"mapping.com"},
+					Visibility: netv1alpha1.IngressVisibilityExternalIP,
+					HTTP: &netv1alpha1.HTTPIngressRuleValue{
+						Paths: []netv1alpha1.HTTPIngressPath{{
+							RewriteHost: "the-rewrite-host",
+							Splits: []netv1alpha1.IngressBackendSplit{{
+								Percent: 100,
+								AppendHeaders: map[string]string{
+									network.OriginalHostHeader:        "mapping.com",
+									activator.SourceServiceHeaderName: activator.NoSourceService,
+									activator.OverflowHeaderName:      activator.NoOverflow,
+									RedirectHeaderName:                "mapping.com",
+									RedirectHeaderNamespace:           "the-namespace",
+								},
+								IngressBackend: netv1alpha1.IngressBackend{
+									ServiceName:      "the-target-svc",
+									ServiceNamespace: "the-namespace",
+									ServicePort:      intstr.FromInt(80),
+								},
+							}},
+						}},
+					},
+				}},
+			},
+		},
 	}} {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.want.Labels = kmeta.UnionMaps(tc.dm.Labels, map[string]string{
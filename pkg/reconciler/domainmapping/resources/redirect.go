@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"crypto/md5"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"knative.dev/networking/pkg/apis/networking"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/network"
+	"knative.dev/pkg/system"
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1alpha1 "knative.dev/serving/pkg/apis/serving/v1alpha1"
+)
+
+const (
+	// RedirectHeaderName is the header key for the name of the redirect-only
+	// DomainMapping a request was sent to.
+	RedirectHeaderName = "Knative-Serving-Domain-Mapping"
+	// RedirectHeaderNamespace is the header key for the namespace of the
+	// redirect-only DomainMapping a request was sent to.
+	RedirectHeaderNamespace = "Knative-Serving-Domain-Mapping-Namespace"
+
+	// RedirectBackendServiceName is the name of the Kubernetes Service in the
+	// system namespace in front of the DomainMapping controller's redirect
+	// server.
+	RedirectBackendServiceName = "domainmapping-redirect"
+	// RedirectBackendPort is the port the redirect server listens on.
+	RedirectBackendPort = 8080
+)
+
+// RedirectServiceName returns the name of the Kubernetes Service backing the
+// given redirect-only DomainMapping. DomainMapping names are domains, which
+// are not valid Service names, so the name is derived from a hash instead.
+func RedirectServiceName(dm *servingv1alpha1.DomainMapping) string {
+	return fmt.Sprintf("dm-redirect-%x", md5.Sum([]byte(dm.Name)))
+}
+
+// MakeRedirectService creates the ExternalName Service through which the
+// Ingress of a redirect-only DomainMapping reaches the redirect server.
+// KIngress requires its backends to live in the namespace of the Ingress, so
+// the redirect server can't be referenced directly.
+func MakeRedirectService(dm *servingv1alpha1.DomainMapping) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      RedirectServiceName(dm),
+			Namespace: dm.Namespace,
+			Labels: map[string]string{
+				serving.DomainMappingLabelKey: dm.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(dm)},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:            corev1.ServiceTypeExternalName,
+			ExternalName:    network.GetServiceHostname(RedirectBackendServiceName, system.Namespace()),
+			SessionAffinity: corev1.ServiceAffinityNone,
+			Ports: []corev1.ServicePort{{
+				Name:       networking.ServicePortNameHTTP1,
+				Port:       networking.ServiceHTTPPort,
+				TargetPort: intstr.FromInt(networking.ServiceHTTPPort),
+			}},
+		},
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"knative.dev/pkg/kmeta"
+	_ "knative.dev/pkg/system/testing"
+	"knative.dev/serving/pkg/apis/serving"
+	"knative.dev/serving/pkg/apis/serving/v1alpha1"
+)
+
+func TestMakeRedirectService(t *testing.T) {
+	dm := &v1alpha1.DomainMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "old.mapping.com",
+			Namespace: "the-namespace",
+		},
+	}
+
+	want := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dm-redirect-578115770d33a7026431730d80c5b48e",
+			Namespace: "the-namespace",
+			Labels: map[string]string{
+				serving.DomainMappingLabelKey: "old.mapping.com",
+			},
+			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(dm)},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:            corev1.ServiceTypeExternalName,
+			ExternalName:    "domainmapping-redirect.knative-testing.svc.cluster.local",
+			SessionAffinity: corev1.ServiceAffinityNone,
+			Ports: []corev1.ServicePort{{
+				Name:       "http",
+				Port:       80,
+				TargetPort: intstr.FromInt(80),
+			}},
+		},
+	}
+
+	got := MakeRedirectService(dm)
+	if !cmp.Equal(got, want) {
+		t.Error("MakeRedirectService (-want, +got):", cmp.Diff(want, got))
+	}
+}
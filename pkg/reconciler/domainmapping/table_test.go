@@ -34,11 +34,13 @@ import (
 	networkingclient "knative.dev/networking/pkg/client/injection/client/fake"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+	kubeclient "knative.dev/pkg/client/injection/kube/client/fake"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/logging"
 	pkgnetwork "knative.dev/pkg/network"
+	"knative.dev/pkg/ptr"
 	pkgreconciler "knative.dev/pkg/reconciler"
 	"knative.dev/pkg/resolver"
 	"knative.dev/serving/pkg/apis/serving"
@@ -678,6 +680,8 @@ func TestReconcile(t *testing.T) {
 			netclient:         networkingclient.Get(ctx),
 			resolver:          resolver.NewURIResolver(ctx, func(types.NamespacedName) {}),
 			domainClaimLister: listers.GetDomainClaimLister(),
+			serviceLister:     listers.GetK8sServiceLister(),
+			kubeclient:        kubeclient.Get(ctx),
 		}
 
 		return domainmappingreconciler.NewReconciler(ctx, logging.FromContext(ctx),
@@ -806,6 +810,8 @@ func TestReconcileAutocreateClaimsDisabled(t *testing.T) {
 			netclient:         networkingclient.Get(ctx),
 			resolver:          resolver.NewURIResolver(ctx, func(types.NamespacedName) {}),
 			domainClaimLister: listers.GetDomainClaimLister(),
+			serviceLister:     listers.GetK8sServiceLister(),
+			kubeclient:        kubeclient.Get(ctx),
 		}
 
 		return domainmappingreconciler.NewReconciler(ctx, logging.FromContext(ctx),
@@ -1112,6 +1118,8 @@ func TestReconcileTLSEnabled(t *testing.T) {
 			certificateLister: listers.GetCertificateLister(),
 			ingressLister:     listers.GetIngressLister(),
 			domainClaimLister: listers.GetDomainClaimLister(),
+			serviceLister:     listers.GetK8sServiceLister(),
+			kubeclient:        kubeclient.Get(ctx),
 			netclient:         networkingclient.Get(ctx),
 			resolver:          resolver.NewURIResolver(ctx, func(types.NamespacedName) {}),
 		}
@@ -1176,6 +1184,8 @@ func TestReconcileTLSEnabledButDowngraded(t *testing.T) {
 		r := &Reconciler{
 			certificateLister: listers.GetCertificateLister(),
 			domainClaimLister: listers.GetDomainClaimLister(),
+			serviceLister:     listers.GetK8sServiceLister(),
+			kubeclient:        kubeclient.Get(ctx),
 			ingressLister:     listers.GetIngressLister(),
 			netclient:         networkingclient.Get(ctx),
 			resolver:          resolver.NewURIResolver(ctx, func(types.NamespacedName) {}),
@@ -1197,6 +1207,156 @@ func TestReconcileTLSEnabledButDowngraded(t *testing.T) {
 	}))
 }
 
+func TestReconcileRedirect(t *testing.T) {
+	redirectService := func(dm *v1alpha1.DomainMapping) *corev1.Service {
+		svc := resources.MakeRedirectService(dm)
+		svc.OwnerReferences = nil
+		return svc
+	}
+
+	table := TableTest{{
+		Name: "first reconcile",
+		Key:  "default/old.example.com",
+		Objects: []runtime.Object{
+			domainMapping("default", "old.example.com", withRedirect("https://new.example.com")),
+			resources.MakeDomainClaim(domainMapping("default", "old.example.com")),
+		},
+		WantCreates: []runtime.Object{
+			resources.MakeCertificate(domainMapping("default", "old.example.com",
+				withRedirect("https://new.example.com"),
+				withURL("http", "old.example.com"),
+				withAddress("http", "old.example.com"),
+			), "the-cert-class"),
+			redirectIngress(domainMapping("default", "old.example.com", withRedirect("https://new.example.com"))),
+			resources.MakeRedirectService(domainMapping("default", "old.example.com", withRedirect("https://new.example.com"))),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: domainMapping("default", "old.example.com",
+				withRedirect("https://new.example.com"),
+				withURL("https", "old.example.com"),
+				withAddress("https", "old.example.com"),
+				withCertificateNotReady,
+				withInitDomainMappingConditions,
+				withIngressNotConfigured,
+				withDomainClaimed,
+				withReferenceResolved,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchAddFinalizerAction("default", "old.example.com"),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", "old.example.com"),
+			Eventf(corev1.EventTypeNormal, "Created", "Created Certificate %s/%s", "default", "old.example.com"),
+			Eventf(corev1.EventTypeNormal, "Created", "Created redirect Service %q",
+				resources.RedirectServiceName(domainMapping("default", "old.example.com"))),
+			Eventf(corev1.EventTypeNormal, "Created", "Created Ingress %q", "old.example.com"),
+		},
+	}, {
+		Name: "redirect service not owned",
+		Key:  "default/old.example.com",
+		Objects: []runtime.Object{
+			domainMapping("default", "old.example.com", withRedirect("https://new.example.com"), withFinalizer),
+			resources.MakeDomainClaim(domainMapping("default", "old.example.com")),
+			redirectService(domainMapping("default", "old.example.com")),
+		},
+		WantCreates: []runtime.Object{
+			resources.MakeCertificate(domainMapping("default", "old.example.com",
+				withRedirect("https://new.example.com"),
+				withURL("http", "old.example.com"),
+				withAddress("http", "old.example.com"),
+			), "the-cert-class"),
+		},
+		WantErr: true,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: domainMapping("default", "old.example.com",
+				withRedirect("https://new.example.com"),
+				withFinalizer,
+				withURL("https", "old.example.com"),
+				withAddress("https", "old.example.com"),
+				withCertificateNotReady,
+				withInitDomainMappingConditions,
+				withDomainClaimed,
+				withReferenceNotResolved(fmt.Sprintf("DomainMapping does not own Service %q",
+					resources.RedirectServiceName(domainMapping("default", "old.example.com")))),
+			),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Created", "Created Certificate %s/%s", "default", "old.example.com"),
+			Eventf(corev1.EventTypeWarning, "InternalError", "DomainMapping %q does not own Service %q",
+				"old.example.com", resources.RedirectServiceName(domainMapping("default", "old.example.com"))),
+		},
+	}, {
+		Name: "redirect replaced by ref",
+		Key:  "default/old.example.com",
+		Objects: []runtime.Object{
+			ksvc("default", "target", "target.default.svc.cluster.local", ""),
+			domainMapping("default", "old.example.com", withRef("default", "target"), withFinalizer),
+			resources.MakeDomainClaim(domainMapping("default", "old.example.com")),
+			resources.MakeRedirectService(domainMapping("default", "old.example.com")),
+		},
+		WantCreates: []runtime.Object{
+			resources.MakeCertificate(domainMapping("default", "old.example.com",
+				withRef("default", "target"),
+				withURL("http", "old.example.com"),
+				withAddress("http", "old.example.com"),
+			), "the-cert-class"),
+			ingress(domainMapping("default", "old.example.com", withRef("default", "target")), "the-ingress-class"),
+		},
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: "default",
+				Verb:      "delete",
+				Resource:  corev1.SchemeGroupVersion.WithResource("services"),
+			},
+			Name: resources.RedirectServiceName(domainMapping("default", "old.example.com")),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: domainMapping("default", "old.example.com",
+				withRef("default", "target"),
+				withFinalizer,
+				withURL("https", "old.example.com"),
+				withAddress("https", "old.example.com"),
+				withCertificateNotReady,
+				withInitDomainMappingConditions,
+				withIngressNotConfigured,
+				withDomainClaimed,
+				withReferenceResolved,
+			),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Created", "Created Certificate %s/%s", "default", "old.example.com"),
+			Eventf(corev1.EventTypeNormal, "Created", "Created Ingress %q", "old.example.com"),
+		},
+	}}
+
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
+		ctx = addressable.WithDuck(ctx)
+		r := &Reconciler{
+			certificateLister: listers.GetCertificateLister(),
+			ingressLister:     listers.GetIngressLister(),
+			domainClaimLister: listers.GetDomainClaimLister(),
+			serviceLister:     listers.GetK8sServiceLister(),
+			kubeclient:        kubeclient.Get(ctx),
+			netclient:         networkingclient.Get(ctx),
+			resolver:          resolver.NewURIResolver(ctx, func(types.NamespacedName) {}),
+		}
+
+		return domainmappingreconciler.NewReconciler(ctx, logging.FromContext(ctx),
+			servingclient.Get(ctx), listers.GetDomainMappingLister(), controller.GetEventRecorder(ctx), r,
+			controller.Options{ConfigStore: &testConfigStore{
+				config: &config.Config{
+					Network: &network.Config{
+						DefaultIngressClass:     "the-ingress-class",
+						DefaultCertificateClass: "the-cert-class",
+						AutoTLS:                 true,
+					},
+				},
+			}},
+		)
+	}))
+}
+
 type domainMappingOption func(dm *v1alpha1.DomainMapping)
 
 func domainMapping(namespace, name string, opt ...domainMappingOption) *v1alpha1.DomainMapping {
@@ -1239,6 +1399,17 @@ func withRef(namespace, name string, opt ...refOption) domainMappingOption {
 	}
 }
 
+func withRedirect(url string) domainMappingOption {
+	return func(dm *v1alpha1.DomainMapping) {
+		u, _ := apis.ParseURL(url)
+		dm.Spec.Redirect = &v1alpha1.DomainMappingRedirect{
+			URL:          u,
+			Code:         308,
+			PreservePath: ptr.Bool(true),
+		}
+	}
+}
+
 func withAPIVersionKind(apiVersion, kind string) refOption {
 	return func(ref *duckv1.KReference) {
 		ref.APIVersion = apiVersion
@@ -1350,6 +1521,14 @@ func ingressWithChallenges(dm *v1alpha1.DomainMapping, ingressClass string, chal
 	return ing
 }
 
+func redirectIngress(dm *v1alpha1.DomainMapping, opt ...IngressOption) *netv1alpha1.Ingress {
+	ing := resources.MakeIngress(dm, resources.RedirectServiceName(dm), "", "the-ingress-class", nil /* tls */)
+	for _, o := range opt {
+		o(ing)
+	}
+	return ing
+}
+
 func withIngressReady(ing *netv1alpha1.Ingress) {
 	status := netv1alpha1.IngressStatus{}
 	status.InitializeConditions()
@@ -19,9 +19,13 @@ package gc
 import (
 	"context"
 
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
+	namespaceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
+	namespacereconciler "knative.dev/pkg/client/injection/kube/reconciler/core/v1/namespace"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/logging"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	servingclient "knative.dev/serving/pkg/client/injection/client"
@@ -32,7 +36,10 @@ import (
 	configns "knative.dev/serving/pkg/reconciler/gc/config"
 )
 
-const controllerAgentName = "revision-gc-controller"
+const (
+	controllerAgentName          = "revision-gc-controller"
+	namespaceControllerAgentName = "namespace-revision-gc-controller"
+)
 
 // NewController creates a new Garbage Collection controller
 func NewController(
@@ -82,3 +89,58 @@ func NewController(
 		}
 	})
 }
+
+// NewNamespaceController creates a new Garbage Collection controller capping
+// the revisions of each namespace.
+func NewNamespaceController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+) *controller.Impl {
+	logger := logging.FromContext(ctx)
+	configurationInformer := configurationinformer.Get(ctx)
+	revisionInformer := revisioninformer.Get(ctx)
+	namespaceInformer := namespaceinformer.Get(ctx)
+
+	c := &namespaceReconciler{
+		client:              servingclient.Get(ctx),
+		configurationLister: configurationInformer.Lister(),
+		revisionLister:      revisionInformer.Lister(),
+	}
+	return namespacereconciler.NewImpl(ctx, c, func(impl *controller.Impl) controller.Options {
+		logger.Info("Setting up event handlers")
+
+		namespaceInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+
+		// Changes to the revisions and configurations of a namespace are
+		// collected in a single pass over the namespace.
+		enqueueNamespace := controller.HandleAll(func(obj interface{}) {
+			if object, err := kmeta.DeletionHandlingAccessor(obj); err == nil {
+				impl.EnqueueKey(types.NamespacedName{Name: object.GetNamespace()})
+			}
+		})
+		configurationInformer.Informer().AddEventHandler(enqueueNamespace)
+		revisionInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+			FilterFunc: controller.FilterControllerGK(v1.Kind("Configuration")),
+			Handler:    enqueueNamespace,
+		})
+
+		logger.Info("Setting up ConfigMap receivers with resync func")
+		configsToResync := []interface{}{
+			&gcconfig.Config{},
+		}
+		resync := configmap.TypeFilter(configsToResync...)(func(string, interface{}) {
+			impl.GlobalResync(namespaceInformer.Informer())
+		})
+
+		logger.Info("Setting up ConfigMap receivers")
+		configStore := configns.NewStore(logging.WithLogger(ctx, logger.Named("config-store")), resync)
+		configStore.WatchConfigs(cmw)
+
+		return controller.Options{
+			ConfigStore: configStore,
+			AgentName:   namespaceControllerAgentName,
+			// The GC reconciler shouldn't mutate the namespace's status.
+			SkipStatusUpdates: true,
+		}
+	})
+}
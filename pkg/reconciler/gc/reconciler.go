@@ -19,6 +19,8 @@ package gc
 import (
 	"context"
 
+	corev1 "k8s.io/api/core/v1"
+	namespacereconciler "knative.dev/pkg/client/injection/kube/reconciler/core/v1/namespace"
 	pkgreconciler "knative.dev/pkg/reconciler"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	clientset "knative.dev/serving/pkg/client/clientset/versioned"
@@ -42,3 +44,21 @@ var _ configreconciler.Interface = (*reconciler)(nil)
 func (c *reconciler) ReconcileKind(ctx context.Context, config *v1.Configuration) pkgreconciler.Event {
 	return gcv2.Collect(ctx, c.client, c.revisionLister, config)
 }
+
+// namespaceReconciler implements controller.Reconciler for the revisions
+// garbage collected across a namespace.
+type namespaceReconciler struct {
+	client clientset.Interface
+
+	// listers index properties about resources
+	configurationLister listers.ConfigurationLister
+	revisionLister      listers.RevisionLister
+}
+
+// Check that our reconciler implements namespacereconciler.Interface
+var _ namespacereconciler.Interface = (*namespaceReconciler)(nil)
+
+// ReconcileKind implements Interface.ReconcileKind.
+func (c *namespaceReconciler) ReconcileKind(ctx context.Context, ns *corev1.Namespace) pkgreconciler.Event {
+	return gcv2.CollectNamespace(ctx, c.client, c.configurationLister, c.revisionLister, ns)
+}
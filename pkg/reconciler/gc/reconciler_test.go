@@ -27,11 +27,14 @@ import (
 	"k8s.io/apimachinery/pkg/util/clock"
 	clientgotesting "k8s.io/client-go/testing"
 
+	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
+	namespacereconciler "knative.dev/pkg/client/injection/kube/reconciler/core/v1/namespace"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/ptr"
 	pkgrec "knative.dev/pkg/reconciler"
+	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	servingclient "knative.dev/serving/pkg/client/injection/client/fake"
 	configreconciler "knative.dev/serving/pkg/client/injection/reconciler/serving/v1/configuration"
@@ -67,10 +70,11 @@ func TestGCReconcile(t *testing.T) {
 		ConfigStore: &testConfigStore{
 			config: &config.Config{
 				RevisionGC: &gc.Config{
-					RetainSinceCreateTime:     5 * time.Minute,
-					RetainSinceLastActiveTime: 5 * time.Minute,
-					MinNonActiveRevisions:     1,
-					MaxNonActiveRevisions:     gc.Disabled,
+					RetainSinceCreateTime:             5 * time.Minute,
+					RetainSinceLastActiveTime:         5 * time.Minute,
+					MinNonActiveRevisions:             1,
+					MaxNonActiveRevisions:             gc.Disabled,
+					MaxNonActiveRevisionsPerNamespace: gc.Disabled,
 				},
 			},
 		}}
@@ -118,6 +122,118 @@ func TestGCReconcile(t *testing.T) {
 	}))
 }
 
+func TestGCReconcileNamespaceMax(t *testing.T) {
+	now := time.Now()
+
+	old := now.Add(-11 * time.Minute)
+	older := now.Add(-12 * time.Minute)
+	oldest := now.Add(-13 * time.Minute)
+
+	controllerOpts := controller.Options{
+		ConfigStore: &testConfigStore{
+			config: &config.Config{
+				RevisionGC: &gc.Config{
+					RetainSinceCreateTime:             gc.Disabled,
+					RetainSinceLastActiveTime:         gc.Disabled,
+					MinNonActiveRevisions:             1,
+					MaxNonActiveRevisions:             1,
+					MaxNonActiveRevisionsPerNamespace: 5,
+				},
+			},
+		}}
+
+	fc := clock.NewFakeClock(time.Now())
+	ns := func(max string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo",
+				Annotations: map[string]string{
+					serving.MaxNonActiveRevisionsAnnotationKey: max,
+				},
+			},
+		}
+	}
+	objects := func(ns *corev1.Namespace, extra ...runtime.Object) []runtime.Object {
+		return append([]runtime.Object{
+			ns,
+			cfg("first", "foo", 2,
+				WithLatestCreated("first-2"),
+				WithLatestReady("first-2"),
+				WithConfigObservedGen),
+			cfg("second", "foo", 3,
+				WithLatestCreated("second-3"),
+				WithLatestReady("second-3"),
+				WithConfigObservedGen),
+			// Non-active, oldest of the namespace.
+			rev("first", "foo", 1, MarkRevisionReady,
+				WithRevName("first-1"),
+				WithRoutingState(v1.RoutingStateReserve, fc),
+				WithRoutingStateModified(older)),
+			// Latest ready, never deleted.
+			rev("first", "foo", 2, MarkRevisionReady,
+				WithRevName("first-2"),
+				WithRoutingState(v1.RoutingStateReserve, fc),
+				WithRoutingStateModified(oldest)),
+			// Preserved, never deleted.
+			rev("second", "foo", 1, MarkRevisionReady,
+				WithRevName("second-1"),
+				WithRevisionPreserveAnnotation(),
+				WithRoutingState(v1.RoutingStateReserve, fc),
+				WithRoutingStateModified(oldest)),
+			// Non-active.
+			rev("second", "foo", 2, MarkRevisionReady,
+				WithRevName("second-2"),
+				WithRoutingState(v1.RoutingStateReserve, fc),
+				WithRoutingStateModified(old)),
+			rev("second", "foo", 3, MarkRevisionReady,
+				WithRevName("second-3"),
+				WithRoutingState(v1.RoutingStateActive, fc),
+				WithRoutingStateModified(old)),
+		}, extra...)
+	}
+
+	table := TableTest{{
+		Name:    "under the config-gc maximum",
+		Objects: objects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}),
+		Key:     "foo",
+	}, {
+		Name:    "namespace maximum deletes the oldest non-active revisions",
+		Objects: objects(ns("1")),
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: "foo",
+				Verb:      "delete",
+				Resource:  v1.SchemeGroupVersion.WithResource("revisions"),
+			},
+			Name: "first-1",
+		}},
+		Key: "foo",
+	}, {
+		Name: "revisions collected for their configuration are not counted",
+		Objects: objects(ns("2"),
+			rev("second", "foo", 4, MarkRevisionReady,
+				WithRevName("second-4"),
+				WithRoutingState(v1.RoutingStateReserve, fc),
+				WithRoutingStateModified(oldest))),
+		Key: "foo",
+	}, {
+		Name:    "namespace maximum disabled",
+		Objects: objects(ns("disabled")),
+		Key:     "foo",
+	}}
+
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
+		r := &namespaceReconciler{
+			client:              servingclient.Get(ctx),
+			configurationLister: listers.GetConfigurationLister(),
+			revisionLister:      listers.GetRevisionLister(),
+		}
+		return namespacereconciler.NewReconciler(ctx, logging.FromContext(ctx),
+			fakekubeclient.Get(ctx), listers.GetNamespaceLister(),
+			controller.GetEventRecorder(ctx), r, controllerOpts)
+	}))
+}
+
 func cfg(name, namespace string, generation int64, co ...ConfigOption) *v1.Configuration {
 	c := &v1.Configuration{
 		ObjectMeta: metav1.ObjectMeta{
@@ -23,8 +23,10 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 	"knative.dev/serving/pkg/apis/serving"
@@ -44,16 +46,28 @@ func Collect(
 	cfg := configns.FromContext(ctx).RevisionGC
 	logger := logging.FromContext(ctx)
 
-	min, max := int(cfg.MinNonActiveRevisions), int(cfg.MaxNonActiveRevisions)
-	if max == gc.Disabled && cfg.RetainSinceCreateTime == gc.Disabled && cfg.RetainSinceLastActiveTime == gc.Disabled {
-		return nil // all deletion settings are disabled
-	}
-
 	selector := labels.SelectorFromSet(labels.Set{serving.ConfigurationLabelKey: config.Name})
 	revs, err := revisionLister.Revisions(config.Namespace).List(selector)
 	if err != nil {
 		return err
 	}
+
+	for _, rev := range collectable(cfg, revs, config, logger) {
+		logger.Info("Deleting non-active revision: ", rev.ObjectMeta.Name)
+		if err := client.ServingV1().Revisions(rev.Namespace).Delete(ctx, rev.Name, metav1.DeleteOptions{}); err != nil {
+			logger.Errorw("Failed to GC revision: "+rev.Name, zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// collectable returns the revisions of the configuration that are stale, or
+// past the configuration maximum. It reorders `revs`.
+func collectable(cfg *gc.Config, revs []*v1.Revision, config *v1.Configuration, logger *zap.SugaredLogger) []*v1.Revision {
+	min, max := int(cfg.MinNonActiveRevisions), int(cfg.MaxNonActiveRevisions)
+	if max == gc.Disabled && cfg.RetainSinceCreateTime == gc.Disabled && cfg.RetainSinceLastActiveTime == gc.Disabled {
+		return nil // all deletion settings are disabled
+	}
 	if len(revs) <= min {
 		return nil // not enough total revs
 	}
@@ -71,7 +85,7 @@ func Collect(
 		return a.Before(b)
 	})
 
-	// Delete stale revisions while more than min remain, swap nonstale revisions to the end.
+	// Collect stale revisions while more than min remain, swap nonstale revisions to the end.
 	swap := len(revs)
 
 	// If we need `min` to remain, this is the max index i can reach.
@@ -80,28 +94,110 @@ func Collect(
 		rev := revs[i]
 		switch {
 		case i >= maxIdx:
-			return nil
+			return revs[:i]
 		case isRevisionStale(cfg, rev, logger):
 			i++
-			logger.Info("Deleting stale revision: ", rev.ObjectMeta.Name)
-			if err := client.ServingV1().Revisions(rev.Namespace).Delete(ctx, rev.Name, metav1.DeleteOptions{}); err != nil {
-				logger.Errorw("Failed to GC revision: "+rev.Name, zap.Error(err))
-			}
 		default:
 			swap--
 			revs[i], revs[swap] = revs[swap], revs[i]
 		}
 	}
+	stale := revs[:swap]
 	revs = revs[swap:] // Reslice to include the nonstale revisions, which are now in reverse order
 
 	if max == gc.Disabled || len(revs) <= max {
-		return nil
+		return stale
 	}
 
-	// Delete extra revisions past max.
+	// Collect extra revisions past max.
 	logger.Infof("Maximum number of revisions (%d) reached, deleting oldest non-active (%d) revisions",
 		max, len(revs)-max)
-	for _, rev := range revs[max:] {
+	return append(stale, revs[max:]...)
+}
+
+// CollectNamespace deletes the oldest non-active revisions of the namespace,
+// across all of its configurations, past the namespace maximum. The revisions
+// Collect deletes for their configuration are not counted, since their
+// deletion may not have reached the listers yet.
+func CollectNamespace(
+	ctx context.Context,
+	client clientset.Interface,
+	configurationLister listers.ConfigurationLister,
+	revisionLister listers.RevisionLister,
+	ns *corev1.Namespace) pkgreconciler.Event {
+	cfg := configns.FromContext(ctx).RevisionGC
+	logger := logging.FromContext(ctx)
+
+	max, err := cfg.NamespaceMaxNonActiveRevisions(ns.Annotations)
+	if err != nil {
+		logger.Warnw("Ignoring invalid namespace revision maximum", zap.Error(err))
+	}
+	if max == gc.Disabled {
+		return nil
+	}
+
+	selector, err := labels.Parse(serving.ConfigurationLabelKey)
+	if err != nil {
+		return err
+	}
+	revs, err := revisionLister.Revisions(ns.Name).List(selector)
+	if err != nil {
+		return err
+	}
+	if int64(len(revs)) <= max {
+		return nil // not enough total revs
+	}
+
+	configs, err := configurationLister.Configurations(ns.Name).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	byConfig := make(map[string][]*v1.Revision, len(configs))
+	for _, rev := range revs {
+		name := rev.Labels[serving.ConfigurationLabelKey]
+		byConfig[name] = append(byConfig[name], rev)
+	}
+
+	// Filter out active revs, revs already being deleted, and revs that the
+	// configuration's own collection deletes.
+	nonactive := make([]*v1.Revision, 0, len(revs))
+	nop := zap.NewNop().Sugar()
+	for _, config := range configs {
+		revs := byConfig[config.Name]
+		delete(byConfig, config.Name)
+		collected := sets.NewString()
+		for _, rev := range collectable(cfg, append([]*v1.Revision(nil), revs...), config, nop) {
+			collected.Insert(rev.Name)
+		}
+		for _, rev := range revs {
+			if rev.DeletionTimestamp == nil && !collected.Has(rev.Name) && !isRevisionActive(rev, config) {
+				nonactive = append(nonactive, rev)
+			}
+		}
+	}
+	// The configuration of the remaining revs is gone, so there is no latest
+	// ready to protect.
+	for _, revs := range byConfig {
+		for _, rev := range revs {
+			if rev.DeletionTimestamp == nil && !isRevisionActive(rev, &v1.Configuration{}) {
+				nonactive = append(nonactive, rev)
+			}
+		}
+	}
+	if int64(len(nonactive)) <= max {
+		return nil // not enough non-active revs
+	}
+
+	// Sort by last active ascending (oldest first)
+	sort.Slice(nonactive, func(i, j int) bool {
+		a, b := revisionLastActiveTime(nonactive[i]), revisionLastActiveTime(nonactive[j])
+		return a.Before(b)
+	})
+
+	extra := int64(len(nonactive)) - max
+	logger.Infof("Maximum number of revisions in namespace (%d) reached, deleting oldest non-active (%d) revisions",
+		max, extra)
+	for _, rev := range nonactive[:extra] {
 		logger.Info("Deleting non-active revision: ", rev.ObjectMeta.Name)
 		if err := client.ServingV1().Revisions(rev.Namespace).Delete(ctx, rev.Name, metav1.DeleteOptions{}); err != nil {
 			logger.Errorw("Failed to GC revision: "+rev.Name, zap.Error(err))
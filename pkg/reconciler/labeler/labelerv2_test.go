@@ -42,6 +42,7 @@ import (
 	"knative.dev/pkg/ptr"
 	"knative.dev/pkg/system"
 	cfgmap "knative.dev/serving/pkg/apis/config"
+	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	autoscalercfg "knative.dev/serving/pkg/autoscaler/config"
 
@@ -51,6 +52,8 @@ import (
 	. "knative.dev/serving/pkg/testing/v1"
 )
 
+const headerPolicy = `{"contentTypeNoSniff":true}`
+
 func TestV2Reconcile(t *testing.T) {
 	now := metav1.Now()
 	fakeTime := now.Time
@@ -112,6 +115,55 @@ func TestV2Reconcile(t *testing.T) {
 				WithRoutingStateModified(now.Time)),
 		},
 		Key: "default/steady-state",
+	}, {
+		Name: "propagate route header policy",
+		Objects: []runtime.Object{
+			simpleRunLatest("default", "header-policy", "the-config", WithRouteFinalizer,
+				WithRouteAnnotation(map[string]string{
+					serving.ResponseHeaderPolicyAnnotationKey: headerPolicy,
+				})),
+			simpleConfig("default", "the-config",
+				WithConfigAnn("serving.knative.dev/routes", "header-policy")),
+			rev("default", "the-config",
+				WithRevisionAnn("serving.knative.dev/routes", "header-policy"),
+				WithRoutingState(v1.RoutingStateActive, clock),
+				WithRoutingStateModified(now.Time)),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchRouteHeaderPolicy("default", rev("default", "the-config").Name, headerPolicy),
+		},
+		Key: "default/header-policy",
+	}, {
+		Name: "route header policy owned by another route",
+		Objects: []runtime.Object{
+			simpleRunLatest("default", "header-policy", "the-config", WithRouteFinalizer,
+				WithRouteAnnotation(map[string]string{
+					serving.ResponseHeaderPolicyAnnotationKey: headerPolicy,
+				})),
+			simpleConfig("default", "the-config",
+				WithConfigAnn("serving.knative.dev/routes", "another-route,header-policy")),
+			rev("default", "the-config",
+				WithRevisionAnn("serving.knative.dev/routes", "another-route,header-policy"),
+				WithRoutingState(v1.RoutingStateActive, clock),
+				WithRoutingStateModified(now.Time)),
+		},
+		Key: "default/header-policy",
+	}, {
+		Name: "clear route header policy",
+		Objects: []runtime.Object{
+			simpleRunLatest("default", "header-policy", "the-config", WithRouteFinalizer),
+			simpleConfig("default", "the-config",
+				WithConfigAnn("serving.knative.dev/routes", "header-policy")),
+			rev("default", "the-config",
+				WithRevisionAnn("serving.knative.dev/routes", "header-policy"),
+				WithRevisionAnn(serving.RouteResponseHeaderPolicyAnnotationKey, headerPolicy),
+				WithRoutingState(v1.RoutingStateActive, clock),
+				WithRoutingStateModified(now.Time)),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchRouteHeaderPolicy("default", rev("default", "the-config").Name, "null"),
+		},
+		Key: "default/header-policy",
 	}, {
 		Name: "no ready revision",
 		Objects: []runtime.Object{
@@ -443,6 +495,21 @@ func patchAddRouteAnn(namespace, name, value string) clientgotesting.PatchAction
 	return action
 }
 
+func patchRouteHeaderPolicy(namespace, name, value string) clientgotesting.PatchActionImpl {
+	action := clientgotesting.PatchActionImpl{
+		Name:       name,
+		ActionImpl: clientgotesting.ActionImpl{Namespace: namespace},
+	}
+
+	if value != "null" {
+		value = fmt.Sprintf("%q", value)
+	}
+
+	action.Patch = []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%s}}}`,
+		serving.RouteResponseHeaderPolicyAnnotationKey, value))
+	return action
+}
+
 func patchRemoveRouteAndServingStateLabel(namespace, name string, now time.Time) clientgotesting.PatchActionImpl {
 	return patchAddRouteAndServingStateLabel(namespace, name, "null", now)
 }
@@ -494,6 +561,12 @@ func TestNew(t *testing.T) {
 			Namespace: system.Namespace(),
 		},
 		Data: map[string]string{},
+	}, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cfgmap.MetadataPropagationConfigName,
+			Namespace: system.Namespace(),
+		},
+		Data: map[string]string{},
 	}, &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cfgmap.DefaultsConfigName,
@@ -73,16 +73,20 @@ func NewRevisionAccessor(
 
 // makeMetadataPatch makes a metadata map to be patched or nil if no changes are needed.
 func makeMetadataPatch(
-	acc kmeta.Accessor, routeName string, addRoutingState, remove bool, clock clock.Clock) (map[string]interface{}, error) {
+	acc kmeta.Accessor, route *v1.Route, addRoutingState, addHeaderPolicy, remove bool, clock clock.Clock) (map[string]interface{}, error) {
 	labels := map[string]interface{}{}
 	annotations := map[string]interface{}{}
 
-	updateRouteAnnotation(acc, routeName, annotations, remove)
+	updateRouteAnnotation(acc, route.Name, annotations, remove)
 
 	if addRoutingState {
 		markRoutingState(acc, clock, labels, annotations)
 	}
 
+	if addHeaderPolicy {
+		updateHeaderPolicyAnnotation(acc, route, annotations)
+	}
+
 	meta := map[string]interface{}{}
 	if len(labels) > 0 {
 		meta["labels"] = labels
@@ -140,6 +144,36 @@ func updateRouteAnnotation(acc kmeta.Accessor, routeName string, diffAnn map[str
 	}
 }
 
+// updateHeaderPolicyAnnotation copies the response header policy of the route
+// to the element, if the route is the first of the element's routes by name,
+// so that the element gets the policy of a single, well-defined route.
+func updateHeaderPolicyAnnotation(acc kmeta.Accessor, route *v1.Route, diffAnn map[string]interface{}) {
+	routes := GetListAnnValue(acc.GetAnnotations(), serving.RoutesAnnotationKey)
+	if val, has := diffAnn[serving.RoutesAnnotationKey]; has {
+		routes = sets.String{}
+		if val != nil {
+			routes = sets.NewString(strings.Split(val.(string), ",")...)
+		}
+	}
+
+	var want string
+	if routes.Len() > 0 {
+		if routes.List()[0] != route.Name {
+			// Another route owns the policy.
+			return
+		}
+		want = route.Annotations[serving.ResponseHeaderPolicyAnnotationKey]
+	}
+
+	have, has := acc.GetAnnotations()[serving.RouteResponseHeaderPolicyAnnotationKey]
+	switch {
+	case want == "" && has:
+		diffAnn[serving.RouteResponseHeaderPolicyAnnotationKey] = nil
+	case want != "" && want != have:
+		diffAnn[serving.RouteResponseHeaderPolicyAnnotationKey] = want
+	}
+}
+
 // list implements Accessor
 func (r *Revision) list(_ context.Context, ns, routeName string, state v1.RoutingState) ([]kmeta.Accessor, error) {
 	kl := make([]kmeta.Accessor, 0, 1)
@@ -170,7 +204,7 @@ func (r *Revision) makeMetadataPatch(route *v1.Route, name string, remove bool)
 	if err != nil {
 		return nil, err
 	}
-	return makeMetadataPatch(rev, route.Name, true /*addRoutingState*/, remove, r.clock)
+	return makeMetadataPatch(rev, route, true /*addRoutingState*/, true /*addHeaderPolicy*/, remove, r.clock)
 }
 
 // Configuration is an implementation of Accessor for Configurations.
@@ -238,5 +272,5 @@ func (c *Configuration) makeMetadataPatch(r *v1.Route, name string, remove bool)
 	if err != nil {
 		return nil, err
 	}
-	return makeMetadataPatch(config, r.Name, false /*addRoutingState*/, remove, c.clock)
+	return makeMetadataPatch(config, r, false /*addRoutingState*/, false /*addHeaderPolicy*/, remove, c.clock)
 }
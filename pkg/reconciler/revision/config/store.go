@@ -19,20 +19,26 @@ package config
 import (
 	"context"
 
+	corev1 "k8s.io/api/core/v1"
 	network "knative.dev/networking/pkg"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/metrics"
 	pkgtracing "knative.dev/pkg/tracing/config"
 	apiconfig "knative.dev/serving/pkg/apis/config"
+	asconfig "knative.dev/serving/pkg/autoscaler/config"
 	"knative.dev/serving/pkg/deployment"
+	servingmetrics "knative.dev/serving/pkg/metrics"
 )
 
 type cfgKey struct{}
 
+type namespaceClassKey struct{}
+
 // Config contains the configmaps requires for revision reconciliation.
 type Config struct {
 	*apiconfig.Config
+	Cardinality   *servingmetrics.CardinalityConfig
 	Deployment    *deployment.Config
 	Logging       *logging.Config
 	Network       *network.Config
@@ -50,11 +56,44 @@ func ToContext(ctx context.Context, c *Config) context.Context {
 	return context.WithValue(ctx, cfgKey{}, c)
 }
 
+// WithNamespaceOverrides returns a context whose autoscaler config carries
+// the overrides of the given namespace.
+func WithNamespaceOverrides(ctx context.Context, ns *corev1.Namespace) (context.Context, error) {
+	cfg := FromContext(ctx)
+	as, err := asconfig.WithNamespaceOverrides(cfg.Autoscaler, ns.Annotations)
+	if err != nil {
+		return ctx, err
+	}
+	if as == cfg.Autoscaler {
+		return ctx, nil
+	}
+
+	api := *cfg.Config
+	api.Autoscaler = as
+	rc := *cfg
+	rc.Config = &api
+	ctx = apiconfig.ToContext(ToContext(ctx, &rc), &api)
+	if class, ok := ns.Annotations[asconfig.NamespaceClassOverrideAnnotationKey]; ok {
+		ctx = context.WithValue(ctx, namespaceClassKey{}, class)
+	}
+	return ctx, nil
+}
+
+// NamespaceClassFromContext returns the PodAutoscaler class overridden by
+// the namespace of the revision, if any.
+func NamespaceClassFromContext(ctx context.Context) (string, bool) {
+	class, ok := ctx.Value(namespaceClassKey{}).(string)
+	return class, ok
+}
+
 // Store is a typed wrapper around configmap.UntypedStore to handle our configmaps.
 // +k8s:deepcopy-gen=false
 type Store struct {
 	*configmap.UntypedStore
 	apiStore *apiconfig.Store
+
+	// cardinalityStore parses the serving specific keys of config-observability.
+	cardinalityStore *configmap.UntypedStore
 }
 
 // NewStore creates a new store of Configs and optionally calls functions when ConfigMaps are updated for Revisions
@@ -73,6 +112,13 @@ func NewStore(logger configmap.Logger, onAfterStore ...func(name string, value i
 			onAfterStore...,
 		),
 		apiStore: apiconfig.NewStore(logger),
+		cardinalityStore: configmap.NewUntypedStore(
+			"revision-metrics",
+			logger,
+			configmap.Constructors{
+				metrics.ConfigMapName(): servingmetrics.NewCardinalityConfigFromConfigMap,
+			},
+		),
 	}
 	return store
 }
@@ -83,6 +129,7 @@ func NewStore(logger configmap.Logger, onAfterStore ...func(name string, value i
 func (s *Store) WatchConfigs(cmw configmap.Watcher) {
 	s.UntypedStore.WatchConfigs(cmw)
 	s.apiStore.WatchConfigs(cmw)
+	s.cardinalityStore.WatchConfigs(cmw)
 }
 
 // ToContext persists the config on the context.
@@ -96,6 +143,9 @@ func (s *Store) Load() *Config {
 		Config: s.apiStore.Load(),
 	}
 
+	if c, ok := s.cardinalityStore.UntypedLoad(metrics.ConfigMapName()).(*servingmetrics.CardinalityConfig); ok {
+		cfg.Cardinality = c.DeepCopy()
+	}
 	if dep, ok := s.UntypedLoad(deployment.ConfigName).(*deployment.Config); ok {
 		cfg.Deployment = dep.DeepCopy()
 	}
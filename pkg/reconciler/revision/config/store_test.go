@@ -48,9 +48,11 @@ func TestStoreLoadWithContext(t *testing.T) {
 	defaultConfig := ConfigMapFromTestFile(t, apiconfig.DefaultsConfigName)
 	autoscalerConfig := ConfigMapFromTestFile(t, autoscalerconfig.ConfigName)
 	featuresConfig := ConfigMapFromTestFile(t, apiconfig.FeaturesConfigName)
+	propagationConfig := ConfigMapFromTestFile(t, apiconfig.MetadataPropagationConfigName)
 
 	watcher := configmap.NewStaticWatcher(
 		featuresConfig,
+		propagationConfig,
 		deploymentConfig,
 		networkConfig,
 		observabilityConfig,
@@ -164,6 +166,7 @@ func TestStoreImmutableConfig(t *testing.T) {
 		ConfigMapFromTestFile(t, apiconfig.DefaultsConfigName),
 		ConfigMapFromTestFile(t, autoscalerconfig.ConfigName),
 		ConfigMapFromTestFile(t, apiconfig.FeaturesConfigName),
+		ConfigMapFromTestFile(t, apiconfig.MetadataPropagationConfigName),
 	)
 
 	store.WatchConfigs(watcher)
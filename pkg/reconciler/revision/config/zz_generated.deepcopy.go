@@ -27,6 +27,7 @@ import (
 	tracingconfig "knative.dev/pkg/tracing/config"
 	apisconfig "knative.dev/serving/pkg/apis/config"
 	deployment "knative.dev/serving/pkg/deployment"
+	servingmetrics "knative.dev/serving/pkg/metrics"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -37,6 +38,11 @@ func (in *Config) DeepCopyInto(out *Config) {
 		*out = new(apisconfig.Config)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Cardinality != nil {
+		in, out := &in.Cardinality, &out.Cardinality
+		*out = new(servingmetrics.CardinalityConfig)
+		**out = **in
+	}
 	if in.Deployment != nil {
 		in, out := &in.Deployment, &out.Deployment
 		*out = new(deployment.Config)
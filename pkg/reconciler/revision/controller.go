@@ -25,13 +25,19 @@ import (
 	imageinformer "knative.dev/caching/pkg/client/injection/informers/caching/v1alpha1/image"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	deploymentinformer "knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment"
+	namespaceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
+	filteredpodinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/pod/filtered"
 	servingclient "knative.dev/serving/pkg/client/injection/client"
 	painformer "knative.dev/serving/pkg/client/injection/informers/autoscaling/v1alpha1/podautoscaler"
 	revisioninformer "knative.dev/serving/pkg/client/injection/informers/serving/v1/revision"
 	revisionreconciler "knative.dev/serving/pkg/client/injection/reconciler/serving/v1/revision"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
 	"k8s.io/client-go/tools/cache"
 	network "knative.dev/networking/pkg"
 	"knative.dev/pkg/configmap"
@@ -39,7 +45,9 @@ import (
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/metrics"
 	apisconfig "knative.dev/serving/pkg/apis/config"
+	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	asconfig "knative.dev/serving/pkg/autoscaler/config"
 	"knative.dev/serving/pkg/deployment"
 	"knative.dev/serving/pkg/reconciler/revision/config"
 )
@@ -49,6 +57,10 @@ import (
 // resolution's Transport will also be set to this value.
 const digestResolutionWorkers = 100
 
+// debugAuthorizationCacheSize is the number of debug container authorization
+// decisions that are cached.
+const debugAuthorizationCacheSize = 1024
+
 // NewController initializes the controller and is called by the generated code
 // Registers eventhandlers to enqueue events
 func NewController(
@@ -70,6 +82,8 @@ func newControllerWithOptions(
 	deploymentInformer := deploymentinformer.Get(ctx)
 	imageInformer := imageinformer.Get(ctx)
 	paInformer := painformer.Get(ctx)
+	// Only the pods of revisions are of interest, see cmd/controller.
+	podInformer := filteredpodinformer.Get(ctx, serving.RevisionLabelKey)
 
 	c := &Reconciler{
 		kubeclient:    kubeclient.Get(ctx),
@@ -79,6 +93,10 @@ func newControllerWithOptions(
 		podAutoscalerLister: paInformer.Lister(),
 		imageLister:         imageInformer.Lister(),
 		deploymentLister:    deploymentInformer.Lister(),
+		namespaceLister:     namespaceinformer.Get(ctx).Lister(),
+		podLister:           podInformer.Lister(),
+
+		debugAuthorizations: utilcache.NewLRUExpireCache(debugAuthorizationCacheSize),
 	}
 
 	impl := revisionreconciler.NewImpl(ctx, c, func(impl *controller.Impl) controller.Options {
@@ -129,6 +147,43 @@ func newControllerWithOptions(
 	deploymentInformer.Informer().AddEventHandler(handleMatchingControllers)
 	paInformer.Informer().AddEventHandler(handleMatchingControllers)
 
+	// Pods only matter while a debug container is requested for their revision,
+	// so that pods created or restarted in the meantime get it too.
+	podInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			pod, ok := obj.(metav1.Object)
+			if !ok {
+				return false
+			}
+			rev, err := revisionInformer.Lister().Revisions(pod.GetNamespace()).Get(pod.GetLabels()[serving.RevisionLabelKey])
+			return err == nil && rev.Annotations[serving.DebugImageAnnotationKey] != ""
+		},
+		Handler: controller.HandleAll(impl.EnqueueLabelOfNamespaceScopedResource("", serving.RevisionLabelKey)),
+	})
+
+	// Namespaces may override the autoscaler config of their revisions.
+	namespaceinformer.Get(ctx).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, obj interface{}) {
+			ons, ok := old.(*corev1.Namespace)
+			if !ok {
+				return
+			}
+			ns, ok := obj.(*corev1.Namespace)
+			if !ok || equality.Semantic.DeepEqual(
+				asconfig.NamespaceOverrides(ons.Annotations), asconfig.NamespaceOverrides(ns.Annotations)) {
+				return
+			}
+			revs, err := revisionInformer.Lister().Revisions(ns.Name).List(labels.Everything())
+			if err != nil {
+				logger.Errorw("Error listing revisions of namespace "+ns.Name, zap.Error(err))
+				return
+			}
+			for _, rev := range revs {
+				impl.Enqueue(rev)
+			}
+		},
+	})
+
 	// We don't watch for changes to Image because we don't incorporate any of its
 	// properties into our own status and should work completely in the absence of
 	// a functioning Image controller.
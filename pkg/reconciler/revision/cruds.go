@@ -20,14 +20,17 @@ import (
 	"context"
 	"fmt"
 
+	"go.uber.org/zap"
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	caching "knative.dev/caching/pkg/apis/caching/v1alpha1"
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/kmp"
 	"knative.dev/pkg/logging"
+	"knative.dev/serving/pkg/apis/autoscaling"
 	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	"knative.dev/serving/pkg/reconciler/revision/config"
@@ -37,7 +40,11 @@ import (
 func (c *Reconciler) createDeployment(ctx context.Context, rev *v1.Revision) (*appsv1.Deployment, error) {
 	cfgs := config.FromContext(ctx)
 
-	deployment, err := resources.MakeDeployment(rev, cfgs)
+	nsLabels, err := c.namespaceLabels(cfgs, rev.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	deployment, err := resources.MakeDeployment(rev, cfgs, nsLabels)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to make deployment: %w", err)
@@ -50,7 +57,11 @@ func (c *Reconciler) checkAndUpdateDeployment(ctx context.Context, rev *v1.Revis
 	logger := logging.FromContext(ctx)
 	cfgs := config.FromContext(ctx)
 
-	deployment, err := resources.MakeDeployment(rev, cfgs)
+	nsLabels, err := c.namespaceLabels(cfgs, rev.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	deployment, err := resources.MakeDeployment(rev, cfgs, nsLabels)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update deployment: %w", err)
 	}
@@ -62,8 +73,16 @@ func (c *Reconciler) checkAndUpdateDeployment(ctx context.Context, rev *v1.Revis
 	// TODO(dprotaso): determine other immutable properties.
 	deployment.Spec.Selector = have.Spec.Selector
 
-	// If the spec we want is the spec we have, then we're good.
-	if equality.Semantic.DeepEqual(have.Spec, deployment.Spec) {
+	patchedLabels, patchedAnns, err := patchedMetadata(rev, cfgs, deployment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update deployment: %w", err)
+	}
+
+	// If the spec we want is the spec we have, and the metadata set by the
+	// deployment patches hasn't drifted, then we're good.
+	if equality.Semantic.DeepEqual(have.Spec, deployment.Spec) &&
+		isSubset(patchedLabels, have.Labels) &&
+		isSubset(patchedAnns, have.Annotations) {
 		return have, nil
 	}
 
@@ -71,8 +90,10 @@ func (c *Reconciler) checkAndUpdateDeployment(ctx context.Context, rev *v1.Revis
 	desiredDeployment := have.DeepCopy()
 	desiredDeployment.Spec = deployment.Spec
 
-	// Carry over new labels.
-	desiredDeployment.Labels = kmeta.UnionMaps(deployment.Labels, desiredDeployment.Labels)
+	// Carry over new labels and annotations, without overriding the existing
+	// ones, except for those the deployment patches set.
+	desiredDeployment.Labels = kmeta.UnionMaps(deployment.Labels, desiredDeployment.Labels, patchedLabels)
+	desiredDeployment.Annotations = kmeta.UnionMaps(deployment.Annotations, desiredDeployment.Annotations, patchedAnns)
 
 	d, err := c.kubeclient.AppsV1().Deployments(deployment.Namespace).Update(ctx, desiredDeployment, metav1.UpdateOptions{})
 	if err != nil {
@@ -94,12 +115,94 @@ func (c *Reconciler) checkAndUpdateDeployment(ctx context.Context, rev *v1.Revis
 	return d, nil
 }
 
+// namespaceLabels returns the labels of the given namespace, used to select
+// the deployment patches to apply. The namespace is only looked up if
+// there are patches configured.
+func (c *Reconciler) namespaceLabels(cfgs *config.Config, namespace string) (map[string]string, error) {
+	if cfgs.Deployment == nil || len(cfgs.Deployment.DeploymentPatches) == 0 {
+		return nil, nil
+	}
+	ns, err := c.namespaceLister.Get(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get namespace %q: %w", namespace, err)
+	}
+	return ns.Labels, nil
+}
+
+// withNamespaceOverrides returns a context whose autoscaler config carries
+// the overrides of the namespace. Invalid overrides are ignored, so that
+// they don't stop the revisions of the namespace from being reconciled.
+func (c *Reconciler) withNamespaceOverrides(ctx context.Context, namespace string) context.Context {
+	ns, err := c.namespaceLister.Get(namespace)
+	if err != nil {
+		if !apierrs.IsNotFound(err) {
+			logging.FromContext(ctx).Warnw("Error retrieving namespace", zap.Error(err))
+		}
+		return ctx
+	}
+	octx, err := config.WithNamespaceOverrides(ctx, ns)
+	if err != nil {
+		logging.FromContext(ctx).Warnw("Ignoring invalid namespace autoscaler overrides", zap.Error(err))
+	}
+	return octx
+}
+
+// patchedMetadata returns the labels and annotations of the given deployment
+// that were set by the deployment patches, i.e. that are missing from or
+// differ in the deployment generated without them.
+func patchedMetadata(rev *v1.Revision, cfgs *config.Config, deployment *appsv1.Deployment) (map[string]string, map[string]string, error) {
+	if cfgs.Deployment == nil || len(cfgs.Deployment.DeploymentPatches) == 0 {
+		return nil, nil, nil
+	}
+	unpatchedCfgs := *cfgs
+	unpatchedCfgs.Deployment = cfgs.Deployment.DeepCopy()
+	unpatchedCfgs.Deployment.DeploymentPatches = nil
+	unpatched, err := resources.MakeDeployment(rev, &unpatchedCfgs, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return changed(unpatched.Labels, deployment.Labels), changed(unpatched.Annotations, deployment.Annotations), nil
+}
+
+// changed returns the entries of after that are missing from or differ in before.
+func changed(before, after map[string]string) map[string]string {
+	ret := make(map[string]string, len(after))
+	for k, v := range after {
+		if bv, ok := before[k]; !ok || bv != v {
+			ret[k] = v
+		}
+	}
+	return ret
+}
+
+// isSubset returns whether every key in want is set to the same value in have.
+func isSubset(want, have map[string]string) bool {
+	for k, v := range want {
+		if hv, ok := have[k]; !ok || hv != v {
+			return false
+		}
+	}
+	return true
+}
+
 func (c *Reconciler) createImageCache(ctx context.Context, rev *v1.Revision, containerName, imageDigest string) (*caching.Image, error) {
 	image := resources.MakeImageCache(rev, containerName, imageDigest)
 	return c.cachingclient.CachingV1alpha1().Images(image.Namespace).Create(ctx, image, metav1.CreateOptions{})
 }
 
 func (c *Reconciler) createPA(ctx context.Context, rev *v1.Revision) (*autoscalingv1alpha1.PodAutoscaler, error) {
-	pa := resources.MakePA(rev)
+	pa := makePA(ctx, rev)
 	return c.client.AutoscalingV1alpha1().PodAutoscalers(pa.Namespace).Create(ctx, pa, metav1.CreateOptions{})
 }
+
+// makePA makes the PA of the revision, whose class is the one overridden by
+// the namespace, unless the revision specifies its own.
+func makePA(ctx context.Context, rev *v1.Revision) *autoscalingv1alpha1.PodAutoscaler {
+	pa := resources.MakePA(rev)
+	if class, ok := config.NamespaceClassFromContext(ctx); ok {
+		if _, ok := pa.Annotations[autoscaling.ClassAnnotationKey]; !ok {
+			pa.Annotations[autoscaling.ClassAnnotationKey] = class
+		}
+	}
+	return pa
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+	apisconfig "knative.dev/serving/pkg/apis/config"
+	"knative.dev/serving/pkg/apis/serving"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/reconciler/revision/config"
+	"knative.dev/serving/pkg/reconciler/revision/resources"
+)
+
+// debugAuthorizationTTL is how long the outcome of a debug container
+// authorization check is reused before it is checked again.
+const debugAuthorizationTTL = time.Minute
+
+// reconcileDebugContainers attaches the ephemeral debug container requested
+// through the debug annotations to the running pods of the revision, provided
+// the requesting user is allowed to do so. Once the debug containers of all
+// targeted pods have terminated the request is considered done, and the
+// debug annotations are removed from the revision.
+func (c *Reconciler) reconcileDebugContainers(ctx context.Context, rev *v1.Revision) error {
+	image := rev.Annotations[serving.DebugImageAnnotationKey]
+	if image == "" || config.FromContext(ctx).Features.DebugContainers != apisconfig.Enabled {
+		return nil
+	}
+	recorder := controller.GetEventRecorder(ctx)
+
+	pods, err := c.podLister.Pods(rev.Namespace).List(
+		labels.SelectorFromSet(labels.Set{serving.RevisionLabelKey: rev.Name}))
+	if err != nil {
+		return fmt.Errorf("failed to list pods of revision: %w", err)
+	}
+
+	target := rev.Annotations[serving.DebugPodAnnotationKey]
+	debugContainer := resources.MakeDebugContainer(rev, image)
+	var pending, debugged []*corev1.Pod
+	for _, pod := range pods {
+		if target != "" && pod.Name != target {
+			continue
+		}
+		switch {
+		case hasEphemeralContainer(pod, debugContainer.Name):
+			debugged = append(debugged, pod)
+		case pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil:
+			pending = append(pending, pod)
+		}
+	}
+	if len(pending) == 0 {
+		if debugDone(target, debugged, debugContainer.Name) {
+			return c.clearDebugRequest(ctx, rev, debugContainer.Name)
+		}
+		return nil
+	}
+
+	user, ok := debugRequester(rev)
+	if !ok {
+		recorder.Eventf(rev, corev1.EventTypeWarning, "DebugContainerDenied",
+			"Debug container with image %q was requested by an unknown user", image)
+		return nil
+	}
+	requester := user.Username
+	if requester == controllerUsername() {
+		// The controller stamps Revisions out of Configuration templates, so a
+		// request it made was not made by a user that can be authorized.
+		recorder.Eventf(rev, corev1.EventTypeWarning, "DebugContainerDenied",
+			"Debug container with image %q must be requested on the Revision, not through its Configuration", image)
+		return nil
+	}
+	if allowed, err := c.canAttachDebugContainer(ctx, rev.Namespace, user); err != nil {
+		return fmt.Errorf("failed to authorize debug container for %q: %w", requester, err)
+	} else if !allowed {
+		recorder.Eventf(rev, corev1.EventTypeWarning, "DebugContainerDenied",
+			"User %q is not allowed to attach debug containers in namespace %q", requester, rev.Namespace)
+		return nil
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Name < pending[j].Name
+	})
+	logger := logging.FromContext(ctx)
+	for _, pod := range pending {
+		// Copy the existing containers, since the pod is shared with the informer cache.
+		containers := make([]corev1.EphemeralContainer, 0, len(pod.Spec.EphemeralContainers)+1)
+		containers = append(containers, pod.Spec.EphemeralContainers...)
+		ecs := &corev1.EphemeralContainers{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            pod.Name,
+				Namespace:       pod.Namespace,
+				ResourceVersion: pod.ResourceVersion,
+			},
+			EphemeralContainers: append(containers, debugContainer),
+		}
+		if _, err := c.kubeclient.CoreV1().Pods(pod.Namespace).UpdateEphemeralContainers(
+			ctx, pod.Name, ecs, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to attach debug container to pod %q: %w", pod.Name, err)
+		}
+		logger.Infof("Attached debug container %q to pod %q", debugContainer.Name, pod.Name)
+		recorder.Eventf(rev, corev1.EventTypeNormal, "DebugContainerAttached",
+			"Attached debug container %q with image %q to pod %q on behalf of %q",
+			debugContainer.Name, image, pod.Name, requester)
+	}
+	return nil
+}
+
+// debugDone returns whether the debug request is over: either the targeted
+// pod is gone, or debug containers were attached and all of them terminated.
+func debugDone(target string, debugged []*corev1.Pod, name string) bool {
+	if target != "" && len(debugged) == 0 {
+		return true
+	}
+	if len(debugged) == 0 {
+		return false
+	}
+	for _, pod := range debugged {
+		if pod.Status.Phase == corev1.PodRunning && !ephemeralContainerTerminated(pod, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// clearDebugRequest removes the debug annotations from the revision.
+func (c *Reconciler) clearDebugRequest(ctx context.Context, rev *v1.Revision, name string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				serving.DebugImageAnnotationKey:     nil,
+				serving.DebugPodAnnotationKey:       nil,
+				serving.DebugRequesterAnnotationKey: nil,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := c.client.ServingV1().Revisions(rev.Namespace).Patch(
+		ctx, rev.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to clear debug request: %w", err)
+	}
+	controller.GetEventRecorder(ctx).Eventf(rev, corev1.EventTypeNormal, "DebugContainerFinished",
+		"Debug container %q terminated, cleared the debug request", name)
+	return nil
+}
+
+// debugRequester returns the user recorded as having requested the debug
+// container of the revision, and whether one was recorded.
+func debugRequester(rev *v1.Revision) (*authenticationv1.UserInfo, bool) {
+	user := &authenticationv1.UserInfo{}
+	if err := json.Unmarshal([]byte(rev.Annotations[serving.DebugRequesterAnnotationKey]), user); err != nil {
+		return nil, false
+	}
+	return user, user.Username != ""
+}
+
+// controllerUsername returns the username the controller acts as when it
+// creates Revisions.
+func controllerUsername() string {
+	return "system:serviceaccount:" + system.Namespace() + ":controller"
+}
+
+// canAttachDebugContainer checks whether the given user is allowed to attach
+// ephemeral containers to pods in the given namespace. The outcome is cached
+// for debugAuthorizationTTL to avoid a SubjectAccessReview per reconcile.
+func (c *Reconciler) canAttachDebugContainer(ctx context.Context, namespace string, user *authenticationv1.UserInfo) (bool, error) {
+	id, err := json.Marshal(user)
+	if err != nil {
+		return false, err
+	}
+	key := namespace + "/" + string(id)
+	if allowed, ok := c.debugAuthorizations.Get(key); ok {
+		return allowed.(bool), nil
+	}
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	sar, err := c.kubeclient.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   namespace,
+				Verb:        "update",
+				Resource:    "pods",
+				Subresource: "ephemeralcontainers",
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	c.debugAuthorizations.Add(key, sar.Status.Allowed, debugAuthorizationTTL)
+	return sar.Status.Allowed, nil
+}
+
+func hasEphemeralContainer(pod *corev1.Pod, name string) bool {
+	for i := range pod.Spec.EphemeralContainers {
+		if pod.Spec.EphemeralContainers[i].Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func ephemeralContainerTerminated(pod *corev1.Pod, name string) bool {
+	for i := range pod.Status.EphemeralContainerStatuses {
+		if st := &pod.Status.EphemeralContainerStatuses[i]; st.Name == name {
+			return st.State.Terminated != nil
+		}
+	}
+	return false
+}
@@ -0,0 +1,396 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientgotesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+
+	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+	apisconfig "knative.dev/serving/pkg/apis/config"
+	"knative.dev/serving/pkg/apis/serving"
+	fakeservingclient "knative.dev/serving/pkg/client/injection/client/fake"
+	"knative.dev/serving/pkg/reconciler/revision/config"
+	"knative.dev/serving/pkg/reconciler/revision/resources"
+
+	. "knative.dev/serving/pkg/reconciler/testing/v1"
+)
+
+const debugRevName = "test-rev"
+
+func debugPod(name, rev string, phase corev1.PodPhase, ecs ...corev1.EphemeralContainer) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels:    map[string]string{serving.RevisionLabelKey: rev},
+		},
+		Spec: corev1.PodSpec{
+			EphemeralContainers: ecs,
+		},
+		Status: corev1.PodStatus{
+			Phase: phase,
+		},
+	}
+	for _, ec := range ecs {
+		pod.Status.EphemeralContainerStatuses = append(pod.Status.EphemeralContainerStatuses, corev1.ContainerStatus{
+			Name: ec.Name,
+			State: corev1.ContainerState{
+				Running: &corev1.ContainerStateRunning{},
+			},
+		})
+	}
+	return pod
+}
+
+// terminated marks the ephemeral containers of the pod as terminated.
+func terminated(pod *corev1.Pod) *corev1.Pod {
+	for i := range pod.Status.EphemeralContainerStatuses {
+		pod.Status.EphemeralContainerStatuses[i].State = corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{},
+		}
+	}
+	return pod
+}
+
+func TestReconcileDebugContainers(t *testing.T) {
+	const (
+		image     = "debugger"
+		requester = "oveja@knative.dev"
+		// requesterInfo is what the webhook records for requester.
+		requesterInfo = `{"username":"oveja@knative.dev","uid":"42","groups":["debuggers"],"extra":{"scopes":["debug"]}}`
+	)
+	debugContainer := corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name: resources.DebugContainerName(image),
+		},
+	}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		disabled    bool
+		allowed     bool
+		updateErr   error
+		pods        []*corev1.Pod
+		wantPods    sets.String
+		wantCleared bool
+		wantEvents  []string
+		wantErr     bool
+	}{{
+		name: "no debug request",
+		pods: []*corev1.Pod{debugPod("pod-1", debugRevName, corev1.PodRunning)},
+	}, {
+		name: "feature disabled",
+		annotations: map[string]string{
+			serving.DebugImageAnnotationKey:     image,
+			serving.DebugRequesterAnnotationKey: requesterInfo,
+		},
+		disabled: true,
+		allowed:  true,
+		pods:     []*corev1.Pod{debugPod("pod-1", debugRevName, corev1.PodRunning)},
+	}, {
+		name: "unknown requester",
+		annotations: map[string]string{
+			serving.DebugImageAnnotationKey: image,
+		},
+		allowed: true,
+		pods:    []*corev1.Pod{debugPod("pod-1", debugRevName, corev1.PodRunning)},
+		wantEvents: []string{
+			`Warning DebugContainerDenied Debug container with image "debugger" was requested by an unknown user`,
+		},
+	}, {
+		name: "unparsable requester",
+		annotations: map[string]string{
+			serving.DebugImageAnnotationKey:     image,
+			serving.DebugRequesterAnnotationKey: requester,
+		},
+		allowed: true,
+		pods:    []*corev1.Pod{debugPod("pod-1", debugRevName, corev1.PodRunning)},
+		wantEvents: []string{
+			`Warning DebugContainerDenied Debug container with image "debugger" was requested by an unknown user`,
+		},
+	}, {
+		name: "requested by the controller",
+		annotations: map[string]string{
+			serving.DebugImageAnnotationKey:     image,
+			serving.DebugRequesterAnnotationKey: `{"username":"system:serviceaccount:knative-testing:controller"}`,
+		},
+		allowed: true,
+		pods:    []*corev1.Pod{debugPod("pod-1", debugRevName, corev1.PodRunning)},
+		wantEvents: []string{
+			`Warning DebugContainerDenied Debug container with image "debugger" must be requested on the Revision, not through its Configuration`,
+		},
+	}, {
+		name: "requester not allowed",
+		annotations: map[string]string{
+			serving.DebugImageAnnotationKey:     image,
+			serving.DebugRequesterAnnotationKey: requesterInfo,
+		},
+		pods: []*corev1.Pod{debugPod("pod-1", debugRevName, corev1.PodRunning)},
+		wantEvents: []string{
+			`Warning DebugContainerDenied User "oveja@knative.dev" is not allowed to attach debug containers in namespace "test"`,
+		},
+	}, {
+		name: "all running pods",
+		annotations: map[string]string{
+			serving.DebugImageAnnotationKey:     image,
+			serving.DebugRequesterAnnotationKey: requesterInfo,
+		},
+		allowed: true,
+		pods: []*corev1.Pod{
+			debugPod("pod-1", debugRevName, corev1.PodRunning),
+			debugPod("pod-2", debugRevName, corev1.PodRunning),
+			debugPod("pod-3", debugRevName, corev1.PodRunning, debugContainer),
+			debugPod("pod-4", debugRevName, corev1.PodPending),
+			debugPod("pod-5", "other-revision", corev1.PodRunning),
+		},
+		wantPods: sets.NewString("pod-1", "pod-2"),
+		wantEvents: []string{
+			`Normal DebugContainerAttached Attached debug container "` + debugContainer.Name +
+				`" with image "debugger" to pod "pod-1" on behalf of "oveja@knative.dev"`,
+			`Normal DebugContainerAttached Attached debug container "` + debugContainer.Name +
+				`" with image "debugger" to pod "pod-2" on behalf of "oveja@knative.dev"`,
+		},
+	}, {
+		name: "single pod",
+		annotations: map[string]string{
+			serving.DebugImageAnnotationKey:     image,
+			serving.DebugPodAnnotationKey:       "pod-2",
+			serving.DebugRequesterAnnotationKey: requesterInfo,
+		},
+		allowed: true,
+		pods: []*corev1.Pod{
+			debugPod("pod-1", debugRevName, corev1.PodRunning),
+			debugPod("pod-2", debugRevName, corev1.PodRunning),
+		},
+		wantPods: sets.NewString("pod-2"),
+		wantEvents: []string{
+			`Normal DebugContainerAttached Attached debug container "` + debugContainer.Name +
+				`" with image "debugger" to pod "pod-2" on behalf of "oveja@knative.dev"`,
+		},
+	}, {
+		name: "update failure",
+		annotations: map[string]string{
+			serving.DebugImageAnnotationKey:     image,
+			serving.DebugRequesterAnnotationKey: requesterInfo,
+		},
+		allowed:   true,
+		updateErr: errors.New("ephemeral containers are disabled"),
+		pods:      []*corev1.Pod{debugPod("pod-1", debugRevName, corev1.PodRunning)},
+		wantErr:   true,
+	}, {
+		name: "debug containers running",
+		annotations: map[string]string{
+			serving.DebugImageAnnotationKey:     image,
+			serving.DebugRequesterAnnotationKey: requesterInfo,
+		},
+		allowed: true,
+		pods: []*corev1.Pod{
+			debugPod("pod-1", debugRevName, corev1.PodRunning, debugContainer),
+			terminated(debugPod("pod-2", debugRevName, corev1.PodRunning, debugContainer)),
+		},
+	}, {
+		name: "debug containers terminated",
+		annotations: map[string]string{
+			serving.DebugImageAnnotationKey:     image,
+			serving.DebugRequesterAnnotationKey: requesterInfo,
+		},
+		allowed: true,
+		pods: []*corev1.Pod{
+			terminated(debugPod("pod-1", debugRevName, corev1.PodRunning, debugContainer)),
+			terminated(debugPod("pod-2", debugRevName, corev1.PodRunning, debugContainer)),
+			debugPod("pod-3", debugRevName, corev1.PodPending),
+		},
+		wantCleared: true,
+		wantEvents: []string{
+			`Normal DebugContainerFinished Debug container "` + debugContainer.Name +
+				`" terminated, cleared the debug request`,
+		},
+	}, {
+		name: "debugged pod gone",
+		annotations: map[string]string{
+			serving.DebugImageAnnotationKey:     image,
+			serving.DebugPodAnnotationKey:       "pod-2",
+			serving.DebugRequesterAnnotationKey: requesterInfo,
+		},
+		allowed:     true,
+		pods:        []*corev1.Pod{debugPod("pod-1", debugRevName, corev1.PodRunning)},
+		wantCleared: true,
+		wantEvents: []string{
+			`Normal DebugContainerFinished Debug container "` + debugContainer.Name +
+				`" terminated, cleared the debug request`,
+		},
+	}, {
+		name: "no pods yet",
+		annotations: map[string]string{
+			serving.DebugImageAnnotationKey:     image,
+			serving.DebugRequesterAnnotationKey: requesterInfo,
+		},
+		allowed: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rev := testRevision(testPodSpec())
+			rev.Annotations = test.annotations
+
+			ctx := logtesting.TestContextWithLogger(t)
+			ctx, kubeClient := fakekubeclient.With(ctx)
+			ctx, servingClient := fakeservingclient.With(ctx, rev)
+			objs := make([]runtime.Object, 0, len(test.pods))
+			for _, pod := range test.pods {
+				objs = append(objs, pod)
+			}
+			listers := NewListers(objs)
+
+			var gotSAR *authorizationv1.SubjectAccessReview
+			kubeClient.PrependReactor("create", "subjectaccessreviews",
+				func(action clientgotesting.Action) (bool, runtime.Object, error) {
+					gotSAR = action.(clientgotesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview).DeepCopy()
+					gotSAR.Status.Allowed = test.allowed
+					return true, gotSAR, nil
+				})
+			gotCleared := false
+			servingClient.PrependReactor("patch", "revisions",
+				func(action clientgotesting.Action) (bool, runtime.Object, error) {
+					patch := string(action.(clientgotesting.PatchAction).GetPatch())
+					for _, key := range []string{serving.DebugImageAnnotationKey, serving.DebugPodAnnotationKey, serving.DebugRequesterAnnotationKey} {
+						if !strings.Contains(patch, `"`+key+`":null`) {
+							t.Errorf("Patch %s does not clear %s", patch, key)
+						}
+					}
+					gotCleared = true
+					return true, rev, nil
+				})
+			gotPods := sets.NewString()
+			kubeClient.PrependReactor("update", "pods",
+				func(action clientgotesting.Action) (bool, runtime.Object, error) {
+					if action.GetSubresource() != "ephemeralcontainers" {
+						return false, nil, nil
+					}
+					if test.updateErr != nil {
+						return true, nil, test.updateErr
+					}
+					ecs := action.(clientgotesting.UpdateAction).GetObject().(*corev1.EphemeralContainers)
+					if got, want := ecs.EphemeralContainers[len(ecs.EphemeralContainers)-1].Image, image; got != want {
+						t.Errorf("Debug container image = %q, want: %q", got, want)
+					}
+					gotPods.Insert(ecs.Name)
+					return true, ecs, nil
+				})
+
+			features := &apisconfig.Features{DebugContainers: apisconfig.Enabled}
+			if test.disabled {
+				features.DebugContainers = apisconfig.Disabled
+			}
+			ctx = config.ToContext(ctx, &config.Config{
+				Config: &apisconfig.Config{Features: features},
+			})
+			recorder := record.NewFakeRecorder(len(test.pods) + 1)
+			ctx = controller.WithEventRecorder(ctx, recorder)
+
+			c := &Reconciler{
+				kubeclient:          kubeClient,
+				client:              servingClient,
+				podLister:           listers.GetPodsLister(),
+				debugAuthorizations: utilcache.NewLRUExpireCache(debugAuthorizationCacheSize),
+			}
+			if err := c.reconcileDebugContainers(ctx, rev); (err != nil) != test.wantErr {
+				t.Fatalf("reconcileDebugContainers() = %v, wantErr: %v", err, test.wantErr)
+			}
+
+			if got, want := gotPods, test.wantPods; !got.Equal(want) {
+				t.Errorf("Debugged pods = %v, want: %v", got.List(), want.List())
+			}
+			if gotCleared != test.wantCleared {
+				t.Errorf("Debug request cleared = %v, want: %v", gotCleared, test.wantCleared)
+			}
+			if gotSAR != nil {
+				attrs := gotSAR.Spec.ResourceAttributes
+				if gotSAR.Spec.User != requester || gotSAR.Spec.UID != "42" ||
+					!cmp.Equal(gotSAR.Spec.Groups, []string{"debuggers"}) ||
+					!cmp.Equal(gotSAR.Spec.Extra, map[string]authorizationv1.ExtraValue{"scopes": {"debug"}}) ||
+					attrs.Namespace != testNamespace ||
+					attrs.Resource != "pods" || attrs.Subresource != "ephemeralcontainers" || attrs.Verb != "update" {
+					t.Errorf("Unexpected SubjectAccessReview: %#v", gotSAR.Spec)
+				}
+			}
+
+			close(recorder.Events)
+			var gotEvents []string
+			for e := range recorder.Events {
+				gotEvents = append(gotEvents, e)
+			}
+			if !cmp.Equal(gotEvents, test.wantEvents) {
+				t.Error("Events (-want, +got):", cmp.Diff(test.wantEvents, gotEvents))
+			}
+		})
+	}
+}
+
+func TestDebugAuthorizationCached(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	ctx, kubeClient := fakekubeclient.With(ctx)
+	reviews := 0
+	kubeClient.PrependReactor("create", "subjectaccessreviews",
+		func(action clientgotesting.Action) (bool, runtime.Object, error) {
+			reviews++
+			sar := action.(clientgotesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview).DeepCopy()
+			sar.Status.Allowed = sar.Spec.User == "allowed"
+			return true, sar, nil
+		})
+
+	c := &Reconciler{
+		kubeclient:          kubeClient,
+		debugAuthorizations: utilcache.NewLRUExpireCache(debugAuthorizationCacheSize),
+	}
+	for _, tc := range []struct {
+		namespace, user string
+		want            bool
+		wantReviews     int
+	}{
+		{testNamespace, "allowed", true, 1},
+		{testNamespace, "allowed", true, 1},
+		{testNamespace, "denied", false, 2},
+		{testNamespace, "denied", false, 2},
+		{"other", "allowed", true, 3},
+	} {
+		got, err := c.canAttachDebugContainer(ctx, tc.namespace, &authenticationv1.UserInfo{Username: tc.user})
+		if err != nil {
+			t.Fatal("canAttachDebugContainer() =", err)
+		}
+		if got != tc.want || reviews != tc.wantReviews {
+			t.Errorf("canAttachDebugContainer(%q, %q) = %v with %d reviews, want: %v with %d reviews",
+				tc.namespace, tc.user, got, reviews, tc.want, tc.wantReviews)
+		}
+	}
+}
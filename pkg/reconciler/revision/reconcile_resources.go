@@ -32,9 +32,11 @@ import (
 	"knative.dev/pkg/kmp"
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/logging/logkey"
+	"knative.dev/serving/pkg/apis/autoscaling"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
-	"knative.dev/serving/pkg/reconciler/revision/resources"
+	"knative.dev/serving/pkg/reconciler/revision/config"
 	resourcenames "knative.dev/serving/pkg/reconciler/revision/resources/names"
+	presources "knative.dev/serving/pkg/resources"
 )
 
 func (c *Reconciler) reconcileDeployment(ctx context.Context, rev *v1.Revision) error {
@@ -109,12 +111,32 @@ func (c *Reconciler) reconcileDeployment(ctx context.Context, rev *v1.Revision)
 					break
 				}
 			}
+
+			// The containers are up, but the pod is held back by a custom
+			// readiness gate; surface which one, unless we already know of
+			// a more severe problem.
+			if gate, pending := presources.PendingReadinessGate(&pod); pending && containersReady(&pod) &&
+				!rev.Status.GetCondition(v1.RevisionConditionResourcesAvailable).IsFalse() &&
+				!rev.Status.GetCondition(v1.RevisionConditionContainerHealthy).IsFalse() {
+				rev.Status.MarkResourcesAvailableUnknown(v1.ReasonReadinessGatePending,
+					v1.ReadinessGatePendingMessage(pod.Name, string(gate)))
+			}
 		}
 	}
 
 	return nil
 }
 
+// containersReady checks whether pod's ContainersReady status is True.
+func containersReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.ContainersReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
 func (c *Reconciler) reconcileImageCache(ctx context.Context, rev *v1.Revision) error {
 	logger := logging.FromContext(ctx)
 
@@ -159,14 +181,27 @@ func (c *Reconciler) reconcilePA(ctx context.Context, rev *v1.Revision) error {
 
 	// Perhaps tha PA spec changed underneath ourselves?
 	// We no longer require immutability, so need to reconcile PA each time.
-	tmpl := resources.MakePA(rev)
+	tmpl := makePA(ctx, rev)
 	logger.Debugf("Desired PASpec: %#v", tmpl.Spec)
-	if !equality.Semantic.DeepEqual(tmpl.Spec, pa.Spec) {
+	// The namespace may override the class after the PA was created, or stop
+	// overriding it, in which case the cluster default applies again.
+	class, ok := tmpl.Annotations[autoscaling.ClassAnnotationKey]
+	if !ok {
+		class = config.FromContext(ctx).Autoscaler.PodAutoscalerClass
+	}
+	have, hasClass := pa.Annotations[autoscaling.ClassAnnotationKey]
+	classChanged := class != "" && (ok || hasClass) && have != class
+	if classChanged || !equality.Semantic.DeepEqual(tmpl.Spec, pa.Spec) {
 		diff, _ := kmp.SafeDiff(tmpl.Spec, pa.Spec) // Can't realistically fail on PASpec.
 		logger.Infof("PA %s needs reconciliation, diff(-want,+got):\n%s", pa.Name, diff)
 
 		want := pa.DeepCopy()
 		want.Spec = tmpl.Spec
+		if classChanged {
+			want.Annotations = kmeta.UnionMaps(want.Annotations, map[string]string{
+				autoscaling.ClassAnnotationKey: class,
+			})
+		}
 		if pa, err = c.client.AutoscalingV1alpha1().PodAutoscalers(ns).Update(ctx, want, metav1.UpdateOptions{}); err != nil {
 			return fmt.Errorf("failed to update PA %q: %w", paName, err)
 		}
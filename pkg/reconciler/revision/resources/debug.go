@@ -0,0 +1,51 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// debugContainerPrefix is the name prefix of the ephemeral debug containers
+// attached to the pods of a Revision.
+const debugContainerPrefix = "knative-debug-"
+
+// DebugContainerName returns the name of the debug container running the
+// given image. The name is stable for an image, so that the same image is
+// never attached twice to the same pod.
+func DebugContainerName(image string) string {
+	return fmt.Sprintf("%s%x", debugContainerPrefix, sha256.Sum256([]byte(image)))[:len(debugContainerPrefix)+10]
+}
+
+// MakeDebugContainer makes the ephemeral debug container running the given
+// image, targeting the user container of the Revision.
+func MakeDebugContainer(rev *v1.Revision, image string) corev1.EphemeralContainer {
+	return corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:                     DebugContainerName(image),
+			Image:                    image,
+			Stdin:                    true,
+			TTY:                      true,
+			TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+		},
+		TargetContainerName: rev.Spec.GetContainer().Name,
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+func TestDebugContainerName(t *testing.T) {
+	name := DebugContainerName("busybox")
+	if !strings.HasPrefix(name, debugContainerPrefix) {
+		t.Errorf("DebugContainerName = %q, want prefix %q", name, debugContainerPrefix)
+	}
+	if got, want := len(name), len(debugContainerPrefix)+10; got != want {
+		t.Errorf("len(DebugContainerName) = %d, want: %d", got, want)
+	}
+	if got, want := DebugContainerName("busybox"), name; got != want {
+		t.Errorf("DebugContainerName is not stable: %q != %q", got, want)
+	}
+	if got := DebugContainerName("alpine"); got == name {
+		t.Errorf("DebugContainerName(alpine) = DebugContainerName(busybox) = %q", got)
+	}
+}
+
+func TestMakeDebugContainer(t *testing.T) {
+	rev := revision("bar", "foo", withContainers([]corev1.Container{{
+		Name:  "sidecar",
+		Image: "sidecar-image",
+	}, {
+		Name:  servingContainerName,
+		Image: "busybox",
+		Ports: buildContainerPorts(v1.DefaultUserPort),
+	}}))
+
+	got := MakeDebugContainer(rev, "debugger")
+	want := corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:                     DebugContainerName("debugger"),
+			Image:                    "debugger",
+			Stdin:                    true,
+			TTY:                      true,
+			TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+		},
+		TargetContainerName: servingContainerName,
+	}
+	if !cmp.Equal(got, want) {
+		t.Error("MakeDebugContainer (-want, +got):", cmp.Diff(want, got))
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/ptr"
 	"knative.dev/serving/pkg/apis/autoscaling"
+	apicfg "knative.dev/serving/pkg/apis/config"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	"knative.dev/serving/pkg/networking"
 	"knative.dev/serving/pkg/queue"
@@ -227,7 +228,9 @@ func buildUserPortEnv(userPort string) corev1.EnvVar {
 }
 
 // MakeDeployment constructs a K8s Deployment resource from a revision.
-func MakeDeployment(rev *v1.Revision, cfg *config.Config) (*appsv1.Deployment, error) {
+// The config-deployment patches matching nsLabels, the labels of the
+// revision's namespace, are applied to the result.
+func MakeDeployment(rev *v1.Revision, cfg *config.Config, nsLabels map[string]string) (*appsv1.Deployment, error) {
 	podSpec, err := makePodSpec(rev, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create PodSpec: %w", err)
@@ -241,15 +244,17 @@ func MakeDeployment(rev *v1.Revision, cfg *config.Config) (*appsv1.Deployment, e
 		replicaCount = int32(rc)
 	}
 
-	labels := makeLabels(rev)
-	anns := makeAnnotations(rev)
+	var propagation *apicfg.MetadataPropagation
+	if cfg.Config != nil {
+		propagation = cfg.MetadataPropagation
+	}
 
-	return &appsv1.Deployment{
+	d := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            names.Deployment(rev),
 			Namespace:       rev.Namespace,
-			Labels:          labels,
-			Annotations:     anns,
+			Labels:          makeTargetLabels(rev, propagation, apicfg.PropagateToDeployment),
+			Annotations:     makeTargetAnnotations(rev, propagation, apicfg.PropagateToDeployment),
 			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(rev)},
 		},
 		Spec: appsv1.DeploymentSpec{
@@ -258,11 +263,19 @@ func MakeDeployment(rev *v1.Revision, cfg *config.Config) (*appsv1.Deployment, e
 			ProgressDeadlineSeconds: ptr.Int32(int32(cfg.Deployment.ProgressDeadline.Seconds())),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels:      labels,
-					Annotations: anns,
+					Labels:      makeTargetLabels(rev, propagation, apicfg.PropagateToPod),
+					Annotations: makeTargetAnnotations(rev, propagation, apicfg.PropagateToPod),
 				},
 				Spec: *podSpec,
 			},
 		},
-	}, nil
+	}
+
+	// Apply the admin-defined patches last so they see the full pod spec.
+	if cfg.Deployment != nil && len(cfg.Deployment.DeploymentPatches) > 0 {
+		if d, err = cfg.Deployment.ApplyPatches(d, nsLabels); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
 }
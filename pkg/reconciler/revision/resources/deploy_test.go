@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -168,6 +169,30 @@ var (
 		}, {
 			Name:  "METRICS_COLLECTOR_ADDRESS",
 			Value: "",
+		}, {
+			Name:  "METRICS_REQUEST_DROP_POD_TAG",
+			Value: "false",
+		}, {
+			Name:  "METRICS_REQUEST_RESPONSE_CODE_CLASS_ONLY",
+			Value: "false",
+		}, {
+			Name:  "METRICS_REQUEST_MAX_ROUTE_TAGS",
+			Value: "0",
+		}, {
+			Name:  "METRICS_REQUEST_MAX_SOURCE_SERVICES",
+			Value: "0",
+		}, {
+			Name:  "SERVING_RESPONSE_HEADER_POLICY",
+			Value: "",
+		}, {
+			Name:  "SERVING_WARMUP_REQUESTS",
+			Value: "",
+		}, {
+			Name:  "SERVING_WARMUP_TIMEOUT",
+			Value: "",
+		}, {
+			Name:  "SERVING_CONCURRENCY_PERCENTILE",
+			Value: "0",
 		}},
 	}
 
@@ -1089,7 +1114,7 @@ var quantityComparer = cmp.Comparer(func(x, y resource.Quantity) bool {
 })
 
 func TestMissingProbeError(t *testing.T) {
-	if _, err := MakeDeployment(revision("bar", "foo"), &revCfg); err == nil {
+	if _, err := MakeDeployment(revision("bar", "foo"), &revCfg, nil); err == nil {
 		t.Error("expected error from MakeDeployment")
 	}
 }
@@ -1101,6 +1126,8 @@ func TestMakeDeployment(t *testing.T) {
 		want      *appsv1.Deployment
 		dc        deployment.Config
 		acMutator func(*autoscalerconfig.Config)
+		mp        *apicfg.MetadataPropagation
+		nsLabels  map[string]string
 	}{{
 		name: "with concurrency=1",
 		rev: revision("bar", "foo",
@@ -1213,6 +1240,82 @@ func TestMakeDeployment(t *testing.T) {
 			deploy.Spec.Template.Annotations = map[string]string{autoscaling.InitialScaleAnnotationKey: "20"}
 			deploy.Annotations = map[string]string{autoscaling.InitialScaleAnnotationKey: "20"}
 		}),
+	}, {
+		name: "metadata propagation policy",
+		mp: &apicfg.MetadataPropagation{
+			Labels: map[apicfg.PropagationTarget]apicfg.PropagationRule{
+				apicfg.PropagateToDeployment: {Deny: []string{"cost.example.com/*"}},
+			},
+			Annotations: map[apicfg.PropagationTarget]apicfg.PropagationRule{
+				apicfg.PropagateToDeployment: {Deny: []string{"prometheus.io/*"}},
+				apicfg.PropagateToPod:        {Allow: []string{"prometheus.io/*"}},
+			},
+		},
+		rev: revision("bar", "foo",
+			withoutLabels,
+			withContainers([]corev1.Container{{
+				Name:           servingContainerName,
+				Image:          "ubuntu",
+				ReadinessProbe: withTCPReadinessProbe(12345),
+			}}),
+			func(revision *v1.Revision) {
+				revision.Labels = map[string]string{"cost.example.com/center": "1234"}
+				revision.Annotations = map[string]string{
+					"prometheus.io/scrape":                "true",
+					"owner":                               "hannibal",
+					autoscaling.InitialScaleAnnotationKey: "1",
+				}
+			},
+		),
+		want: appsv1deployment(func(deploy *appsv1.Deployment) {
+			deploy.Annotations = map[string]string{
+				"owner":                               "hannibal",
+				autoscaling.InitialScaleAnnotationKey: "1",
+			}
+			deploy.Spec.Template.Labels = kmeta.UnionMaps(deploy.Spec.Template.Labels,
+				map[string]string{"cost.example.com/center": "1234"})
+			deploy.Spec.Template.Annotations = map[string]string{
+				"prometheus.io/scrape":                "true",
+				autoscaling.InitialScaleAnnotationKey: "1",
+			}
+		}),
+	}, {
+		name: "with deployment patches",
+		rev: revision("bar", "foo",
+			withoutLabels,
+			withContainers([]corev1.Container{{
+				Name:           servingContainerName,
+				Image:          "ubuntu",
+				ReadinessProbe: withTCPReadinessProbe(12345),
+			}}),
+		),
+		nsLabels: map[string]string{"tier": "gold"},
+		dc: deployment.Config{
+			DeploymentPatches: []deployment.DeploymentPatch{{
+				Name:  "team",
+				Type:  deployment.StrategicMergePatchType,
+				Patch: `{"metadata":{"labels":{"team":"serving"}},"spec":{"template":{"metadata":{"annotations":{"sidecar.example.com/inject":"false"}}}}}`,
+			}, {
+				Name: "gold",
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"tier": "gold"},
+				},
+				Type:  deployment.JSONPatchType,
+				Patch: `[{"op":"add","path":"/spec/minReadySeconds","value":5}]`,
+			}, {
+				Name: "silver",
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"tier": "silver"},
+				},
+				Type:  deployment.JSONPatchType,
+				Patch: `[{"op":"add","path":"/spec/paused","value":true}]`,
+			}},
+		},
+		want: appsv1deployment(func(deploy *appsv1.Deployment) {
+			deploy.Labels = kmeta.UnionMaps(deploy.Labels, map[string]string{"team": "serving"})
+			deploy.Spec.Template.Annotations = map[string]string{"sidecar.example.com/inject": "false"}
+			deploy.Spec.MinReadySeconds = 5
+		}),
 	}}
 
 	for _, test := range tests {
@@ -1228,6 +1331,7 @@ func TestMakeDeployment(t *testing.T) {
 			cfg := (&revCfg).DeepCopy()
 			cfg.Autoscaler = ac
 			cfg.Deployment = &test.dc
+			cfg.MetadataPropagation = test.mp
 			podSpec, err := makePodSpec(test.rev, cfg)
 			if err != nil {
 				t.Fatal("makePodSpec returned error:", err)
@@ -1236,11 +1340,16 @@ func TestMakeDeployment(t *testing.T) {
 				test.want.Spec.Template.Spec = *podSpec
 			}
 			// Copy to override
-			got, err := MakeDeployment(test.rev, cfg)
+			got, err := MakeDeployment(test.rev, cfg, test.nsLabels)
 			if err != nil {
 				t.Fatal("Got unexpected error:", err)
 			}
-			if diff := cmp.Diff(test.want, got, quantityComparer); diff != "" {
+			opts := []cmp.Option{quantityComparer}
+			if len(test.dc.DeploymentPatches) > 0 {
+				// Patching round-trips through JSON, which drops empty maps.
+				opts = append(opts, cmpopts.EquateEmpty())
+			}
+			if diff := cmp.Diff(test.want, got, opts...); diff != "" {
 				t.Errorf("MakeDeployment (-want, +got) =\n%s", diff)
 			}
 		})
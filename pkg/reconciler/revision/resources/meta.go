@@ -20,6 +20,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"knative.dev/pkg/kmeta"
+	apicfg "knative.dev/serving/pkg/apis/config"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 )
@@ -40,7 +41,18 @@ var (
 
 // makeLabels constructs the labels we will apply to K8s resources.
 func makeLabels(revision *v1.Revision) map[string]string {
-	labels := kmeta.FilterMap(revision.GetLabels(), excludeLabels.Has)
+	return withRevisionLabels(revision, kmeta.FilterMap(revision.GetLabels(), excludeLabels.Has))
+}
+
+// makeTargetLabels constructs the labels we will apply to K8s resources of the
+// given kind, honoring the metadata propagation policy.
+func makeTargetLabels(revision *v1.Revision, mp *apicfg.MetadataPropagation, target apicfg.PropagationTarget) map[string]string {
+	return withRevisionLabels(revision, mp.FilterLabels(target, revision.GetLabels(), func(key string) bool {
+		return !excludeLabels.Has(key)
+	}))
+}
+
+func withRevisionLabels(revision *v1.Revision, labels map[string]string) map[string]string {
 	labels = kmeta.UnionMaps(labels, map[string]string{
 		serving.RevisionLabelKey: revision.Name,
 		serving.RevisionUID:      string(revision.UID),
@@ -59,6 +71,14 @@ func makeAnnotations(revision *v1.Revision) map[string]string {
 	return kmeta.FilterMap(revision.GetAnnotations(), excludeAnnotations.Has)
 }
 
+// makeTargetAnnotations constructs the annotations we will apply to K8s
+// resources of the given kind, honoring the metadata propagation policy.
+func makeTargetAnnotations(revision *v1.Revision, mp *apicfg.MetadataPropagation, target apicfg.PropagationTarget) map[string]string {
+	return mp.FilterAnnotations(target, revision.GetAnnotations(), func(key string) bool {
+		return !excludeAnnotations.Has(key)
+	})
+}
+
 // makeSelector constructs the Selector we will apply to K8s resources.
 func makeSelector(revision *v1.Revision) *metav1.LabelSelector {
 	return &metav1.LabelSelector{
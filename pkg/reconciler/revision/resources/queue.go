@@ -32,9 +32,12 @@ import (
 	"knative.dev/pkg/profiling"
 	"knative.dev/pkg/ptr"
 	"knative.dev/pkg/system"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	"knative.dev/serving/pkg/deployment"
+	servingmetrics "knative.dev/serving/pkg/metrics"
 	"knative.dev/serving/pkg/networking"
 	"knative.dev/serving/pkg/queue"
 	"knative.dev/serving/pkg/queue/readiness"
@@ -243,6 +246,23 @@ func makeQueueContainer(rev *v1.Revision, cfg *config.Config) (*corev1.Container
 		ts = *rev.Spec.TimeoutSeconds
 	}
 
+	cardinality := cfg.Cardinality
+	if cardinality == nil {
+		cardinality = &servingmetrics.CardinalityConfig{}
+	}
+
+	// The value is validated in the webhook.
+	percentile := "0"
+	if p, ok := rev.Annotations[autoscaling.ConcurrencyPercentileAnnotationKey]; ok {
+		percentile = p
+	}
+
+	// The Revision's own policy takes precedence over the one of its Route.
+	headerPolicy := rev.Annotations[serving.ResponseHeaderPolicyAnnotationKey]
+	if headerPolicy == "" {
+		headerPolicy = rev.Annotations[serving.RouteResponseHeaderPolicyAnnotationKey]
+	}
+
 	ports := queueNonServingPorts
 	if cfg.Observability.EnableProfiling {
 		ports = append(ports, profilingPort)
@@ -358,10 +378,64 @@ func makeQueueContainer(rev *v1.Revision, cfg *config.Config) (*corev1.Container
 		}, {
 			Name:  "METRICS_COLLECTOR_ADDRESS",
 			Value: cfg.Observability.MetricsCollectorAddress,
+		}, {
+			Name:  "METRICS_REQUEST_DROP_POD_TAG",
+			Value: strconv.FormatBool(cardinality.DropPodTag),
+		}, {
+			Name:  "METRICS_REQUEST_RESPONSE_CODE_CLASS_ONLY",
+			Value: strconv.FormatBool(cardinality.ResponseCodeClassOnly),
+		}, {
+			Name:  "METRICS_REQUEST_MAX_ROUTE_TAGS",
+			Value: strconv.Itoa(cardinality.MaxRouteTags),
+		}, {
+			Name:  "METRICS_REQUEST_MAX_SOURCE_SERVICES",
+			Value: strconv.Itoa(cardinality.MaxSourceServices),
+		}, {
+			Name:  "SERVING_RESPONSE_HEADER_POLICY",
+			Value: headerPolicy,
+		}, {
+			Name:  "SERVING_WARMUP_REQUESTS",
+			Value: rev.Annotations[serving.WarmupRequestsAnnotationKey],
+		}, {
+			Name:  "SERVING_WARMUP_TIMEOUT",
+			Value: rev.Annotations[serving.WarmupTimeoutAnnotationKey],
+		}, {
+			Name:  "SERVING_CONCURRENCY_PERCENTILE",
+			Value: percentile,
 		}},
 	}, nil
 }
 
+// MakeStandaloneQueueContainer creates the container spec of the queue sidecar
+// injected into the pods of the Deployment scaled by a standalone
+// PodAutoscaler, so that the autoscaler can scrape their stats. The pods are
+// reported as those of a revision named after the PodAutoscaler.
+func MakeStandaloneQueueContainer(pa *autoscalingv1alpha1.PodAutoscaler, podSpec *corev1.PodSpec, cfg *config.Config) (*corev1.Container, error) {
+	rev := &v1.Revision{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   pa.Namespace,
+			Name:        pa.Name,
+			Annotations: pa.Annotations,
+		},
+		Spec: v1.RevisionSpec{
+			PodSpec:              *podSpec.DeepCopy(),
+			ContainerConcurrency: ptr.Int64(pa.Spec.ContainerConcurrency),
+			TimeoutSeconds:       ptr.Int64(cfg.Defaults.RevisionTimeoutSeconds),
+		},
+	}
+	// Unlike those of revisions, the user container's probe isn't defaulted
+	// by the webhook, so probe its port like the defaulting would.
+	if container := rev.Spec.GetContainer(); container.ReadinessProbe == nil {
+		container.ReadinessProbe = &corev1.Probe{
+			Handler: corev1.Handler{
+				TCPSocket: &corev1.TCPSocketAction{},
+			},
+			SuccessThreshold: 1,
+		}
+	}
+	return makeQueueContainer(rev, cfg)
+}
+
 func applyReadinessProbeDefaults(p *corev1.Probe, port int32) {
 	switch {
 	case p == nil:
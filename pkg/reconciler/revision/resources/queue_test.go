@@ -39,11 +39,14 @@ import (
 	"knative.dev/pkg/ptr"
 	"knative.dev/pkg/system"
 	tracingconfig "knative.dev/pkg/tracing/config"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
 	apicfg "knative.dev/serving/pkg/apis/config"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
 	"knative.dev/serving/pkg/deployment"
+	servingmetrics "knative.dev/serving/pkg/metrics"
 	"knative.dev/serving/pkg/queue"
 	"knative.dev/serving/pkg/reconciler/revision/config"
 
@@ -98,6 +101,7 @@ func TestMakeQueueContainer(t *testing.T) {
 		lc   logging.Config
 		nc   network.Config
 		oc   metrics.ObservabilityConfig
+		cc   servingmetrics.CardinalityConfig
 		dc   deployment.Config
 		want corev1.Container
 	}{{
@@ -309,6 +313,97 @@ func TestMakeQueueContainer(t *testing.T) {
 				"METRICS_COLLECTOR_ADDRESS":       "otel:55678",
 			})
 		}),
+	}, {
+		name: "metric cardinality",
+		rev: revision("bar", "foo",
+			withContainers(containers)),
+		cc: servingmetrics.CardinalityConfig{
+			DropPodTag:            true,
+			ResponseCodeClassOnly: true,
+			MaxRouteTags:          5,
+			MaxSourceServices:     20,
+		},
+		want: queueContainer(func(c *corev1.Container) {
+			c.Env = env(map[string]string{
+				"METRICS_REQUEST_DROP_POD_TAG":             "true",
+				"METRICS_REQUEST_MAX_ROUTE_TAGS":           "5",
+				"METRICS_REQUEST_MAX_SOURCE_SERVICES":      "20",
+				"METRICS_REQUEST_RESPONSE_CODE_CLASS_ONLY": "true",
+			})
+		}),
+	}, {
+		name: "response header policy",
+		rev: revision("bar", "foo",
+			withContainers(containers),
+			func(r *v1.Revision) {
+				r.Annotations = map[string]string{
+					serving.ResponseHeaderPolicyAnnotationKey: `{"contentTypeNoSniff":true}`,
+				}
+			}),
+		want: queueContainer(func(c *corev1.Container) {
+			c.Env = env(map[string]string{
+				"SERVING_RESPONSE_HEADER_POLICY": `{"contentTypeNoSniff":true}`,
+			})
+		}),
+	}, {
+		name: "route response header policy",
+		rev: revision("bar", "foo",
+			withContainers(containers),
+			func(r *v1.Revision) {
+				r.Annotations = map[string]string{
+					serving.RouteResponseHeaderPolicyAnnotationKey: `{"hsts":{"maxAgeSeconds":60}}`,
+				}
+			}),
+		want: queueContainer(func(c *corev1.Container) {
+			c.Env = env(map[string]string{
+				"SERVING_RESPONSE_HEADER_POLICY": `{"hsts":{"maxAgeSeconds":60}}`,
+			})
+		}),
+	}, {
+		name: "revision response header policy takes precedence over the route's",
+		rev: revision("bar", "foo",
+			withContainers(containers),
+			func(r *v1.Revision) {
+				r.Annotations = map[string]string{
+					serving.ResponseHeaderPolicyAnnotationKey:      `{"contentTypeNoSniff":true}`,
+					serving.RouteResponseHeaderPolicyAnnotationKey: `{"hsts":{"maxAgeSeconds":60}}`,
+				}
+			}),
+		want: queueContainer(func(c *corev1.Container) {
+			c.Env = env(map[string]string{
+				"SERVING_RESPONSE_HEADER_POLICY": `{"contentTypeNoSniff":true}`,
+			})
+		}),
+	}, {
+		name: "warm-up requests",
+		rev: revision("bar", "foo",
+			withContainers(containers),
+			func(r *v1.Revision) {
+				r.Annotations = map[string]string{
+					serving.WarmupRequestsAnnotationKey: `[{"path":"/warm"}]`,
+					serving.WarmupTimeoutAnnotationKey:  "30s",
+				}
+			}),
+		want: queueContainer(func(c *corev1.Container) {
+			c.Env = env(map[string]string{
+				"SERVING_WARMUP_REQUESTS": `[{"path":"/warm"}]`,
+				"SERVING_WARMUP_TIMEOUT":  "30s",
+			})
+		}),
+	}, {
+		name: "concurrency percentile",
+		rev: revision("bar", "foo",
+			withContainers(containers),
+			func(r *v1.Revision) {
+				r.Annotations = map[string]string{
+					autoscaling.ConcurrencyPercentileAnnotationKey: "95",
+				}
+			}),
+		want: queueContainer(func(c *corev1.Container) {
+			c.Env = env(map[string]string{
+				"SERVING_CONCURRENCY_PERCENTILE": "95",
+			})
+		}),
 	}}
 
 	for _, test := range tests {
@@ -325,6 +420,7 @@ func TestMakeQueueContainer(t *testing.T) {
 				Tracing:       &traceConfig,
 				Logging:       &test.lc,
 				Observability: &test.oc,
+				Cardinality:   &test.cc,
 				Deployment:    &test.dc,
 			}
 			got, err := makeQueueContainer(test.rev, cfg)
@@ -780,30 +876,83 @@ func TestTCPProbeGeneration(t *testing.T) {
 	}
 }
 
+func TestMakeStandaloneQueueContainer(t *testing.T) {
+	pa := &autoscalingv1alpha1.PodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "foo",
+			Name:      "user-pa",
+		},
+		Spec: autoscalingv1alpha1.PodAutoscalerSpec{
+			ContainerConcurrency: 10,
+		},
+	}
+	podSpec := &corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Name:  "user-container",
+			Image: "busybox",
+			Ports: []corev1.ContainerPort{{
+				ContainerPort: 8888,
+			}},
+		}},
+	}
+
+	got, err := MakeStandaloneQueueContainer(pa, podSpec, &revCfg)
+	if err != nil {
+		t.Fatal("MakeStandaloneQueueContainer returned error:", err)
+	}
+	want := env(map[string]string{
+		"CONTAINER_CONCURRENCY":    "10",
+		"REVISION_TIMEOUT_SECONDS": strconv.Itoa(int(revCfg.Defaults.RevisionTimeoutSeconds)),
+		"SERVING_NAMESPACE":        "foo",
+		"SERVING_REVISION":         "user-pa",
+		"USER_PORT":                "8888",
+	})
+	want = append(want, corev1.EnvVar{
+		Name:  "SERVING_READINESS_PROBE",
+		Value: `{"tcpSocket":{"port":8888,"host":"127.0.0.1"},"successThreshold":1}`,
+	})
+	sortEnv(got.Env)
+	sortEnv(want)
+	if !cmp.Equal(got.Env, want) {
+		t.Errorf("Env (-want, +got) =\n%s", cmp.Diff(want, got.Env))
+	}
+	if got.Name != QueueContainerName {
+		t.Errorf("Name = %q, want: %q", got.Name, QueueContainerName)
+	}
+}
+
 var defaultEnv = map[string]string{
-	"CONTAINER_CONCURRENCY":                 "0",
-	"ENABLE_PROFILING":                      "false",
-	"METRICS_DOMAIN":                        metrics.Domain(),
-	"METRICS_COLLECTOR_ADDRESS":             "",
-	"QUEUE_SERVING_PORT":                    "8012",
-	"REVISION_TIMEOUT_SECONDS":              "45",
-	"SERVING_CONFIGURATION":                 "",
-	"SERVING_ENABLE_PROBE_REQUEST_LOG":      "false",
-	"SERVING_ENABLE_REQUEST_LOG":            "false",
-	"SERVING_LOGGING_CONFIG":                "",
-	"SERVING_LOGGING_LEVEL":                 "",
-	"SERVING_NAMESPACE":                     "foo",
-	"SERVING_REQUEST_LOG_TEMPLATE":          "",
-	"SERVING_REQUEST_METRICS_BACKEND":       "",
-	"SERVING_REVISION":                      "bar",
-	"SERVING_SERVICE":                       "",
-	"SYSTEM_NAMESPACE":                      system.Namespace(),
-	"TRACING_CONFIG_BACKEND":                "",
-	"TRACING_CONFIG_DEBUG":                  "false",
-	"TRACING_CONFIG_SAMPLE_RATE":            "0",
-	"TRACING_CONFIG_STACKDRIVER_PROJECT_ID": "",
-	"TRACING_CONFIG_ZIPKIN_ENDPOINT":        "",
-	"USER_PORT":                             strconv.Itoa(v1.DefaultUserPort),
+	"CONTAINER_CONCURRENCY":                    "0",
+	"ENABLE_PROFILING":                         "false",
+	"METRICS_DOMAIN":                           metrics.Domain(),
+	"METRICS_COLLECTOR_ADDRESS":                "",
+	"METRICS_REQUEST_DROP_POD_TAG":             "false",
+	"METRICS_REQUEST_MAX_ROUTE_TAGS":           "0",
+	"METRICS_REQUEST_MAX_SOURCE_SERVICES":      "0",
+	"METRICS_REQUEST_RESPONSE_CODE_CLASS_ONLY": "false",
+	"QUEUE_SERVING_PORT":                       "8012",
+	"REVISION_TIMEOUT_SECONDS":                 "45",
+	"SERVING_CONCURRENCY_PERCENTILE":           "0",
+	"SERVING_CONFIGURATION":                    "",
+	"SERVING_ENABLE_PROBE_REQUEST_LOG":         "false",
+	"SERVING_ENABLE_REQUEST_LOG":               "false",
+	"SERVING_LOGGING_CONFIG":                   "",
+	"SERVING_LOGGING_LEVEL":                    "",
+	"SERVING_NAMESPACE":                        "foo",
+	"SERVING_REQUEST_LOG_TEMPLATE":             "",
+	"SERVING_REQUEST_METRICS_BACKEND":          "",
+	"SERVING_RESPONSE_HEADER_POLICY":           "",
+	"SERVING_REVISION":                         "bar",
+	"SERVING_SERVICE":                          "",
+	"SERVING_WARMUP_REQUESTS":                  "",
+	"SERVING_WARMUP_TIMEOUT":                   "",
+	"SYSTEM_NAMESPACE":                         system.Namespace(),
+	"TRACING_CONFIG_BACKEND":                   "",
+	"TRACING_CONFIG_DEBUG":                     "false",
+	"TRACING_CONFIG_SAMPLE_RATE":               "0",
+	"TRACING_CONFIG_STACKDRIVER_PROJECT_ID":    "",
+	"TRACING_CONFIG_ZIPKIN_ENDPOINT":           "",
+	"USER_PORT":                                strconv.Itoa(v1.DefaultUserPort),
 }
 
 func probeJSON(container *corev1.Container) string {
@@ -27,9 +27,11 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
 	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	cachingclientset "knative.dev/caching/pkg/client/clientset/versioned"
 	clientset "knative.dev/serving/pkg/client/clientset/versioned"
 	revisionreconciler "knative.dev/serving/pkg/client/injection/reconciler/serving/v1/revision"
@@ -58,6 +60,12 @@ type Reconciler struct {
 	podAutoscalerLister palisters.PodAutoscalerLister
 	imageLister         cachinglisters.ImageLister
 	deploymentLister    appsv1listers.DeploymentLister
+	namespaceLister     corev1listers.NamespaceLister
+	podLister           corev1listers.PodLister
+
+	// debugAuthorizations caches the outcome of debug container
+	// authorization checks, keyed by namespace and user.
+	debugAuthorizations *utilcache.LRUExpireCache
 
 	resolver resolver
 }
@@ -126,6 +134,7 @@ func (c *Reconciler) reconcileDigest(ctx context.Context, rev *v1.Revision) (boo
 // ReconcileKind implements Interface.ReconcileKind.
 func (c *Reconciler) ReconcileKind(ctx context.Context, rev *v1.Revision) pkgreconciler.Event {
 	readyBeforeReconcile := rev.IsReady()
+	ctx = c.withNamespaceOverrides(ctx, rev.Namespace)
 	c.updateRevisionLoggingURL(ctx, rev)
 
 	reconciled, err := c.reconcileDigest(ctx, rev)
@@ -151,6 +160,7 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, rev *v1.Revision) pkgrec
 		c.reconcileDeployment,
 		c.reconcileImageCache,
 		c.reconcilePA,
+		c.reconcileDebugContainers,
 	} {
 		if err := phase(ctx, rev); err != nil {
 			return err
@@ -30,7 +30,11 @@ import (
 	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
 	fakedeploymentinformer "knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment/fake"
 	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap/fake"
+	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace/fake"
+	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/pod/filtered/fake"
 	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/service/fake"
+	filteredFactory "knative.dev/pkg/client/injection/kube/informers/factory/filtered"
+	_ "knative.dev/pkg/client/injection/kube/informers/factory/filtered/fake"
 	"knative.dev/pkg/ptr"
 	fakeservingclient "knative.dev/serving/pkg/client/injection/client/fake"
 	fakepainformer "knative.dev/serving/pkg/client/injection/informers/autoscaling/v1alpha1/podautoscaler/fake"
@@ -47,13 +51,17 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 
 	network "knative.dev/networking/pkg"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/logging"
+	logtesting "knative.dev/pkg/logging/testing"
 	"knative.dev/pkg/metrics"
 	"knative.dev/pkg/reconciler"
 	"knative.dev/pkg/system"
@@ -77,6 +85,16 @@ const (
 	testQueueImage      = "queueImage"
 )
 
+// setupFakeContextWithCancel is SetupFakeContextWithCancel, with the label
+// selectors of the filtered informers used by the controller.
+func setupFakeContextWithCancel(t *testing.T) (context.Context, context.CancelFunc, []controller.Informer) {
+	ctx, cancel := context.WithCancel(logtesting.TestContextWithLogger(t))
+	ctx = controller.WithEventRecorder(ctx, record.NewFakeRecorder(1000))
+	ctx = filteredFactory.WithSelectors(ctx, serving.RevisionLabelKey)
+	ctx, informers := injection.Fake.SetupInformers(ctx, &rest.Config{})
+	return ctx, cancel, informers
+}
+
 func newTestController(t *testing.T, configs []*corev1.ConfigMap, opts ...reconcilerOption) (
 	context.Context,
 	context.CancelFunc,
@@ -84,7 +102,7 @@ func newTestController(t *testing.T, configs []*corev1.ConfigMap, opts ...reconc
 	*controller.Impl,
 	*configmap.ManualWatcher) {
 
-	ctx, cancel, informers := SetupFakeContextWithCancel(t)
+	ctx, cancel, informers := setupFakeContextWithCancel(t)
 	t.Cleanup(cancel) // cancel is reentrant, so if necessary callers can call it directly, if needed.
 	configMapWatcher := &configmap.ManualWatcher{Namespace: system.Namespace()}
 
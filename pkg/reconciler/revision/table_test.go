@@ -38,12 +38,15 @@ import (
 	"knative.dev/pkg/metrics"
 	pkgreconciler "knative.dev/pkg/reconciler"
 	tracingconfig "knative.dev/pkg/tracing/config"
+	"knative.dev/serving/pkg/apis/autoscaling"
 	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
 	defaultconfig "knative.dev/serving/pkg/apis/config"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	asconfig "knative.dev/serving/pkg/autoscaler/config"
 	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
 	servingclient "knative.dev/serving/pkg/client/injection/client"
 	revisionreconciler "knative.dev/serving/pkg/client/injection/reconciler/serving/v1/revision"
+	"knative.dev/serving/pkg/deployment"
 	"knative.dev/serving/pkg/reconciler/revision/config"
 	"knative.dev/serving/pkg/reconciler/revision/resources"
 
@@ -552,6 +555,27 @@ func TestReconcile(t *testing.T) {
 			Object: pa("foo", "pod-schedule-error", WithReachabilityUnreachable),
 		}},
 		Key: "foo/pod-schedule-error",
+	}, {
+		Name: "surface pending readiness gate",
+		// Test that a pod whose containers are up, but which is waiting on
+		// a custom readiness gate, surfaces the blocking gate in the revision.
+		Objects: []runtime.Object{
+			Revision("foo", "pod-gate-pending",
+				WithK8sServiceName, WithLogURL, allUnknownConditions, MarkActive),
+			pa("foo", "pod-gate-pending"), // PA can't be ready, since no traffic.
+			pod(t, "foo", "pod-gate-pending", WithPendingReadinessGate("example.com/cache-warm")),
+			deploy(t, "foo", "pod-gate-pending"),
+			image("foo", "pod-gate-pending"),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: Revision("foo", "pod-gate-pending", WithK8sServiceName,
+				WithLogURL, allUnknownConditions, MarkReadinessGatePending("pod-gate-pending",
+					"example.com/cache-warm"), withDefaultContainerStatuses(), WithRevisionObservedGeneration(1)),
+		}},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: pa("foo", "pod-gate-pending", WithReachabilityUnreachable),
+		}},
+		Key: "foo/pod-gate-pending",
 	}, {
 		Name: "ready steady state",
 		// Test the transition that Reconcile makes when Endpoints become ready on the
@@ -644,6 +668,8 @@ func TestReconcile(t *testing.T) {
 			podAutoscalerLister: listers.GetPodAutoscalerLister(),
 			imageLister:         listers.GetImageLister(),
 			deploymentLister:    listers.GetDeploymentLister(),
+			namespaceLister:     listers.GetNamespaceLister(),
+			podLister:           listers.GetPodsLister(),
 			resolver:            &nopResolver{},
 		}
 
@@ -657,6 +683,232 @@ func TestReconcile(t *testing.T) {
 	}))
 }
 
+func TestReconcileDeploymentPatches(t *testing.T) {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "foo",
+			Labels: map[string]string{"tier": "gold"},
+		},
+	}
+
+	table := TableTest{{
+		Name: "first reconciliation applies patches",
+		Objects: []runtime.Object{
+			ns,
+			Revision("foo", "first-reconcile"),
+		},
+		WantCreates: []runtime.Object{
+			pa("foo", "first-reconcile"),
+			deploy(t, "foo", "first-reconcile", withDeploymentPatches),
+			image("foo", "first-reconcile"),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: Revision("foo", "first-reconcile",
+				WithLogURL, allUnknownConditions, MarkDeploying("Deploying"), WithK8sServiceName,
+				withDefaultContainerStatuses(), WithRevisionObservedGeneration(1)),
+		}},
+		Key: "foo/first-reconcile",
+	}, {
+		Name: "patched deployment is stable",
+		Objects: []runtime.Object{
+			ns,
+			Revision("foo", "stable-reconcile", WithLogURL, allUnknownConditions,
+				WithK8sServiceName, withDefaultContainerStatuses(), WithRevisionObservedGeneration(1)),
+			pa("foo", "stable-reconcile", WithReachabilityUnknown),
+			deploy(t, "foo", "stable-reconcile", withDeploymentPatches),
+			image("foo", "stable-reconcile"),
+		},
+		Key: "foo/stable-reconcile",
+	}, {
+		Name: "drifted deployment is re-patched",
+		Objects: []runtime.Object{
+			ns,
+			Revision("foo", "drifted", WithLogURL, allUnknownConditions,
+				WithK8sServiceName, withDefaultContainerStatuses(), WithRevisionObservedGeneration(1)),
+			pa("foo", "drifted", WithReachabilityUnknown),
+			deploy(t, "foo", "drifted"),
+			image("foo", "drifted"),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: deploy(t, "foo", "drifted", withDeploymentPatches),
+		}},
+		Key: "foo/drifted",
+	}, {
+		Name: "only patched metadata is restored",
+		Objects: []runtime.Object{
+			ns,
+			Revision("foo", "relabeled", WithLogURL, allUnknownConditions,
+				WithK8sServiceName, withDefaultContainerStatuses(), WithRevisionObservedGeneration(1)),
+			pa("foo", "relabeled", WithReachabilityUnknown),
+			withLabels(deploy(t, "foo", "relabeled", withDeploymentPatches), map[string]string{
+				"app":  "custom",
+				"team": "other",
+			}),
+			image("foo", "relabeled"),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: withLabels(deploy(t, "foo", "relabeled", withDeploymentPatches), map[string]string{
+				"app": "custom",
+			}),
+		}},
+		Key: "foo/relabeled",
+	}, {
+		Name:    "missing namespace",
+		WantErr: true,
+		Objects: []runtime.Object{
+			Revision("foo", "no-namespace", WithLogURL, allUnknownConditions,
+				WithK8sServiceName, withDefaultContainerStatuses(), WithRevisionObservedGeneration(1)),
+			pa("foo", "no-namespace", WithReachabilityUnknown),
+			deploy(t, "foo", "no-namespace", withDeploymentPatches),
+			image("foo", "no-namespace"),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, "InternalError",
+				`failed to update deployment "no-namespace-deployment": failed to get namespace "foo": namespace "foo" not found`),
+		},
+		Key: "foo/no-namespace",
+	}}
+
+	cfg := reconcilerTestConfig()
+	withDeploymentPatches(cfg)
+
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
+		r := &Reconciler{
+			kubeclient:    kubeclient.Get(ctx),
+			client:        servingclient.Get(ctx),
+			cachingclient: cachingclient.Get(ctx),
+
+			podAutoscalerLister: listers.GetPodAutoscalerLister(),
+			imageLister:         listers.GetImageLister(),
+			deploymentLister:    listers.GetDeploymentLister(),
+			namespaceLister:     listers.GetNamespaceLister(),
+			podLister:           listers.GetPodsLister(),
+			resolver:            &nopResolver{},
+		}
+
+		return revisionreconciler.NewReconciler(ctx, logging.FromContext(ctx), servingclient.Get(ctx),
+			listers.GetRevisionLister(), controller.GetEventRecorder(ctx), r,
+			controller.Options{
+				ConfigStore: &testConfigStore{
+					config: cfg,
+				},
+			})
+	}))
+}
+
+func TestReconcileNamespaceOverrides(t *testing.T) {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foo",
+		},
+	}
+	hpaNS := ns.DeepCopy()
+	hpaNS.Annotations = map[string]string{
+		asconfig.NamespaceClassOverrideAnnotationKey: autoscaling.HPA,
+	}
+	withKPAClass := func(pa *autoscalingv1alpha1.PodAutoscaler) {
+		pa.Annotations[autoscaling.ClassAnnotationKey] = autoscaling.KPA
+	}
+
+	table := TableTest{{
+		Name: "namespace class applies to new PAs",
+		Objects: []runtime.Object{
+			hpaNS,
+			Revision("foo", "first-reconcile"),
+		},
+		WantCreates: []runtime.Object{
+			pa("foo", "first-reconcile", WithHPAClass),
+			deploy(t, "foo", "first-reconcile"),
+			image("foo", "first-reconcile"),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: Revision("foo", "first-reconcile",
+				WithLogURL, allUnknownConditions, MarkDeploying("Deploying"), WithK8sServiceName,
+				withDefaultContainerStatuses(), WithRevisionObservedGeneration(1)),
+		}},
+		Key: "foo/first-reconcile",
+	}, {
+		Name: "namespace class applies to existing PAs",
+		Objects: []runtime.Object{
+			hpaNS,
+			Revision("foo", "existing", WithLogURL, allUnknownConditions,
+				WithK8sServiceName, withDefaultContainerStatuses(), WithRevisionObservedGeneration(1)),
+			pa("foo", "existing", withKPAClass, WithReachabilityUnknown),
+			deploy(t, "foo", "existing"),
+			image("foo", "existing"),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: pa("foo", "existing", WithHPAClass, WithReachabilityUnknown),
+		}},
+		Key: "foo/existing",
+	}, {
+		Name: "revision class wins over the namespace class",
+		Objects: []runtime.Object{
+			hpaNS,
+			Revision("foo", "stable-reconcile", WithLogURL, allUnknownConditions,
+				WithK8sServiceName, withDefaultContainerStatuses(), WithRevisionObservedGeneration(1),
+				WithRevisionAnn(autoscaling.ClassAnnotationKey, autoscaling.KPA)),
+			pa("foo", "stable-reconcile", withKPAClass, WithReachabilityUnknown),
+			deploy(t, "foo", "stable-reconcile", WithRevisionAnn(autoscaling.ClassAnnotationKey, autoscaling.KPA)),
+			image("foo", "stable-reconcile"),
+		},
+		Key: "foo/stable-reconcile",
+	}, {
+		Name: "cluster class applies once the namespace stops overriding it",
+		Objects: []runtime.Object{
+			ns,
+			Revision("foo", "reverted", WithLogURL, allUnknownConditions,
+				WithK8sServiceName, withDefaultContainerStatuses(), WithRevisionObservedGeneration(1)),
+			pa("foo", "reverted", WithHPAClass, WithReachabilityUnknown),
+			deploy(t, "foo", "reverted"),
+			image("foo", "reverted"),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: pa("foo", "reverted", withKPAClass, WithReachabilityUnknown),
+		}},
+		Key: "foo/reverted",
+	}}
+
+	// The overrides are validated like config-autoscaler, so start from a
+	// valid config.
+	cfg := reconcilerTestConfig()
+	as, err := asconfig.NewConfigFromMap(nil)
+	if err != nil {
+		t.Fatal("NewConfigFromMap() =", err)
+	}
+	cfg.Autoscaler = as
+
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
+		r := &Reconciler{
+			kubeclient:    kubeclient.Get(ctx),
+			client:        servingclient.Get(ctx),
+			cachingclient: cachingclient.Get(ctx),
+
+			podAutoscalerLister: listers.GetPodAutoscalerLister(),
+			imageLister:         listers.GetImageLister(),
+			deploymentLister:    listers.GetDeploymentLister(),
+			namespaceLister:     listers.GetNamespaceLister(),
+			podLister:           listers.GetPodsLister(),
+			resolver:            &nopResolver{},
+		}
+
+		return revisionreconciler.NewReconciler(ctx, logging.FromContext(ctx), servingclient.Get(ctx),
+			listers.GetRevisionLister(), controller.GetEventRecorder(ctx), r,
+			controller.Options{
+				ConfigStore: &testConfigStore{
+					config: cfg,
+				},
+			})
+	}))
+}
+
+func withLabels(deploy *appsv1.Deployment, labels map[string]string) *appsv1.Deployment {
+	for k, v := range labels {
+		deploy.Labels[k] = v
+	}
+	return deploy
+}
+
 func readyDeploy(deploy *appsv1.Deployment) *appsv1.Deployment {
 	deploy.Status.Conditions = []appsv1.DeploymentCondition{{
 		Type:   appsv1.DeploymentProgressing,
@@ -730,6 +982,23 @@ func allUnknownConditions(r *v1.Revision) {
 
 type configOption func(*config.Config)
 
+// withDeploymentPatches configures a patch for every namespace, and one
+// for namespaces that the tests never label to match.
+var withDeploymentPatches configOption = func(cfg *config.Config) {
+	cfg.Deployment.DeploymentPatches = []deployment.DeploymentPatch{{
+		Name:  "team",
+		Type:  deployment.StrategicMergePatchType,
+		Patch: `{"metadata":{"labels":{"team":"serving"}},"spec":{"minReadySeconds":3}}`,
+	}, {
+		Name: "silver",
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"tier": "silver"},
+		},
+		Type:  deployment.JSONPatchType,
+		Patch: `[{"op":"add","path":"/spec/paused","value":true}]`,
+	}}
+}
+
 func deploy(t *testing.T, namespace, name string, opts ...interface{}) *appsv1.Deployment {
 	t.Helper()
 	cfg := reconcilerTestConfig()
@@ -751,7 +1020,7 @@ func deploy(t *testing.T, namespace, name string, opts ...interface{}) *appsv1.D
 	// Do this here instead of in `rev` itself to ensure that we populate defaults
 	// before calling MakeDeployment within Reconcile.
 	rev.SetDefaults(context.Background())
-	deployment, err := resources.MakeDeployment(rev, cfg)
+	deployment, err := resources.MakeDeployment(rev, cfg, nil)
 	if err != nil {
 		t.Fatal("failed to create deployment")
 	}
@@ -31,10 +31,11 @@ type cfgKey struct{}
 // Config is the configuration for the route reconciler.
 // +k8s:deepcopy-gen=false
 type Config struct {
-	Domain   *Domain
-	GC       *gc.Config
-	Network  *network.Config
-	Features *cfgmap.Features
+	Domain              *Domain
+	GC                  *gc.Config
+	Network             *network.Config
+	Features            *cfgmap.Features
+	MetadataPropagation *cfgmap.MetadataPropagation
 }
 
 // FromContext obtains a Config injected into the passed context.
@@ -54,6 +55,10 @@ func FromContextOrDefaults(ctx context.Context) *Config {
 		cfg.Features, _ = cfgmap.NewFeaturesConfigFromMap(map[string]string{})
 	}
 
+	if cfg.MetadataPropagation == nil {
+		cfg.MetadataPropagation, _ = cfgmap.NewMetadataPropagationFromMap(map[string]string{})
+	}
+
 	return cfg
 }
 
@@ -87,10 +92,11 @@ func NewStore(ctx context.Context, onAfterStore ...func(name string, value inter
 			"route",
 			logger,
 			configmap.Constructors{
-				DomainConfigName:          NewDomainFromConfigMap,
-				gc.ConfigName:             gc.NewConfigFromConfigMapFunc(ctx),
-				network.ConfigName:        network.NewConfigFromConfigMap,
-				cfgmap.FeaturesConfigName: cfgmap.NewFeaturesConfigFromConfigMap,
+				DomainConfigName:                     NewDomainFromConfigMap,
+				gc.ConfigName:                        gc.NewConfigFromConfigMapFunc(ctx),
+				network.ConfigName:                   network.NewConfigFromConfigMap,
+				cfgmap.FeaturesConfigName:            cfgmap.NewFeaturesConfigFromConfigMap,
+				cfgmap.MetadataPropagationConfigName: cfgmap.NewMetadataPropagationFromConfigMap,
 			},
 			onAfterStore...,
 		),
@@ -116,6 +122,9 @@ func (s *Store) Load() *Config {
 	if featureConfig := s.UntypedLoad(cfgmap.FeaturesConfigName); featureConfig != nil {
 		config.Features = featureConfig.(*cfgmap.Features).DeepCopy()
 	}
+	if mp := s.UntypedLoad(cfgmap.MetadataPropagationConfigName); mp != nil {
+		config.MetadataPropagation = mp.(*cfgmap.MetadataPropagation).DeepCopy()
+	}
 
 	return config
 }
@@ -37,11 +37,13 @@ func TestStoreLoadWithContext(t *testing.T) {
 	gcConfig := ConfigMapFromTestFile(t, gc.ConfigName)
 	networkConfig := ConfigMapFromTestFile(t, network.ConfigName)
 	featureConfig := ConfigMapFromTestFile(t, cfgmap.FeaturesConfigName)
+	propagationConfig := ConfigMapFromTestFile(t, cfgmap.MetadataPropagationConfigName)
 
 	store.OnConfigChanged(domainConfig)
 	store.OnConfigChanged(gcConfig)
 	store.OnConfigChanged(networkConfig)
 	store.OnConfigChanged(featureConfig)
+	store.OnConfigChanged(propagationConfig)
 
 	config := FromContext(store.ToContext(context.Background()))
 
@@ -61,6 +63,13 @@ func TestStoreLoadWithContext(t *testing.T) {
 			t.Error("Unexpected controller config (-want, +got):", diff)
 		}
 	})
+
+	t.Run("metadata-propagation", func(t *testing.T) {
+		expected, _ := cfgmap.NewMetadataPropagationFromConfigMap(propagationConfig)
+		if diff := cmp.Diff(expected, config.MetadataPropagation); diff != "" {
+			t.Error("Unexpected metadata propagation config (-want, +got):", diff)
+		}
+	})
 }
 
 func TestStoreLoadWithContextOrDefaults(t *testing.T) {
@@ -23,6 +23,7 @@ import (
 	certificateinformer "knative.dev/networking/pkg/client/injection/informers/networking/v1alpha1/certificate"
 	ingressinformer "knative.dev/networking/pkg/client/injection/informers/networking/v1alpha1/ingress"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	namespaceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
 	serviceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/service"
 	servingclient "knative.dev/serving/pkg/client/injection/client"
 	configurationinformer "knative.dev/serving/pkg/client/injection/informers/serving/v1/configuration"
@@ -30,14 +31,19 @@ import (
 	routeinformer "knative.dev/serving/pkg/client/injection/informers/serving/v1/route"
 	routereconciler "knative.dev/serving/pkg/client/injection/reconciler/serving/v1/route"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/cache"
 	network "knative.dev/networking/pkg"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
 	"knative.dev/pkg/system"
 	"knative.dev/pkg/tracker"
+	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/networking"
 	"knative.dev/serving/pkg/reconciler/route/config"
 )
 
@@ -65,6 +71,7 @@ func newControllerWithClock(
 	revisionInformer := revisioninformer.Get(ctx)
 	ingressInformer := ingressinformer.Get(ctx)
 	certificateInformer := certificateinformer.Get(ctx)
+	namespaceInformer := namespaceinformer.Get(ctx)
 
 	c := &Reconciler{
 		kubeclient:          kubeclient.Get(ctx),
@@ -75,6 +82,7 @@ func newControllerWithClock(
 		serviceLister:       serviceInformer.Lister(),
 		ingressLister:       ingressInformer.Lister(),
 		certificateLister:   certificateInformer.Lister(),
+		namespaceLister:     namespaceInformer.Lister(),
 		clock:               clock,
 	}
 	impl := routereconciler.NewImpl(ctx, c, func(impl *controller.Impl) controller.Options {
@@ -102,6 +110,34 @@ func newControllerWithClock(
 	certificateInformer.Informer().AddEventHandler(handleControllerOf)
 	ingressInformer.Informer().AddEventHandler(handleControllerOf)
 
+	// Watch namespaces, since their labels opt their Routes in to revision
+	// previews and select the activator pool the previews are sent to.
+	namespaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldNs, newNs := oldObj.(*corev1.Namespace), newObj.(*corev1.Namespace)
+			if oldNs.Labels[serving.RevisionPreviewLabelKey] == newNs.Labels[serving.RevisionPreviewLabelKey] &&
+				oldNs.Labels[networking.ActivatorPoolLabelKey] == newNs.Labels[networking.ActivatorPoolLabelKey] {
+				return
+			}
+			impl.FilteredGlobalResync(pkgreconciler.NamespaceFilterFunc(newNs.Name), routeInformer.Informer())
+		},
+	})
+	// The previews fall back to the default activator pool while the
+	// selected one doesn't exist.
+	serviceInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: pkgreconciler.ChainFilterFuncs(
+			pkgreconciler.NamespaceFilterFunc(system.Namespace()),
+			func(obj interface{}) bool {
+				if mo, ok := obj.(metav1.Object); ok {
+					return networking.IsActivatorServiceName(mo.GetName())
+				}
+				return false
+			}),
+		Handler: controller.HandleAll(func(interface{}) {
+			impl.GlobalResync(routeInformer.Informer())
+		}),
+	})
+
 	c.tracker = tracker.New(impl.EnqueueKey, controller.GetTrackerLease(ctx))
 
 	// Make sure trackers are deleted once the observers are removed.
@@ -84,6 +84,11 @@ func TestNewRouteCallsSyncHandler(t *testing.T) {
 			Name:      cfgmap.FeaturesConfigName,
 			Namespace: system.Namespace(),
 		},
+	}, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cfgmap.MetadataPropagationConfigName,
+			Namespace: system.Namespace(),
+		},
 	})
 
 	servingClient := fakeservingclient.Get(ctx)
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"sort"
+	"strings"
 
 	"github.com/davecgh/go-spew/spew"
 	"go.uber.org/zap"
@@ -34,6 +35,7 @@ import (
 	ingress "knative.dev/networking/pkg/ingress"
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
 	"knative.dev/serving/pkg/activator"
 	apicfg "knative.dev/serving/pkg/apis/config"
 	"knative.dev/serving/pkg/apis/serving"
@@ -85,20 +87,21 @@ func MakeIngressWithRollout(
 	if err != nil {
 		return nil, err
 	}
+	propagation := config.FromContextOrDefaults(ctx).MetadataPropagation
 	return &netv1alpha1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      names.Ingress(r),
 			Namespace: r.Namespace,
-			Labels: kmeta.UnionMaps(r.Labels, map[string]string{
+			Labels: kmeta.UnionMaps(propagation.FilterLabels(apicfg.PropagateToIngress, r.Labels, nil), map[string]string{
 				serving.RouteLabelKey:          r.Name,
 				serving.RouteNamespaceLabelKey: r.Namespace,
 			}),
-			Annotations: kmeta.FilterMap(kmeta.UnionMaps(map[string]string{
+			Annotations: kmeta.UnionMaps(map[string]string{
 				networking.IngressClassAnnotationKey: ingressClass,
 				networking.RolloutAnnotationKey:      serializeRollout(ctx, ro),
-			}, r.GetAnnotations()), func(key string) bool {
-				return key == corev1.LastAppliedConfigAnnotation
-			}),
+			}, propagation.FilterAnnotations(apicfg.PropagateToIngress, r.GetAnnotations(), func(key string) bool {
+				return key != corev1.LastAppliedConfigAnnotation
+			})),
 			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(r)},
 		},
 		Spec: spec,
@@ -184,8 +187,24 @@ func makeIngressSpec(
 					rule.HTTP.Paths[0].AppendHeaders[network.TagHeaderName] = name
 				}
 			}
-			// If this is a public rule, we need to configure ACME challenge paths.
+			if name == traffic.DefaultTarget && tc.Preview != nil {
+				// Requests naming a Revision to preview are matched ahead
+				// of the traffic split.
+				rule.HTTP.Paths = append([]netv1alpha1.HTTPIngressPath{
+					makePreviewIngressPath(r.Namespace, tc.Preview)}, rule.HTTP.Paths...)
+			}
+			// If this is a public rule, the requests don't come from a
+			// Knative Service nor from an activator, and we need to
+			// configure ACME challenge paths.
 			if visibility == netv1alpha1.IngressVisibilityExternalIP {
+				for i := range rule.HTTP.Paths {
+					path := &rule.HTTP.Paths[i]
+					if path.AppendHeaders == nil {
+						path.AppendHeaders = make(map[string]string, 2)
+					}
+					path.AppendHeaders[activator.SourceServiceHeaderName] = activator.NoSourceService
+					path.AppendHeaders[activator.OverflowHeaderName] = activator.NoOverflow
+				}
 				rule.HTTP.Paths = append(
 					MakeACMEIngressPaths(acmeChallenges, domains...), rule.HTTP.Paths...)
 			}
@@ -284,6 +303,32 @@ func makeTagBasedRoutingIngressPaths(ns string, tc *traffic.Config, ro *traffic.
 	return paths
 }
 
+// makePreviewIngressPath returns the path routing the requests naming a
+// Revision to preview in the Knative-Serving-Preview-Revision header to the
+// activator, which looks the Revision up among the Route's Configurations
+// and checks that the request is authorized. HeaderMatch only matches exact
+// values, and an empty one matches any request carrying the header.
+func makePreviewIngressPath(ns string, preview *traffic.Preview) netv1alpha1.HTTPIngressPath {
+	// The activator sets the Revision header to the previewed Revision.
+	headers := appendHeaders(ns, "", &servingv1.TrafficTarget{})
+	delete(headers, activator.RevisionHeaderName)
+	headers[activator.PreviewConfigurationsHeaderName] = strings.Join(preview.Configurations, ",")
+	return netv1alpha1.HTTPIngressPath{
+		Headers: map[string]netv1alpha1.HeaderMatch{
+			activator.PreviewRevisionHeaderName: {Exact: ""},
+		},
+		Splits: []netv1alpha1.IngressBackendSplit{{
+			IngressBackend: netv1alpha1.IngressBackend{
+				ServiceNamespace: system.Namespace(),
+				ServiceName:      preview.ActivatorService,
+				ServicePort:      intstr.FromInt(networking.ServicePort(networking.ProtocolHTTP1)),
+			},
+			Percent:       100,
+			AppendHeaders: headers,
+		}},
+	}
+}
+
 func rolloutConfig(cfgName string, ros []*traffic.ConfigurationRollout) *traffic.ConfigurationRollout {
 	idx := sort.Search(len(ros), func(i int) bool {
 		return ros[i].ConfigurationName >= cfgName
@@ -318,11 +363,8 @@ func makeBaseIngressPath(ns string, targets traffic.RevisionTargets,
 					// Otherwise, the serverless services can't guarantee seamless positive handoff.
 					ServicePort: intstr.FromInt(networking.ServicePort(t.Protocol)),
 				},
-				Percent: int(*t.Percent),
-				AppendHeaders: map[string]string{
-					activator.RevisionHeaderName:      t.TrafficTarget.RevisionName,
-					activator.RevisionHeaderNamespace: ns,
-				},
+				Percent:       int(*t.Percent),
+				AppendHeaders: appendHeaders(ns, t.TrafficTarget.RevisionName, &t.TrafficTarget),
 			})
 		} else {
 			for i := range cfg.Revisions {
@@ -335,11 +377,8 @@ func makeBaseIngressPath(ns string, targets traffic.RevisionTargets,
 						// Otherwise, the serverless services can't guarantee seamless positive handoff.
 						ServicePort: intstr.FromInt(networking.ServicePort(t.Protocol)),
 					},
-					Percent: rev.Percent,
-					AppendHeaders: map[string]string{
-						activator.RevisionHeaderName:      rev.RevisionName,
-						activator.RevisionHeaderNamespace: ns,
-					},
+					Percent:       rev.Percent,
+					AppendHeaders: appendHeaders(ns, rev.RevisionName, &t.TrafficTarget),
 				})
 			}
 		}
@@ -349,3 +388,33 @@ func makeBaseIngressPath(ns string, targets traffic.RevisionTargets,
 		Splits: splits,
 	}
 }
+
+// appendHeaders returns the headers the ingress adds to the requests sent to
+// the given revision of the traffic target. The ingress can only add headers,
+// so header removal and path rewrites are handed down to the data-plane
+// through headers of their own. These are set for every target, so that the
+// data-plane never applies rewrites sent by a client.
+func appendHeaders(ns, revisionName string, tt *servingv1.TrafficTarget) map[string]string {
+	headers := make(map[string]string, 5)
+	if h := tt.Headers; h != nil {
+		for k, v := range h.Set {
+			headers[k] = v
+		}
+	}
+	headers[activator.RemoveHeadersHeaderName] = activator.NoRewrite
+	if h := tt.Headers; h != nil && len(h.Remove) > 0 {
+		headers[activator.RemoveHeadersHeaderName] = strings.Join(h.Remove, ",")
+	}
+	headers[activator.PathPrefixHeaderName] = activator.NoRewrite
+	headers[activator.PathReplacementHeaderName] = activator.NoRewrite
+	if pr := tt.PathPrefixRewrite; pr != nil {
+		headers[activator.PathPrefixHeaderName] = "/"
+		if pr.Prefix != "" {
+			headers[activator.PathPrefixHeaderName] = pr.Prefix
+		}
+		headers[activator.PathReplacementHeaderName] = pr.Replacement
+	}
+	headers[activator.RevisionHeaderName] = revisionName
+	headers[activator.RevisionHeaderNamespace] = ns
+	return headers
+}
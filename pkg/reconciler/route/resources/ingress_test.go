@@ -95,6 +95,46 @@ func TestMakeIngressCorrectMetadata(t *testing.T) {
 	}
 }
 
+func TestMakeIngressMetadataPropagation(t *testing.T) {
+	const ingressClass = "ng-ingress"
+	r := Route(ns, "test-route", WithRouteLabel(map[string]string{
+		"test-label":            "foo",
+		"contact.example.com/a": "hannibal",
+	}), WithRouteAnnotation(map[string]string{
+		"test-annotation":  "bar",
+		"cost.example.com": "1234",
+	}), WithRouteUID("1234-5678"), WithURL)
+
+	ctx := testContext()
+	config.FromContext(ctx).MetadataPropagation = &apicfg.MetadataPropagation{
+		Labels: map[apicfg.PropagationTarget]apicfg.PropagationRule{
+			apicfg.PropagateToIngress: {Deny: []string{"contact.example.com/*"}},
+		},
+		Annotations: map[apicfg.PropagationTarget]apicfg.PropagationRule{
+			apicfg.PropagateToIngress: {Allow: []string{"cost.*"}},
+		},
+	}
+	ia, err := MakeIngress(ctx, r, &traffic.Config{Targets: map[string]traffic.RevisionTargets{}}, nil, ingressClass)
+	if err != nil {
+		t.Fatal("Unexpected error", err)
+	}
+
+	if got, want := ia.Labels, map[string]string{
+		serving.RouteLabelKey:          "test-route",
+		serving.RouteNamespaceLabelKey: ns,
+		"test-label":                   "foo",
+	}; !cmp.Equal(got, want) {
+		t.Error("Unexpected labels (-want, +got):", cmp.Diff(want, got))
+	}
+	if got, want := ia.Annotations, map[string]string{
+		networking.IngressClassAnnotationKey: ingressClass,
+		networking.RolloutAnnotationKey:      emptyRollout,
+		"cost.example.com":                   "1234",
+	}; !cmp.Equal(got, want) {
+		t.Error("Unexpected annotations (-want, +got):", cmp.Diff(want, got))
+	}
+}
+
 func TestMakeIngressWithTaggedRollout(t *testing.T) {
 	const ingressClass = "ng-ingress"
 
@@ -270,8 +310,11 @@ func TestMakeIngressWithActualRollout(t *testing.T) {
 					},
 					Percent: 1,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "rune-01911",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "rune-01911",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}, {
 					IngressBackend: netv1alpha1.IngressBackend{
@@ -281,8 +324,11 @@ func TestMakeIngressWithActualRollout(t *testing.T) {
 					},
 					Percent: 41,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "valhalla-01981",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "valhalla-01981",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}, {
 					IngressBackend: netv1alpha1.IngressBackend{
@@ -292,8 +338,11 @@ func TestMakeIngressWithActualRollout(t *testing.T) {
 					},
 					Percent: 68,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "valhalla-01982",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "valhalla-01982",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}},
 			}},
@@ -313,8 +362,11 @@ func TestMakeIngressWithActualRollout(t *testing.T) {
 					},
 					Percent: 1,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "rune-01911",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "rune-01911",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}, {
 					IngressBackend: netv1alpha1.IngressBackend{
@@ -324,8 +376,11 @@ func TestMakeIngressWithActualRollout(t *testing.T) {
 					},
 					Percent: 41,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "valhalla-01981",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "valhalla-01981",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}, {
 					IngressBackend: netv1alpha1.IngressBackend{
@@ -335,10 +390,17 @@ func TestMakeIngressWithActualRollout(t *testing.T) {
 					},
 					Percent: 68,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "valhalla-01982",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "valhalla-01982",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}},
+				AppendHeaders: map[string]string{
+					"K-Source-Service":         "-",
+					"Knative-Serving-Overflow": "-",
+				},
 			}},
 		},
 		Visibility: netv1alpha1.IngressVisibilityExternalIP,
@@ -358,8 +420,11 @@ func TestMakeIngressWithActualRollout(t *testing.T) {
 					},
 					Percent: 60,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "thor-02018",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "thor-02018",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}, {
 					IngressBackend: netv1alpha1.IngressBackend{
@@ -369,8 +434,11 @@ func TestMakeIngressWithActualRollout(t *testing.T) {
 					},
 					Percent: 15,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "thor-02019",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "thor-02019",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}, {
 					IngressBackend: netv1alpha1.IngressBackend{
@@ -380,8 +448,11 @@ func TestMakeIngressWithActualRollout(t *testing.T) {
 					},
 					Percent: 5,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "thor-02020",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "thor-02020",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}, {
 					IngressBackend: netv1alpha1.IngressBackend{
@@ -391,8 +462,11 @@ func TestMakeIngressWithActualRollout(t *testing.T) {
 					},
 					Percent: 20,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "thor-beta",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "thor-beta",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}},
 			}},
@@ -412,8 +486,11 @@ func TestMakeIngressWithActualRollout(t *testing.T) {
 					},
 					Percent: 60,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "thor-02018",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "thor-02018",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}, {
 					IngressBackend: netv1alpha1.IngressBackend{
@@ -423,8 +500,11 @@ func TestMakeIngressWithActualRollout(t *testing.T) {
 					},
 					Percent: 15,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "thor-02019",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "thor-02019",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}, {
 					IngressBackend: netv1alpha1.IngressBackend{
@@ -434,8 +514,11 @@ func TestMakeIngressWithActualRollout(t *testing.T) {
 					},
 					Percent: 5,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "thor-02020",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "thor-02020",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}, {
 					IngressBackend: netv1alpha1.IngressBackend{
@@ -445,10 +528,17 @@ func TestMakeIngressWithActualRollout(t *testing.T) {
 					},
 					Percent: 20,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "thor-beta",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "thor-beta",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}},
+				AppendHeaders: map[string]string{
+					"K-Source-Service":         "-",
+					"Knative-Serving-Overflow": "-",
+				},
 			}},
 		},
 		Visibility: netv1alpha1.IngressVisibilityExternalIP,
@@ -509,8 +599,11 @@ func TestMakeIngressSpecCorrectRules(t *testing.T) {
 					},
 					Percent: 100,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "v2",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "v2",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}},
 			}},
@@ -530,10 +623,17 @@ func TestMakeIngressSpecCorrectRules(t *testing.T) {
 					},
 					Percent: 100,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "v2",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "v2",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}},
+				AppendHeaders: map[string]string{
+					"K-Source-Service":         "-",
+					"Knative-Serving-Overflow": "-",
+				},
 			}},
 		},
 		Visibility: netv1alpha1.IngressVisibilityExternalIP,
@@ -553,8 +653,11 @@ func TestMakeIngressSpecCorrectRules(t *testing.T) {
 					},
 					Percent: 100,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "v1",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "v1",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}},
 			}},
@@ -574,10 +677,17 @@ func TestMakeIngressSpecCorrectRules(t *testing.T) {
 					},
 					Percent: 100,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "v1",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "v1",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}},
+				AppendHeaders: map[string]string{
+					"K-Source-Service":         "-",
+					"Knative-Serving-Overflow": "-",
+				},
 			}},
 		},
 		Visibility: netv1alpha1.IngressVisibilityExternalIP,
@@ -595,6 +705,88 @@ func TestMakeIngressSpecCorrectRules(t *testing.T) {
 	}
 }
 
+func TestMakeIngressSpecCorrectRulesWithPreview(t *testing.T) {
+	targets := map[string]traffic.RevisionTargets{
+		traffic.DefaultTarget: {{
+			TrafficTarget: v1.TrafficTarget{
+				ConfigurationName: "config",
+				RevisionName:      "v2",
+				Percent:           ptr.Int64(100),
+			},
+		}},
+	}
+	preview := &traffic.Preview{
+		ActivatorService: "activator-service",
+		Configurations:   []string{"config", "other"},
+	}
+
+	r := Route(ns, "test-route", WithURL)
+
+	expected := []netv1alpha1.IngressRule{{
+		Hosts: []string{
+			"test-route." + ns,
+			"test-route." + ns + ".svc",
+			pkgnet.GetServiceHostname("test-route", ns),
+		},
+		HTTP: &netv1alpha1.HTTPIngressRuleValue{
+			Paths: []netv1alpha1.HTTPIngressPath{{
+				Headers: map[string]netv1alpha1.HeaderMatch{
+					"Knative-Serving-Preview-Revision": {Exact: ""},
+				},
+				Splits: []netv1alpha1.IngressBackendSplit{{
+					IngressBackend: netv1alpha1.IngressBackend{
+						ServiceNamespace: system.Namespace(),
+						ServiceName:      "activator-service",
+						ServicePort:      intstr.FromInt(80),
+					},
+					Percent: 100,
+					AppendHeaders: map[string]string{
+						"Knative-Serving-Namespace":              ns,
+						"Knative-Serving-Preview-Configurations": "config,other",
+						"Knative-Serving-Remove-Headers":         "-",
+						"Knative-Serving-Path-Prefix":            "-",
+						"Knative-Serving-Path-Replacement":       "-",
+					},
+				}},
+			}, {
+				Splits: []netv1alpha1.IngressBackendSplit{{
+					IngressBackend: netv1alpha1.IngressBackend{
+						ServiceNamespace: ns,
+						ServiceName:      "v2",
+						ServicePort:      intstr.FromInt(80),
+					},
+					Percent: 100,
+					AppendHeaders: map[string]string{
+						"Knative-Serving-Revision":         "v2",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
+					},
+				}},
+			}},
+		},
+		Visibility: netv1alpha1.IngressVisibilityClusterLocal,
+	}}
+
+	tc := &traffic.Config{
+		Targets: targets,
+		Visibility: map[string]netv1alpha1.IngressVisibility{
+			traffic.DefaultTarget: netv1alpha1.IngressVisibilityClusterLocal,
+		},
+		Preview: preview,
+	}
+	ro := tc.BuildRollout()
+	ci, err := makeIngressSpec(testContext(), r, nil /*tls*/, tc, ro)
+	if err != nil {
+		t.Error("Unexpected error", err)
+	}
+
+	if !cmp.Equal(expected, ci.Rules) {
+		t.Error("Unexpected rules (-want, +got):", cmp.Diff(expected, ci.Rules))
+	}
+}
+
 func TestMakeIngressSpecCorrectRuleVisibility(t *testing.T) {
 	cases := []struct {
 		name               string
@@ -718,8 +910,11 @@ func TestMakeIngressSpecCorrectRulesWithTagBasedRouting(t *testing.T) {
 					},
 					Percent: 100,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "v1",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "v1",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}},
 			}, {
@@ -734,8 +929,11 @@ func TestMakeIngressSpecCorrectRulesWithTagBasedRouting(t *testing.T) {
 					},
 					Percent: 100,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "v2",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "v2",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}},
 			}},
@@ -760,12 +958,21 @@ func TestMakeIngressSpecCorrectRulesWithTagBasedRouting(t *testing.T) {
 					},
 					Percent: 100,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "v1",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "v1",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}},
+				AppendHeaders: map[string]string{
+					"K-Source-Service":         "-",
+					"Knative-Serving-Overflow": "-",
+				},
 			}, {
 				AppendHeaders: map[string]string{
+					"K-Source-Service":             "-",
+					"Knative-Serving-Overflow":     "-",
 					network.DefaultRouteHeaderName: "true",
 				},
 				Splits: []netv1alpha1.IngressBackendSplit{{
@@ -776,8 +983,11 @@ func TestMakeIngressSpecCorrectRulesWithTagBasedRouting(t *testing.T) {
 					},
 					Percent: 100,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "v2",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "v2",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}},
 			}},
@@ -802,8 +1012,11 @@ func TestMakeIngressSpecCorrectRulesWithTagBasedRouting(t *testing.T) {
 					},
 					Percent: 100,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "v1",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "v1",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}},
 			}},
@@ -816,7 +1029,9 @@ func TestMakeIngressSpecCorrectRulesWithTagBasedRouting(t *testing.T) {
 		HTTP: &netv1alpha1.HTTPIngressRuleValue{
 			Paths: []netv1alpha1.HTTPIngressPath{{
 				AppendHeaders: map[string]string{
-					network.TagHeaderName: "v1",
+					"K-Source-Service":         "-",
+					"Knative-Serving-Overflow": "-",
+					network.TagHeaderName:      "v1",
 				},
 				Splits: []netv1alpha1.IngressBackendSplit{{
 					IngressBackend: netv1alpha1.IngressBackend{
@@ -826,8 +1041,11 @@ func TestMakeIngressSpecCorrectRulesWithTagBasedRouting(t *testing.T) {
 					},
 					Percent: 100,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "v1",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "v1",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}},
 			}},
@@ -883,8 +1101,65 @@ func TestMakeIngressRuleVanilla(t *testing.T) {
 					},
 					Percent: 100,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "revision-shark",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "revision-shark",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
+					},
+				}},
+			}},
+		},
+		Visibility: netv1alpha1.IngressVisibilityExternalIP,
+	}
+
+	if !cmp.Equal(expected, rule) {
+		t.Error("Unexpected rule (-want, +got):", cmp.Diff(expected, rule))
+	}
+}
+
+func TestMakeIngressRuleRewrites(t *testing.T) {
+	targets := traffic.RevisionTargets{{
+		TrafficTarget: v1.TrafficTarget{
+			ConfigurationName: "config",
+			RevisionName:      "revision-shark",
+			Percent:           ptr.Int64(100),
+			Headers: &v1.RequestHeaders{
+				Set:    map[string]string{"X-Api-Version": "2"},
+				Remove: []string{"Cookie", "X-Debug"},
+			},
+			PathPrefixRewrite: &v1.PathPrefixRewrite{
+				Prefix:      "/v2",
+				Replacement: "/",
+			},
+		},
+	}}
+	tc := &traffic.Config{
+		Targets: map[string]traffic.RevisionTargets{
+			traffic.DefaultTarget: targets,
+		},
+	}
+	ro := tc.BuildRollout()
+	rule := makeIngressRule([]string{"a.com"}, ns,
+		netv1alpha1.IngressVisibilityExternalIP, targets, ro.RolloutsByTag(traffic.DefaultTarget))
+	expected := netv1alpha1.IngressRule{
+		Hosts: []string{"a.com"},
+		HTTP: &netv1alpha1.HTTPIngressRuleValue{
+			Paths: []netv1alpha1.HTTPIngressPath{{
+				Splits: []netv1alpha1.IngressBackendSplit{{
+					IngressBackend: netv1alpha1.IngressBackend{
+						ServiceNamespace: ns,
+						ServiceName:      "revision-shark",
+						ServicePort:      intstr.FromInt(80),
+					},
+					Percent: 100,
+					AppendHeaders: map[string]string{
+						"Knative-Serving-Revision":         "revision-shark",
+						"Knative-Serving-Namespace":        ns,
+						"X-Api-Version":                    "2",
+						"Knative-Serving-Remove-Headers":   "Cookie,X-Debug",
+						"Knative-Serving-Path-Prefix":      "/v2",
+						"Knative-Serving-Path-Replacement": "/",
 					},
 				}},
 			}},
@@ -933,8 +1208,11 @@ func TestMakeIngressRuleZeroPercentTarget(t *testing.T) {
 					},
 					Percent: 100,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "revision-dolphin",
-						"Knative-Serving-Namespace": ns,
+						"Knative-Serving-Revision":         "revision-dolphin",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}},
 			}},
@@ -983,8 +1261,11 @@ func TestMakeIngressRuleTwoTargets(t *testing.T) {
 					},
 					Percent: 80,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Namespace": ns,
-						"Knative-Serving-Revision":  "revision-beluga",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
+						"Knative-Serving-Revision":         "revision-beluga",
 					},
 				}, {
 					IngressBackend: netv1alpha1.IngressBackend{
@@ -994,8 +1275,11 @@ func TestMakeIngressRuleTwoTargets(t *testing.T) {
 					},
 					Percent: 20,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Namespace": ns,
-						"Knative-Serving-Revision":  "new-revision-narwhal",
+						"Knative-Serving-Namespace":        ns,
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
+						"Knative-Serving-Revision":         "new-revision-narwhal",
 					},
 				}},
 			}},
@@ -1122,8 +1406,11 @@ func TestMakeIngressACMEChallenges(t *testing.T) {
 					},
 					Percent: 100,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "v2",
-						"Knative-Serving-Namespace": "test-ns",
+						"Knative-Serving-Revision":         "v2",
+						"Knative-Serving-Namespace":        "test-ns",
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}},
 			}}},
@@ -1152,10 +1439,17 @@ func TestMakeIngressACMEChallenges(t *testing.T) {
 					},
 					Percent: 100,
 					AppendHeaders: map[string]string{
-						"Knative-Serving-Revision":  "v2",
-						"Knative-Serving-Namespace": "test-ns",
+						"Knative-Serving-Revision":         "v2",
+						"Knative-Serving-Namespace":        "test-ns",
+						"Knative-Serving-Remove-Headers":   "-",
+						"Knative-Serving-Path-Prefix":      "-",
+						"Knative-Serving-Path-Replacement": "-",
 					},
 				}},
+				AppendHeaders: map[string]string{
+					"K-Source-Service":         "-",
+					"Knative-Serving-Overflow": "-",
+				},
 			}}},
 	}}
 
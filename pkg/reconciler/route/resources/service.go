@@ -30,8 +30,10 @@ import (
 	"knative.dev/networking/pkg/apis/networking"
 	netv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
 	"knative.dev/pkg/kmeta"
+	apicfg "knative.dev/serving/pkg/apis/config"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/reconciler/route/config"
 	"knative.dev/serving/pkg/reconciler/route/domains"
 )
 
@@ -108,6 +110,11 @@ func makeK8sService(ctx context.Context, route *v1.Route, targetName string) (*c
 	svcLabels := map[string]string{
 		serving.RouteLabelKey: route.Name,
 	}
+	propagation := config.FromContextOrDefaults(ctx).MetadataPropagation
+	anns := route.GetAnnotations()
+	if anns != nil {
+		anns = propagation.FilterAnnotations(apicfg.PropagateToService, anns, nil)
+	}
 
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -117,12 +124,12 @@ func makeK8sService(ctx context.Context, route *v1.Route, targetName string) (*c
 				// This service is owned by the Route.
 				*kmeta.NewControllerRef(route),
 			},
-			Labels: kmeta.UnionMaps(kmeta.FilterMap(route.GetLabels(), func(key string) bool {
+			Labels: kmeta.UnionMaps(propagation.FilterLabels(apicfg.PropagateToService, route.GetLabels(), func(key string) bool {
 				// Do not propagate the visibility label from Route as users may want to set the label
 				// in the specific k8s svc for subroute. see https://github.com/knative/serving/pull/4560.
-				return key == network.VisibilityLabelKey
+				return key != network.VisibilityLabelKey
 			}), svcLabels),
-			Annotations: route.GetAnnotations(),
+			Annotations: anns,
 		},
 	}, nil
 }
@@ -267,6 +267,7 @@ func TestMakeK8sPlaceholderService(t *testing.T) {
 		expectedAnnos  map[string]string
 		wantErr        bool
 		route          *v1.Route
+		propagation    *apiConfig.MetadataPropagation
 	}{{
 		name:  "default public domain route",
 		route: r,
@@ -306,6 +307,34 @@ func TestMakeK8sPlaceholderService(t *testing.T) {
 			"route-anno": "bar",
 		},
 		wantErr: false,
+	}, {
+		name: "metadata propagation policy is applied",
+		route: Route("test-ns", "test-route",
+			WithRouteLabel(map[string]string{"route-label": "foo", "contact.example.com/a": "b"}),
+			WithRouteAnnotation(map[string]string{"route-anno": "bar"})),
+		propagation: &apiConfig.MetadataPropagation{
+			Labels: map[apiConfig.PropagationTarget]apiConfig.PropagationRule{
+				apiConfig.PropagateToService: {Deny: []string{"contact.example.com/*"}},
+			},
+			Annotations: map[apiConfig.PropagationTarget]apiConfig.PropagationRule{
+				apiConfig.PropagateToService: {Deny: []string{"*"}},
+			},
+		},
+		expectedSpec: corev1.ServiceSpec{
+			Type:            corev1.ServiceTypeExternalName,
+			ExternalName:    "foo-test-route.test-ns.example.com",
+			SessionAffinity: corev1.ServiceAffinityNone,
+			Ports: []corev1.ServicePort{{
+				Name:       networking.ServicePortNameH2C,
+				Port:       int32(80),
+				TargetPort: intstr.FromInt(80),
+			}},
+		},
+		expectedLabels: map[string]string{
+			serving.RouteLabelKey: "test-route",
+			"route-label":         "foo",
+		},
+		expectedAnnos: map[string]string{},
 	}, {
 		name:  "cluster local route",
 		route: Route("test-ns", "test-route", WithRouteLabel(map[string]string{network.VisibilityLabelKey: serving.VisibilityClusterLocal})),
@@ -326,6 +355,7 @@ func TestMakeK8sPlaceholderService(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := testConfig()
+			cfg.MetadataPropagation = tt.propagation
 			ctx := config.ToContext(context.Background(), cfg)
 			target := traffic.RevisionTarget{
 				TrafficTarget: v1.TrafficTarget{
@@ -19,12 +19,14 @@ package route
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	kubelabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -38,14 +40,18 @@ import (
 	networkinglisters "knative.dev/networking/pkg/client/listers/networking/v1alpha1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 	"knative.dev/pkg/system"
 	"knative.dev/pkg/tracker"
+	apicfg "knative.dev/serving/pkg/apis/config"
+	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	clientset "knative.dev/serving/pkg/client/clientset/versioned"
 	routereconciler "knative.dev/serving/pkg/client/injection/reconciler/serving/v1/route"
 	listers "knative.dev/serving/pkg/client/listers/serving/v1"
+	servingnetworking "knative.dev/serving/pkg/networking"
 	kaccessor "knative.dev/serving/pkg/reconciler/accessor"
 	networkaccessor "knative.dev/serving/pkg/reconciler/accessor/networking"
 	"knative.dev/serving/pkg/reconciler/route/config"
@@ -69,6 +75,7 @@ type Reconciler struct {
 	serviceLister       corev1listers.ServiceLister
 	ingressLister       networkinglisters.IngressLister
 	certificateLister   networkinglisters.CertificateLister
+	namespaceLister     corev1listers.NamespaceLister
 	tracker             tracker.Interface
 
 	clock        system.Clock
@@ -270,6 +277,78 @@ func (c *Reconciler) tls(ctx context.Context, host string, r *v1.Route, traffic
 	return tls, acmeChallenges, nil
 }
 
+// withoutExpiredTags returns the route with the traffic targets whose tags
+// expired removed from its spec, and schedules a resync for the next expiry.
+func (c *Reconciler) withoutExpiredTags(ctx context.Context, r *v1.Route) *v1.Route {
+	expired, next := v1.ExpiredTags(r.Annotations, r.Spec.Traffic, c.clock.Now())
+	if next > 0 {
+		c.enqueueAfter(r, next)
+	}
+	if expired.Len() == 0 {
+		return r
+	}
+	// Only report the tags once, when they are dropped from the status.
+	for _, tt := range r.Status.Traffic {
+		if expired.Has(tt.Tag) {
+			controller.GetEventRecorder(ctx).Eventf(r, corev1.EventTypeNormal, "TagExpired",
+				"Removed expired traffic tag %q", tt.Tag)
+		}
+	}
+	effective := r.DeepCopy()
+	effective.Spec.Traffic = v1.WithoutTags(r.Spec.Traffic, expired)
+	return effective
+}
+
+// preview returns how the Revisions of the Route's Configurations are
+// previewed by name, nil if the feature is disabled or the Route's namespace
+// didn't opt in.
+func (c *Reconciler) preview(ctx context.Context, r *v1.Route, t *traffic.Config) (*traffic.Preview, error) {
+	if config.FromContext(ctx).Features.RevisionPreview != apicfg.Enabled || len(t.Configurations) == 0 {
+		return nil, nil
+	}
+	ns, err := c.namespaceLister.Get(r.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get namespace %q: %w", r.Namespace, err)
+	}
+	if ns.Labels[serving.RevisionPreviewLabelKey] != serving.RevisionPreviewEnabled {
+		return nil, nil
+	}
+
+	activatorService, err := c.activatorService(ctx, r, ns)
+	if err != nil {
+		return nil, err
+	}
+	preview := &traffic.Preview{
+		ActivatorService: activatorService,
+		Configurations:   make([]string, 0, len(t.Configurations)),
+	}
+	for name := range t.Configurations {
+		preview.Configurations = append(preview.Configurations, name)
+	}
+	sort.Strings(preview.Configurations)
+	return preview, nil
+}
+
+// activatorService returns the name of the service of the activator pool
+// selected by the namespace, or of the default activator pool when the
+// selected pool does not exist.
+func (c *Reconciler) activatorService(ctx context.Context, r *v1.Route, ns *corev1.Namespace) (string, error) {
+	pool := ns.Labels[servingnetworking.ActivatorPoolLabelKey]
+	if pool == "" {
+		return servingnetworking.ActivatorServiceName, nil
+	}
+	name := servingnetworking.ActivatorPoolServiceName(pool)
+	if _, err := c.serviceLister.Services(system.Namespace()).Get(name); err == nil {
+		return name, nil
+	} else if !apierrs.IsNotFound(err) {
+		return "", fmt.Errorf("failed to get activator service %q: %w", name, err)
+	}
+	controller.GetEventRecorder(ctx).Eventf(r, corev1.EventTypeWarning, "ActivatorPoolNotFound",
+		"Activator pool %q selected by namespace %q does not exist, using the default activator pool",
+		pool, ns.Name)
+	return servingnetworking.ActivatorServiceName, nil
+}
+
 // configureTraffic attempts to configure traffic based on the RouteSpec.  If there are missing
 // targets (e.g. Configurations without a Ready Revision, or Revision that isn't Ready or Inactive),
 // no traffic will be configured.
@@ -278,7 +357,7 @@ func (c *Reconciler) tls(ctx context.Context, host string, r *v1.Route, traffic
 // mark AllTrafficAssigned = False, with a message referring to one of the missing target.
 func (c *Reconciler) configureTraffic(ctx context.Context, r *v1.Route) (*traffic.Config, error) {
 	logger := logging.FromContext(ctx)
-	t, trafficErr := traffic.BuildTrafficConfiguration(c.configurationLister, c.revisionLister, r)
+	t, trafficErr := traffic.BuildTrafficConfiguration(c.configurationLister, c.revisionLister, c.withoutExpiredTags(ctx, r))
 	if t == nil {
 		return nil, trafficErr
 	}
@@ -289,6 +368,9 @@ func (c *Reconciler) configureTraffic(ctx context.Context, r *v1.Route) (*traffi
 		return nil, err
 	}
 	t.Visibility = visibility
+	if t.Preview, err = c.preview(ctx, r, t); err != nil {
+		return nil, err
+	}
 	// Update the Route URL.
 	if err := c.updateRouteStatusURL(ctx, r, t.Visibility); err != nil {
 		return nil, err
@@ -312,6 +394,18 @@ func (c *Reconciler) configureTraffic(ctx context.Context, r *v1.Route) (*traffi
 			return nil, err
 		}
 	}
+	// Revisions chosen by selector may change whenever revisions are
+	// (re)labeled, so track all revisions matching the selectors.
+	for _, selector := range t.RevisionSelectors {
+		if err := c.tracker.TrackReference(tracker.Reference{
+			APIVersion: v1.SchemeGroupVersion.String(),
+			Kind:       "Revision",
+			Namespace:  r.Namespace,
+			Selector:   selector,
+		}, r); err != nil {
+			return nil, err
+		}
+	}
 	for _, revision := range t.Revisions {
 		if revision.Status.IsActivationRequired() {
 			logger.Infof("Revision %s/%s is inactive", revision.Namespace, revision.Name)
@@ -27,6 +27,7 @@ import (
 	fakenetworkingclient "knative.dev/networking/pkg/client/injection/client/fake"
 	_ "knative.dev/networking/pkg/client/injection/informers/networking/v1alpha1/certificate/fake"
 	fakeingressinformer "knative.dev/networking/pkg/client/injection/informers/networking/v1alpha1/ingress/fake"
+	fakenamespaceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace/fake"
 	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/service/fake"
 	fakeservingclient "knative.dev/serving/pkg/client/injection/client/fake"
 	fakecfginformer "knative.dev/serving/pkg/client/injection/informers/serving/v1/configuration/fake"
@@ -47,16 +48,19 @@ import (
 	network "knative.dev/networking/pkg"
 	"knative.dev/networking/pkg/apis/networking"
 	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/apis"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	pkgnet "knative.dev/pkg/network"
 	"knative.dev/pkg/ptr"
 	"knative.dev/pkg/reconciler"
 	"knative.dev/pkg/system"
+	"knative.dev/serving/pkg/activator"
 	cfgmap "knative.dev/serving/pkg/apis/config"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	"knative.dev/serving/pkg/gc"
+	servingnetworking "knative.dev/serving/pkg/networking"
 	"knative.dev/serving/pkg/reconciler/route/config"
 	"knative.dev/serving/pkg/reconciler/route/domains"
 
@@ -122,6 +126,11 @@ func newTestSetup(t *testing.T, opts ...reconcilerOption) (
 			Name:      cfgmap.FeaturesConfigName,
 			Namespace: system.Namespace(),
 		},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cfgmap.MetadataPropagationConfigName,
+			Namespace: system.Namespace(),
+		},
 	}} {
 		configMapWatcher.OnChange(cfg)
 	}
@@ -230,8 +239,11 @@ func TestCreateRouteForOneReserveRevision(t *testing.T) {
 						},
 						Percent: 100,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  "test-rev",
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         "test-rev",
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
 				}},
@@ -251,10 +263,17 @@ func TestCreateRouteForOneReserveRevision(t *testing.T) {
 						},
 						Percent: 100,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  "test-rev",
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         "test-rev",
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
+					AppendHeaders: map[string]string{
+						"K-Source-Service":         "-",
+						"Knative-Serving-Overflow": "-",
+					},
 				}},
 			},
 		}},
@@ -358,8 +377,11 @@ func TestCreateRouteWithMultipleTargets(t *testing.T) {
 						},
 						Percent: 90,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  cfgrev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         cfgrev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}, {
 						IngressBackend: v1alpha1.IngressBackend{
@@ -369,8 +391,11 @@ func TestCreateRouteWithMultipleTargets(t *testing.T) {
 						},
 						Percent: 10,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  rev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         rev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
 				}},
@@ -390,8 +415,11 @@ func TestCreateRouteWithMultipleTargets(t *testing.T) {
 						},
 						Percent: 90,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  cfgrev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         cfgrev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}, {
 						IngressBackend: v1alpha1.IngressBackend{
@@ -401,10 +429,17 @@ func TestCreateRouteWithMultipleTargets(t *testing.T) {
 						},
 						Percent: 10,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  rev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         rev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
+					AppendHeaders: map[string]string{
+						"K-Source-Service":         "-",
+						"Knative-Serving-Overflow": "-",
+					},
 				}},
 			},
 			Visibility: v1alpha1.IngressVisibilityExternalIP,
@@ -475,8 +510,11 @@ func TestCreateRouteWithOneTargetReserve(t *testing.T) {
 						},
 						Percent: 90,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  cfgrev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         cfgrev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}, {
 						IngressBackend: v1alpha1.IngressBackend{
@@ -486,8 +524,11 @@ func TestCreateRouteWithOneTargetReserve(t *testing.T) {
 						},
 						Percent: 10,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  rev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         rev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
 				}},
@@ -507,8 +548,11 @@ func TestCreateRouteWithOneTargetReserve(t *testing.T) {
 						},
 						Percent: 90,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  cfgrev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         cfgrev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}, {
 						IngressBackend: v1alpha1.IngressBackend{
@@ -518,10 +562,17 @@ func TestCreateRouteWithOneTargetReserve(t *testing.T) {
 						},
 						Percent: 10,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  rev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         rev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
+					AppendHeaders: map[string]string{
+						"K-Source-Service":         "-",
+						"Knative-Serving-Overflow": "-",
+					},
 				}},
 			},
 			Visibility: v1alpha1.IngressVisibilityExternalIP,
@@ -606,8 +657,11 @@ func TestCreateRouteWithDuplicateTargets(t *testing.T) {
 						},
 						Percent: 50,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  cfgrev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         cfgrev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}, {
 						IngressBackend: v1alpha1.IngressBackend{
@@ -617,8 +671,11 @@ func TestCreateRouteWithDuplicateTargets(t *testing.T) {
 						},
 						Percent: 50,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  rev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         rev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
 				}},
@@ -638,8 +695,11 @@ func TestCreateRouteWithDuplicateTargets(t *testing.T) {
 						},
 						Percent: 50,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  cfgrev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         cfgrev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}, {
 						IngressBackend: v1alpha1.IngressBackend{
@@ -649,10 +709,17 @@ func TestCreateRouteWithDuplicateTargets(t *testing.T) {
 						},
 						Percent: 50,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  rev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         rev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
+					AppendHeaders: map[string]string{
+						"K-Source-Service":         "-",
+						"Knative-Serving-Overflow": "-",
+					},
 				}},
 			},
 			Visibility: v1alpha1.IngressVisibilityExternalIP,
@@ -672,8 +739,11 @@ func TestCreateRouteWithDuplicateTargets(t *testing.T) {
 						},
 						Percent: 100,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  rev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         rev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
 				}},
@@ -693,10 +763,17 @@ func TestCreateRouteWithDuplicateTargets(t *testing.T) {
 						},
 						Percent: 100,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  rev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         rev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
+					AppendHeaders: map[string]string{
+						"K-Source-Service":         "-",
+						"Knative-Serving-Overflow": "-",
+					},
 				}},
 			},
 			Visibility: v1alpha1.IngressVisibilityExternalIP,
@@ -716,8 +793,11 @@ func TestCreateRouteWithDuplicateTargets(t *testing.T) {
 						},
 						Percent: 100,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  rev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         rev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
 				}},
@@ -737,10 +817,17 @@ func TestCreateRouteWithDuplicateTargets(t *testing.T) {
 						},
 						Percent: 100,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  rev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         rev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
+					AppendHeaders: map[string]string{
+						"K-Source-Service":         "-",
+						"Knative-Serving-Overflow": "-",
+					},
 				}},
 			},
 			Visibility: v1alpha1.IngressVisibilityExternalIP,
@@ -812,8 +899,11 @@ func TestCreateRouteWithNamedTargets(t *testing.T) {
 						},
 						Percent: 50,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  rev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         rev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}, {
 						IngressBackend: v1alpha1.IngressBackend{
@@ -823,8 +913,11 @@ func TestCreateRouteWithNamedTargets(t *testing.T) {
 						},
 						Percent: 50,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  cfgrev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         cfgrev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
 				}},
@@ -844,8 +937,11 @@ func TestCreateRouteWithNamedTargets(t *testing.T) {
 						},
 						Percent: 50,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  rev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         rev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}, {
 						IngressBackend: v1alpha1.IngressBackend{
@@ -855,10 +951,17 @@ func TestCreateRouteWithNamedTargets(t *testing.T) {
 						},
 						Percent: 50,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  cfgrev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         cfgrev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
+					AppendHeaders: map[string]string{
+						"K-Source-Service":         "-",
+						"Knative-Serving-Overflow": "-",
+					},
 				}},
 			},
 			Visibility: v1alpha1.IngressVisibilityExternalIP,
@@ -878,8 +981,11 @@ func TestCreateRouteWithNamedTargets(t *testing.T) {
 						},
 						Percent: 100,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  cfgrev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         cfgrev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
 				}},
@@ -899,10 +1005,17 @@ func TestCreateRouteWithNamedTargets(t *testing.T) {
 						},
 						Percent: 100,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  cfgrev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         cfgrev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
+					AppendHeaders: map[string]string{
+						"K-Source-Service":         "-",
+						"Knative-Serving-Overflow": "-",
+					},
 				}},
 			},
 			Visibility: v1alpha1.IngressVisibilityExternalIP,
@@ -922,8 +1035,11 @@ func TestCreateRouteWithNamedTargets(t *testing.T) {
 						},
 						Percent: 100,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  rev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         rev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
 				}},
@@ -943,10 +1059,17 @@ func TestCreateRouteWithNamedTargets(t *testing.T) {
 						},
 						Percent: 100,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  rev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         rev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
+					AppendHeaders: map[string]string{
+						"K-Source-Service":         "-",
+						"Knative-Serving-Overflow": "-",
+					},
 				}},
 			},
 			Visibility: v1alpha1.IngressVisibilityExternalIP,
@@ -1032,8 +1155,11 @@ func TestCreateRouteWithNamedTargetsAndTagBasedRouting(t *testing.T) {
 							},
 							Percent: 100,
 							AppendHeaders: map[string]string{
-								"Knative-Serving-Namespace": "test",
-								"Knative-Serving-Revision":  "p-deadbeef",
+								"Knative-Serving-Namespace":        "test",
+								"Knative-Serving-Remove-Headers":   "-",
+								"Knative-Serving-Path-Prefix":      "-",
+								"Knative-Serving-Path-Replacement": "-",
+								"Knative-Serving-Revision":         "p-deadbeef",
 							},
 						},
 					},
@@ -1052,8 +1178,11 @@ func TestCreateRouteWithNamedTargetsAndTagBasedRouting(t *testing.T) {
 							},
 							Percent: 100,
 							AppendHeaders: map[string]string{
-								"Knative-Serving-Namespace": "test",
-								"Knative-Serving-Revision":  "test-rev",
+								"Knative-Serving-Namespace":        "test",
+								"Knative-Serving-Remove-Headers":   "-",
+								"Knative-Serving-Path-Prefix":      "-",
+								"Knative-Serving-Path-Replacement": "-",
+								"Knative-Serving-Revision":         "test-rev",
 							},
 						},
 					},
@@ -1069,8 +1198,11 @@ func TestCreateRouteWithNamedTargetsAndTagBasedRouting(t *testing.T) {
 						},
 						Percent: 50,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  rev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         rev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}, {
 						IngressBackend: v1alpha1.IngressBackend{
@@ -1080,8 +1212,11 @@ func TestCreateRouteWithNamedTargetsAndTagBasedRouting(t *testing.T) {
 						},
 						Percent: 50,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  cfgrev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         cfgrev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
 				}},
@@ -1107,11 +1242,18 @@ func TestCreateRouteWithNamedTargetsAndTagBasedRouting(t *testing.T) {
 							},
 							Percent: 100,
 							AppendHeaders: map[string]string{
-								"Knative-Serving-Namespace": "test",
-								"Knative-Serving-Revision":  "p-deadbeef",
+								"Knative-Serving-Namespace":        "test",
+								"Knative-Serving-Remove-Headers":   "-",
+								"Knative-Serving-Path-Prefix":      "-",
+								"Knative-Serving-Path-Replacement": "-",
+								"Knative-Serving-Revision":         "p-deadbeef",
 							},
 						},
 					},
+					AppendHeaders: map[string]string{
+						"K-Source-Service":         "-",
+						"Knative-Serving-Overflow": "-",
+					},
 				}, {
 					Headers: map[string]v1alpha1.HeaderMatch{
 						network.TagHeaderName: {
@@ -1127,13 +1269,22 @@ func TestCreateRouteWithNamedTargetsAndTagBasedRouting(t *testing.T) {
 							},
 							Percent: 100,
 							AppendHeaders: map[string]string{
-								"Knative-Serving-Namespace": "test",
-								"Knative-Serving-Revision":  "test-rev",
+								"Knative-Serving-Namespace":        "test",
+								"Knative-Serving-Remove-Headers":   "-",
+								"Knative-Serving-Path-Prefix":      "-",
+								"Knative-Serving-Path-Replacement": "-",
+								"Knative-Serving-Revision":         "test-rev",
 							},
 						},
 					},
+					AppendHeaders: map[string]string{
+						"K-Source-Service":         "-",
+						"Knative-Serving-Overflow": "-",
+					},
 				}, {
 					AppendHeaders: map[string]string{
+						"K-Source-Service":             "-",
+						"Knative-Serving-Overflow":     "-",
 						network.DefaultRouteHeaderName: "true",
 					},
 					Splits: []v1alpha1.IngressBackendSplit{{
@@ -1144,8 +1295,11 @@ func TestCreateRouteWithNamedTargetsAndTagBasedRouting(t *testing.T) {
 						},
 						Percent: 50,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  rev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         rev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}, {
 						IngressBackend: v1alpha1.IngressBackend{
@@ -1155,8 +1309,11 @@ func TestCreateRouteWithNamedTargetsAndTagBasedRouting(t *testing.T) {
 						},
 						Percent: 50,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  cfgrev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         cfgrev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
 				}},
@@ -1178,8 +1335,11 @@ func TestCreateRouteWithNamedTargetsAndTagBasedRouting(t *testing.T) {
 						},
 						Percent: 100,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  cfgrev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         cfgrev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
 					AppendHeaders: map[string]string{
@@ -1202,12 +1362,17 @@ func TestCreateRouteWithNamedTargetsAndTagBasedRouting(t *testing.T) {
 						},
 						Percent: 100,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  cfgrev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         cfgrev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
 					AppendHeaders: map[string]string{
-						network.TagHeaderName: "bar",
+						"K-Source-Service":         "-",
+						"Knative-Serving-Overflow": "-",
+						network.TagHeaderName:      "bar",
 					},
 				}},
 			},
@@ -1228,8 +1393,11 @@ func TestCreateRouteWithNamedTargetsAndTagBasedRouting(t *testing.T) {
 						},
 						Percent: 100,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  rev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         rev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
 					AppendHeaders: map[string]string{
@@ -1252,12 +1420,17 @@ func TestCreateRouteWithNamedTargetsAndTagBasedRouting(t *testing.T) {
 						},
 						Percent: 100,
 						AppendHeaders: map[string]string{
-							"Knative-Serving-Revision":  rev.Name,
-							"Knative-Serving-Namespace": testNamespace,
+							"Knative-Serving-Revision":         rev.Name,
+							"Knative-Serving-Namespace":        testNamespace,
+							"Knative-Serving-Remove-Headers":   "-",
+							"Knative-Serving-Path-Prefix":      "-",
+							"Knative-Serving-Path-Replacement": "-",
 						},
 					}},
 					AppendHeaders: map[string]string{
-						network.TagHeaderName: "foo",
+						"K-Source-Service":         "-",
+						"Knative-Serving-Overflow": "-",
+						network.TagHeaderName:      "foo",
 					},
 				}},
 			},
@@ -1271,6 +1444,185 @@ func TestCreateRouteWithNamedTargetsAndTagBasedRouting(t *testing.T) {
 	}
 }
 
+func TestCreateRouteWithExpiredTag(t *testing.T) {
+	ctx, _, ctl, _, cf := newTestSetup(t)
+	defer cf()
+
+	fakeRecorder := controller.GetEventRecorder(ctx).(*record.FakeRecorder)
+
+	rev := Revision(testNamespace, "test-rev", MarkRevisionReady, WithK8sServiceName)
+	fakeservingclient.Get(ctx).ServingV1().Revisions(testNamespace).Create(ctx, rev, metav1.CreateOptions{})
+	fakerevisioninformer.Get(ctx).Informer().GetIndexer().Add(rev)
+	preview := Revision(testNamespace, "preview-rev", MarkRevisionReady, WithK8sServiceName)
+	fakeservingclient.Get(ctx).ServingV1().Revisions(testNamespace).Create(ctx, preview, metav1.CreateOptions{})
+	fakerevisioninformer.Get(ctx).Informer().GetIndexer().Add(preview)
+
+	// A route whose preview tag expired an hour ago.
+	route := Route(testNamespace, "test-route", WithSpecTraffic(
+		v1.TrafficTarget{
+			RevisionName: "test-rev",
+			Percent:      ptr.Int64(100),
+		}, v1.TrafficTarget{
+			Tag:          "preview",
+			RevisionName: "preview-rev",
+			Percent:      ptr.Int64(0),
+		}), WithRouteAnnotation(map[string]string{
+		serving.TagExpiryAnnotationPrefix + "preview": time.Now().Add(-time.Hour).Format(time.RFC3339),
+	}), WithStatusTraffic(
+		v1.TrafficTarget{
+			RevisionName: "test-rev",
+			Percent:      ptr.Int64(100),
+		}, v1.TrafficTarget{
+			Tag:          "preview",
+			RevisionName: "preview-rev",
+			Percent:      ptr.Int64(0),
+			URL: &apis.URL{
+				Scheme: "http",
+				Host:   "preview-test-route.test.example.com",
+			},
+		}))
+	fakeservingclient.Get(ctx).ServingV1().Routes(testNamespace).Create(ctx, route, metav1.CreateOptions{})
+	fakerouteinformer.Get(ctx).Informer().GetIndexer().Add(route)
+
+	ctl.Reconciler.Reconcile(context.Background(), KeyOrDie(route))
+
+	ci := getRouteIngressFromClient(ctx, t, route)
+	for _, rule := range ci.Spec.Rules {
+		for _, host := range rule.Hosts {
+			if strings.HasPrefix(host, "preview-") {
+				t.Errorf("Ingress host %q is for the expired tag", host)
+			}
+		}
+	}
+
+	select {
+	case got := <-fakeRecorder.Events:
+		const want = `Normal TagExpired Removed expired traffic tag "preview"`
+		if got != want {
+			t.Errorf("<-Events = %s, wanted %s", got, want)
+		}
+	case <-time.After(3 * time.Second):
+		t.Error("timed out waiting for expected events.")
+	}
+}
+
+func TestCreateRouteWithRevisionPreview(t *testing.T) {
+	ctx, _, ctl, watcher, cf := newTestSetup(t)
+	defer cf()
+
+	watcher.OnChange(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cfgmap.FeaturesConfigName,
+			Namespace: system.Namespace(),
+		},
+		Data: map[string]string{
+			"revision-preview": "enabled",
+		},
+	})
+	fakenamespaceinformer.Get(ctx).Informer().GetIndexer().Add(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testNamespace,
+			Labels: map[string]string{
+				serving.RevisionPreviewLabelKey: serving.RevisionPreviewEnabled,
+			},
+		},
+	})
+
+	// A configuration with an old revision, which has no traffic target.
+	config := testConfiguration()
+	cfgrev := revisionForConfig(config)
+	config.Status.SetLatestCreatedRevisionName(cfgrev.Name)
+	config.Status.SetLatestReadyRevisionName(cfgrev.Name)
+	fakeservingclient.Get(ctx).ServingV1().Configurations(testNamespace).Create(ctx, config, metav1.CreateOptions{})
+	fakecfginformer.Get(ctx).Informer().GetIndexer().Add(config)
+	fakeservingclient.Get(ctx).ServingV1().Revisions(testNamespace).Create(ctx, cfgrev, metav1.CreateOptions{})
+	fakerevisioninformer.Get(ctx).Informer().GetIndexer().Add(cfgrev)
+
+	route := Route(testNamespace, "test-route", WithSpecTraffic(v1.TrafficTarget{
+		ConfigurationName: "test-config",
+		Percent:           ptr.Int64(100),
+	}))
+	fakeservingclient.Get(ctx).ServingV1().Routes(testNamespace).Create(ctx, route, metav1.CreateOptions{})
+	fakerouteinformer.Get(ctx).Informer().GetIndexer().Add(route)
+	ctl.Reconciler.Reconcile(context.Background(), KeyOrDie(route))
+
+	ci := getRouteIngressFromClient(ctx, t, route)
+	for _, rule := range ci.Spec.Rules {
+		var got []string
+		for _, path := range rule.HTTP.Paths {
+			if _, ok := path.Headers[activator.PreviewRevisionHeaderName]; ok {
+				split := path.Splits[0]
+				got = append(got, split.AppendHeaders[activator.PreviewConfigurationsHeaderName])
+				if split.ServiceName != "activator-service" {
+					t.Errorf("Preview routed to %s, want: activator-service", split.ServiceName)
+				}
+			}
+		}
+		if want := []string{"test-config"}; !cmp.Equal(got, want) {
+			t.Errorf("Preview configurations of %v = %v, want: %v", rule.Hosts, got, want)
+		}
+	}
+}
+
+func TestCreateRouteWithRevisionPreviewMissingPool(t *testing.T) {
+	ctx, _, ctl, watcher, cf := newTestSetup(t)
+	defer cf()
+	fakeRecorder := controller.GetEventRecorder(ctx).(*record.FakeRecorder)
+
+	watcher.OnChange(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cfgmap.FeaturesConfigName,
+			Namespace: system.Namespace(),
+		},
+		Data: map[string]string{
+			"revision-preview": "enabled",
+		},
+	})
+	fakenamespaceinformer.Get(ctx).Informer().GetIndexer().Add(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testNamespace,
+			Labels: map[string]string{
+				serving.RevisionPreviewLabelKey:         serving.RevisionPreviewEnabled,
+				servingnetworking.ActivatorPoolLabelKey: "missing",
+			},
+		},
+	})
+
+	config := testConfiguration()
+	cfgrev := revisionForConfig(config)
+	config.Status.SetLatestCreatedRevisionName(cfgrev.Name)
+	config.Status.SetLatestReadyRevisionName(cfgrev.Name)
+	fakeservingclient.Get(ctx).ServingV1().Configurations(testNamespace).Create(ctx, config, metav1.CreateOptions{})
+	fakecfginformer.Get(ctx).Informer().GetIndexer().Add(config)
+	fakeservingclient.Get(ctx).ServingV1().Revisions(testNamespace).Create(ctx, cfgrev, metav1.CreateOptions{})
+	fakerevisioninformer.Get(ctx).Informer().GetIndexer().Add(cfgrev)
+
+	route := Route(testNamespace, "test-route", WithSpecTraffic(v1.TrafficTarget{
+		ConfigurationName: "test-config",
+		Percent:           ptr.Int64(100),
+	}))
+	fakeservingclient.Get(ctx).ServingV1().Routes(testNamespace).Create(ctx, route, metav1.CreateOptions{})
+	fakerouteinformer.Get(ctx).Informer().GetIndexer().Add(route)
+	ctl.Reconciler.Reconcile(context.Background(), KeyOrDie(route))
+
+	ci := getRouteIngressFromClient(ctx, t, route)
+	for _, rule := range ci.Spec.Rules {
+		if got, want := rule.HTTP.Paths[0].Splits[0].ServiceName, "activator-service"; got != want {
+			t.Errorf("Preview of %v routed to %s, want: %s", rule.Hosts, got, want)
+		}
+	}
+
+	select {
+	case got := <-fakeRecorder.Events:
+		const want = `Warning ActivatorPoolNotFound Activator pool "missing" selected by namespace "test" does not exist, using the default activator pool`
+		if got != want {
+			t.Errorf("<-Events = %s, wanted %s", got, want)
+		}
+	case <-time.After(3 * time.Second):
+		t.Error("timed out waiting for expected events.")
+	}
+}
+
 func TestUpdateDomainConfigMap(t *testing.T) {
 	templateCM := corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1939,6 +1939,7 @@ func TestReconcile(t *testing.T) {
 			revisionLister:      listers.GetRevisionLister(),
 			serviceLister:       listers.GetK8sServiceLister(),
 			ingressLister:       listers.GetIngressLister(),
+			namespaceLister:     listers.GetNamespaceLister(),
 			tracker:             ctx.Value(TrackerKey).(tracker.Interface),
 			clock:               FakeClock{Time: fakeCurTime},
 			enqueueAfter:        func(interface{}, time.Duration) {},
@@ -2491,6 +2492,7 @@ func TestReconcileEnableAutoTLS(t *testing.T) {
 			revisionLister:      listers.GetRevisionLister(),
 			serviceLister:       listers.GetK8sServiceLister(),
 			ingressLister:       listers.GetIngressLister(),
+			namespaceLister:     listers.GetNamespaceLister(),
 			certificateLister:   listers.GetCertificateLister(),
 			tracker:             &NullTracker{},
 			clock:               FakeClock{Time: fakeCurTime},
@@ -2607,6 +2609,7 @@ func TestReconcileEnableAutoTLSHTTPDisabled(t *testing.T) {
 			revisionLister:      listers.GetRevisionLister(),
 			serviceLister:       listers.GetK8sServiceLister(),
 			ingressLister:       listers.GetIngressLister(),
+			namespaceLister:     listers.GetNamespaceLister(),
 			certificateLister:   listers.GetCertificateLister(),
 			tracker:             &NullTracker{},
 			clock:               FakeClock{Time: fakeCurTime},
@@ -108,6 +108,29 @@ func (e *unreadyRevisionError) IsFailure() bool {
 	return e.isFailure
 }
 
+type noMatchingRevisionError struct {
+	config   string // Name of the Configuration the selector applies to.
+	selector string // The selector no ready Revision matches.
+}
+
+var _ TargetError = (*noMatchingRevisionError)(nil)
+
+// Error implements error.
+func (e *noMatchingRevisionError) Error() string {
+	return fmt.Sprintf("no ready Revision of Configuration %q matches %q", e.config, e.selector)
+}
+
+// MarkBadTrafficTarget implements TargetError.
+func (e *noMatchingRevisionError) MarkBadTrafficTarget(rs *v1.RouteStatus) {
+	rs.MarkNoMatchingRevision(e.config, e.selector)
+}
+
+// IsFailure implements TargetError. Labeling a Revision may resolve
+// the target at any time, so this is never a failure.
+func (e *noMatchingRevisionError) IsFailure() bool {
+	return false
+}
+
 // errUnreadyConfiguration returns a TargetError for a Configuration that is not ready.
 func errUnreadyConfiguration(config *v1.Configuration) TargetError {
 	status := corev1.ConditionUnknown
@@ -147,3 +170,12 @@ func errMissingRevision(name string) TargetError {
 		name: name,
 	}
 }
+
+// errNoMatchingRevision returns a TargetError for a selector that no ready
+// Revision of the Configuration matches.
+func errNoMatchingRevision(config, selector string) TargetError {
+	return &noMatchingRevisionError{
+		config:   config,
+		selector: selector,
+	}
+}
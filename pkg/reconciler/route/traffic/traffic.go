@@ -19,9 +19,11 @@ package traffic
 import (
 	"context"
 	"errors"
+	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	net "knative.dev/networking/pkg/apis/networking"
 	netv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
@@ -70,6 +72,26 @@ type Config struct {
 	// MissingTargets are references to Configurations or Revisions
 	// that are missing
 	MissingTargets []corev1.ObjectReference
+
+	// RevisionSelectors select the Revisions that traffic targets with a
+	// revision selector choose from.
+	RevisionSelectors []*metav1.LabelSelector
+
+	// Preview configures previewing Revisions by name, nil if the Route
+	// doesn't allow it.
+	Preview *Preview
+}
+
+// Preview configures routing the requests naming a Revision of the Route's
+// Configurations to the activator, which looks the Revision up, regardless
+// of the Route's traffic targets.
+type Preview struct {
+	// ActivatorService is the name of the activator service serving the
+	// Route's namespace.
+	ActivatorService string
+
+	// Configurations whose Revisions can be previewed, sorted by name.
+	Configurations []string
 }
 
 // BuildTrafficConfiguration consolidates and flattens the Route.Spec.Traffic to the Revision-level. It also provides a
@@ -199,6 +221,10 @@ type configBuilder struct {
 	// in our listers
 	missingTargets []corev1.ObjectReference
 
+	// revisionSelectors is a collection of the selectors of the revisions
+	// referred to by selector.
+	revisionSelectors []*metav1.LabelSelector
+
 	// TargetError are deferred until we got a complete list of all referred targets.
 	deferredTargetErr TargetError
 }
@@ -352,6 +378,9 @@ func (cb *configBuilder) addConfigurationTarget(tt *v1.TrafficTarget) error {
 	if err != nil {
 		return err
 	}
+	if tt.RevisionSelector != nil {
+		return cb.addSelectorTarget(config, tt)
+	}
 	if config.Status.LatestReadyRevisionName == "" {
 		return errUnreadyConfiguration(config)
 	}
@@ -369,6 +398,58 @@ func (cb *configBuilder) addConfigurationTarget(tt *v1.TrafficTarget) error {
 	return nil
 }
 
+// addSelectorTarget flattens a traffic target to the Revision level, by looking up the newest ready Revision of the
+// referred Configuration that matches the target's selector. The selected Revision is treated like a Revision target,
+// i.e. it is not gradually rolled out.
+func (cb *configBuilder) addSelectorTarget(config *v1.Configuration, tt *v1.TrafficTarget) error {
+	ls := tt.RevisionSelector.DeepCopy()
+	if ls.MatchLabels == nil {
+		ls.MatchLabels = make(map[string]string, 1)
+	}
+	ls.MatchLabels[serving.ConfigurationLabelKey] = config.Name
+	selector, err := metav1.LabelSelectorAsSelector(ls)
+	if err != nil {
+		return err
+	}
+	cb.revisionSelectors = append(cb.revisionSelectors, ls)
+
+	revs, err := cb.revLister.List(selector)
+	if err != nil {
+		return err
+	}
+	var newest *v1.Revision
+	for _, rev := range revs {
+		if rev.IsReady() && (newest == nil || isNewerRevision(rev, newest)) {
+			newest = rev
+		}
+	}
+	if newest == nil {
+		return errNoMatchingRevision(config.Name, metav1.FormatLabelSelector(tt.RevisionSelector))
+	}
+	cb.revisions[newest.Name] = newest
+
+	ntt := tt.DeepCopy()
+	ntt.RevisionSelector = nil
+	ntt.RevisionName = newest.Name
+	ntt.LatestRevision = ptr.Bool(false)
+	cb.addFlattenedTarget(RevisionTarget{
+		TrafficTarget: *ntt,
+		Protocol:      newest.GetProtocol(),
+	})
+	return nil
+}
+
+// isNewerRevision returns whether a was created from a later generation of
+// their Configuration than b, falling back to the creation timestamps.
+func isNewerRevision(a, b *v1.Revision) bool {
+	ag, aErr := strconv.ParseInt(a.Labels[serving.ConfigurationGenerationLabelKey], 10, 64)
+	bg, bErr := strconv.ParseInt(b.Labels[serving.ConfigurationGenerationLabelKey], 10, 64)
+	if aErr == nil && bErr == nil && ag != bg {
+		return ag > bg
+	}
+	return b.CreationTimestamp.Before(&a.CreationTimestamp)
+}
+
 func (cb *configBuilder) addRevisionTarget(tt *v1.TrafficTarget) error {
 	rev, err := cb.getRevision(tt.RevisionName)
 	if err != nil {
@@ -421,11 +502,12 @@ func (cb *configBuilder) build() (*Config, error) {
 		cb.revisionTargets = nil
 	}
 	return &Config{
-		Targets:         consolidateAll(cb.targets),
-		revisionTargets: cb.revisionTargets,
-		Configurations:  cb.configurations,
-		Revisions:       cb.revisions,
-		MissingTargets:  cb.missingTargets,
+		Targets:           consolidateAll(cb.targets),
+		revisionTargets:   cb.revisionTargets,
+		Configurations:    cb.configurations,
+		Revisions:         cb.revisions,
+		MissingTargets:    cb.missingTargets,
+		RevisionSelectors: cb.revisionSelectors,
 	}, cb.deferredTargetErr
 }
 
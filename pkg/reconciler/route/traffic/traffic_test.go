@@ -1140,6 +1140,105 @@ func TestBuildTrafficConfigurationMissingConfig(t *testing.T) {
 	}
 }
 
+func TestBuildTrafficConfigurationRevisionSelector(t *testing.T) {
+	config := testConfig("selected-config")
+	config.Status.SetLatestReadyRevisionName("selected-revision-3")
+	revs := make([]*v1.Revision, 0, 3)
+	for i := 1; i <= 3; i++ {
+		rev := testRevForConfig(config, fmt.Sprint("selected-revision-", i))
+		rev.Labels[serving.ConfigurationGenerationLabelKey] = fmt.Sprint(i)
+		rev.Labels["release"] = "stable"
+		rev.Status.MarkResourcesAvailableTrue()
+		rev.Status.MarkContainerHealthyTrue()
+		rev.Status.MarkActiveTrue()
+		revs = append(revs, rev)
+	}
+	// The newest revision is not ready yet.
+	revs[2].Status.MarkContainerHealthyUnknown("Deploying", "")
+
+	servingInformer := informers.NewSharedInformerFactory(fakeclientset.NewSimpleClientset(), 0)
+	configInformer := servingInformer.Serving().V1().Configurations()
+	configInformer.Informer().GetIndexer().Add(config)
+	revInformer := servingInformer.Serving().V1().Revisions()
+	for _, rev := range revs {
+		revInformer.Informer().GetIndexer().Add(rev)
+	}
+
+	tests := []struct {
+		name     string
+		selector map[string]string
+		want     *Config
+		wantErr  error
+	}{{
+		name:     "newest ready match",
+		selector: map[string]string{"release": "stable"},
+		want: &Config{
+			Targets: map[string]RevisionTargets{
+				DefaultTarget: {{
+					TrafficTarget: v1.TrafficTarget{
+						ConfigurationName: config.Name,
+						RevisionName:      revs[1].Name,
+						Percent:           ptr.Int64(100),
+						LatestRevision:    ptr.Bool(false),
+					},
+					Protocol: net.ProtocolHTTP1,
+				}},
+			},
+			revisionTargets: []RevisionTarget{{
+				TrafficTarget: v1.TrafficTarget{
+					ConfigurationName: config.Name,
+					RevisionName:      revs[1].Name,
+					Percent:           ptr.Int64(100),
+					LatestRevision:    ptr.Bool(false),
+				},
+				Protocol: net.ProtocolHTTP1,
+			}},
+			Configurations: map[string]*v1.Configuration{config.Name: config},
+			Revisions:      map[string]*v1.Revision{revs[1].Name: revs[1]},
+			RevisionSelectors: []*metav1.LabelSelector{{
+				MatchLabels: map[string]string{
+					"release":                     "stable",
+					serving.ConfigurationLabelKey: config.Name,
+				},
+			}},
+		},
+	}, {
+		name:     "no match",
+		selector: map[string]string{"release": "canary"},
+		want: &Config{
+			Targets:        map[string]RevisionTargets{},
+			Configurations: map[string]*v1.Configuration{config.Name: config},
+			Revisions:      map[string]*v1.Revision{},
+			RevisionSelectors: []*metav1.LabelSelector{{
+				MatchLabels: map[string]string{
+					"release":                     "canary",
+					serving.ConfigurationLabelKey: config.Name,
+				},
+			}},
+		},
+		wantErr: errNoMatchingRevision(config.Name, "release=canary"),
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tt := v1.TrafficTarget{
+				ConfigurationName: config.Name,
+				RevisionSelector:  &metav1.LabelSelector{MatchLabels: tc.selector},
+				LatestRevision:    ptr.Bool(true),
+				Percent:           ptr.Int64(100),
+			}
+			got, err := BuildTrafficConfiguration(configInformer.Lister(), revInformer.Lister(),
+				testRouteWithTrafficTargets(WithSpecTraffic(tt)))
+			if fmt.Sprint(err) != fmt.Sprint(tc.wantErr) {
+				t.Errorf("BuildTrafficConfiguration() error = %v, want: %v", err, tc.wantErr)
+			}
+			if !cmp.Equal(tc.want, got, cmpOpts...) {
+				t.Error("Unexpected traffic diff (-want +got):", cmp.Diff(tc.want, got, cmpOpts...))
+			}
+		})
+	}
+}
+
 func TestBuildTrafficConfigurationNotRoutableRevision(t *testing.T) {
 	expected := &Config{
 		Targets:        map[string]RevisionTargets{},
@@ -19,6 +19,8 @@ package serverlessservice
 import (
 	"context"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/cache"
 
@@ -27,6 +29,7 @@ import (
 	sksreconciler "knative.dev/networking/pkg/client/injection/reconciler/networking/v1alpha1/serverlessservice"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	endpointsinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/endpoints"
+	namespaceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
 	serviceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/service"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
@@ -47,6 +50,7 @@ func NewController(
 	logger := logging.FromContext(ctx)
 	serviceInformer := serviceinformer.Get(ctx)
 	endpointsInformer := endpointsinformer.Get(ctx)
+	namespaceInformer := namespaceinformer.Get(ctx)
 	psInformerFactory := podscalable.Get(ctx)
 	sksInformer := sksinformer.Get(ctx)
 
@@ -55,6 +59,7 @@ func NewController(
 
 		endpointsLister: endpointsInformer.Lister(),
 		serviceLister:   serviceInformer.Lister(),
+		namespaceLister: namespaceInformer.Lister(),
 
 		// We wrap the PodScalable Informer Factory here so Get() uses the outer context.
 		// As the returned Informer is shared across reconciles, passing the context from
@@ -83,7 +88,7 @@ func NewController(
 		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
 	})
 
-	// Watch activator-service endpoints.
+	// Watch activator-service endpoints of the default and dedicated pools.
 	grCb := func(obj interface{}) {
 		// Since changes in the Activator Service endpoints affect all the SKS objects,
 		// do a global resync.
@@ -94,9 +99,27 @@ func NewController(
 		// Accept only ActivatorService K8s service objects.
 		FilterFunc: pkgreconciler.ChainFilterFuncs(
 			pkgreconciler.NamespaceFilterFunc(system.Namespace()),
-			pkgreconciler.NameFilterFunc(networking.ActivatorServiceName)),
+			func(obj interface{}) bool {
+				if mo, ok := obj.(metav1.Object); ok {
+					return networking.IsActivatorServiceName(mo.GetName())
+				}
+				return false
+			}),
 		Handler: controller.HandleAll(grCb),
 	})
 
+	// Watch namespaces, since their labels select the activator pool
+	// serving the SKS objects within them.
+	namespaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldNs, newNs := oldObj.(*corev1.Namespace), newObj.(*corev1.Namespace)
+			if oldNs.Labels[networking.ActivatorPoolLabelKey] == newNs.Labels[networking.ActivatorPoolLabelKey] {
+				return
+			}
+			logger.Info("Resyncing SKS objects in namespace due to activator pool change: ", newNs.Name)
+			impl.FilteredGlobalResync(pkgreconciler.NamespaceFilterFunc(newNs.Name), sksInformer.Informer())
+		},
+	})
+
 	return impl
 }
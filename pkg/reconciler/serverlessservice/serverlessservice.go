@@ -37,12 +37,11 @@ import (
 	sksreconciler "knative.dev/networking/pkg/client/injection/reconciler/networking/v1alpha1/serverlessservice"
 
 	netv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/controller"
 	"knative.dev/pkg/hash"
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
-	"knative.dev/pkg/system"
-	"knative.dev/serving/pkg/networking"
 	"knative.dev/serving/pkg/reconciler/serverlessservice/resources"
 	presources "knative.dev/serving/pkg/resources"
 )
@@ -54,6 +53,7 @@ type reconciler struct {
 	// listers index properties about resources
 	serviceLister   corev1listers.ServiceLister
 	endpointsLister corev1listers.EndpointsLister
+	namespaceLister corev1listers.NamespaceLister
 
 	// Used to get PodScalables from object references.
 	listerFactory func(schema.GroupVersionResource) (cache.GenericLister, error)
@@ -191,10 +191,16 @@ func (r *reconciler) reconcilePublicEndpoints(ctx context.Context, sks *netv1alp
 		srcEps                *corev1.Endpoints
 		foundServingEndpoints bool
 	)
-	activatorEps, err := r.endpointsLister.Endpoints(system.Namespace()).Get(networking.ActivatorServiceName)
+	// The revision's namespace may select a dedicated activator pool.
+	activatorEps, missingPool, err := presources.ActivatorEndpoints(r.namespaceLister, r.endpointsLister, sks.Namespace)
 	if err != nil {
 		return fmt.Errorf("failed to get activator service endpoints: %w", err)
 	}
+	if missingPool != "" {
+		controller.GetEventRecorder(ctx).Eventf(sks, corev1.EventTypeWarning, "ActivatorPoolNotFound",
+			"Activator pool %q selected by namespace %q does not exist, using the default activator pool",
+			missingPool, sks.Namespace)
+	}
 	if dlogger.Core().Enabled(zap.DebugLevel) {
 		// Spew is expensive and there might be a lof of activator endpoints.
 		logger.Debug("Activator endpoints: ", spew.Sprint(activatorEps))
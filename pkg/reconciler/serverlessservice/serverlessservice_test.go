@@ -28,6 +28,7 @@ import (
 	_ "knative.dev/networking/pkg/client/injection/informers/networking/v1alpha1/serverlessservice/fake"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/endpoints/fake"
+	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace/fake"
 	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/service/fake"
 	"knative.dev/pkg/logging"
 	"knative.dev/serving/pkg/client/injection/ducks/autoscaling/v1alpha1/podscalable"
@@ -619,6 +620,55 @@ func TestReconcile(t *testing.T) {
 		WantUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: endpointspub("pod", "change", withOtherSubsets, withFilteredPorts(networking.BackendHTTP2Port)),
 		}},
+	}, {
+		Name: "proxy mode; dedicated activator pool",
+		Key:  "pool/change",
+		Objects: []runtime.Object{
+			SKS("pool", "change", markNoEndpoints, WithPubService, withHTTP2Protocol,
+				WithPrivateService, WithDeployRef("blah")),
+			&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "pool",
+					Labels: map[string]string{networking.ActivatorPoolLabelKey: "tenant"},
+				},
+			},
+			deploy("pool", "blah"),
+			svcpub("pool", "change", withHTTP2),
+			svcpriv("pool", "change", withHTTP2Priv),
+			endpointspub("pool", "change", WithSubsets),
+			endpointspriv("pool", "change"),
+			activatorEndpoints(WithSubsets),
+			poolActivatorEndpoints("tenant", withOtherSubsets),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: endpointspub("pool", "change", withOtherSubsets, withFilteredPorts(networking.BackendHTTP2Port)),
+		}},
+	}, {
+		Name: "proxy mode; missing activator pool falls back to the default pool",
+		Key:  "pool/change",
+		Objects: []runtime.Object{
+			SKS("pool", "change", markNoEndpoints, WithPubService, withHTTP2Protocol,
+				WithPrivateService, WithDeployRef("blah")),
+			&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "pool",
+					Labels: map[string]string{networking.ActivatorPoolLabelKey: "missing"},
+				},
+			},
+			deploy("pool", "blah"),
+			svcpub("pool", "change", withHTTP2),
+			svcpriv("pool", "change", withHTTP2Priv),
+			endpointspub("pool", "change", WithSubsets),
+			endpointspriv("pool", "change"),
+			activatorEndpoints(withOtherSubsets),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: endpointspub("pool", "change", withOtherSubsets, withFilteredPorts(networking.BackendHTTP2Port)),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, "ActivatorPoolNotFound",
+				`Activator pool "missing" selected by namespace "pool" does not exist, using the default activator pool`),
+		},
 	}, {
 		Name: "serving mode; serving pod comes online",
 		Key:  "pod/change",
@@ -670,6 +720,7 @@ func TestReconcile(t *testing.T) {
 			kubeclient:      kubeclient.Get(ctx),
 			serviceLister:   listers.GetK8sServiceLister(),
 			endpointsLister: listers.GetEndpointsLister(),
+			namespaceLister: listers.GetNamespaceLister(),
 			listerFactory: func(gvr schema.GroupVersionResource) (cache.GenericLister, error) {
 				_, l, err := psInformerFactory.Get(ctx, gvr)
 				return l, err
@@ -809,10 +860,14 @@ func svcpriv(namespace, name string, so ...K8sServiceOption) *corev1.Service {
 }
 
 func activatorEndpoints(eo ...EndpointsOption) *corev1.Endpoints {
+	return poolActivatorEndpoints("", eo...)
+}
+
+func poolActivatorEndpoints(pool string, eo ...EndpointsOption) *corev1.Endpoints {
 	ep := &corev1.Endpoints{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: system.Namespace(),
-			Name:      networking.ActivatorServiceName,
+			Name:      networking.ActivatorPoolServiceName(pool),
 		},
 	}
 	for _, opt := range eo {
@@ -25,13 +25,17 @@ import (
 	revisioninformer "knative.dev/serving/pkg/client/injection/informers/serving/v1/revision"
 	routeinformer "knative.dev/serving/pkg/client/injection/informers/serving/v1/route"
 	kserviceinformer "knative.dev/serving/pkg/client/injection/informers/serving/v1/service"
+	servicetemplateinformer "knative.dev/serving/pkg/client/injection/informers/serving/v1alpha1/servicetemplate"
 	ksvcreconciler "knative.dev/serving/pkg/client/injection/reconciler/serving/v1/service"
 
 	"k8s.io/client-go/tools/cache"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+	"knative.dev/pkg/tracker"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/apis/serving/v1alpha1"
 )
 
 // NewController initializes the controller and is called by the generated code
@@ -45,21 +49,25 @@ func NewController(
 	routeInformer := routeinformer.Get(ctx)
 	configurationInformer := configurationinformer.Get(ctx)
 	revisionInformer := revisioninformer.Get(ctx)
+	serviceTemplateInformer := servicetemplateinformer.Get(ctx)
 
 	logger.Info("Setting up ConfigMap receivers")
 	configStore := cfgmap.NewStore(logger.Named("config-store"))
 	configStore.WatchConfigs(cmw)
 
 	c := &Reconciler{
-		client:              servingclient.Get(ctx),
-		configurationLister: configurationInformer.Lister(),
-		revisionLister:      revisionInformer.Lister(),
-		routeLister:         routeInformer.Lister(),
+		client:                servingclient.Get(ctx),
+		configurationLister:   configurationInformer.Lister(),
+		revisionLister:        revisionInformer.Lister(),
+		routeLister:           routeInformer.Lister(),
+		serviceTemplateLister: serviceTemplateInformer.Lister(),
+		clock:                 system.RealClock{},
 	}
 	opts := func(*controller.Impl) controller.Options {
 		return controller.Options{ConfigStore: configStore}
 	}
 	impl := ksvcreconciler.NewImpl(ctx, c, opts)
+	c.enqueueAfter = impl.EnqueueAfter
 
 	logger.Info("Setting up event handlers")
 	serviceInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
@@ -71,5 +79,22 @@ func NewController(
 	configurationInformer.Informer().AddEventHandler(handleControllerOf)
 	routeInformer.Informer().AddEventHandler(handleControllerOf)
 
+	c.tracker = tracker.New(impl.EnqueueKey, controller.GetTrackerLease(ctx))
+
+	// Make sure trackers are deleted once the observers are removed.
+	serviceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: c.tracker.OnDeletedObserver,
+	})
+
+	serviceTemplateInformer.Informer().AddEventHandler(controller.HandleAll(
+		// Call the tracker's OnChanged method, but we've seen the objects
+		// coming through this path missing TypeMeta, so ensure it is properly
+		// populated.
+		controller.EnsureTypeMeta(
+			c.tracker.OnChanged,
+			v1alpha1.SchemeGroupVersion.WithKind("ServiceTemplate"),
+		),
+	))
+
 	return impl
 }
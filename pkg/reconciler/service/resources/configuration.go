@@ -17,33 +17,41 @@ limitations under the License.
 package resources
 
 import (
+	"context"
+	"fmt"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"knative.dev/pkg/kmeta"
+	cfgmap "knative.dev/serving/pkg/apis/config"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/apis/serving/v1alpha1"
 	labelerv2 "knative.dev/serving/pkg/reconciler/labeler/v2"
 	"knative.dev/serving/pkg/reconciler/service/resources/names"
 )
 
-// MakeConfiguration creates a Configuration from a Service object.
-func MakeConfiguration(service *v1.Service) *v1.Configuration {
-	return MakeConfigurationFromExisting(service, &v1.Configuration{})
+// MakeConfiguration creates a Configuration from a Service object, merged on
+// top of the given ServiceTemplate, if any.
+func MakeConfiguration(ctx context.Context, service *v1.Service, tmpl *v1alpha1.ServiceTemplate) (*v1.Configuration, error) {
+	return MakeConfigurationFromExisting(ctx, service, &v1.Configuration{}, tmpl)
 }
 
-// MakeConfigurationFromExisting creates a Configuration from a Service object given an existing Configuration.
-func MakeConfigurationFromExisting(service *v1.Service, existing *v1.Configuration) *v1.Configuration {
+// MakeConfigurationFromExisting creates a Configuration from a Service object given an existing Configuration,
+// merged on top of the given ServiceTemplate, if any.
+func MakeConfigurationFromExisting(ctx context.Context, service *v1.Service, existing *v1.Configuration,
+	tmpl *v1alpha1.ServiceTemplate) (*v1.Configuration, error) {
+	propagation := cfgmap.FromContextOrDefaults(ctx).MetadataPropagation
 	labels := map[string]string{
 		serving.ServiceLabelKey:    service.Name,
 		serving.ServiceUIDLabelKey: string(service.ObjectMeta.UID),
 	}
-	anns := kmeta.FilterMap(service.GetAnnotations(), func(key string) bool {
-		return key == corev1.LastAppliedConfigAnnotation ||
+	anns := propagation.FilterAnnotations(cfgmap.PropagateToConfiguration, service.GetAnnotations(), func(key string) bool {
+		return key != corev1.LastAppliedConfigAnnotation &&
 			// Configs & Revisions don't use rollout information, it is only for routes.
-			key == serving.RolloutDurationKey
+			key != serving.RolloutDurationKey
 	})
 
 	routeName := names.Route(service)
@@ -51,16 +59,28 @@ func MakeConfigurationFromExisting(service *v1.Service, existing *v1.Configurati
 	set.Insert(routeName)
 	anns[serving.RoutesAnnotationKey] = strings.Join(set.UnsortedList(), ",")
 
-	return &v1.Configuration{
+	config := &v1.Configuration{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      names.Configuration(service),
 			Namespace: service.Namespace,
 			OwnerReferences: []metav1.OwnerReference{
 				*kmeta.NewControllerRef(service),
 			},
-			Labels:      kmeta.UnionMaps(service.GetLabels(), labels),
+			Labels:      kmeta.UnionMaps(propagation.FilterLabels(cfgmap.PropagateToConfiguration, service.GetLabels(), nil), labels),
 			Annotations: anns,
 		},
 		Spec: service.Spec.ConfigurationSpec,
 	}
+
+	if tmpl != nil {
+		merged, err := MergeServiceTemplate(tmpl, &service.Spec.Template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge ServiceTemplate %q: %w", tmpl.Name, err)
+		}
+		config.Spec.Template = *merged
+		// The Service's template wasn't defaulted on its own, so default the
+		// merged one like the webhook does to the stored Configuration.
+		config.SetDefaults(ctx)
+	}
+	return config, nil
 }
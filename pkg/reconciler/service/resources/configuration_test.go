@@ -17,6 +17,7 @@ limitations under the License.
 package resources
 
 import (
+	"context"
 	"sort"
 	"strings"
 	"testing"
@@ -25,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	"knative.dev/pkg/kmeta"
+	cfgmap "knative.dev/serving/pkg/apis/config"
 	"knative.dev/serving/pkg/apis/serving"
 )
 
@@ -36,7 +38,10 @@ func TestConfigurationSpec(t *testing.T) {
 		},
 	)
 
-	c := MakeConfiguration(s)
+	c, err := MakeConfiguration(context.Background(), s, nil)
+	if err != nil {
+		t.Fatal("MakeConfiguration() =", err)
+	}
 	if got, want := c.Name, testServiceName; got != want {
 		t.Errorf("Service name = %q; want: %q", got, want)
 	}
@@ -61,7 +66,9 @@ func TestConfigurationSpec(t *testing.T) {
 	}
 
 	// Create the configuration based on the same existing configuration.
-	c = MakeConfigurationFromExisting(s, c)
+	if c, err = MakeConfigurationFromExisting(context.Background(), s, c, nil); err != nil {
+		t.Fatal("MakeConfigurationFromExisting() =", err)
+	}
 	if got, want := c.Annotations, map[string]string{
 		serving.RoutesAnnotationKey: testServiceName,
 	}; !cmp.Equal(got, want) {
@@ -71,8 +78,11 @@ func TestConfigurationSpec(t *testing.T) {
 	// Create the configuration based on the configuration with a different value for the
 	// annotation key serving.RoutesAnnotationKey.
 	const secTestServiceName = "second-test-service"
-	secondConfig := MakeConfigurationFromExisting(
-		createServiceWithName(secTestServiceName), c)
+	secondConfig, err := MakeConfigurationFromExisting(context.Background(),
+		createServiceWithName(secTestServiceName), c, nil)
+	if err != nil {
+		t.Fatal("MakeConfigurationFromExisting() =", err)
+	}
 
 	// MakeConfigurationFromExisting employs maps in process, so order is
 	// not guaranteed.
@@ -88,8 +98,51 @@ func TestConfigurationSpec(t *testing.T) {
 
 func TestConfigurationHasNoKubectlAnnotation(t *testing.T) {
 	s := createServiceWithKubectlAnnotation()
-	c := MakeConfiguration(s)
+	c, err := MakeConfiguration(context.Background(), s, nil)
+	if err != nil {
+		t.Fatal("MakeConfiguration() =", err)
+	}
 	if v, ok := c.Annotations[corev1.LastAppliedConfigAnnotation]; ok {
 		t.Errorf(`Annotation[%s] = %q, want: ""`, corev1.LastAppliedConfigAnnotation, v)
 	}
 }
+
+func TestConfigurationMetadataPropagation(t *testing.T) {
+	s := createService()
+	s.Labels = map[string]string{
+		"team":                    "a-team",
+		"cost.example.com/center": "1234",
+	}
+	s.Annotations = map[string]string{
+		"prometheus.io/scrape": "true",
+		"contact":              "hannibal",
+	}
+	ctx := cfgmap.ToContext(context.Background(), &cfgmap.Config{
+		MetadataPropagation: &cfgmap.MetadataPropagation{
+			Labels: map[cfgmap.PropagationTarget]cfgmap.PropagationRule{
+				cfgmap.PropagateToConfiguration: {Deny: []string{"team"}},
+			},
+			Annotations: map[cfgmap.PropagationTarget]cfgmap.PropagationRule{
+				cfgmap.PropagateToConfiguration: {Allow: []string{"prometheus.io/*"}},
+			},
+		},
+	})
+
+	c, err := MakeConfiguration(ctx, s, nil)
+	if err != nil {
+		t.Fatal("MakeConfiguration() =", err)
+	}
+	if got, want := c.Labels, map[string]string{
+		"cost.example.com/center":  "1234",
+		serving.ServiceLabelKey:    testServiceName,
+		serving.ServiceUIDLabelKey: "cccccccc-cccc-cccc-cccc-cccccccccccc",
+	}; !cmp.Equal(got, want) {
+		t.Errorf("Labels mismatch: diff(-want,+got):\n%s", cmp.Diff(want, got))
+	}
+	if got, want := c.Annotations, map[string]string{
+		"prometheus.io/scrape":      "true",
+		serving.RoutesAnnotationKey: testServiceName,
+	}; !cmp.Equal(got, want) {
+		t.Errorf("Annotations mismatch: diff(-want,+got):\n%s", cmp.Diff(want, got))
+	}
+}
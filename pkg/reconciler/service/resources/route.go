@@ -17,17 +17,21 @@ limitations under the License.
 package resources
 
 import (
+	"context"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"knative.dev/pkg/kmeta"
+	cfgmap "knative.dev/serving/pkg/apis/config"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	"knative.dev/serving/pkg/reconciler/service/resources/names"
 )
 
 // MakeRoute creates a Route from a Service object.
-func MakeRoute(service *v1.Service) *v1.Route {
+func MakeRoute(ctx context.Context, service *v1.Service) *v1.Route {
+	propagation := cfgmap.FromContextOrDefaults(ctx).MetadataPropagation
 	c := &v1.Route{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      names.Route(service),
@@ -35,10 +39,10 @@ func MakeRoute(service *v1.Service) *v1.Route {
 			OwnerReferences: []metav1.OwnerReference{
 				*kmeta.NewControllerRef(service),
 			},
-			Annotations: kmeta.FilterMap(service.GetAnnotations(), func(key string) bool {
-				return key == corev1.LastAppliedConfigAnnotation
+			Annotations: propagation.FilterAnnotations(cfgmap.PropagateToRoute, service.GetAnnotations(), func(key string) bool {
+				return key != corev1.LastAppliedConfigAnnotation
 			}),
-			Labels: kmeta.UnionMaps(service.GetLabels(), map[string]string{
+			Labels: kmeta.UnionMaps(propagation.FilterLabels(cfgmap.PropagateToRoute, service.GetLabels(), nil), map[string]string{
 				// Add this service's name to the route annotations.
 				serving.ServiceLabelKey: service.Name,
 			}),
@@ -17,6 +17,7 @@ limitations under the License.
 package resources
 
 import (
+	"context"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -24,6 +25,7 @@ import (
 
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/ptr"
+	cfgmap "knative.dev/serving/pkg/apis/config"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	"knative.dev/serving/pkg/reconciler/service/resources/names"
@@ -38,7 +40,7 @@ func TestRouteSpec(t *testing.T) {
 	)
 
 	testConfigName := names.Configuration(s)
-	r := MakeRoute(s)
+	r := MakeRoute(context.Background(), s)
 	if got, want := r.Name, testServiceName; got != want {
 		t.Errorf("Expected %q for service name got %q", want, got)
 	}
@@ -75,8 +77,31 @@ func TestRouteSpec(t *testing.T) {
 
 func TestRouteHasNoKubectlAnnotation(t *testing.T) {
 	s := createServiceWithKubectlAnnotation()
-	r := MakeRoute(s)
+	r := MakeRoute(context.Background(), s)
 	if v, ok := r.Annotations[corev1.LastAppliedConfigAnnotation]; ok {
 		t.Errorf("Annotation %s = %q, want empty", corev1.LastAppliedConfigAnnotation, v)
 	}
 }
+
+func TestRouteMetadataPropagation(t *testing.T) {
+	s := createService()
+	s.Labels = map[string]string{
+		"team":                    "a-team",
+		"cost.example.com/center": "1234",
+	}
+	ctx := cfgmap.ToContext(context.Background(), &cfgmap.Config{
+		MetadataPropagation: &cfgmap.MetadataPropagation{
+			Labels: map[cfgmap.PropagationTarget]cfgmap.PropagationRule{
+				cfgmap.PropagateToRoute: {Deny: []string{"cost.example.com/*"}},
+			},
+		},
+	})
+
+	r := MakeRoute(ctx, s)
+	if got, want := r.Labels, map[string]string{
+		"team":                  "a-team",
+		serving.ServiceLabelKey: testServiceName,
+	}; !cmp.Equal(got, want) {
+		t.Errorf("Labels mismatch: diff(-want,+got):\n%s", cmp.Diff(want, got))
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/json"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+
+	"knative.dev/serving/pkg/apis/serving"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/apis/serving/v1alpha1"
+)
+
+// unnamedContainer stands in for the name of the lone container of a
+// template and a Service that both leave it unset, so that the two are
+// merged together.
+const unnamedContainer = "unnamed-container"
+
+// MergeServiceTemplate merges the Service's revision template on top of the
+// given ServiceTemplate's, and records the ServiceTemplate's name and
+// generation on the result.
+func MergeServiceTemplate(tmpl *v1alpha1.ServiceTemplate, rts *v1.RevisionTemplateSpec) (*v1.RevisionTemplateSpec, error) {
+	base := tmpl.Spec.Template.DeepCopy()
+	out := rts.DeepCopy()
+
+	out.Labels = unionMaps(base.Labels, out.Labels)
+	out.Annotations = unionMaps(base.Annotations, out.Annotations, map[string]string{
+		serving.ServiceTemplateAnnotationKey:           tmpl.Name,
+		serving.ServiceTemplateGenerationAnnotationKey: strconv.FormatInt(tmpl.Generation, 10),
+	})
+
+	if out.Spec.ContainerConcurrency == nil {
+		out.Spec.ContainerConcurrency = base.Spec.ContainerConcurrency
+	}
+	if out.Spec.TimeoutSeconds == nil {
+		out.Spec.TimeoutSeconds = base.Spec.TimeoutSeconds
+	}
+
+	podSpec, err := mergePodSpec(&base.Spec.PodSpec, &out.Spec.PodSpec)
+	if err != nil {
+		return nil, err
+	}
+	out.Spec.PodSpec = *podSpec
+	return out, nil
+}
+
+// mergePodSpec merges override on top of base using strategic merge patch
+// semantics, so that e.g. containers and their env are merged by name.
+func mergePodSpec(base, override *corev1.PodSpec) (*corev1.PodSpec, error) {
+	// Pair up a lone unnamed container with the template's lone container.
+	unnamed := false
+	if len(base.Containers) == 1 && len(override.Containers) == 1 && override.Containers[0].Name == "" {
+		if base.Containers[0].Name == "" {
+			base.Containers[0].Name = unnamedContainer
+			unnamed = true
+		}
+		override.Containers[0].Name = base.Containers[0].Name
+	}
+
+	original, err := json.Marshal(base)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := json.Marshal(override)
+	if err != nil {
+		return nil, err
+	}
+	// Unset fields, e.g. a nil list of containers, serialize as null, which
+	// would delete the template's values, so drop them from the patch.
+	var fields map[string]interface{}
+	if err := json.Unmarshal(patch, &fields); err != nil {
+		return nil, err
+	}
+	if patch, err = json.Marshal(dropNulls(fields)); err != nil {
+		return nil, err
+	}
+	merged, err := strategicpatch.StrategicMergePatch(original, patch, corev1.PodSpec{})
+	if err != nil {
+		return nil, err
+	}
+
+	out := &corev1.PodSpec{}
+	if err := json.Unmarshal(merged, out); err != nil {
+		return nil, err
+	}
+	if unnamed {
+		out.Containers[0].Name = ""
+	}
+	return out, nil
+}
+
+// dropNulls recursively removes the null values from the given JSON value.
+func dropNulls(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			if e == nil {
+				delete(v, k)
+			} else {
+				v[k] = dropNulls(e)
+			}
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = dropNulls(e)
+		}
+	}
+	return v
+}
+
+// unionMaps is like kmeta.UnionMaps, but returns nil rather than an empty map.
+func unionMaps(maps ...map[string]string) map[string]string {
+	var out map[string]string
+	for _, m := range maps {
+		for k, v := range m {
+			if out == nil {
+				out = make(map[string]string, len(m))
+			}
+			out[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/ptr"
+	"knative.dev/serving/pkg/apis/serving"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/apis/serving/v1alpha1"
+)
+
+func TestMergeServiceTemplate(t *testing.T) {
+	tmpl := &v1alpha1.ServiceTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "base",
+			Generation: 3,
+		},
+		Spec: v1alpha1.ServiceTemplateSpec{
+			Template: v1.RevisionTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"team": "base", "tier": "web"},
+					Annotations: map[string]string{"autoscaling.knative.dev/target": "10"},
+				},
+				Spec: v1.RevisionSpec{
+					PodSpec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Image: "busybox",
+							Env:   []corev1.EnvVar{{Name: "FOO", Value: "bar"}, {Name: "BAZ", Value: "base"}},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU: resource.MustParse("100m"),
+								},
+							},
+						}},
+					},
+					TimeoutSeconds:       ptr.Int64(60),
+					ContainerConcurrency: ptr.Int64(10),
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		rts  v1.RevisionTemplateSpec
+		want v1.RevisionTemplateSpec
+	}{{
+		name: "empty service template",
+		want: v1.RevisionTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"team": "base", "tier": "web"},
+				Annotations: map[string]string{
+					"autoscaling.knative.dev/target":               "10",
+					serving.ServiceTemplateAnnotationKey:           "base",
+					serving.ServiceTemplateGenerationAnnotationKey: "3",
+				},
+			},
+			Spec: tmpl.Spec.Template.Spec,
+		},
+	}, {
+		name: "overrides",
+		rts: v1.RevisionTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "svc-v2",
+				Labels: map[string]string{"team": "svc"},
+			},
+			Spec: v1.RevisionSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Env: []corev1.EnvVar{{Name: "BAZ", Value: "svc"}, {Name: "QUX", Value: "svc"}},
+						Resources: corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+						},
+					}},
+				},
+				ContainerConcurrency: ptr.Int64(1),
+			},
+		},
+		want: v1.RevisionTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "svc-v2",
+				Labels: map[string]string{"team": "svc", "tier": "web"},
+				Annotations: map[string]string{
+					"autoscaling.knative.dev/target":               "10",
+					serving.ServiceTemplateAnnotationKey:           "base",
+					serving.ServiceTemplateGenerationAnnotationKey: "3",
+				},
+			},
+			Spec: v1.RevisionSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Image: "busybox",
+						Env: []corev1.EnvVar{
+							{Name: "FOO", Value: "bar"},
+							{Name: "BAZ", Value: "svc"},
+							{Name: "QUX", Value: "svc"},
+						},
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("100m"),
+							},
+							Limits: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+						},
+					}},
+				},
+				TimeoutSeconds:       ptr.Int64(60),
+				ContainerConcurrency: ptr.Int64(1),
+			},
+		},
+	}, {
+		name: "additional named container",
+		rts: v1.RevisionTemplateSpec{
+			Spec: v1.RevisionSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "sidecar",
+						Image: "envoy",
+					}},
+				},
+			},
+		},
+		want: v1.RevisionTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{"team": "base", "tier": "web"},
+				Annotations: map[string]string{
+					"autoscaling.knative.dev/target":               "10",
+					serving.ServiceTemplateAnnotationKey:           "base",
+					serving.ServiceTemplateGenerationAnnotationKey: "3",
+				},
+			},
+			Spec: v1.RevisionSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "sidecar",
+						Image: "envoy",
+					}, {
+						Image: "busybox",
+						Env:   []corev1.EnvVar{{Name: "FOO", Value: "bar"}, {Name: "BAZ", Value: "base"}},
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("100m"),
+							},
+						},
+					}},
+				},
+				TimeoutSeconds:       ptr.Int64(60),
+				ContainerConcurrency: ptr.Int64(10),
+			},
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := MergeServiceTemplate(tmpl, &test.rts)
+			if err != nil {
+				t.Fatal("MergeServiceTemplate() =", err)
+			}
+			if !cmp.Equal(got, &test.want, cmp.Comparer(func(a, b resource.Quantity) bool {
+				return a.Cmp(b) == 0
+			})) {
+				t.Error("MergeServiceTemplate (-want, +got):", cmp.Diff(&test.want, got))
+			}
+		})
+	}
+}
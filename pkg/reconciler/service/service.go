@@ -18,7 +18,11 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"go.uber.org/zap"
@@ -27,6 +31,8 @@ import (
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	clientset "knative.dev/serving/pkg/client/clientset/versioned"
 	ksvcreconciler "knative.dev/serving/pkg/client/injection/reconciler/serving/v1/service"
 
@@ -34,22 +40,36 @@ import (
 	"knative.dev/pkg/kmp"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/system"
+	"knative.dev/pkg/tracker"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/apis/serving/v1alpha1"
 	listers "knative.dev/serving/pkg/client/listers/serving/v1"
+	v1alpha1listers "knative.dev/serving/pkg/client/listers/serving/v1alpha1"
 	configresources "knative.dev/serving/pkg/reconciler/configuration/resources"
 	"knative.dev/serving/pkg/reconciler/service/resources"
 	resourcenames "knative.dev/serving/pkg/reconciler/service/resources/names"
 )
 
+// revisionHistoryLimit is the number of Revisions surfaced in the
+// Service's revision history.
+const revisionHistoryLimit = 5
+
 // Reconciler implements controller.Reconciler for Service resources.
 type Reconciler struct {
 	client clientset.Interface
 
 	// listers index properties about resources
-	configurationLister listers.ConfigurationLister
-	revisionLister      listers.RevisionLister
-	routeLister         listers.RouteLister
+	configurationLister   listers.ConfigurationLister
+	revisionLister        listers.RevisionLister
+	routeLister           listers.RouteLister
+	serviceTemplateLister v1alpha1listers.ServiceTemplateLister
+
+	tracker tracker.Interface
+
+	clock        system.Clock
+	enqueueAfter func(interface{}, time.Duration)
 }
 
 // Check that our Reconciler implements ksvcreconciler.Interface
@@ -78,6 +98,7 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, service *v1.Service) pkg
 		logger.Debugf("Configuration Conditions = %#v", config.Status.Conditions)
 		// Update our Status based on the state of our underlying Configuration.
 		service.Status.PropagateConfigurationStatus(&config.Status)
+		service.Status.RevisionHistory = c.revisionHistory(config)
 	}
 
 	// When the Configuration names a Revision, check that the named Revision is owned
@@ -94,6 +115,9 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, service *v1.Service) pkg
 	if err != nil {
 		return err
 	}
+	if err := c.dropExpiredTags(ctx, service); err != nil {
+		return err
+	}
 
 	// Update our Status based on the state of our underlying Route.
 	ss := &service.Status
@@ -112,10 +136,15 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, service *v1.Service) pkg
 
 func (c *Reconciler) config(ctx context.Context, service *v1.Service) (*v1.Configuration, error) {
 	recorder := controller.GetEventRecorder(ctx)
+	tmpl, err := c.serviceTemplate(service)
+	if err != nil {
+		return nil, err
+	}
+
 	configName := resourcenames.Configuration(service)
 	config, err := c.configurationLister.Configurations(service.Namespace).Get(configName)
 	if apierrs.IsNotFound(err) {
-		config, err = c.createConfiguration(ctx, service)
+		config, err = c.createConfiguration(ctx, service, tmpl)
 		if err != nil {
 			recorder.Eventf(service, corev1.EventTypeWarning, "CreationFailed", "Failed to create Configuration %q: %v", configName, err)
 			return nil, fmt.Errorf("failed to create Configuration: %w", err)
@@ -127,7 +156,7 @@ func (c *Reconciler) config(ctx context.Context, service *v1.Service) (*v1.Confi
 		// Surface an error in the service's status,and return an error.
 		service.Status.MarkConfigurationNotOwned(configName)
 		return nil, fmt.Errorf("service: %q does not own configuration: %q", service.Name, configName)
-	} else if config, err = c.reconcileConfiguration(ctx, service, config); err != nil {
+	} else if config, err = c.reconcileConfiguration(ctx, service, config, tmpl); err != nil {
 		return nil, fmt.Errorf("failed to reconcile Configuration: %w", err)
 	}
 	return config, nil
@@ -183,9 +212,41 @@ func (c *Reconciler) checkRoutesNotReady(config *v1.Configuration, logger *zap.S
 	}
 }
 
-func (c *Reconciler) createConfiguration(ctx context.Context, service *v1.Service) (*v1.Configuration, error) {
-	return c.client.ServingV1().Configurations(service.Namespace).Create(
-		ctx, resources.MakeConfiguration(service), metav1.CreateOptions{})
+// serviceTemplate returns the ServiceTemplate the Service is built on, if any,
+// and tracks it so that the Service is reconciled when it changes.
+func (c *Reconciler) serviceTemplate(service *v1.Service) (*v1alpha1.ServiceTemplate, error) {
+	name := service.Annotations[serving.ServiceTemplateAnnotationKey]
+	if name == "" {
+		return nil, nil
+	}
+
+	if err := c.tracker.TrackReference(tracker.Reference{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "ServiceTemplate",
+		Namespace:  service.Namespace,
+		Name:       name,
+	}, service); err != nil {
+		return nil, fmt.Errorf("failed to track ServiceTemplate %q: %w", name, err)
+	}
+
+	tmpl, err := c.serviceTemplateLister.ServiceTemplates(service.Namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		// We are tracking the ServiceTemplate, so we'll be reconciled
+		// again once it is created.
+		service.Status.MarkServiceTemplateMissing(name)
+		return nil, controller.NewPermanentError(fmt.Errorf("ServiceTemplate %q does not exist", name))
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get ServiceTemplate: %w", err)
+	}
+	return tmpl, nil
+}
+
+func (c *Reconciler) createConfiguration(ctx context.Context, service *v1.Service, tmpl *v1alpha1.ServiceTemplate) (*v1.Configuration, error) {
+	config, err := resources.MakeConfiguration(ctx, service, tmpl)
+	if err != nil {
+		return nil, err
+	}
+	return c.client.ServingV1().Configurations(service.Namespace).Create(ctx, config, metav1.CreateOptions{})
 }
 
 func configSemanticEquals(ctx context.Context, desiredConfig, config *v1.Configuration) (bool, error) {
@@ -203,14 +264,23 @@ func configSemanticEquals(ctx context.Context, desiredConfig, config *v1.Configu
 		specDiff == "", nil
 }
 
-func (c *Reconciler) reconcileConfiguration(ctx context.Context, service *v1.Service, config *v1.Configuration) (*v1.Configuration, error) {
+func (c *Reconciler) reconcileConfiguration(ctx context.Context, service *v1.Service, config *v1.Configuration,
+	tmpl *v1alpha1.ServiceTemplate) (*v1.Configuration, error) {
+	if holdServiceTemplateRollout(service, config, tmpl) {
+		logging.FromContext(ctx).Debugf("Holding the rollout of ServiceTemplate %q generation %d", tmpl.Name, tmpl.Generation)
+		return config, nil
+	}
+
 	existing := config.DeepCopy()
 	// In the case of an upgrade, there can be default values set that don't exist pre-upgrade.
 	// We are setting the up-to-date default values here so an update won't be triggered if the only
 	// diff is the new default values.
 	existing.SetDefaults(ctx)
 
-	desiredConfig := resources.MakeConfigurationFromExisting(service, existing)
+	desiredConfig, err := resources.MakeConfigurationFromExisting(ctx, service, existing, tmpl)
+	if err != nil {
+		return nil, err
+	}
 	equals, err := configSemanticEquals(ctx, desiredConfig, existing)
 	if err != nil {
 		return nil, err
@@ -229,9 +299,79 @@ func (c *Reconciler) reconcileConfiguration(ctx context.Context, service *v1.Ser
 	return c.client.ServingV1().Configurations(service.Namespace).Update(ctx, existing, metav1.UpdateOptions{})
 }
 
+// holdServiceTemplateRollout returns whether the Configuration should be left
+// as is, because the only change is to a ServiceTemplate whose changes the
+// Service rolls out manually.
+func holdServiceTemplateRollout(service *v1.Service, config *v1.Configuration, tmpl *v1alpha1.ServiceTemplate) bool {
+	if tmpl == nil || serving.ServiceTemplateRollout(service.Annotations[serving.ServiceTemplateRolloutAnnotationKey]) !=
+		serving.ServiceTemplateRolloutManual {
+		return false
+	}
+	// The Service itself changed, so pick up the latest ServiceTemplate.
+	if service.Generation != service.Status.ObservedGeneration {
+		return false
+	}
+	anns := config.Spec.GetTemplate().Annotations
+	return anns[serving.ServiceTemplateAnnotationKey] == tmpl.Name &&
+		anns[serving.ServiceTemplateGenerationAnnotationKey] != strconv.FormatInt(tmpl.Generation, 10)
+}
+
 func (c *Reconciler) createRoute(ctx context.Context, service *v1.Service) (*v1.Route, error) {
 	return c.client.ServingV1().Routes(service.Namespace).Create(
-		ctx, resources.MakeRoute(service), metav1.CreateOptions{})
+		ctx, c.makeRoute(ctx, service), metav1.CreateOptions{})
+}
+
+// makeRoute returns the desired Route of the Service without the traffic
+// targets whose tags expired, so that the Route drops them before the
+// Service spec is patched, and schedules a resync for the next expiry.
+func (c *Reconciler) makeRoute(ctx context.Context, service *v1.Service) *v1.Route {
+	route := resources.MakeRoute(ctx, service)
+	expired, next := v1.ExpiredTags(service.Annotations, service.Spec.Traffic, c.clock.Now())
+	if next > 0 {
+		c.enqueueAfter(service, next)
+	}
+	if expired.Len() > 0 {
+		route.Spec.Traffic = v1.WithoutTags(route.Spec.Traffic, expired)
+	}
+	return route
+}
+
+// dropExpiredTags removes the traffic targets whose tags expired, along with
+// their expiry annotations, from the Service spec. The Service is patched
+// rather than updated in place, and the patch is conditional on the observed
+// resourceVersion, so concurrent edits of the traffic block are not lost.
+func (c *Reconciler) dropExpiredTags(ctx context.Context, service *v1.Service) error {
+	expired, _ := v1.ExpiredTags(service.Annotations, service.Spec.Traffic, c.clock.Now())
+	if expired.Len() == 0 {
+		return nil
+	}
+
+	annotations := make(map[string]interface{}, expired.Len())
+	for _, tag := range expired.List() {
+		annotations[serving.TagExpiryAnnotationPrefix+tag] = nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"resourceVersion": service.ResourceVersion,
+			"annotations":     annotations,
+		},
+		"spec": map[string]interface{}{
+			"traffic": v1.WithoutTags(service.Spec.Traffic, expired),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := c.client.ServingV1().Services(service.Namespace).Patch(
+		ctx, service.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to remove expired traffic tags: %w", err)
+	}
+
+	recorder := controller.GetEventRecorder(ctx)
+	for _, tag := range expired.List() {
+		recorder.Eventf(service, corev1.EventTypeNormal, "TagExpired", "Removed expired traffic tag %q", tag)
+	}
+	return nil
 }
 
 func routeSemanticEquals(ctx context.Context, desiredRoute, route *v1.Route) (bool, error) {
@@ -255,7 +395,7 @@ func (c *Reconciler) reconcileRoute(ctx context.Context, service *v1.Service, ro
 	// We are setting the up-to-date default values here so an update won't be triggered if the only
 	// diff is the new default values.
 	existing.SetDefaults(ctx)
-	desiredRoute := resources.MakeRoute(service)
+	desiredRoute := c.makeRoute(ctx, service)
 	equals, err := routeSemanticEquals(ctx, desiredRoute, existing)
 	if err != nil {
 		return nil, err
@@ -308,3 +448,39 @@ func CheckNameAvailability(config *v1.Configuration, lister listers.RevisionList
 	}
 	return nil
 }
+
+// revisionHistory returns the most recent Revisions of the Configuration,
+// newest first, along with who created them and why.
+func (c *Reconciler) revisionHistory(config *v1.Configuration) []v1.RevisionHistoryEntry {
+	revs, err := c.revisionLister.Revisions(config.Namespace).List(labels.SelectorFromSet(labels.Set{
+		serving.ConfigurationLabelKey: config.Name,
+	}))
+	if err != nil || len(revs) == 0 {
+		return nil
+	}
+
+	generation := func(r *v1.Revision) int64 {
+		g, _ := strconv.ParseInt(r.Labels[serving.ConfigurationGenerationLabelKey], 10, 64)
+		return g
+	}
+	sort.Slice(revs, func(i, j int) bool {
+		gi, gj := generation(revs[i]), generation(revs[j])
+		if gi != gj {
+			return gi > gj
+		}
+		return revs[j].CreationTimestamp.Before(&revs[i].CreationTimestamp)
+	})
+	if len(revs) > revisionHistoryLimit {
+		revs = revs[:revisionHistoryLimit]
+	}
+
+	history := make([]v1.RevisionHistoryEntry, 0, len(revs))
+	for _, rev := range revs {
+		history = append(history, v1.RevisionHistoryEntry{
+			RevisionName: rev.Name,
+			Creator:      rev.Annotations[serving.CreatorAnnotation],
+			ChangeCause:  rev.Annotations[serving.ChangeCauseAnnotationKey],
+		})
+	}
+	return history
+}
@@ -19,13 +19,18 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
 
 	// Install our fake informers
 	_ "knative.dev/serving/pkg/client/injection/informers/serving/v1/configuration/fake"
 	_ "knative.dev/serving/pkg/client/injection/informers/serving/v1/revision/fake"
 	_ "knative.dev/serving/pkg/client/injection/informers/serving/v1/route/fake"
 	_ "knative.dev/serving/pkg/client/injection/informers/serving/v1/service/fake"
+	_ "knative.dev/serving/pkg/client/injection/informers/serving/v1alpha1/servicetemplate/fake"
 
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/configmap"
@@ -34,9 +39,11 @@ import (
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/ptr"
 	"knative.dev/pkg/system"
+	"knative.dev/serving/pkg/apis/autoscaling"
 	cfgmap "knative.dev/serving/pkg/apis/config"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/apis/serving/v1alpha1"
 	autoscalercfg "knative.dev/serving/pkg/autoscaler/config"
 	servingclient "knative.dev/serving/pkg/client/injection/client"
 	ksvcreconciler "knative.dev/serving/pkg/client/injection/reconciler/serving/v1/service"
@@ -55,6 +62,25 @@ import (
 	. "knative.dev/serving/pkg/testing/v1"
 )
 
+var fakeCurTime = time.Unix(1e9, 0)
+
+// withExpiredTag adds a traffic target whose tag expired an hour ago.
+func withExpiredTag(s *v1.Service) {
+	WithServiceAnnotation(serving.TagExpiryAnnotationPrefix+"preview",
+		fakeCurTime.Add(-time.Hour).Format(time.RFC3339))(s)
+	WithRouteSpec(v1.RouteSpec{
+		Traffic: []v1.TrafficTarget{{
+			Percent:        ptr.Int64(100),
+			LatestRevision: ptr.Bool(true),
+		}, {
+			Tag:            "preview",
+			RevisionName:   "expired-tag-00001",
+			Percent:        ptr.Int64(0),
+			LatestRevision: ptr.Bool(false),
+		}},
+	})(s)
+}
+
 func TestReconcile(t *testing.T) {
 	retryAttempted := false
 	table := TableTest{{
@@ -148,7 +174,8 @@ func TestReconcile(t *testing.T) {
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: DefaultService("byo-rev", "foo",
 				WithNamedRevision, WithInitSvcConditions, WithServiceObservedGenFailure,
-				WithServiceGeneration(2), WithServiceObservedGeneration),
+				WithServiceGeneration(2), WithServiceObservedGeneration,
+				WithServiceRevisionHistory(v1.RevisionHistoryEntry{RevisionName: "byo-rev-byo"})),
 		}},
 	}, {
 		Name: "inline - byo rev name - existing older revision with different spec",
@@ -277,6 +304,30 @@ func TestReconcile(t *testing.T) {
 		}, {
 			Object: route("update-annos", "foo", WithRunLatestRollout),
 		}},
+	}, {
+		Name: "expired traffic tag",
+		Objects: []runtime.Object{
+			DefaultService("expired-tag", "foo", withExpiredTag, WithInitSvcConditions),
+			config("expired-tag", "foo", withExpiredTag),
+			route("expired-tag", "foo", withExpiredTag),
+		},
+		Key: "foo/expired-tag",
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: route("expired-tag", "foo", withExpiredTag, WithSpecTraffic(v1.TrafficTarget{
+				ConfigurationName: "expired-tag",
+				Percent:           ptr.Int64(100),
+				LatestRevision:    ptr.Bool(true),
+			})),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{Namespace: "foo"},
+			Name:       "expired-tag",
+			Patch: []byte(`{"metadata":{"annotations":{"` + serving.TagExpiryAnnotationPrefix + `preview":null},"resourceVersion":""},` +
+				`"spec":{"traffic":[{"latestRevision":true,"percent":100}]}}`),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "TagExpired", "Removed expired traffic tag %q", "preview"),
+		},
 	}, {
 		Name: "update route and configuration",
 		Objects: []runtime.Object{
@@ -776,10 +827,14 @@ func TestReconcile(t *testing.T) {
 	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
 		retryAttempted = false
 		r := &Reconciler{
-			client:              servingclient.Get(ctx),
-			configurationLister: listers.GetConfigurationLister(),
-			revisionLister:      listers.GetRevisionLister(),
-			routeLister:         listers.GetRouteLister(),
+			client:                servingclient.Get(ctx),
+			configurationLister:   listers.GetConfigurationLister(),
+			revisionLister:        listers.GetRevisionLister(),
+			routeLister:           listers.GetRouteLister(),
+			serviceTemplateLister: listers.GetServiceTemplateLister(),
+			tracker:               &NullTracker{},
+			clock:                 FakeClock{Time: fakeCurTime},
+			enqueueAfter:          func(interface{}, time.Duration) {},
 		}
 
 		return ksvcreconciler.NewReconciler(ctx, logging.FromContext(ctx), servingclient.Get(ctx),
@@ -787,6 +842,187 @@ func TestReconcile(t *testing.T) {
 	}))
 }
 
+func TestReconcileServiceTemplate(t *testing.T) {
+	table := TableTest{{
+		Name: "create configuration from template",
+		Objects: []runtime.Object{
+			serviceTemplate(1),
+			templatedService("create", WithServiceGeneration(1)),
+		},
+		Key: "foo/create",
+		WantCreates: []runtime.Object{
+			templatedConfig(t, "create", serviceTemplate(1)),
+			templatedRoute("create"),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: templatedService("create",
+				WithInitSvcConditions, WithServiceObservedGenFailure,
+				WithServiceGeneration(1), WithServiceObservedGeneration),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Created", "Created Configuration %q", "create"),
+			Eventf(corev1.EventTypeNormal, "Created", "Created Route %q", "create"),
+		},
+	}, {
+		Name: "missing template",
+		Objects: []runtime.Object{
+			templatedService("missing"),
+		},
+		Key:     "foo/missing",
+		WantErr: true,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: templatedService("missing", WithInitSvcConditions,
+				func(s *v1.Service) {
+					s.Status.MarkServiceTemplateMissing("base")
+				}),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, "InternalError", `ServiceTemplate "base" does not exist`),
+		},
+	}, {
+		Name: "steady state",
+		Objects: []runtime.Object{
+			serviceTemplate(1),
+			templatedService("steady", WithInitSvcConditions),
+			templatedConfig(t, "steady", serviceTemplate(1)),
+			templatedRoute("steady"),
+		},
+		Key: "foo/steady",
+	}, {
+		Name: "template change rolls out",
+		Objects: []runtime.Object{
+			serviceTemplate(2, withTemplateEnv("FOO", "baz")),
+			templatedService("rollout", WithInitSvcConditions),
+			templatedConfig(t, "rollout", serviceTemplate(1)),
+			templatedRoute("rollout"),
+		},
+		Key: "foo/rollout",
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: templatedConfig(t, "rollout", serviceTemplate(2, withTemplateEnv("FOO", "baz"))),
+		}},
+	}, {
+		Name: "template change held with manual rollout",
+		Objects: []runtime.Object{
+			serviceTemplate(2, withTemplateEnv("FOO", "baz")),
+			templatedService("manual", WithInitSvcConditions, withManualRollout),
+			templatedConfig(t, "manual", serviceTemplate(1), withManualRollout),
+			templatedRoute("manual", withManualRollout),
+		},
+		Key: "foo/manual",
+	}, {
+		Name: "template change picked up by service change with manual rollout",
+		Objects: []runtime.Object{
+			serviceTemplate(2, withTemplateEnv("FOO", "baz")),
+			templatedService("manual-changed", WithInitSvcConditions, withManualRollout,
+				func(s *v1.Service) {
+					s.Generation = 2
+					s.Status.ObservedGeneration = 1
+				}),
+			templatedConfig(t, "manual-changed", serviceTemplate(1), withManualRollout),
+			templatedRoute("manual-changed", withManualRollout),
+		},
+		Key: "foo/manual-changed",
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: templatedConfig(t, "manual-changed", serviceTemplate(2, withTemplateEnv("FOO", "baz")),
+				withManualRollout),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: templatedService("manual-changed", WithInitSvcConditions, withManualRollout,
+				WithServiceObservedGenFailure,
+				func(s *v1.Service) {
+					s.Generation = 2
+					s.Status.ObservedGeneration = 2
+				}),
+		}},
+	}}
+
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
+		r := &Reconciler{
+			client:                servingclient.Get(ctx),
+			configurationLister:   listers.GetConfigurationLister(),
+			revisionLister:        listers.GetRevisionLister(),
+			routeLister:           listers.GetRouteLister(),
+			serviceTemplateLister: listers.GetServiceTemplateLister(),
+			tracker:               &NullTracker{},
+			clock:                 FakeClock{Time: fakeCurTime},
+			enqueueAfter:          func(interface{}, time.Duration) {},
+		}
+
+		return ksvcreconciler.NewReconciler(ctx, logging.FromContext(ctx), servingclient.Get(ctx),
+			listers.GetServiceLister(), controller.GetEventRecorder(ctx), r)
+	}))
+}
+
+func serviceTemplate(generation int64, opts ...func(*v1alpha1.ServiceTemplate)) *v1alpha1.ServiceTemplate {
+	st := &v1alpha1.ServiceTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "base",
+			Namespace:  "foo",
+			Generation: generation,
+		},
+		Spec: v1alpha1.ServiceTemplateSpec{
+			Template: v1.RevisionTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						autoscaling.TargetAnnotationKey: "10",
+					},
+				},
+				Spec: v1.RevisionSpec{
+					PodSpec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Image: "busybox",
+							Env:   []corev1.EnvVar{{Name: "FOO", Value: "bar"}},
+						}},
+					},
+					TimeoutSeconds: ptr.Int64(60),
+				},
+			},
+		},
+	}
+	for _, opt := range opts {
+		opt(st)
+	}
+	return st
+}
+
+func withTemplateEnv(name, value string) func(*v1alpha1.ServiceTemplate) {
+	return func(st *v1alpha1.ServiceTemplate) {
+		st.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{{Name: name, Value: value}}
+	}
+}
+
+func withManualRollout(s *v1.Service) {
+	s.Annotations = kmeta.UnionMaps(s.Annotations, map[string]string{
+		serving.ServiceTemplateRolloutAnnotationKey: string(serving.ServiceTemplateRolloutManual),
+	})
+}
+
+// templatedService returns a Service built on the "base" ServiceTemplate,
+// overriding the env of its container.
+func templatedService(name string, so ...ServiceOption) *v1.Service {
+	return DefaultService(name, "foo", append([]ServiceOption{
+		WithServiceAnnotation(serving.ServiceTemplateAnnotationKey, "base"),
+		func(s *v1.Service) {
+			s.Spec.Template.Spec.Containers = []corev1.Container{{
+				Env: []corev1.EnvVar{{Name: "BAZ", Value: "qux"}},
+			}}
+		},
+	}, so...)...)
+}
+
+func templatedConfig(t *testing.T, name string, st *v1alpha1.ServiceTemplate, so ...ServiceOption) *v1.Configuration {
+	t.Helper()
+	cfg, err := resources.MakeConfiguration(context.Background(), templatedService(name, so...), st)
+	if err != nil {
+		t.Fatal("MakeConfiguration() =", err)
+	}
+	return cfg
+}
+
+func templatedRoute(name string, so ...ServiceOption) *v1.Route {
+	return resources.MakeRoute(context.Background(), templatedService(name, so...))
+}
+
 func TestNew(t *testing.T) {
 	ctx, _ := SetupFakeContext(t)
 
@@ -796,6 +1032,12 @@ func TestNew(t *testing.T) {
 			Namespace: system.Namespace(),
 		},
 		Data: map[string]string{},
+	}, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cfgmap.MetadataPropagationConfigName,
+			Namespace: system.Namespace(),
+		},
+		Data: map[string]string{},
 	}, &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cfgmap.DefaultsConfigName,
@@ -820,7 +1062,7 @@ func TestNew(t *testing.T) {
 func config(name, namespace string, so ServiceOption, co ...ConfigOption) *v1.Configuration {
 	s := DefaultService(name, namespace, so)
 	s.SetDefaults(context.Background())
-	cfg := resources.MakeConfiguration(s)
+	cfg, _ := resources.MakeConfiguration(context.Background(), s, nil)
 	for _, opt := range co {
 		opt(cfg)
 	}
@@ -830,7 +1072,7 @@ func config(name, namespace string, so ServiceOption, co ...ConfigOption) *v1.Co
 func route(name, namespace string, so ServiceOption, ro ...RouteOption) *v1.Route {
 	s := DefaultService(name, namespace, so)
 	s.SetDefaults(context.Background())
-	route := resources.MakeRoute(s)
+	route := resources.MakeRoute(context.Background(), s)
 	for _, opt := range ro {
 		opt(route)
 	}
@@ -868,6 +1110,35 @@ func RouteFailed(reason, message string) RouteOption {
 	}
 }
 
+func TestRevisionHistory(t *testing.T) {
+	cfg := config("history", "foo", WithRunLatestRollout)
+	var objs []runtime.Object
+	for gen := 1; gen <= revisionHistoryLimit+2; gen++ {
+		r := rev("history", "foo", WithRunLatestRollout, WithConfigGeneration(int64(gen)))
+		r.Annotations[serving.CreatorAnnotation] = "someone"
+		r.Annotations[serving.ChangeCauseAnnotationKey] = fmt.Sprint("change ", gen)
+		objs = append(objs, r)
+	}
+	// Revisions of other Configurations are not part of the history.
+	objs = append(objs, rev("other", "foo", WithRunLatestRollout))
+
+	listers := NewListers(objs)
+	c := &Reconciler{revisionLister: listers.GetRevisionLister()}
+	got := c.revisionHistory(cfg)
+
+	var want []v1.RevisionHistoryEntry
+	for gen := revisionHistoryLimit + 2; gen > 2; gen-- {
+		want = append(want, v1.RevisionHistoryEntry{
+			RevisionName: fmt.Sprintf("history-%05d", gen),
+			Creator:      "someone",
+			ChangeCause:  fmt.Sprint("change ", gen),
+		})
+	}
+	if !cmp.Equal(got, want) {
+		t.Error("revisionHistory() (-want, +got):", cmp.Diff(want, got))
+	}
+}
+
 func rev(name, namespace string, so ServiceOption, co ...ConfigOption) *v1.Revision {
 	cfg := config(name, namespace, so, co...)
 	return configresources.MakeRevision(context.Background(), cfg, clock.RealClock{})
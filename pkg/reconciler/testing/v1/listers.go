@@ -123,6 +123,11 @@ func (l *Listers) GetDomainMappingLister() servingv1alpha1listers.DomainMappingL
 	return servingv1alpha1listers.NewDomainMappingLister(l.IndexerFor(&v1alpha1.DomainMapping{}))
 }
 
+// GetServiceTemplateLister returns a lister for ServiceTemplate objects.
+func (l *Listers) GetServiceTemplateLister() servingv1alpha1listers.ServiceTemplateLister {
+	return servingv1alpha1listers.NewServiceTemplateLister(l.IndexerFor(&v1alpha1.ServiceTemplate{}))
+}
+
 // GetServerlessServiceLister returns a lister for the ServerlessService objects.
 func (l *Listers) GetServerlessServiceLister() networkinglisters.ServerlessServiceLister {
 	return networkinglisters.NewServerlessServiceLister(l.IndexerFor(&networking.ServerlessService{}))
@@ -18,7 +18,10 @@ package resources
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	corev1listers "k8s.io/client-go/listers/core/v1"
+	"knative.dev/pkg/system"
+	"knative.dev/serving/pkg/networking"
 )
 
 // ReadyAddressCount returns the total number of addresses ready for the given endpoint.
@@ -86,3 +89,29 @@ func NewScopedEndpointsCounter(lister corev1listers.EndpointsLister, namespace,
 		serviceName:     serviceName,
 	}
 }
+
+// ActivatorEndpoints returns the endpoints of the activator pool serving
+// revisions in the given namespace. The pool is selected by the
+// networking.ActivatorPoolLabelKey label on the namespace; namespaces without
+// the label, or that cannot be found, use the default activator pool. When the
+// selected pool does not exist, the default activator pool is used as well,
+// and the name of the missing pool is returned so it can be reported.
+func ActivatorEndpoints(nsLister corev1listers.NamespaceLister, epsLister corev1listers.EndpointsLister, namespace string) (
+	eps *corev1.Endpoints, missingPool string, err error) {
+	var pool string
+	ns, err := nsLister.Get(namespace)
+	if err == nil {
+		pool = ns.Labels[networking.ActivatorPoolLabelKey]
+	} else if !apierrs.IsNotFound(err) {
+		return nil, "", err
+	}
+	if pool != "" {
+		eps, err := epsLister.Endpoints(system.Namespace()).Get(networking.ActivatorPoolServiceName(pool))
+		if !apierrs.IsNotFound(err) {
+			return eps, "", err
+		}
+		missingPool = pool
+	}
+	eps, err = epsLister.Endpoints(system.Namespace()).Get(networking.ActivatorServiceName)
+	return eps, missingPool, err
+}
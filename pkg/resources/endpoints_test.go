@@ -25,6 +25,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubeinformers "k8s.io/client-go/informers"
 	fakek8s "k8s.io/client-go/kubernetes/fake"
+	"knative.dev/pkg/system"
+	"knative.dev/serving/pkg/networking"
+
+	_ "knative.dev/pkg/system/testing"
 )
 
 const (
@@ -130,6 +134,75 @@ func TestReadyAddressCount(t *testing.T) {
 	}
 }
 
+func TestActivatorEndpoints(t *testing.T) {
+	factory := kubeinformers.NewSharedInformerFactory(fakek8s.NewSimpleClientset(), 0)
+	nsInformer := factory.Core().V1().Namespaces()
+	epsInformer := factory.Core().V1().Endpoints()
+
+	for _, ns := range []*corev1.Namespace{{
+		ObjectMeta: metav1.ObjectMeta{Name: "default-pool"},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "tenant",
+			Labels: map[string]string{networking.ActivatorPoolLabelKey: "tenant"},
+		},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "missing-pool",
+			Labels: map[string]string{networking.ActivatorPoolLabelKey: "missing"},
+		},
+	}} {
+		nsInformer.Informer().GetIndexer().Add(ns)
+	}
+	for _, name := range []string{networking.ActivatorServiceName, networking.ActivatorPoolServiceName("tenant")} {
+		epsInformer.Informer().GetIndexer().Add(&corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      name,
+			},
+		})
+	}
+
+	tests := []struct {
+		name        string
+		namespace   string
+		want        string
+		wantMissing string
+	}{{
+		name:      "unlabeled namespace",
+		namespace: "default-pool",
+		want:      networking.ActivatorServiceName,
+	}, {
+		name:      "unknown namespace",
+		namespace: "gone",
+		want:      networking.ActivatorServiceName,
+	}, {
+		name:      "dedicated pool",
+		namespace: "tenant",
+		want:      "activator-service-tenant",
+	}, {
+		name:        "pool without endpoints",
+		namespace:   "missing-pool",
+		want:        networking.ActivatorServiceName,
+		wantMissing: "missing",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			eps, missing, err := ActivatorEndpoints(nsInformer.Lister(), epsInformer.Lister(), test.namespace)
+			if err != nil {
+				t.Fatal("ActivatorEndpoints() =", err)
+			}
+			if eps.Name != test.want {
+				t.Errorf("ActivatorEndpoints() = %s, want: %s", eps.Name, test.want)
+			}
+			if missing != test.wantMissing {
+				t.Errorf("ActivatorEndpoints() missing pool = %q, want: %q", missing, test.wantMissing)
+			}
+		})
+	}
+}
+
 func endpoints(readyIPCount, notReadyIPCount int) *corev1.Endpoints {
 	ep := &corev1.Endpoints{
 		ObjectMeta: metav1.ObjectMeta{
@@ -23,6 +23,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	corev1listers "k8s.io/client-go/listers/core/v1"
+	"knative.dev/serving/pkg/apis/autoscaling"
 	"knative.dev/serving/pkg/apis/serving"
 )
 
@@ -44,6 +45,18 @@ func NewPodAccessor(lister corev1listers.PodLister, namespace, revisionName stri
 	}
 }
 
+// NewStandalonePodAccessor creates a PodAccessor implementation that counts
+// pods for a namespace/standalone PodAutoscaler, i.e. the pods of a user-managed
+// PodScalable labeled with autoscaling.PodAutoscalerLabelKey.
+func NewStandalonePodAccessor(lister corev1listers.PodLister, namespace, paName string) PodAccessor {
+	return PodAccessor{
+		podsLister: lister.Pods(namespace),
+		selector: labels.SelectorFromSet(labels.Set{
+			autoscaling.PodAutoscalerLabelKey: paName,
+		}),
+	}
+}
+
 // PodCountsByState returns number of pods for the revision grouped by their state, that is
 // of interest to knative (e.g. ignoring failed or terminated pods).
 func (pa PodAccessor) PodCountsByState() (ready, notReady, pending, terminating int, err error) {
@@ -124,8 +137,12 @@ func podRunning(p *corev1.Pod) bool {
 	return p.Status.Phase == corev1.PodRunning && p.DeletionTimestamp == nil
 }
 
-// podReady checks whether pod's Ready status is True.
+// podReady checks whether pod's Ready status is True and none of its
+// readiness gates are pending.
 func podReady(p *corev1.Pod) bool {
+	if _, pending := PendingReadinessGate(p); pending {
+		return false
+	}
 	for _, cond := range p.Status.Conditions {
 		if cond.Type == corev1.PodReady {
 			return cond.Status == corev1.ConditionTrue
@@ -135,6 +152,27 @@ func podReady(p *corev1.Pod) bool {
 	return false
 }
 
+// PendingReadinessGate returns the first readiness gate of the pod whose
+// condition is not yet True, and whether there is such a gate.
+// The kubelet already folds readiness gates into the PodReady condition,
+// but it does so asynchronously, so we check them explicitly to avoid
+// counting a pod as ready during the window in between.
+func PendingReadinessGate(p *corev1.Pod) (corev1.PodConditionType, bool) {
+	for _, gate := range p.Spec.ReadinessGates {
+		satisfied := false
+		for _, cond := range p.Status.Conditions {
+			if cond.Type == gate.ConditionType {
+				satisfied = cond.Status == corev1.ConditionTrue
+				break
+			}
+		}
+		if !satisfied {
+			return gate.ConditionType, true
+		}
+	}
+	return "", false
+}
+
 type podIPByAgeSorter struct {
 	pods []*corev1.Pod
 }
@@ -29,6 +29,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubeinformers "k8s.io/client-go/informers"
 	fakek8s "k8s.io/client-go/kubernetes/fake"
+	"knative.dev/serving/pkg/apis/autoscaling"
 	"knative.dev/serving/pkg/apis/serving"
 )
 
@@ -95,6 +96,14 @@ func TestPodReadyUnreadyCount(t *testing.T) {
 			}),
 		},
 		want: 1,
+	}, {
+		name: "readiness gate pending",
+		pods: []*corev1.Pod{
+			pod("custard-pie", makeReady, withReadinessGate("example.com/warm", corev1.ConditionFalse)),
+			pod("the-rover", makeReady, withReadinessGate("example.com/warm", "")),
+			pod("trampled-under-foot", makeReady, withReadinessGate("example.com/warm", corev1.ConditionTrue)),
+		},
+		want: 1,
 	}}
 
 	for _, tc := range tests {
@@ -286,6 +295,24 @@ func TestPendingTerminatingCounts(t *testing.T) {
 	}
 }
 
+func TestStandalonePodAccessor(t *testing.T) {
+	podsClient := kubeinformers.NewSharedInformerFactory(fakek8s.NewSimpleClientset(), 0).Core().V1().Pods()
+	standalone := pod("standalone", makeReady, func(p *corev1.Pod) {
+		p.Labels = map[string]string{autoscaling.PodAutoscalerLabelKey: "my-pa"}
+	})
+	for _, p := range []*corev1.Pod{standalone, pod("revision", makeReady)} {
+		podsClient.Informer().GetIndexer().Add(p)
+	}
+
+	ready, err := NewStandalonePodAccessor(podsClient.Lister(), testNamespace, "my-pa").ReadyCount()
+	if err != nil {
+		t.Fatal("ReadyCount() =", err)
+	}
+	if ready != 1 {
+		t.Errorf("ReadyCount() = %d, want: 1", ready)
+	}
+}
+
 type podOption func(p *corev1.Pod)
 
 func pod(name string, pos ...podOption) *corev1.Pod {
@@ -318,6 +345,58 @@ func makeReady(p *corev1.Pod) {
 	}}
 }
 
+// withReadinessGate adds a readiness gate of the given type to the pod and,
+// if status is not empty, the matching pod condition.
+func withReadinessGate(ct corev1.PodConditionType, status corev1.ConditionStatus) podOption {
+	return func(p *corev1.Pod) {
+		p.Spec.ReadinessGates = append(p.Spec.ReadinessGates, corev1.PodReadinessGate{
+			ConditionType: ct,
+		})
+		if status != "" {
+			p.Status.Conditions = append(p.Status.Conditions, corev1.PodCondition{
+				Type:   ct,
+				Status: status,
+			})
+		}
+	}
+}
+
+func TestPendingReadinessGate(t *testing.T) {
+	tests := []struct {
+		name        string
+		pod         *corev1.Pod
+		wantGate    corev1.PodConditionType
+		wantPending bool
+	}{{
+		name: "no gates",
+		pod:  pod("kashmir", makeReady),
+	}, {
+		name: "gate satisfied",
+		pod:  pod("kashmir", makeReady, withReadinessGate("example.com/lb", corev1.ConditionTrue)),
+	}, {
+		name:        "gate without condition",
+		pod:         pod("kashmir", makeReady, withReadinessGate("example.com/lb", "")),
+		wantGate:    "example.com/lb",
+		wantPending: true,
+	}, {
+		name: "second gate false",
+		pod: pod("kashmir",
+			withReadinessGate("example.com/lb", corev1.ConditionTrue),
+			withReadinessGate("example.com/cache", corev1.ConditionFalse)),
+		wantGate:    "example.com/cache",
+		wantPending: true,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			gate, pending := PendingReadinessGate(tc.pod)
+			if gate != tc.wantGate || pending != tc.wantPending {
+				t.Errorf("PendingReadinessGate = (%q, %v), want: (%q, %v)", gate, pending, tc.wantGate, tc.wantPending)
+			}
+		})
+	}
+}
+
 func withStartTime(t time.Time) podOption {
 	tm := metav1.NewTime(t)
 	return func(p *corev1.Pod) {
@@ -316,6 +316,24 @@ func WithUnschedulableContainer(reason, message string) PodOption {
 	}
 }
 
+// WithPendingReadinessGate adds a readiness gate of the given condition type
+// to the pod, whose containers are ready but whose gate condition is not yet
+// satisfied.
+func WithPendingReadinessGate(conditionType string) PodOption {
+	return func(pod *corev1.Pod) {
+		pod.Spec.ReadinessGates = append(pod.Spec.ReadinessGates, corev1.PodReadinessGate{
+			ConditionType: corev1.PodConditionType(conditionType),
+		})
+		pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{
+			Type:   corev1.ContainersReady,
+			Status: corev1.ConditionTrue,
+		}, corev1.PodCondition{
+			Type:   corev1.PodConditionType(conditionType),
+			Status: corev1.ConditionFalse,
+		})
+	}
+}
+
 // WithWaitingContainer sets the .Status.ContainerStatuses on the pod to
 // include a container named accordingly to wait with the given state.
 func WithWaitingContainer(name, reason, message string) PodOption {
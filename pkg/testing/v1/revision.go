@@ -187,6 +187,15 @@ func MarkResourcesUnavailable(reason, message string) RevisionOption {
 	}
 }
 
+// MarkReadinessGatePending marks the Revision's resources as waiting on the
+// given pod's readiness gate.
+func MarkReadinessGatePending(pod, gate string) RevisionOption {
+	return func(r *v1.Revision) {
+		r.Status.MarkResourcesAvailableUnknown(v1.ReasonReadinessGatePending,
+			v1.ReadinessGatePendingMessage(pod, gate))
+	}
+}
+
 // MarkRevisionReady calls the necessary helpers to make the Revision Ready=True.
 func MarkRevisionReady(r *v1.Revision) {
 	WithInitRevConditions(r)
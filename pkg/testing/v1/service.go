@@ -400,6 +400,13 @@ func WithServiceLatestReadyRevision(lrr string) ServiceOption {
 	}
 }
 
+// WithServiceRevisionHistory sets the revision history on the Service's status.
+func WithServiceRevisionHistory(entries ...v1.RevisionHistoryEntry) ServiceOption {
+	return func(s *v1.Service) {
+		s.Status.RevisionHistory = entries
+	}
+}
+
 // WithReadinessProbe sets the provided probe to be the readiness
 // probe on the service.
 func WithReadinessProbe(p *corev1.Probe) ServiceOption {
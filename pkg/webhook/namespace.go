@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/webhook/resourcesemantics"
+	"knative.dev/serving/pkg/apis/config"
+	asconfig "knative.dev/serving/pkg/autoscaler/config"
+)
+
+// Namespace wraps Namespaces, so that the config-autoscaler overrides they
+// carry are validated on admission, rather than ignored by the reconcilers.
+type Namespace struct {
+	corev1.Namespace
+}
+
+var _ resourcesemantics.GenericCRD = (*Namespace)(nil)
+
+// DeepCopyObject implements runtime.Object.
+func (ns *Namespace) DeepCopyObject() runtime.Object {
+	return &Namespace{Namespace: *ns.Namespace.DeepCopy()}
+}
+
+// SetDefaults implements apis.Defaultable. Namespaces are not defaulted.
+func (ns *Namespace) SetDefaults(context.Context) {}
+
+// Validate implements apis.Validatable.
+func (ns *Namespace) Validate(ctx context.Context) *apis.FieldError {
+	base := config.FromContextOrDefaults(ctx).Autoscaler
+	if _, err := asconfig.WithNamespaceOverrides(base, ns.Annotations); err != nil {
+		return apis.ErrGeneric(err.Error(), "metadata.annotations")
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	asconfig "knative.dev/serving/pkg/autoscaler/config"
+)
+
+func TestNamespaceValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     bool
+	}{{
+		name: "no overrides",
+		annotations: map[string]string{
+			"foo": "bar",
+		},
+	}, {
+		name: "valid overrides",
+		annotations: map[string]string{
+			asconfig.NamespaceClassOverrideAnnotationKey:                              autoscaling.HPA,
+			asconfig.NamespaceOverrideAnnotationPrefix + "stable-window":              "2m",
+			asconfig.NamespaceOverrideAnnotationPrefix + "max-scale":                  "10",
+			asconfig.NamespaceOverrideAnnotationPrefix + "scale-to-zero-grace-period": "1m",
+		},
+	}, {
+		name: "unparsable override",
+		annotations: map[string]string{
+			asconfig.NamespaceOverrideAnnotationPrefix + "stable-window": "forever",
+		},
+		wantErr: true,
+	}, {
+		name: "invalid override",
+		annotations: map[string]string{
+			asconfig.NamespaceOverrideAnnotationPrefix + "stable-window": "1s",
+		},
+		wantErr: true,
+	}, {
+		name: "key not overridable",
+		annotations: map[string]string{
+			asconfig.NamespaceOverrideAnnotationPrefix + "enable-scale-to-zero": "false",
+		},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ns := &Namespace{Namespace: corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "foo",
+					Annotations: test.annotations,
+				},
+			}}
+			if err := ns.Validate(context.Background()); (err != nil) != test.wantErr {
+				t.Errorf("Validate() = %v, wantErr = %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -21,6 +21,8 @@ import (
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"knative.dev/pkg/apis"
@@ -28,6 +30,8 @@ import (
 	"knative.dev/serving/pkg/apis/config"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingclient "knative.dev/serving/pkg/client/injection/client"
+	serviceresources "knative.dev/serving/pkg/reconciler/service/resources"
 )
 
 // PodSpecDryRunAnnotation gates the podspec dryrun feature and runs with the value 'enabled'
@@ -46,9 +50,59 @@ const (
 
 // ValidateService runs extra validation on Service resources
 func ValidateService(ctx context.Context, uns *unstructured.Unstructured) error {
+	if name := uns.GetAnnotations()[serving.ServiceTemplateAnnotationKey]; name != "" {
+		return validateTemplatedService(ctx, uns, name)
+	}
+
 	return validateRevisionTemplate(ctx, uns)
 }
 
+// validateTemplatedService validates the revision template of a Service built
+// on the named ServiceTemplate. The template of such a Service is partial, so
+// it is merged into the ServiceTemplate's first, as the Service's controller
+// does, and the merged template is validated and dry-run.
+func validateTemplatedService(ctx context.Context, uns *unstructured.Unstructured, name string) error {
+	namespace := uns.GetNamespace()
+	tmpl, err := servingclient.Get(ctx).ServingV1alpha1().ServiceTemplates(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrs.IsNotFound(err) {
+		// The Service's controller waits for the ServiceTemplate to be
+		// created, and the merged Configuration is validated once written.
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get ServiceTemplate %q: %w", name, err)
+	}
+
+	templ := &v1.RevisionTemplateSpec{}
+	val, found, err := unstructured.NestedFieldNoCopy(uns.UnstructuredContent(), "spec", "template")
+	if err != nil {
+		return fmt.Errorf("could not traverse nested spec.template field: %w", err)
+	}
+	if found {
+		if templ, err = decodeTemplate(val); err != nil {
+			return err
+		}
+	}
+	merged, err := serviceresources.MergeServiceTemplate(tmpl, templ)
+	if err != nil {
+		return fmt.Errorf("failed to merge ServiceTemplate %q: %w", name, err)
+	}
+
+	merged.SetDefaults(ctx)
+	parent := metav1.ObjectMeta{Name: uns.GetName(), Namespace: namespace}
+	if errs := merged.Validate(apis.WithinParent(ctx, parent)); errs != nil {
+		return errs.ViaField("spec", "template")
+	}
+
+	mode, ok := dryRunMode(ctx, uns)
+	if !ok {
+		return nil
+	}
+	if err := validatePodSpec(ctx, merged.Spec, namespace, mode); err != nil {
+		return err
+	}
+	return nil
+}
+
 // ValidateConfiguration runs extra validation on Configuration resources
 func ValidateConfiguration(ctx context.Context, uns *unstructured.Unstructured) error {
 	// If owned by a service, skip validation for Configuration.
@@ -59,9 +113,9 @@ func ValidateConfiguration(ctx context.Context, uns *unstructured.Unstructured)
 	return validateRevisionTemplate(ctx, uns)
 }
 
-func validateRevisionTemplate(ctx context.Context, uns *unstructured.Unstructured) error {
-	content := uns.UnstructuredContent()
-
+// dryRunMode returns how the pod spec of the resource is dry-run, and whether
+// it is dry-run at all.
+func dryRunMode(ctx context.Context, uns *unstructured.Unstructured) (DryRunMode, bool) {
 	mode := DryRunMode(uns.GetAnnotations()[PodSpecDryRunAnnotation])
 	features := config.FromContextOrDefaults(ctx).Features
 	switch features.PodSpecDryRun {
@@ -70,13 +124,20 @@ func validateRevisionTemplate(ctx context.Context, uns *unstructured.Unstructure
 			mode = DryRunEnabled
 		}
 	case config.Disabled:
-		return nil
+		return "", false
 	}
 
 	// TODO(https://github.com/knative/serving/issues/3425): remove this guard once variations
 	// of this are well-tested. Only run extra validation for the dry-run test.
 	// This will be in place to while the feature is tested for compatibility and later removed.
-	if mode != DryRunStrict && mode != DryRunEnabled {
+	return mode, mode == DryRunStrict || mode == DryRunEnabled
+}
+
+func validateRevisionTemplate(ctx context.Context, uns *unstructured.Unstructured) error {
+	content := uns.UnstructuredContent()
+
+	mode, ok := dryRunMode(ctx, uns)
+	if !ok {
 		return nil
 	}
 
@@ -25,13 +25,17 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	clientgotesting "k8s.io/client-go/testing"
 
 	"knative.dev/pkg/apis"
 	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
 	"knative.dev/pkg/logging"
 	logtesting "knative.dev/pkg/logging/testing"
 	"knative.dev/serving/pkg/apis/config"
+	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"knative.dev/serving/pkg/apis/serving/v1alpha1"
+	fakeservingclient "knative.dev/serving/pkg/client/injection/client/fake"
 )
 
 var (
@@ -277,3 +281,97 @@ func enableDryRun(ctx context.Context, flag config.Flag) context.Context {
 		},
 	})
 }
+
+func TestServiceTemplateValidation(t *testing.T) {
+	tmpl := &v1alpha1.ServiceTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "base",
+			Namespace: "foo",
+		},
+		Spec: v1alpha1.ServiceTemplateSpec{
+			Template: v1.RevisionTemplateSpec{
+				Spec: v1.RevisionSpec{
+					PodSpec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Image: "busybox",
+						}},
+					},
+				},
+			},
+		},
+	}
+	service := func(template string, spec map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":      "valid",
+				"namespace": "foo",
+				"annotations": map[string]interface{}{
+					"features.knative.dev/podspec-dryrun": "enabled",
+					serving.ServiceTemplateAnnotationKey:  template,
+				},
+			},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": spec,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		data      map[string]interface{}
+		want      string
+		wantImage string
+	}{{
+		name: "valid merged template",
+		data: service("base", map[string]interface{}{
+			"containers": []interface{}{map[string]interface{}{
+				"env": []interface{}{map[string]interface{}{"name": "FOO", "value": "bar"}},
+			}},
+		}),
+		wantImage: "busybox",
+	}, {
+		name: "invalid merged template",
+		data: service("base", map[string]interface{}{
+			"containerConcurrency": int64(-1),
+		}),
+		want: "spec.template.spec.containerConcurrency",
+	}, {
+		name: "missing ServiceTemplate",
+		data: service("missing", map[string]interface{}{
+			"containerConcurrency": int64(-1),
+		}),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, kubeClient := fakekubeclient.With(context.Background())
+			ctx, _ = fakeservingclient.With(ctx, tmpl)
+			ctx = logging.WithLogger(ctx, logtesting.TestLogger(t))
+
+			unstruct := &unstructured.Unstructured{}
+			unstruct.SetUnstructuredContent(test.data)
+
+			got := ValidateService(ctx, unstruct)
+			if got == nil {
+				if test.want != "" {
+					t.Errorf("Validate got=nil, want=%q", test.want)
+				}
+			} else if test.want == "" || !strings.Contains(got.Error(), test.want) {
+				t.Errorf("Validate got=%q, want=%q", got.Error(), test.want)
+			}
+
+			var gotImage string
+			for _, action := range kubeClient.Actions() {
+				if action.Matches("create", "pods") {
+					pod := action.(clientgotesting.CreateAction).GetObject().(*corev1.Pod)
+					gotImage = pod.Spec.Containers[0].Image
+				}
+			}
+			if gotImage != test.wantImage {
+				t.Errorf("Dry-run image = %q, want: %q", gotImage, test.wantImage)
+			}
+		})
+	}
+}